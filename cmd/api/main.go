@@ -2,15 +2,25 @@ package main
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/exchange"
 	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
 	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
 	"github.com/ViitoJooj/verkoupe/internal/port/http/routers"
+	"github.com/ViitoJooj/verkoupe/internal/port/mail"
+	"github.com/ViitoJooj/verkoupe/internal/port/payment"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/repositories"
+	"github.com/ViitoJooj/verkoupe/internal/port/remote"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
 	"github.com/ViitoJooj/verkoupe/pkg/dotenv"
 	"github.com/ViitoJooj/verkoupe/pkg/logger"
 	"github.com/ViitoJooj/verkoupe/pkg/postgresql"
+	"github.com/ViitoJooj/verkoupe/pkg/scheduler"
 	"github.com/ViitoJooj/verkoupe/pkg/server"
 )
 
@@ -30,25 +40,43 @@ func main() {
 		logger.Fatal(err).Print()
 	}
 
+	blobStore, err := blob.Conn(cfg.Storage)
+	if err != nil {
+		logger.Fatal(err).Print()
+	}
+
 	mux := http.NewServeMux()
 
+	var mailer contracts.MailerContract
+	if cfg.Mail.Driver == "log" {
+		mailer = mail.NewLogMailer(cfg.Mail.From)
+	}
+
+	websiteRepository := repositories.NewWebsiteRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	userTokenRepository := repositories.NewUserTokenRepository(db)
+	loginAttemptRepository := repositories.NewLoginAttemptRepository(db)
+	twoFactorRepository := repositories.NewTwoFactorRepository(db)
+	authUseCase := usecases.NewAuthUseCase(userRepository, websiteRepository, userTokenRepository, loginAttemptRepository, twoFactorRepository, mailer, cfg.Security.PasetoSecretKey, cfg.Application.ViewUrl)
+	scheduler.Every(time.Hour, authUseCase.PruneLoginFailures)
+
 	corsMiddleware := middleware.CORSMiddleware(cfg.Application.ViewUrl)
-	authMiddleware := middleware.AuthMiddleware(cfg.Security.PasetoSecretKey)
+	authMiddleware := middleware.AuthMiddleware(cfg.Security.PasetoSecretKey, authUseCase.SessionValid)
 
 	addressRepository := repositories.NewAddressRepository(db)
 	createAddressUseCase := usecases.NewCreateAddressUseCase(addressRepository)
 	addressController := controllers.NewAddressController(createAddressUseCase)
 	routers.RegisterAddressRoutes(mux, addressController, corsMiddleware, authMiddleware)
 
-	userRepository := repositories.NewUserRepository(db)
-	authUseCase := usecases.NewAuthUseCase(userRepository)
 	authController := controllers.NewAuthController(authUseCase)
 	routers.RegisterAuthRoutes(mux, authController, corsMiddleware, authMiddleware)
 
+	twoFactorUseCase := usecases.NewTwoFactorUseCase(twoFactorRepository, userRepository, websiteRepository, authUseCase, cfg.Security.PasetoSecretKey)
+	scheduler.Every(24*time.Hour, twoFactorUseCase.PruneTrustedDevices)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorUseCase)
+	routers.RegisterTwoFactorRoutes(mux, twoFactorController, corsMiddleware, authMiddleware)
+
 	cupomRepository := repositories.NewCupomRepository(db)
-	createCupomUseCase := usecases.NewCreateCupomUseCase(cupomRepository)
-	cupomController := controllers.NewCupomController(createCupomUseCase)
-	routers.RegisterCupomRoutes(mux, cupomController, corsMiddleware, authMiddleware)
 
 	organizationRepository := repositories.NewOrganizationRepository(db)
 	organizationUseCase := usecases.NewCreateOrganizationUseCase(organizationRepository)
@@ -65,18 +93,141 @@ func main() {
 	planController := controllers.NewPlanController(createPlanUseCase)
 	routers.RegisterPlanRoutes(mux, planController, corsMiddleware, authMiddleware)
 
+	subscriptionRepository := repositories.NewSubscriptionRepository(db)
+	var paymentProvider contracts.PaymentProviderContract
+	if cfg.Billing.PaymentProvider == "fake" {
+		paymentProvider = payment.NewFakeProvider()
+	}
+	subscriptionUseCase := usecases.NewSubscriptionUseCase(subscriptionRepository, planRepository, paymentProvider)
+	scheduler.Every(15*time.Minute, subscriptionUseCase.RunDue)
+
+	exchangeRateRepository := repositories.NewExchangeRateRepository(db)
+	currencyRoundingRepository := repositories.NewCurrencyRoundingRepository(db)
+	var exchangeRateSource contracts.ExchangeRateSourceContract
+	if cfg.Currency.ExchangeRatesFile != "" {
+		exchangeRateSource = exchange.NewFileSource(cfg.Currency.ExchangeRatesFile)
+	}
+	currencyUseCase := usecases.NewCurrencyUseCase(exchangeRateRepository, currencyRoundingRepository, exchangeRateSource)
+	scheduler.Every(24*time.Hour, currencyUseCase.SyncDaily)
+
+	searchSuggestionRepository := repositories.NewSearchSuggestionRepository(db)
+	searchSuggestionUseCase := usecases.NewSearchSuggestionUseCase(searchSuggestionRepository)
+	searchSuggestionController := controllers.NewSearchSuggestionController(searchSuggestionUseCase)
+	routers.RegisterSearchSuggestionRoutes(mux, searchSuggestionController, corsMiddleware, authMiddleware)
+	scheduler.Every(30*time.Second, searchSuggestionUseCase.FlushSearchQueries)
+
+	productRepository := repositories.NewProductRepository(db)
+	createProductUseCase := usecases.NewCreateProductUseCase(productRepository, websiteRepository, subscriptionRepository)
+	createProductUseCase.OnChange(searchSuggestionUseCase.Invalidate)
+
+	rbacRepository := repositories.NewRbacRepository(db)
+	organizationMemberRepository := repositories.NewOrganizationMemberRepository(db)
+	organizationMemberUseCase := usecases.NewOrganizationMemberUseCase(organizationMemberRepository, organizationRepository, userRepository, rbacRepository, websiteRepository, twoFactorRepository, mailer, cfg.Application.ViewUrl)
+	websiteGuard := controllers.NewWebsiteGuard(organizationMemberUseCase, createProductUseCase)
+
+	subscriptionController := controllers.NewSubscriptionController(subscriptionUseCase, websiteGuard)
+	routers.RegisterSubscriptionRoutes(mux, subscriptionController, corsMiddleware, authMiddleware)
+
+	currencyController := controllers.NewCurrencyController(currencyUseCase, websiteGuard, controllers.NewPlatformAdmins(parseList(cfg.Security.PlatformAdmins)))
+	routers.RegisterCurrencyRoutes(mux, currencyController, corsMiddleware, authMiddleware)
+
+	productMediaRepository := repositories.NewProductMediaRepository(db)
+	maxMediaBytes, _ := strconv.ParseInt(cfg.Storage.MaxUploadBytes, 10, 64)
+	jpegQuality, _ := strconv.Atoi(cfg.Storage.JPEGQuality)
+	createProductMediaUseCase := usecases.NewCreateProductMediaUseCase(productMediaRepository, productRepository, blobStore, maxMediaBytes)
+	processProductMediaUseCase := usecases.NewProcessProductMediaUseCase(productMediaRepository, blobStore, parseIntList(cfg.Storage.ImageWidths), parseList(cfg.Storage.ImageFormats), jpegQuality)
+	scheduler.Every(15*time.Second, processProductMediaUseCase.ProcessPending)
+	productMediaController := controllers.NewProductMediaController(createProductMediaUseCase, websiteGuard)
+	routers.RegisterProductMediaRoutes(mux, productMediaController, corsMiddleware, authMiddleware)
+	if localStore, ok := blobStore.(*blob.LocalStore); ok {
+		routers.RegisterMediaFileRoutes(mux, localStore.Root(), corsMiddleware)
+	}
+
+	productController := controllers.NewProductController(createProductUseCase, createProductMediaUseCase, websiteGuard)
+	routers.RegisterProductRoutes(mux, productController, corsMiddleware, authMiddleware)
+
+	productFileRepository := repositories.NewProductFileRepository(db)
+	maxProductFileBytes, _ := strconv.ParseInt(cfg.Storage.MaxFileBytes, 10, 64)
+	productFileUseCase := usecases.NewProductFileUseCase(productFileRepository, productRepository, blobStore, maxProductFileBytes)
+	productFileController := controllers.NewProductFileController(productFileUseCase, websiteGuard)
+	routers.RegisterProductFileRoutes(mux, productFileController, corsMiddleware, authMiddleware)
+
+	orderRepository := repositories.NewOrderRepository(db)
+	productDownloadRepository := repositories.NewProductDownloadRepository(db)
+	productDownloadUseCase := usecases.NewProductDownloadUseCase(productDownloadRepository, productFileRepository, productRepository, orderRepository, blobStore, cfg.Security.PasetoSecretKey, cfg.Application.DaemonUrl)
+	productDownloadController := controllers.NewProductDownloadController(productDownloadUseCase)
+	routers.RegisterProductDownloadRoutes(mux, productDownloadController, corsMiddleware, authMiddleware)
+	routers.RegisterDownloadFileRoutes(mux, productDownloadController, corsMiddleware)
+
+	productReviewRepository := repositories.NewProductReviewRepository(db)
+	productReviewUseCase := usecases.NewProductReviewUseCase(productReviewRepository, productRepository, blobStore, maxMediaBytes)
+	productReviewController := controllers.NewProductReviewController(productReviewUseCase, websiteGuard)
+	routers.RegisterProductReviewRoutes(mux, productReviewController, corsMiddleware, authMiddleware)
+	routers.RegisterPublicProductReviewRoutes(mux, productReviewController, corsMiddleware)
+
 	preparingShippingProductRepository := repositories.NewPreparingShippingProductRepository(db)
-	createPreparingShippingProductUseCase := usecases.NewCreatePreparingShippingProductUseCase(preparingShippingProductRepository)
+	createPreparingShippingProductUseCase := usecases.NewCreatePreparingShippingProductUseCase(preparingShippingProductRepository, productRepository)
 	preparingShippingProductController := controllers.NewPreparingShippingProductController(createPreparingShippingProductUseCase)
 	routers.RegisterPreparingShippingProductRoutes(mux, preparingShippingProductController, corsMiddleware, authMiddleware)
 
-	productRepository := repositories.NewProductRepository(db)
-	createProductUseCase := usecases.NewCreateProductUseCase(productRepository)
-	productController := controllers.NewProductController(createProductUseCase)
-	routers.RegisterProductRoutes(mux, productController, corsMiddleware, authMiddleware)
+	productImportRepository := repositories.NewProductImportRepository(db)
+	maxImportBytes, _ := strconv.ParseInt(cfg.Storage.MaxImportBytes, 10, 64)
+	mediaFetcher := remote.NewHTTPFetcher(time.Minute)
+	productImportUseCase := usecases.NewProductImportUseCase(productImportRepository, websiteRepository, subscriptionRepository, createProductMediaUseCase, mediaFetcher, blobStore, maxImportBytes)
+	productImportUseCase.OnChange(searchSuggestionUseCase.Invalidate)
+	scheduler.Every(5*time.Second, productImportUseCase.ProcessPending)
+	productImportController := controllers.NewProductImportController(productImportUseCase, websiteGuard)
+	routers.RegisterProductImportRoutes(mux, productImportController, corsMiddleware, authMiddleware)
+
+	productSearchRepository := repositories.NewProductSearchRepository(db)
+	searchProductsUseCase := usecases.NewSearchProductsUseCase(productSearchRepository, searchSuggestionUseCase)
+	productSearchController := controllers.NewProductSearchController(searchProductsUseCase)
+	routers.RegisterProductSearchRoutes(mux, productSearchController, corsMiddleware, authMiddleware)
+
+	productVariantRepository := repositories.NewProductVariantRepository(db)
+	createProductVariantUseCase := usecases.NewCreateProductVariantUseCase(productVariantRepository, productRepository)
+	productVariantController := controllers.NewProductVariantController(createProductVariantUseCase, websiteGuard)
+	routers.RegisterProductVariantRoutes(mux, productVariantController, corsMiddleware, authMiddleware)
+
+	priceListRepository := repositories.NewPriceListRepository(db)
+	createPriceListUseCase := usecases.NewCreatePriceListUseCase(priceListRepository)
+	priceListController := controllers.NewPriceListController(createPriceListUseCase, websiteGuard)
+	routers.RegisterPriceListRoutes(mux, priceListController, corsMiddleware, authMiddleware)
+
+	productPriceRepository := repositories.NewProductPriceRepository(db)
+	createProductPriceUseCase := usecases.NewCreateProductPriceUseCase(productPriceRepository, productRepository, productVariantRepository, priceListRepository)
+	resolveProductPriceUseCase := usecases.NewResolveProductPriceUseCase(productPriceRepository, websiteRepository, priceListRepository, currencyUseCase)
+	productPriceController := controllers.NewProductPriceController(createProductPriceUseCase, resolveProductPriceUseCase, websiteGuard)
+	routers.RegisterProductPriceRoutes(mux, productPriceController, corsMiddleware, authMiddleware)
+
+	wishlistRepository := repositories.NewWishlistRepository(db)
+	wishlistUseCase := usecases.NewWishlistUseCase(wishlistRepository, productRepository, productVariantRepository, cfg.Application.DaemonUrl)
+	scheduler.Every(time.Minute, wishlistUseCase.DetectAlerts)
+	wishlistController := controllers.NewWishlistController(wishlistUseCase)
+	routers.RegisterWishlistRoutes(mux, wishlistController, corsMiddleware, authMiddleware)
+	routers.RegisterSharedWishlistRoutes(mux, wishlistController, corsMiddleware)
+
+	giftCardRepository := repositories.NewGiftCardRepository(db)
+	giftCardUseCase := usecases.NewGiftCardUseCase(giftCardRepository, cupomRepository, mailer)
+	scheduler.Every(time.Hour, giftCardUseCase.ExpireDue)
+	giftCardController := controllers.NewGiftCardController(giftCardUseCase, websiteGuard)
+	routers.RegisterGiftCardRoutes(mux, giftCardController, corsMiddleware, authMiddleware)
+
+	loyaltyRepository := repositories.NewLoyaltyRepository(db)
+	loyaltyUseCase := usecases.NewLoyaltyUseCase(loyaltyRepository, userRepository)
+	productReviewUseCase.OnApprove(loyaltyUseCase.AwardReviewBonus)
+	scheduler.Every(time.Hour, loyaltyUseCase.ExpireDue)
+	loyaltyController := controllers.NewLoyaltyController(loyaltyUseCase, websiteGuard)
+	routers.RegisterLoyaltyRoutes(mux, loyaltyController, corsMiddleware, authMiddleware)
+
+	categoryRepository := repositories.NewCategoryRepository(db)
+	createCategoryUseCase := usecases.NewCreateCategoryUseCase(categoryRepository, productRepository)
+	createCategoryUseCase.OnChange(searchSuggestionUseCase.Invalidate)
+	categoryController := controllers.NewCategoryController(createCategoryUseCase, websiteGuard)
+	routers.RegisterCategoryRoutes(mux, categoryController, corsMiddleware, authMiddleware)
 
 	productShippedRepository := repositories.NewProductShippedRepository(db)
-	createProductShippedUseCase := usecases.NewCreateProductShippedUseCase(productShippedRepository)
+	createProductShippedUseCase := usecases.NewCreateProductShippedUseCase(productShippedRepository, productRepository)
 	productShippedController := controllers.NewProductShippedController(createProductShippedUseCase)
 	routers.RegisterProductShippedRoutes(mux, productShippedController, corsMiddleware, authMiddleware)
 
@@ -85,7 +236,10 @@ func main() {
 	productTagController := controllers.NewProductTagController(createProductTagUseCase)
 	routers.RegisterProductTagRoutes(mux, productTagController, corsMiddleware, authMiddleware)
 
-	rbacRepository := repositories.NewRbacRepository(db)
+	createCupomUseCase := usecases.NewCreateCupomUseCase(cupomRepository, productTagRepository, productRepository)
+	cupomController := controllers.NewCupomController(createCupomUseCase, websiteGuard)
+	routers.RegisterCupomRoutes(mux, cupomController, corsMiddleware, authMiddleware)
+
 	createRbacUseCase := usecases.NewCreateRbacUseCase(rbacRepository)
 	rbacController := controllers.NewRbacController(createRbacUseCase)
 	routers.RegisterRbacRoutes(mux, rbacController, corsMiddleware, authMiddleware)
@@ -105,15 +259,73 @@ func main() {
 	termsAcceptedController := controllers.NewTermsAcceptedController(createTermsAcceptedUseCase)
 	routers.RegisterTermsAcceptedRoutes(mux, termsAcceptedController, corsMiddleware, authMiddleware)
 
-	websiteRepository := repositories.NewWebsiteRepository(db)
-	createWebsiteUseCase := usecases.NewCreateWebsiteUseCase(websiteRepository)
-	websiteController := controllers.NewWebsiteController(createWebsiteUseCase)
+	organizationMemberController := controllers.NewOrganizationMemberController(organizationMemberUseCase)
+	routers.RegisterOrganizationMemberRoutes(mux, organizationMemberController, corsMiddleware, authMiddleware)
+
+	createWebsiteUseCase := usecases.NewCreateWebsiteUseCase(websiteRepository, subscriptionRepository)
+	websiteController := controllers.NewWebsiteController(createWebsiteUseCase, organizationMemberUseCase, websiteGuard)
 	routers.RegisterWebsiteRoutes(mux, websiteController, corsMiddleware, authMiddleware)
 
+	productFeedRepository := repositories.NewProductFeedRepository(db)
+	productFeedUseCase := usecases.NewProductFeedUseCase(productFeedRepository, websiteRepository, resolveProductPriceUseCase, blobStore)
+	createProductUseCase.OnChange(productFeedUseCase.Invalidate)
+	createProductVariantUseCase.OnChange(productFeedUseCase.Invalidate)
+	createProductPriceUseCase.OnChange(productFeedUseCase.Invalidate)
+	createProductMediaUseCase.OnChange(productFeedUseCase.Invalidate)
+	createStorageProductUseCase.OnChange(productFeedUseCase.Invalidate)
+	createCategoryUseCase.OnChange(productFeedUseCase.Invalidate)
+	productImportUseCase.OnChange(productFeedUseCase.Invalidate)
+	subscriptionUseCase.OnChange(productFeedUseCase.Invalidate)
+	productFeedController := controllers.NewProductFeedController(productFeedUseCase)
+	routers.RegisterProductFeedRoutes(mux, productFeedController, corsMiddleware)
+
+	platformFeeRepository := repositories.NewPlatformFeeRepository(db)
+	platformFeeUseCase := usecases.NewPlatformFeeUseCase(platformFeeRepository, websiteRepository, subscriptionRepository)
+	platformFeeController := controllers.NewPlatformFeeController(platformFeeUseCase, websiteGuard)
+	routers.RegisterPlatformFeeRoutes(mux, platformFeeController, corsMiddleware, authMiddleware)
+
+	cartRepository := repositories.NewCartRepository(db)
+	cartUseCase := usecases.NewCartUseCase(cartRepository, productRepository, productVariantRepository, websiteRepository, userRepository, cupomRepository, resolveProductPriceUseCase, mailer, cfg.Security.PasetoSecretKey, cfg.Application.DaemonUrl)
+	scheduler.Every(5*time.Minute, cartUseCase.SendReminders)
+	cartController := controllers.NewCartController(cartUseCase, websiteGuard)
+	routers.RegisterCartRoutes(mux, cartController, corsMiddleware, authMiddleware)
+	routers.RegisterCartRestoreRoutes(mux, cartController, corsMiddleware)
+
+	orderUseCase := usecases.NewOrderUseCase(orderRepository, cupomRepository, productTagRepository, cartUseCase, loyaltyUseCase, giftCardUseCase, paymentProvider)
+	orderUseCase.OnPaid("downloads", productDownloadUseCase.GrantOrder)
+	orderUseCase.OnPaid("gift_cards", giftCardUseCase.IssueOrder)
+	orderUseCase.OnPaid("loyalty", loyaltyUseCase.EarnOrder)
+	orderUseCase.OnRefund("loyalty", loyaltyUseCase.ReverseOrder)
+	orderUseCase.OnPaid("platform_fees", platformFeeUseCase.RecordOrder)
+	orderUseCase.OnRefund("platform_fees", platformFeeUseCase.RefundOrder)
+	scheduler.Every(time.Minute, orderUseCase.RunEvents)
+	orderController := controllers.NewOrderController(orderUseCase, websiteGuard)
+	routers.RegisterOrderRoutes(mux, orderController, corsMiddleware, authMiddleware)
+
 	websiteComponentRepository := repositories.NewWebsiteComponentRepository(db)
-	createWebsiteComponentUseCase := usecases.NewCreateWebsiteComponentUseCase(websiteComponentRepository)
+	createWebsiteComponentUseCase := usecases.NewCreateWebsiteComponentUseCase(websiteComponentRepository, websiteRepository, subscriptionRepository)
 	websiteComponentController := controllers.NewWebsiteComponentController(createWebsiteComponentUseCase)
 	routers.RegisterWebsiteComponentRoutes(mux, websiteComponentController, corsMiddleware, authMiddleware)
 
 	server.Start(cfg.Application.Port, mux)
 }
+
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseIntList(value string) []int {
+	var items []int
+	for _, item := range parseList(value) {
+		if n, err := strconv.Atoi(item); err == nil && n > 0 {
+			items = append(items, n)
+		}
+	}
+	return items
+}
@@ -12,6 +12,8 @@ require (
 	github.com/lib/pq v1.12.3
 	github.com/o1egl/paseto/v2 v2.1.1
 	golang.org/x/crypto v0.0.0-20200117160349-530e935923ad
+	golang.org/x/image v0.25.0
+	rsc.io/qr v0.2.0
 )
 
 require (
@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxCartItems        = 100
+	MaxCartItemQuantity = 99
+)
+
+// Cart recovery defaults, used until a merchant saves their own settings.
+const (
+	DefaultCartIdleMinutes   = 60
+	DefaultCartReminders     = 3
+	DefaultCartIntervalHours = 24
+	MaxCartReminders         = 5
+)
+
+var (
+	ErrCartEmpty           = errors.New("cart is empty")
+	ErrCartFull            = errors.New("cart item limit reached")
+	ErrCartItemUnavailable = errors.New("cart item is no longer available")
+	ErrCartCheckingOut     = errors.New("cart is already being checked out")
+)
+
+// Cart is the shopper's open cart on a website. It is closed when an order
+// made from it is paid. ActivityAt moves with every change and is what
+// abandoned cart recovery measures idleness from.
+type Cart struct {
+	UUID              uuid.UUID
+	WebsiteUUID       uuid.UUID
+	UserUUID          uuid.UUID
+	Email             string
+	Status            enums.CartStatusType
+	RemindersSent     int
+	LastReminderAt    *time.Time
+	RestoredAt        *time.Time
+	RecoveryCupomUUID *uuid.UUID
+	OrderUUID         *uuid.UUID
+	ActivityAt        time.Time
+	UpdatedAt         *time.Time
+	CreatedAt         time.Time
+	Items             []*CartItem
+}
+
+type CartItem struct {
+	UUID        uuid.UUID
+	CartUUID    uuid.UUID
+	ProductUUID uuid.UUID
+	VariantUUID *uuid.UUID
+	Quantity    int
+	CreatedAt   time.Time
+}
+
+// CartLine is a cart item priced in the currency of the checkout.
+type CartLine struct {
+	Item       *CartItem
+	Product    *Products
+	UnitAmount int
+	Amount     int
+}
+
+// CartQuote is the cart priced for checkout.
+type CartQuote struct {
+	Cart     *Cart
+	Coin     enums.CoinType
+	Lines    []*CartLine
+	Subtotal int
+}
+
+// CartRecoverySettings is how a website chases abandoned carts: once a cart
+// with items sits idle for IdleMinutes, up to Reminders emails are sent
+// IntervalHours apart. With CupomValue set, the last reminder carries a
+// single-use coupon valid for CupomValidDays.
+type CartRecoverySettings struct {
+	WebsiteUUID    uuid.UUID
+	Active         bool
+	IdleMinutes    int
+	Reminders      int
+	IntervalHours  int
+	CupomValue     string
+	CupomValueType enums.CupomValueType
+	CupomValidDays int
+	UpdatedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+// CartRecoveryTotal sums the paid orders of one currency that came from a
+// reminded cart, net of refunds.
+type CartRecoveryTotal struct {
+	Coin    enums.CoinType
+	Orders  int
+	Revenue int
+}
+
+func NewCart(websiteUUID string, userUUID string, email string) (*Cart, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cart{
+		UUID:        uuid.Nil,
+		WebsiteUUID: websiteUUIDParsed,
+		UserUUID:    userUUIDParsed,
+		Email:       email,
+		Status:      enums.CartOpen,
+	}, nil
+}
+
+func NewCartItem(cartUUID uuid.UUID, productUUID string, variantUUID string, quantity int) (*CartItem, error) {
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variantPtr *uuid.UUID
+	if variantUUID != "" {
+		parsed, err := uuid.Parse(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		variantPtr = &parsed
+	}
+
+	if err := ValidateCartQuantity(quantity); err != nil {
+		return nil, err
+	}
+
+	return &CartItem{
+		UUID:        uuid.Nil,
+		CartUUID:    cartUUID,
+		ProductUUID: productUUIDParsed,
+		VariantUUID: variantPtr,
+		Quantity:    quantity,
+	}, nil
+}
+
+func ValidateCartQuantity(quantity int) error {
+	if quantity < 1 || quantity > MaxCartItemQuantity {
+		return errors.New("Quantity must be between 1 and 99.")
+	}
+
+	return nil
+}
+
+// Target is what the item is priced as.
+func (i *CartItem) Target() PriceTarget {
+	target := PriceTarget{ProductUUID: i.ProductUUID}
+	if i.VariantUUID != nil {
+		target.VariantUUID = *i.VariantUUID
+	}
+	return target
+}
+
+// Reminded reports whether the cart was sent at least one recovery
+// reminder, which makes an order paid from it count as recovered.
+func (c *Cart) Reminded() bool {
+	return c.RemindersSent > 0
+}
+
+func NewCartRecoverySettings(websiteUUID string, active bool, idleMinutes int, reminders int, intervalHours int, cupomValue string, cupomValueType string, cupomValidDays int) (*CartRecoverySettings, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if idleMinutes == 0 {
+		idleMinutes = DefaultCartIdleMinutes
+	}
+	if reminders == 0 {
+		reminders = DefaultCartReminders
+	}
+	if intervalHours == 0 {
+		intervalHours = DefaultCartIntervalHours
+	}
+
+	if idleMinutes < 15 {
+		return nil, errors.New("IdleMinutes must be at least 15.")
+	}
+
+	if reminders < 1 || reminders > MaxCartReminders {
+		return nil, errors.New("Reminders must be between 1 and 5.")
+	}
+
+	if intervalHours < 1 {
+		return nil, errors.New("IntervalHours must be at least 1.")
+	}
+
+	settings := &CartRecoverySettings{
+		WebsiteUUID:   websiteUUIDParsed,
+		Active:        active,
+		IdleMinutes:   idleMinutes,
+		Reminders:     reminders,
+		IntervalHours: intervalHours,
+	}
+
+	if cupomValue != "" {
+		// The coupon is checked the way any other coupon would be.
+		cupom := &Cupons{Value: cupomValue, ValueType: enums.CupomValueType(cupomValueType)}
+		if cupom.ValueType != enums.CupomPercentage && cupom.ValueType != enums.CupomValue {
+			return nil, errors.New("CupomValueType must be 'percentage' or 'Value'.")
+		}
+		if _, err := cupom.Discount(0); err != nil {
+			return nil, err
+		}
+
+		if cupomValidDays < 1 {
+			return nil, errors.New("CupomValidDays must be at least 1.")
+		}
+
+		settings.CupomValue = cupomValue
+		settings.CupomValueType = cupom.ValueType
+		settings.CupomValidDays = cupomValidDays
+	}
+
+	return settings, nil
+}
+
+// DefaultCartRecoverySettings are the settings of a website that never
+// saved any: recovery stays off.
+func DefaultCartRecoverySettings(websiteUUID uuid.UUID) *CartRecoverySettings {
+	return &CartRecoverySettings{
+		WebsiteUUID:   websiteUUID,
+		IdleMinutes:   DefaultCartIdleMinutes,
+		Reminders:     DefaultCartReminders,
+		IntervalHours: DefaultCartIntervalHours,
+	}
+}
+
+// OffersCupom reports whether reminder number n, counting from one, comes
+// with a coupon: only the last one of the sequence does.
+func (s *CartRecoverySettings) OffersCupom(n int) bool {
+	return s.CupomValue != "" && n == s.Reminders
+}
+
+// RecoveryCupom is the single-use coupon sent with the last reminder of a
+// cart, in the website's base currency.
+func (s *CartRecoverySettings) RecoveryCupom(cart *Cart, coin enums.CoinType, now time.Time) *Cupons {
+	limit := 1
+	expiresAt := now.AddDate(0, 0, s.CupomValidDays)
+
+	return &Cupons{
+		UUID:        uuid.Nil,
+		WebsiteUUID: s.WebsiteUUID,
+		Coin:        coin,
+		Label:       "Cart recovery",
+		Description: "Sent to " + cart.Email + " to finish cart " + cart.UUID.String(),
+		Value:       s.CupomValue,
+		ValueType:   s.CupomValueType,
+		UsageLimit:  &limit,
+		ExpiresAt:   &expiresAt,
+	}
+}
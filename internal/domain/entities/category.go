@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+type Category struct {
+	UUID           uuid.UUID
+	WebsiteUUID    uuid.UUID
+	ParentUUID     *uuid.UUID
+	Path           string
+	Depth          int
+	Name           string
+	Slug           string
+	Position       int
+	SEOTitle       string
+	SEODescription string
+	Active         bool
+	UpdatedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+type ProductCategory struct {
+	ProductUUID  uuid.UUID
+	CategoryUUID uuid.UUID
+	Position     int
+	CreatedAt    time.Time
+}
+
+func NewCategory(websiteUUID string, parentUUID string, name string, slug string, position int, seoTitle string, seoDescription string, active bool) (*Category, error) {
+	if name == "" {
+		return nil, errors.New("Name cannot be null.")
+	}
+
+	if !slugPattern.MatchString(slug) {
+		return nil, errors.New("Slug must contain only lowercase letters, numbers and dashes.")
+	}
+
+	if position < 0 {
+		return nil, errors.New("Position cannot be negative.")
+	}
+
+	if len(seoTitle) > 250 {
+		return nil, errors.New("SEOTitle cannot be longer than 250 characters.")
+	}
+
+	if len(seoDescription) > 500 {
+		return nil, errors.New("SEODescription cannot be longer than 500 characters.")
+	}
+
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentPtr *uuid.UUID
+	if parentUUID != "" {
+		parsed, err := uuid.Parse(parentUUID)
+		if err != nil {
+			return nil, err
+		}
+		parentPtr = &parsed
+	}
+
+	return &Category{
+		UUID:           uuid.Nil,
+		WebsiteUUID:    websiteUUIDParsed,
+		ParentUUID:     parentPtr,
+		Name:           name,
+		Slug:           slug,
+		Position:       position,
+		SEOTitle:       seoTitle,
+		SEODescription: seoDescription,
+		Active:         active,
+	}, nil
+}
+
+// IsAncestorOf reports whether c is other itself or one of its ancestors,
+// which is what makes a move into other create a cycle.
+func (c *Category) IsAncestorOf(other *Category) bool {
+	return strings.HasPrefix(other.Path, c.Path)
+}
+
+func NewProductCategory(productUUID string, categoryUUID string, position int) (*ProductCategory, error) {
+	if position < 0 {
+		return nil, errors.New("Position cannot be negative.")
+	}
+
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryUUIDParsed, err := uuid.Parse(categoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductCategory{
+		ProductUUID:  productUUIDParsed,
+		CategoryUUID: categoryUUIDParsed,
+		Position:     position,
+	}, nil
+}
+
+// CategoryProduct is a product listed under a category. Rank orders products
+// by the depth of their closest category, then by their position in it.
+type CategoryProduct struct {
+	*Products
+	Rank int64
+}
+
+type CategoryNode struct {
+	Category *Category
+	Children []*CategoryNode
+}
+
+// BuildCategoryTree nests a flat list of categories, keeping the input order
+// among siblings.
+func BuildCategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.UUID] = &CategoryNode{Category: c}
+	}
+
+	roots := []*CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.UUID]
+		if c.ParentUUID != nil {
+			if parent, ok := nodes[*c.ParentUUID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
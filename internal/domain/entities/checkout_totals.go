@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+// CheckoutTotals splits what the shopper owes. The coupon comes off the
+// subtotal first; gift cards are a way to pay and cover what is left, in
+// the order they were entered, with AmountDue going to the other payment
+// methods.
+type CheckoutTotals struct {
+	Coin      enums.CoinType
+	Subtotal  int
+	Discount  int
+	Total     int
+	GiftCards []*GiftCardApplication
+	AmountDue int
+}
+
+type GiftCardApplication struct {
+	Card   *GiftCard
+	Amount int
+}
+
+func ComputeCheckoutTotals(websiteUUID string, coin enums.CoinType, subtotal int, cupom *Cupons, cards []*GiftCard, userUUID string, now time.Time) (*CheckoutTotals, error) {
+	if subtotal < 0 {
+		return nil, errors.New("Subtotal cannot be negative.")
+	}
+
+	totals := &CheckoutTotals{
+		Coin:     coin,
+		Subtotal: subtotal,
+	}
+
+	if cupom != nil {
+		if err := cupom.Usable(websiteUUID, coin, now); err != nil {
+			return nil, err
+		}
+
+		discount, err := cupom.Discount(subtotal)
+		if err != nil {
+			return nil, err
+		}
+		totals.Discount = discount
+	}
+
+	totals.Total = subtotal - totals.Discount
+	totals.AmountDue = totals.Total
+
+	seen := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		if seen[card.CodeHash] {
+			return nil, errors.New("The same gift card was entered twice.")
+		}
+		seen[card.CodeHash] = true
+
+		if err := card.Usable(coin, userUUID, now); err != nil {
+			return nil, err
+		}
+
+		amount := min(card.Balance, totals.AmountDue)
+		if amount == 0 {
+			continue
+		}
+
+		totals.GiftCards = append(totals.GiftCards, &GiftCardApplication{Card: card, Amount: amount})
+		totals.AmountDue -= amount
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func TestComputeCheckoutTotals(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	website := uuid.New()
+	shopper := uuid.New()
+	past := now.Add(-time.Hour)
+	one := 1
+
+	cupom := func(value string, valueType enums.CupomValueType) *Cupons {
+		return &Cupons{WebsiteUUID: website, Coin: enums.BRCoin, Value: value, ValueType: valueType}
+	}
+	card := func(hash string, balance int) *GiftCard {
+		return &GiftCard{CodeHash: hash, Coin: enums.BRCoin, Balance: balance}
+	}
+
+	otherWebsite := cupom("10", enums.CupomPercentage)
+	otherWebsite.WebsiteUUID = uuid.New()
+	usdCupom := cupom("10", enums.CupomPercentage)
+	usdCupom.Coin = enums.EUACoin
+	expiredCupom := cupom("10", enums.CupomPercentage)
+	expiredCupom.ExpiresAt = &past
+	usedCupom := cupom("10", enums.CupomPercentage)
+	usedCupom.UsageLimit, usedCupom.UsedCount = &one, 1
+
+	credit := card("credit", 5000)
+	credit.UserUUID = &shopper
+	strangerCredit := card("stranger", 5000)
+	stranger := uuid.New()
+	strangerCredit.UserUUID = &stranger
+	expiredCard := card("expired", 5000)
+	expiredCard.ExpiresAt = &past
+
+	tests := []struct {
+		name      string
+		subtotal  int
+		cupom     *Cupons
+		cards     []*GiftCard
+		discount  int
+		total     int
+		applied   []int
+		amountDue int
+		err       error
+	}{
+		{name: "nothing applied", subtotal: 10000, total: 10000, amountDue: 10000},
+		{name: "percentage cupom", subtotal: 10000, cupom: cupom("15", enums.CupomPercentage), discount: 1500, total: 8500, amountDue: 8500},
+		{name: "percentage rounds", subtotal: 999, cupom: cupom("10", enums.CupomPercentage), discount: 100, total: 899, amountDue: 899},
+		{name: "percentage capped at 100", subtotal: 10000, cupom: cupom("150", enums.CupomPercentage), discount: 10000, total: 0, amountDue: 0},
+		{name: "value cupom", subtotal: 10000, cupom: cupom("15.50", enums.CupomValue), discount: 1550, total: 8450, amountDue: 8450},
+		{name: "value cupom over subtotal", subtotal: 1000, cupom: cupom("25", enums.CupomValue), discount: 1000, total: 0, amountDue: 0},
+		{name: "gift card covers part", subtotal: 10000, cards: []*GiftCard{card("a", 3000)}, total: 10000, applied: []int{3000}, amountDue: 7000},
+		{name: "gift cards in order", subtotal: 10000, cards: []*GiftCard{card("a", 6000), card("b", 6000)}, total: 10000, applied: []int{6000, 4000}, amountDue: 0},
+		{name: "card left over skipped", subtotal: 5000, cards: []*GiftCard{card("a", 6000), card("b", 6000)}, total: 5000, applied: []int{5000}, amountDue: 0},
+		{name: "cupom before gift cards", subtotal: 10000, cupom: cupom("50", enums.CupomPercentage), cards: []*GiftCard{card("a", 6000)}, discount: 5000, total: 5000, applied: []int{5000}, amountDue: 0},
+		{name: "own store credit", subtotal: 10000, cards: []*GiftCard{credit}, total: 10000, applied: []int{5000}, amountDue: 5000},
+		{name: "negative subtotal", subtotal: -1, err: errAny},
+		{name: "cupom of another website", subtotal: 10000, cupom: otherWebsite, err: ErrCupomNotFound},
+		{name: "cupom in another coin", subtotal: 10000, cupom: usdCupom, err: ErrCupomCoin},
+		{name: "expired cupom", subtotal: 10000, cupom: expiredCupom, err: ErrCupomExpired},
+		{name: "used up cupom", subtotal: 10000, cupom: usedCupom, err: ErrCupomUsedUp},
+		{name: "invalid cupom value", subtotal: 10000, cupom: cupom("ten", enums.CupomValue), err: errAny},
+		{name: "same card twice", subtotal: 10000, cards: []*GiftCard{card("a", 1000), card("a", 1000)}, err: errAny},
+		{name: "empty card", subtotal: 10000, cards: []*GiftCard{card("a", 0)}, err: ErrGiftCardEmpty},
+		{name: "expired card", subtotal: 10000, cards: []*GiftCard{expiredCard}, err: ErrGiftCardExpired},
+		{name: "card in another coin", subtotal: 10000, cards: []*GiftCard{{CodeHash: "usd", Coin: enums.EUACoin, Balance: 1000}}, err: ErrGiftCardCoin},
+		{name: "store credit of another shopper", subtotal: 10000, cards: []*GiftCard{strangerCredit}, err: ErrGiftCardOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totals, err := ComputeCheckoutTotals(website.String(), enums.BRCoin, tt.subtotal, tt.cupom, tt.cards, shopper.String(), now)
+			if tt.err != nil {
+				if err == nil || (tt.err != errAny && !errors.Is(err, tt.err)) {
+					t.Fatalf("ComputeCheckoutTotals() = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if totals.Subtotal != tt.subtotal || totals.Discount != tt.discount || totals.Total != tt.total || totals.AmountDue != tt.amountDue {
+				t.Fatalf("totals = subtotal %d discount %d total %d due %d, want %d %d %d %d",
+					totals.Subtotal, totals.Discount, totals.Total, totals.AmountDue, tt.subtotal, tt.discount, tt.total, tt.amountDue)
+			}
+
+			if len(totals.GiftCards) != len(tt.applied) {
+				t.Fatalf("applied %d gift cards, want %d", len(totals.GiftCards), len(tt.applied))
+			}
+			for i, application := range totals.GiftCards {
+				if application.Amount != tt.applied[i] {
+					t.Fatalf("gift card %d amount = %d, want %d", i, application.Amount, tt.applied[i])
+				}
+			}
+		})
+	}
+}
+
+// errAny stands for a validation error without a sentinel of its own.
+var errAny = errors.New("any error")
@@ -2,21 +2,50 @@ package domain
 
 import (
 	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrCupomNotFound = errors.New("cupom not found")
+	ErrCupomCoin     = errors.New("cupom currency does not match")
+	ErrCupomExpired  = errors.New("cupom expired")
+	ErrCupomUsedUp   = errors.New("cupom already used")
+)
+
+// Cupons is a discount of one website, in one currency. TagUUID is nil for
+// coupons not tied to a tag, like the ones cart recovery makes. UsageLimit
+// caps how many orders may use it; nil means no cap.
 type Cupons struct {
 	UUID        uuid.UUID
-	TagUUID     uuid.UUID
+	WebsiteUUID uuid.UUID
+	TagUUID     *uuid.UUID
+	Coin        enums.CoinType
 	Label       string
 	Description string
 	Value       string
 	ValueType   enums.CupomValueType
+	UsageLimit  *int
+	UsedCount   int
+	ExpiresAt   *time.Time
 }
 
-func NewCupom(tagUUID string, label string, description string, value string, valueType string) (*Cupons, error) {
+func NewCupom(websiteUUID string, tagUUID string, coin string, label string, description string, value string, valueType string) (*Cupons, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
 	if label == "" {
 		return nil, errors.New("Label cannot be null.")
 	}
@@ -30,17 +59,65 @@ func NewCupom(tagUUID string, label string, description string, value string, va
 		return nil, errors.New("ValueType must be 'percentage' or 'Value'.")
 	}
 
-	tagUUIDParsed, err := uuid.Parse(tagUUID)
-	if err != nil {
-		return nil, err
+	var tagPtr *uuid.UUID
+	if tagUUID != "" {
+		parsed, err := uuid.Parse(tagUUID)
+		if err != nil {
+			return nil, err
+		}
+		tagPtr = &parsed
 	}
 
 	return &Cupons{
 		UUID:        uuid.Nil,
-		TagUUID:     tagUUIDParsed,
+		WebsiteUUID: websiteUUIDParsed,
+		TagUUID:     tagPtr,
+		Coin:        coinType,
 		Label:       label,
 		Description: description,
 		Value:       value,
 		ValueType:   vtype,
 	}, nil
 }
+
+// Usable tells whether the coupon may be applied to an order of websiteUUID
+// in coin at now. A coupon of another website is reported as not found.
+func (c *Cupons) Usable(websiteUUID string, coin enums.CoinType, now time.Time) error {
+	if c.WebsiteUUID == uuid.Nil || c.WebsiteUUID.String() != websiteUUID {
+		return ErrCupomNotFound
+	}
+
+	if c.Coin != coin {
+		return ErrCupomCoin
+	}
+
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) {
+		return ErrCupomExpired
+	}
+
+	if c.UsageLimit != nil && c.UsedCount >= *c.UsageLimit {
+		return ErrCupomUsedUp
+	}
+
+	return nil
+}
+
+// Discount is what the coupon takes off subtotal, in cents. A percentage
+// coupon holds the rate, e.g. "10"; a Value coupon holds currency units,
+// e.g. "15.50". The discount never exceeds the subtotal.
+func (c *Cupons) Discount(subtotal int) (int, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(c.Value), 64)
+	if err != nil || value < 0 {
+		return 0, errors.New("Cupom value is invalid.")
+	}
+
+	var discount int
+	switch c.ValueType {
+	case enums.CupomPercentage:
+		discount = int(math.Round(float64(subtotal) * min(value, 100) / 100))
+	default:
+		discount = int(math.Round(value * 100))
+	}
+
+	return min(discount, subtotal), nil
+}
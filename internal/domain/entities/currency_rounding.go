@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+type CurrencyRounding struct {
+	UUID        uuid.UUID
+	WebsiteUUID uuid.UUID
+	Coin        enums.CoinType
+	Mode        enums.RoundingType
+	Increment   int
+	Ending      int
+	UpdatedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func NewCurrencyRounding(websiteUUID string, coin string, mode string, increment int, ending int) (*CurrencyRounding, error) {
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	roundingType := enums.RoundingType(mode)
+	if roundingType != enums.RoundNone && roundingType != enums.RoundUp && roundingType != enums.RoundDown && roundingType != enums.RoundNearest {
+		return nil, errors.New("Mode must be 'none', 'up', 'down' or 'nearest'.")
+	}
+
+	if increment <= 0 {
+		return nil, errors.New("Increment must be greater than 0.")
+	}
+
+	if ending < 0 || ending >= increment {
+		return nil, errors.New("Ending must be between 0 and Increment.")
+	}
+
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CurrencyRounding{
+		UUID:        uuid.Nil,
+		WebsiteUUID: websiteUUIDParsed,
+		Coin:        coinType,
+		Mode:        roundingType,
+		Increment:   increment,
+		Ending:      ending,
+	}, nil
+}
+
+// Apply snaps amount (in cents) to the closest value ending in Ending within
+// each Increment, e.g. Increment 100 and Ending 90 turns 12.34 into 12.90
+// when rounding up.
+func (r *CurrencyRounding) Apply(amount int) int {
+	if r == nil || r.Mode == enums.RoundNone {
+		return amount
+	}
+
+	candidate := (amount/r.Increment)*r.Increment + r.Ending
+
+	switch r.Mode {
+	case enums.RoundUp:
+		if candidate < amount {
+			candidate += r.Increment
+		}
+	case enums.RoundDown:
+		if candidate > amount {
+			candidate -= r.Increment
+		}
+	case enums.RoundNearest:
+		lower, upper := candidate, candidate
+		if candidate > amount {
+			lower = candidate - r.Increment
+		} else {
+			upper = candidate + r.Increment
+		}
+		if amount-lower < upper-amount {
+			candidate = lower
+		} else {
+			candidate = upper
+		}
+	}
+
+	if candidate < 0 {
+		return 0
+	}
+
+	return candidate
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Email is a plain text message sent to one address.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
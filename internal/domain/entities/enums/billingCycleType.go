@@ -0,0 +1,8 @@
+package enums
+
+type BillingCycleType string
+
+const (
+	MonthlyCycle BillingCycleType = "monthly"
+	AnnualCycle  BillingCycleType = "annual"
+)
@@ -0,0 +1,8 @@
+package enums
+
+type CartStatusType string
+
+const (
+	CartOpen    CartStatusType = "open"
+	CartOrdered CartStatusType = "ordered"
+)
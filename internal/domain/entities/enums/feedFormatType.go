@@ -0,0 +1,8 @@
+package enums
+
+type FeedFormatType string
+
+const (
+	FeedGoogle FeedFormatType = "google"
+	FeedMeta   FeedFormatType = "meta"
+)
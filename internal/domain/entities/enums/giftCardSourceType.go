@@ -0,0 +1,9 @@
+package enums
+
+type GiftCardSourceType string
+
+const (
+	GiftCardManual GiftCardSourceType = "manual"
+	GiftCardOrder  GiftCardSourceType = "order"
+	GiftCardRefund GiftCardSourceType = "refund"
+)
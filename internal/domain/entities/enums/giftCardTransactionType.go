@@ -0,0 +1,10 @@
+package enums
+
+type GiftCardTransactionType string
+
+const (
+	GiftCardIssue   GiftCardTransactionType = "issue"
+	GiftCardRedeem  GiftCardTransactionType = "redeem"
+	GiftCardRelease GiftCardTransactionType = "release"
+	GiftCardExpire  GiftCardTransactionType = "expire"
+)
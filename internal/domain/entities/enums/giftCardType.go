@@ -0,0 +1,8 @@
+package enums
+
+type GiftCardType string
+
+const (
+	GiftCard    GiftCardType = "gift_card"
+	StoreCredit GiftCardType = "store_credit"
+)
@@ -0,0 +1,8 @@
+package enums
+
+type ImportIssueLevelType string
+
+const (
+	ImportIssueError   ImportIssueLevelType = "error"
+	ImportIssueWarning ImportIssueLevelType = "warning"
+)
@@ -0,0 +1,8 @@
+package enums
+
+type ImportSourceType string
+
+const (
+	ImportSourceNative  ImportSourceType = "native"
+	ImportSourceShopify ImportSourceType = "shopify"
+)
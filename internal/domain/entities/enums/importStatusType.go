@@ -0,0 +1,10 @@
+package enums
+
+type ImportStatusType string
+
+const (
+	ImportPending   ImportStatusType = "pending"
+	ImportRunning   ImportStatusType = "running"
+	ImportCompleted ImportStatusType = "completed"
+	ImportFailed    ImportStatusType = "failed"
+)
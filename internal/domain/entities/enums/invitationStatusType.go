@@ -0,0 +1,10 @@
+package enums
+
+type InvitationStatusType string
+
+const (
+	InvitationPending  InvitationStatusType = "pending"
+	InvitationAccepted InvitationStatusType = "accepted"
+	InvitationDeclined InvitationStatusType = "declined"
+	InvitationRevoked  InvitationStatusType = "revoked"
+)
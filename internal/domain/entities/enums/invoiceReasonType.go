@@ -0,0 +1,9 @@
+package enums
+
+type InvoiceReasonType string
+
+const (
+	InvoiceSignup    InvoiceReasonType = "signup"
+	InvoiceRenewal   InvoiceReasonType = "renewal"
+	InvoiceProration InvoiceReasonType = "proration"
+)
@@ -0,0 +1,9 @@
+package enums
+
+type InvoiceStatusType string
+
+const (
+	InvoiceOpen InvoiceStatusType = "open"
+	InvoicePaid InvoiceStatusType = "paid"
+	InvoiceVoid InvoiceStatusType = "void"
+)
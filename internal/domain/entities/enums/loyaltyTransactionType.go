@@ -0,0 +1,13 @@
+package enums
+
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyEarn            LoyaltyTransactionType = "earn"
+	LoyaltyFirstOrderBonus LoyaltyTransactionType = "first_order_bonus"
+	LoyaltyReviewBonus     LoyaltyTransactionType = "review_bonus"
+	LoyaltyRedeem          LoyaltyTransactionType = "redeem"
+	LoyaltyRelease         LoyaltyTransactionType = "release"
+	LoyaltyReverse         LoyaltyTransactionType = "reverse"
+	LoyaltyExpire          LoyaltyTransactionType = "expire"
+)
@@ -0,0 +1,11 @@
+package enums
+
+type MediaStatusType string
+
+const (
+	MediaPending    MediaStatusType = "pending"
+	MediaProcessing MediaStatusType = "processing"
+	MediaReady      MediaStatusType = "ready"
+	MediaFailed     MediaStatusType = "failed"
+	MediaSkipped    MediaStatusType = "skipped"
+)
@@ -0,0 +1,9 @@
+package enums
+
+type MemberRoleType string
+
+const (
+	MemberOwner  MemberRoleType = "owner"
+	MemberAdmin  MemberRoleType = "admin"
+	MemberMember MemberRoleType = "member"
+)
@@ -0,0 +1,10 @@
+package enums
+
+type OrderEventStatusType string
+
+const (
+	OrderEventPending OrderEventStatusType = "pending"
+	OrderEventRunning OrderEventStatusType = "running"
+	OrderEventDone    OrderEventStatusType = "done"
+	OrderEventFailed  OrderEventStatusType = "failed"
+)
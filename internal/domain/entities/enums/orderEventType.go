@@ -0,0 +1,8 @@
+package enums
+
+type OrderEventType string
+
+const (
+	OrderEventPaid   OrderEventType = "paid"
+	OrderEventRefund OrderEventType = "refund"
+)
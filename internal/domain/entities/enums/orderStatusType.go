@@ -0,0 +1,10 @@
+package enums
+
+type OrderStatusType string
+
+const (
+	OrderPending  OrderStatusType = "pending"
+	OrderPaid     OrderStatusType = "paid"
+	OrderFailed   OrderStatusType = "failed"
+	OrderRefunded OrderStatusType = "refunded"
+)
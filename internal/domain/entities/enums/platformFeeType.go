@@ -0,0 +1,8 @@
+package enums
+
+type PlatformFeeType string
+
+const (
+	PlatformFeeCharge PlatformFeeType = "fee"
+	PlatformFeeRefund PlatformFeeType = "refund"
+)
@@ -0,0 +1,9 @@
+package enums
+
+type ProductType string
+
+const (
+	PhysicalProduct ProductType = "physical"
+	DigitalProduct  ProductType = "digital"
+	GiftCardProduct ProductType = "gift_card"
+)
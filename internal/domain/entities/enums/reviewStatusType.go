@@ -0,0 +1,9 @@
+package enums
+
+type ReviewStatusType string
+
+const (
+	ReviewPending  ReviewStatusType = "pending"
+	ReviewApproved ReviewStatusType = "approved"
+	ReviewRejected ReviewStatusType = "rejected"
+)
@@ -0,0 +1,10 @@
+package enums
+
+type RoundingType string
+
+const (
+	RoundNone    RoundingType = "none"
+	RoundUp      RoundingType = "up"
+	RoundDown    RoundingType = "down"
+	RoundNearest RoundingType = "nearest"
+)
@@ -0,0 +1,12 @@
+package enums
+
+type SubscriptionStatusType string
+
+const (
+	SubscriptionTrialing   SubscriptionStatusType = "trialing"
+	SubscriptionIncomplete SubscriptionStatusType = "incomplete"
+	SubscriptionActive     SubscriptionStatusType = "active"
+	SubscriptionPastDue    SubscriptionStatusType = "past_due"
+	SubscriptionSuspended  SubscriptionStatusType = "suspended"
+	SubscriptionCanceled   SubscriptionStatusType = "canceled"
+)
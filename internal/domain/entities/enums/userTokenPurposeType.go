@@ -0,0 +1,10 @@
+package enums
+
+type UserTokenPurposeType string
+
+const (
+	EmailVerificationToken  UserTokenPurposeType = "email_verification"
+	PasswordResetToken      UserTokenPurposeType = "password_reset"
+	AccountUnlockToken      UserTokenPurposeType = "account_unlock"
+	TwoFactorChallengeToken UserTokenPurposeType = "two_factor_challenge"
+)
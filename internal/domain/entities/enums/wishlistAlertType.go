@@ -0,0 +1,8 @@
+package enums
+
+type WishlistAlertType string
+
+const (
+	BackInStockAlert WishlistAlertType = "back_in_stock"
+	OnSaleAlert      WishlistAlertType = "on_sale"
+)
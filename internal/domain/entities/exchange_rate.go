@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+type ExchangeRate struct {
+	UUID      uuid.UUID
+	BaseCoin  enums.CoinType
+	QuoteCoin enums.CoinType
+	Rate      *big.Rat
+	RateDate  time.Time
+	Source    string
+	UpdatedAt *time.Time
+	CreatedAt time.Time
+}
+
+func NewExchangeRate(baseCoin string, quoteCoin string, rate string, rateDate time.Time, source string) (*ExchangeRate, error) {
+	base := enums.CoinType(baseCoin)
+	if base != enums.BRCoin && base != enums.EUACoin && base != enums.EURCoin {
+		return nil, errors.New("BaseCoin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	quote := enums.CoinType(quoteCoin)
+	if quote != enums.BRCoin && quote != enums.EUACoin && quote != enums.EURCoin {
+		return nil, errors.New("QuoteCoin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	if base == quote {
+		return nil, errors.New("BaseCoin and QuoteCoin must be different.")
+	}
+
+	parsed, ok := new(big.Rat).SetString(rate)
+	if !ok || parsed.Sign() <= 0 {
+		return nil, errors.New("Rate must be a positive decimal.")
+	}
+
+	if source == "" {
+		return nil, errors.New("Source cannot be null.")
+	}
+
+	return &ExchangeRate{
+		UUID:      uuid.Nil,
+		BaseCoin:  base,
+		QuoteCoin: quote,
+		Rate:      parsed,
+		RateDate:  time.Date(rateDate.Year(), rateDate.Month(), rateDate.Day(), 0, 0, 0, 0, time.UTC),
+		Source:    source,
+	}, nil
+}
+
+// ConvertAmount converts an amount in cents using rate, rounding half away
+// from zero to the nearest cent.
+func ConvertAmount(amount int, rate *big.Rat) int {
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), rate)
+
+	num := converted.Num()
+	den := converted.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return int(quotient.Int64())
+}
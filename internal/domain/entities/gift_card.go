@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+// Gift cards bought in an order are valid for this long unless the
+// merchant says otherwise. Store credit does not expire by default.
+const DefaultGiftCardValidityDays = 365
+
+// Codes use Crockford's base32 alphabet, which leaves out letters easily
+// mistaken for digits, in four groups of four: 80 random bits.
+const (
+	giftCardCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	giftCardCodeLength   = 16
+)
+
+var (
+	ErrGiftCardCodeExists = errors.New("gift card code already exists")
+	ErrGiftCardExpired    = errors.New("gift card expired")
+	ErrGiftCardEmpty      = errors.New("gift card has no balance")
+	ErrGiftCardCoin       = errors.New("gift card currency does not match")
+	ErrGiftCardOwner      = errors.New("store credit belongs to another customer")
+	ErrGiftCardRedeemed   = errors.New("gift card already used on this order")
+	ErrGiftCardChanged    = errors.New("gift card balance changed")
+)
+
+// GiftCard is a prepaid balance redeemable at checkout. Only the code hash
+// is stored; the code itself is shown once, when the card is issued. Store
+// credit is a card bound to the customer it was refunded to.
+type GiftCard struct {
+	UUID           uuid.UUID
+	WebsiteUUID    uuid.UUID
+	Type           enums.GiftCardType
+	Source         enums.GiftCardSourceType
+	CodeHash       string
+	CodeLast4      string
+	Coin           enums.CoinType
+	InitialAmount  int
+	Balance        int
+	UserUUID       *uuid.UUID
+	ProductUUID    *uuid.UUID
+	OrderUUID      *uuid.UUID
+	RecipientEmail string
+	Note           string
+	ExpiresAt      *time.Time
+	UpdatedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+// GiftCardTransaction is one entry of a card's balance ledger. Amount is
+// negative when money leaves the card.
+type GiftCardTransaction struct {
+	UUID         uuid.UUID
+	GiftCardUUID uuid.UUID
+	Type         enums.GiftCardTransactionType
+	Amount       int
+	BalanceAfter int
+	OrderUUID    *uuid.UUID
+	CreatedAt    time.Time
+}
+
+func NewGiftCard(websiteUUID string, cardType enums.GiftCardType, source enums.GiftCardSourceType, code string, coin string, amount int, expiresAt *time.Time) (*GiftCard, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cardType != enums.GiftCard && cardType != enums.StoreCredit {
+		return nil, errors.New("Type must be 'gift_card' or 'store_credit'.")
+	}
+
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("Amount must be greater than zero.")
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("ExpiresAt must be in the future.")
+	}
+
+	normalized := NormalizeGiftCardCode(code)
+	if len(normalized) != giftCardCodeLength {
+		return nil, errors.New("Code is invalid.")
+	}
+
+	return &GiftCard{
+		UUID:          uuid.Nil,
+		WebsiteUUID:   websiteUUIDParsed,
+		Type:          cardType,
+		Source:        source,
+		CodeHash:      HashGiftCardCode(normalized),
+		CodeLast4:     normalized[len(normalized)-4:],
+		Coin:          coinType,
+		InitialAmount: amount,
+		Balance:       amount,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// NewGiftCardCode returns a random code formatted as XXXX-XXXX-XXXX-XXXX.
+func NewGiftCardCode() (string, error) {
+	b := make([]byte, giftCardCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, c := range b {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(giftCardCodeAlphabet[int(c)%len(giftCardCodeAlphabet)])
+	}
+
+	return code.String(), nil
+}
+
+// NormalizeGiftCardCode undoes what shoppers do to codes when typing them:
+// lower case, dashes, spaces and the letters O, I and L for digits.
+func NormalizeGiftCardCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "", "O", "0", "I", "1", "L", "1").Replace(code)
+	return code
+}
+
+func HashGiftCardCode(code string) string {
+	sum := sha256.Sum256([]byte(NormalizeGiftCardCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (g *GiftCard) Expired(now time.Time) bool {
+	return g.ExpiresAt != nil && !now.Before(*g.ExpiresAt)
+}
+
+// Usable reports why the card cannot pay for an order in coin placed by
+// userUUID, if it can't.
+func (g *GiftCard) Usable(coin enums.CoinType, userUUID string, now time.Time) error {
+	if g.Expired(now) {
+		return ErrGiftCardExpired
+	}
+
+	if g.Balance <= 0 {
+		return ErrGiftCardEmpty
+	}
+
+	if g.Coin != coin {
+		return ErrGiftCardCoin
+	}
+
+	if g.UserUUID != nil && g.UserUUID.String() != userUUID {
+		return ErrGiftCardOwner
+	}
+
+	return nil
+}
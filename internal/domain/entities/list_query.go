@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// Cursor marks the last row of a page. UUID breaks ties between rows sharing
+// the same sort value, and Sort ties the cursor to the ordering it came from.
+type Cursor struct {
+	Sort  string    `json:"s,omitempty"`
+	Value string    `json:"v,omitempty"`
+	UUID  uuid.UUID `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.UUID == uuid.Nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+
+	return &cursor, nil
+}
+
+// ListQuery describes one page of a list endpoint. Sort and Filters hold the
+// public parameter names; each repository decides which ones it accepts.
+type ListQuery struct {
+	After   *Cursor
+	Limit   int
+	Sort    string
+	Desc    bool
+	Filters map[string]string
+}
+
+// NewListQuery parses the raw parameters. A sort prefixed with "-" orders
+// descending, and an empty sort falls back to the primary key.
+func NewListQuery(after string, limit int, sort string, filters map[string]string) (*ListQuery, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidListQuery)
+	}
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	query := &ListQuery{
+		Limit:   limit,
+		Filters: filters,
+	}
+
+	if strings.HasPrefix(sort, "-") {
+		query.Desc = true
+		sort = sort[1:]
+	}
+	query.Sort = sort
+
+	if after != "" {
+		cursor, err := DecodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != query.SortKey() {
+			return nil, fmt.Errorf("%w: cursor does not match sort", ErrInvalidListQuery)
+		}
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+// SortKey identifies the ordering, direction included.
+func (q *ListQuery) SortKey() string {
+	if q.Desc {
+		return "-" + q.Sort
+	}
+	return q.Sort
+}
+
+// Page is one slice of a list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Limit      int
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := uuid.MustParse("0190f3a8-7c2e-7d41-9b6a-2f1e4c3d5a6b")
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "key only", cursor: Cursor{UUID: id}},
+		{name: "sort and value", cursor: Cursor{Sort: "-created_at", Value: "2026-03-01T12:00:00.123456Z", UUID: id}},
+		{name: "value needing escapes", cursor: Cursor{Sort: "name", Value: `Caneca "azul" / 50%`, UUID: id}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.cursor.Encode()
+			if _, err := base64.RawURLEncoding.DecodeString(encoded); err != nil {
+				t.Fatalf("Encode() = %q is not raw url base64: %v", encoded, err)
+			}
+
+			decoded, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *decoded != tt.cursor {
+				t.Fatalf("DecodeCursor() = %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not base64", input: "***"},
+		{name: "not json", input: encode("created_at")},
+		{name: "missing uuid", input: encode(`{"s":"name","v":"a"}`)},
+		{name: "nil uuid", input: encode(`{"id":"00000000-0000-0000-0000-000000000000"}`)},
+		{name: "invalid uuid", input: encode(`{"id":"42"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.input); !errors.Is(err, ErrInvalidListQuery) {
+				t.Fatalf("DecodeCursor(%q) = %v, want ErrInvalidListQuery", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestNewListQuery(t *testing.T) {
+	id := uuid.MustParse("0190f3a8-7c2e-7d41-9b6a-2f1e4c3d5a6b")
+
+	tests := []struct {
+		name      string
+		after     string
+		limit     int
+		sort      string
+		wantLimit int
+		wantSort  string
+		wantDesc  bool
+		wantAfter *Cursor
+		err       bool
+	}{
+		{name: "defaults", wantLimit: DefaultListLimit},
+		{name: "limit kept", limit: 10, wantLimit: 10},
+		{name: "limit capped", limit: MaxListLimit + 1, wantLimit: MaxListLimit},
+		{name: "negative limit", limit: -1, err: true},
+		{name: "descending sort", sort: "-created_at", wantLimit: DefaultListLimit, wantSort: "created_at", wantDesc: true},
+		{
+			name:      "cursor of the same sort",
+			after:     Cursor{Sort: "-created_at", Value: "v", UUID: id}.Encode(),
+			sort:      "-created_at",
+			wantLimit: DefaultListLimit,
+			wantSort:  "created_at",
+			wantDesc:  true,
+			wantAfter: &Cursor{Sort: "-created_at", Value: "v", UUID: id},
+		},
+		{name: "cursor of another direction", after: Cursor{Sort: "created_at", UUID: id}.Encode(), sort: "-created_at", err: true},
+		{name: "cursor of another sort", after: Cursor{Sort: "name", UUID: id}.Encode(), sort: "created_at", err: true},
+		{name: "malformed cursor", after: "***", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewListQuery(tt.after, tt.limit, tt.sort, nil)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidListQuery) {
+					t.Fatalf("NewListQuery() = %v, want ErrInvalidListQuery", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if query.Limit != tt.wantLimit || query.Sort != tt.wantSort || query.Desc != tt.wantDesc {
+				t.Fatalf("NewListQuery() = limit %d sort %q desc %v, want %d %q %v", query.Limit, query.Sort, query.Desc, tt.wantLimit, tt.wantSort, tt.wantDesc)
+			}
+			if (query.After == nil) != (tt.wantAfter == nil) || (query.After != nil && *query.After != *tt.wantAfter) {
+				t.Fatalf("After = %+v, want %+v", query.After, tt.wantAfter)
+			}
+		})
+	}
+}
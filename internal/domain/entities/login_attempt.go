@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// An account may fail LoginFreeAttempts sign ins in a row before each
+	// new attempt has to wait LoginBackoff; at LoginLockAttempts it is
+	// locked for LoginLockDuration, or until unlocked from the email.
+	LoginFreeAttempts = 5
+	LoginLockAttempts = 10
+	LoginLockDuration = 30 * time.Minute
+
+	// A client address may fail LoginIPFreeAttempts sign ins within
+	// LoginIPWindow, over any emails, before it backs off the same way.
+	LoginIPFreeAttempts = 20
+	LoginIPWindow       = 15 * time.Minute
+
+	LoginBackoffBase = 2 * time.Second
+	LoginBackoffMax  = 15 * time.Minute
+
+	// AccountUnlockTTL is how long the unlock link mailed on lockout works.
+	AccountUnlockTTL = time.Hour
+)
+
+var (
+	ErrAccountLocked        = errors.New("account locked")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+)
+
+// LoginThrottleError is returned by a sign in refused before checking the
+// password. RetryAfter is how long until it is worth trying again.
+type LoginThrottleError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottleError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// LoginBackoff is how long to wait after the last of failures failed sign
+// ins: nothing for the first free ones, then doubling up to
+// LoginBackoffMax.
+func LoginBackoff(failures int, free int) time.Duration {
+	if failures < free {
+		return 0
+	}
+
+	shift := min(failures-free, 20)
+	return min(LoginBackoffBase<<shift, LoginBackoffMax)
+}
+
+// LoginWait tells whether the user may try to sign in at now, and if not,
+// the error to answer with. Once a lock runs out the failures before it no
+// longer count.
+func (u *User) LoginWait(now time.Time) error {
+	if u.LockedUntil != nil {
+		if now.Before(*u.LockedUntil) {
+			return &LoginThrottleError{Err: ErrAccountLocked, RetryAfter: u.LockedUntil.Sub(now)}
+		}
+		return nil
+	}
+
+	if u.LastFailedLoginAt == nil {
+		return nil
+	}
+
+	if next := u.LastFailedLoginAt.Add(LoginBackoff(u.FailedLogins, LoginFreeAttempts)); now.Before(next) {
+		return &LoginThrottleError{Err: ErrTooManyLoginAttempts, RetryAfter: next.Sub(now)}
+	}
+
+	return nil
+}
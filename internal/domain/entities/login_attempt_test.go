@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures", failures: 0, want: 0},
+		{name: "last free attempt", failures: LoginFreeAttempts - 1, want: 0},
+		{name: "first throttled", failures: LoginFreeAttempts, want: LoginBackoffBase},
+		{name: "doubles", failures: LoginFreeAttempts + 1, want: 2 * LoginBackoffBase},
+		{name: "doubles again", failures: LoginFreeAttempts + 3, want: 8 * LoginBackoffBase},
+		{name: "capped", failures: LoginFreeAttempts + 12, want: LoginBackoffMax},
+		{name: "shift does not overflow", failures: 1000, want: LoginBackoffMax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoginBackoff(tt.failures, LoginFreeAttempts); got != tt.want {
+				t.Fatalf("LoginBackoff(%d) = %s, want %s", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserLoginWait(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		user       User
+		err        error
+		retryAfter time.Duration
+	}{
+		{
+			name: "never failed",
+			user: User{},
+		},
+		{
+			name: "within free attempts",
+			user: User{FailedLogins: LoginFreeAttempts - 1, LastFailedLoginAt: at(-time.Second)},
+		},
+		{
+			name:       "backing off",
+			user:       User{FailedLogins: LoginFreeAttempts + 1, LastFailedLoginAt: at(-time.Second)},
+			err:        ErrTooManyLoginAttempts,
+			retryAfter: 2*LoginBackoffBase - time.Second,
+		},
+		{
+			name: "backoff over",
+			user: User{FailedLogins: LoginFreeAttempts + 1, LastFailedLoginAt: at(-time.Minute)},
+		},
+		{
+			name:       "locked",
+			user:       User{FailedLogins: LoginLockAttempts, LastFailedLoginAt: at(-time.Minute), LockedUntil: at(10 * time.Minute)},
+			err:        ErrAccountLocked,
+			retryAfter: 10 * time.Minute,
+		},
+		{
+			name: "lock expired",
+			user: User{FailedLogins: LoginLockAttempts + 8, LastFailedLoginAt: at(-LoginLockDuration), LockedUntil: at(-time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.user.LoginWait(now)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("LoginWait() = %v, want nil", err)
+				}
+				return
+			}
+
+			var throttle *LoginThrottleError
+			if !errors.As(err, &throttle) || !errors.Is(err, tt.err) {
+				t.Fatalf("LoginWait() = %v, want %v", err, tt.err)
+			}
+			if throttle.RetryAfter != tt.retryAfter {
+				t.Fatalf("RetryAfter = %s, want %s", throttle.RetryAfter, tt.retryAfter)
+			}
+		})
+	}
+}
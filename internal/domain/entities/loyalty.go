@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLoyaltyInactive     = errors.New("loyalty program is not active")
+	ErrLoyaltyCoin         = errors.New("loyalty program has no rate for this currency")
+	ErrLoyaltyInsufficient = errors.New("not enough loyalty points")
+	ErrLoyaltyMinimum      = errors.New("below the minimum points to redeem")
+	ErrLoyaltyApplied      = errors.New("loyalty points already applied")
+)
+
+// LoyaltyProgram holds a website's rules. Points are earned per whole
+// currency unit paid and are worth PointValue cents each at checkout, both
+// set per currency; ExpiryDays of zero keeps points forever.
+type LoyaltyProgram struct {
+	WebsiteUUID     uuid.UUID
+	Active          bool
+	FirstOrderBonus int
+	ReviewBonus     int
+	ExpiryDays      int
+	MinRedeemPoints int
+	Rates           []*LoyaltyRate
+	UpdatedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+type LoyaltyRate struct {
+	Coin              enums.CoinType
+	EarnPointsPerUnit int
+	PointValue        int
+}
+
+// LoyaltyTransaction is one ledger entry. Points is negative when points
+// leave the balance; Remaining is what is left to spend of an entry that
+// added points.
+type LoyaltyTransaction struct {
+	UUID        uuid.UUID
+	WebsiteUUID uuid.UUID
+	UserUUID    uuid.UUID
+	Type        enums.LoyaltyTransactionType
+	Points      int
+	Remaining   int
+	Coin        *enums.CoinType
+	Amount      *int
+	OrderUUID   *uuid.UUID
+	ReviewUUID  *uuid.UUID
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// LoyaltyBalance is what a customer can spend, with the points that expire
+// first.
+type LoyaltyBalance struct {
+	WebsiteUUID    uuid.UUID
+	UserUUID       uuid.UUID
+	Points         int
+	ExpiringPoints int
+	ExpiringAt     *time.Time
+}
+
+func NewLoyaltyProgram(websiteUUID string, active bool, firstOrderBonus int, reviewBonus int, expiryDays int, minRedeemPoints int, rates []*LoyaltyRate) (*LoyaltyProgram, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if firstOrderBonus < 0 || reviewBonus < 0 {
+		return nil, errors.New("Bonuses cannot be negative.")
+	}
+
+	if expiryDays < 0 {
+		return nil, errors.New("ExpiryDays cannot be negative.")
+	}
+
+	if minRedeemPoints < 0 {
+		return nil, errors.New("MinRedeemPoints cannot be negative.")
+	}
+
+	seen := make(map[enums.CoinType]bool, len(rates))
+	for _, rate := range rates {
+		if rate.Coin != enums.BRCoin && rate.Coin != enums.EUACoin && rate.Coin != enums.EURCoin {
+			return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+		}
+
+		if seen[rate.Coin] {
+			return nil, errors.New("Each currency can only have one rate.")
+		}
+		seen[rate.Coin] = true
+
+		if rate.EarnPointsPerUnit < 0 {
+			return nil, errors.New("EarnPointsPerUnit cannot be negative.")
+		}
+
+		if rate.PointValue <= 0 {
+			return nil, errors.New("PointValue must be greater than zero.")
+		}
+	}
+
+	return &LoyaltyProgram{
+		WebsiteUUID:     websiteUUIDParsed,
+		Active:          active,
+		FirstOrderBonus: firstOrderBonus,
+		ReviewBonus:     reviewBonus,
+		ExpiryDays:      expiryDays,
+		MinRedeemPoints: minRedeemPoints,
+		Rates:           rates,
+	}, nil
+}
+
+func (p *LoyaltyProgram) Rate(coin enums.CoinType) (*LoyaltyRate, error) {
+	for _, rate := range p.Rates {
+		if rate.Coin == coin {
+			return rate, nil
+		}
+	}
+
+	return nil, ErrLoyaltyCoin
+}
+
+// PointsFor is what paying amount cents earns. Fractions of a currency unit
+// earn nothing.
+func (p *LoyaltyProgram) PointsFor(coin enums.CoinType, amount int) (int, error) {
+	rate, err := p.Rate(coin)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(amount, 0) / 100 * rate.EarnPointsPerUnit, nil
+}
+
+// ExpiresAt is when points earned at now expire, or nil if they don't.
+func (p *LoyaltyProgram) ExpiresAt(now time.Time) *time.Time {
+	if p.ExpiryDays == 0 {
+		return nil
+	}
+
+	expiresAt := now.AddDate(0, 0, p.ExpiryDays)
+	return &expiresAt
+}
+
+// Redemption works out how many of the offered points a checkout of
+// subtotal cents can use and the discount they give. Points are only spent
+// in whole point values, so the discount never exceeds the subtotal.
+func (p *LoyaltyProgram) Redemption(coin enums.CoinType, points int, subtotal int) (int, int, error) {
+	rate, err := p.Rate(coin)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if points <= 0 {
+		return 0, 0, errors.New("Points must be greater than zero.")
+	}
+
+	if points < p.MinRedeemPoints {
+		return 0, 0, ErrLoyaltyMinimum
+	}
+
+	used := min(points, max(subtotal, 0)/rate.PointValue)
+	return used, used * rate.PointValue, nil
+}
+
+// LoyaltyReversal is how many earned points are left to take back from an
+// order that paid orderAmount once refundedTo of it was refunded in total,
+// given what earlier reversals already took. Working from the total makes
+// running it again for the same refund take nothing more.
+func LoyaltyReversal(earned int, orderAmount int, reversed int, refundedTo int) int {
+	if earned <= 0 || orderAmount <= 0 || refundedTo <= 0 {
+		return 0
+	}
+
+	points := earned
+	if refundedTo < orderAmount {
+		points = int(int64(earned) * int64(refundedTo) / int64(orderAmount))
+	}
+
+	return max(min(points, earned)-reversed, 0)
+}
+
+// LoyaltyQuote is what redeeming points at a checkout would do. Points is
+// what gets spent, which may be less than offered when the subtotal is
+// small.
+type LoyaltyQuote struct {
+	Coin      enums.CoinType
+	Points    int
+	Discount  int
+	Available int
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestLoyaltyReversal(t *testing.T) {
+	tests := []struct {
+		name       string
+		reversed   int
+		refundedTo int
+		want       int
+	}{
+		{name: "first partial refund", reversed: 0, refundedTo: 2500, want: 25},
+		{name: "same refund again", reversed: 25, refundedTo: 2500, want: 0},
+		{name: "second partial refund", reversed: 25, refundedTo: 5000, want: 25},
+		{name: "full refund", reversed: 50, refundedTo: 10000, want: 50},
+		{name: "nothing refunded", reversed: 0, refundedTo: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoyaltyReversal(100, 10000, tt.reversed, tt.refundedTo); got != tt.want {
+				t.Fatalf("LoyaltyReversal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
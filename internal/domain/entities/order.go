@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrderNotPaid      = errors.New("order is not paid")
+	ErrOrderRefundAmount = errors.New("refund exceeds what is left to refund")
+)
+
+// Order is a checked out cart. Amounts are cents of Coin: Total is what
+// the order costs after the coupon and loyalty discounts and AmountPaid the
+// part of it charged to the payment method, gift cards covering the rest.
+// RefundedAmount counts every refund, ProviderRefunded only the ones sent
+// back to the payment method. Recovered orders came from a cart that was
+// sent a recovery reminder.
+type Order struct {
+	UUID             uuid.UUID
+	WebsiteUUID      uuid.UUID
+	UserUUID         uuid.UUID
+	CartUUID         *uuid.UUID
+	Email            string
+	Status           enums.OrderStatusType
+	Coin             enums.CoinType
+	Subtotal         int
+	Discount         int
+	LoyaltyPoints    int
+	LoyaltyDiscount  int
+	GiftCardAmount   int
+	Total            int
+	AmountPaid       int
+	RefundedAmount   int
+	ProviderRefunded int
+	CupomUUID        *uuid.UUID
+	PaymentMethod    string
+	PaymentReference string
+	FailureReason    string
+	Recovered        bool
+	PaidAt           *time.Time
+	UpdatedAt        *time.Time
+	CreatedAt        time.Time
+	Items            []*OrderItem
+}
+
+// OrderItem keeps the name, type and price the product had when ordered.
+type OrderItem struct {
+	UUID        uuid.UUID
+	OrderUUID   uuid.UUID
+	ProductUUID uuid.UUID
+	VariantUUID *uuid.UUID
+	ProductType enums.ProductType
+	Name        string
+	Quantity    int
+	UnitAmount  int
+	Amount      int
+}
+
+// NewOrder makes a pending order of the quoted cart.
+func NewOrder(quote *CartQuote, paymentMethod string) *Order {
+	cart := quote.Cart
+
+	order := &Order{
+		UUID:          uuid.Nil,
+		WebsiteUUID:   cart.WebsiteUUID,
+		UserUUID:      cart.UserUUID,
+		CartUUID:      &cart.UUID,
+		Email:         cart.Email,
+		Status:        enums.OrderPending,
+		Coin:          quote.Coin,
+		Subtotal:      quote.Subtotal,
+		Total:         quote.Subtotal,
+		AmountPaid:    quote.Subtotal,
+		PaymentMethod: paymentMethod,
+		Recovered:     cart.Reminded(),
+	}
+
+	for _, line := range quote.Lines {
+		order.Items = append(order.Items, &OrderItem{
+			ProductUUID: line.Item.ProductUUID,
+			VariantUUID: line.Item.VariantUUID,
+			ProductType: line.Product.Type,
+			Name:        line.Product.Name,
+			Quantity:    line.Item.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+		})
+	}
+
+	return order
+}
+
+// Discountable is what the items of products cost in the order, or of
+// every product when products is nil. Gift cards are issued at what they
+// cost, so their items are never discounted and never count.
+func (o *Order) Discountable(products map[uuid.UUID]bool) int {
+	amount := 0
+	for _, item := range o.Items {
+		if item.ProductType == enums.GiftCardProduct {
+			continue
+		}
+		if products == nil || products[item.ProductUUID] {
+			amount += item.Amount
+		}
+	}
+	return amount
+}
+
+// ApplyDiscounts takes the coupon and loyalty discounts off the subtotal.
+func (o *Order) ApplyDiscounts(discount int, loyaltyPoints int, loyaltyDiscount int) {
+	o.Discount = discount
+	o.LoyaltyPoints = loyaltyPoints
+	o.LoyaltyDiscount = loyaltyDiscount
+	o.Total = o.Subtotal - discount - loyaltyDiscount
+	o.AmountPaid = o.Total - o.GiftCardAmount
+}
+
+// ApplyGiftCards records the part of the total paid with gift cards.
+func (o *Order) ApplyGiftCards(amount int) {
+	o.GiftCardAmount = amount
+	o.AmountPaid = o.Total - amount
+}
+
+func (o *Order) Paid() bool {
+	return o.Status == enums.OrderPaid || o.Status == enums.OrderRefunded
+}
+
+// Refundable is what is left to refund, and how much of it may still go
+// back to the payment method.
+func (o *Order) Refundable() (total int, provider int) {
+	return o.Total - o.RefundedAmount, o.AmountPaid - o.ProviderRefunded
+}
+
+// CheckRefund tells whether amount may be refunded, back to the payment
+// method unless toStoreCredit.
+func (o *Order) CheckRefund(amount int, toStoreCredit bool) error {
+	if !o.Paid() {
+		return ErrOrderNotPaid
+	}
+
+	if amount <= 0 {
+		return errors.New("Amount must be greater than zero.")
+	}
+
+	total, provider := o.Refundable()
+	if amount > total || (!toStoreCredit && amount > provider) {
+		return ErrOrderRefundAmount
+	}
+
+	return nil
+}
+
+// ProductUUIDs lists every product ordered once, for the hooks that act on
+// paid orders.
+func (o *Order) ProductUUIDs() []string {
+	seen := make(map[uuid.UUID]bool, len(o.Items))
+	uuids := make([]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		if !seen[item.ProductUUID] {
+			seen[item.ProductUUID] = true
+			uuids = append(uuids, item.ProductUUID.String())
+		}
+	}
+	return uuids
+}
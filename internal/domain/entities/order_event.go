@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+// How many times an order hook is tried before it is left failed for
+// someone to look at.
+const MaxOrderEventAttempts = 10
+
+// OrderEvent is a hook owed to an order that was paid or refunded. It is
+// written with the change it follows, so a hook that fails, or never ran
+// because the process stopped, is tried again. Amount is what a refund
+// event gave back and RefundedTo what the order had refunded in total
+// after it.
+type OrderEvent struct {
+	UUID       uuid.UUID
+	OrderUUID  uuid.UUID
+	Hook       string
+	Type       enums.OrderEventType
+	Amount     int
+	RefundedTo int
+	Status     enums.OrderEventStatusType
+	Attempts   int
+	LastError  string
+	RunAt      time.Time
+	UpdatedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Retry schedules the event again after a failed attempt, waiting twice as
+// long after every attempt up to a day, or leaves it failed once it was
+// tried MaxOrderEventAttempts times.
+func (e *OrderEvent) Retry(err error, now time.Time) {
+	e.LastError = err.Error()
+	if e.Attempts >= MaxOrderEventAttempts {
+		e.Status = enums.OrderEventFailed
+		return
+	}
+
+	e.Status = enums.OrderEventPending
+	e.RunAt = now.Add(min(time.Minute<<max(e.Attempts-1, 0), 24*time.Hour))
+}
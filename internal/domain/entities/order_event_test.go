@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+func TestOrderEventRetry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		attempts   int
+		wantStatus enums.OrderEventStatusType
+		wantRunAt  time.Time
+	}{
+		{name: "first attempt", attempts: 1, wantStatus: enums.OrderEventPending, wantRunAt: now.Add(time.Minute)},
+		{name: "third attempt", attempts: 3, wantStatus: enums.OrderEventPending, wantRunAt: now.Add(4 * time.Minute)},
+		{name: "ninth attempt", attempts: 9, wantStatus: enums.OrderEventPending, wantRunAt: now.Add(256 * time.Minute)},
+		{name: "last attempt", attempts: MaxOrderEventAttempts, wantStatus: enums.OrderEventFailed, wantRunAt: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &OrderEvent{Status: enums.OrderEventRunning, Attempts: tt.attempts, RunAt: now}
+			event.Retry(errors.New("boom"), now)
+
+			if event.Status != tt.wantStatus {
+				t.Fatalf("Status = %s, want %s", event.Status, tt.wantStatus)
+			}
+			if !event.RunAt.Equal(tt.wantRunAt) {
+				t.Fatalf("RunAt = %s, want %s", event.RunAt, tt.wantRunAt)
+			}
+			if event.LastError != "boom" {
+				t.Fatalf("LastError = %q, want %q", event.LastError, "boom")
+			}
+		})
+	}
+}
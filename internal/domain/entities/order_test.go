@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func TestOrderDiscountable(t *testing.T) {
+	tagged := uuid.New()
+	other := uuid.New()
+	card := uuid.New()
+	order := &Order{Items: []*OrderItem{
+		{ProductUUID: tagged, Quantity: 2, UnitAmount: 1500, Amount: 3000},
+		{ProductUUID: other, Quantity: 1, UnitAmount: 7000, Amount: 7000},
+		{ProductUUID: card, ProductType: enums.GiftCardProduct, Quantity: 1, UnitAmount: 5000, Amount: 5000},
+	}}
+
+	tests := []struct {
+		name     string
+		products map[uuid.UUID]bool
+		want     int
+	}{
+		{name: "every product", want: 10000},
+		{name: "no product", products: map[uuid.UUID]bool{}, want: 0},
+		{name: "tagged product", products: map[uuid.UUID]bool{tagged: true}, want: 3000},
+		{name: "both products", products: map[uuid.UUID]bool{tagged: true, other: true}, want: 10000},
+		{name: "gift card", products: map[uuid.UUID]bool{card: true}, want: 0},
+		{name: "product not ordered", products: map[uuid.UUID]bool{uuid.New(): true}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := order.Discountable(tt.products); got != tt.want {
+				t.Fatalf("Discountable() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/go-sdk/validate"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+// InvitationTTL is how long an invitation link can be accepted.
+const InvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrMemberExists       = errors.New("user is already a member")
+	ErrInvitationPending  = errors.New("invitation already pending for this email")
+	ErrInvitationExpired  = errors.New("invitation expired")
+	ErrInvitationAnswered = errors.New("invitation already answered")
+	ErrOwnerMember        = errors.New("the owner cannot be removed or change role")
+)
+
+// OrganizationMember links a user to an organization. Members with the
+// member role may carry a custom role from the rbac table of the
+// organization's website; Email and Name come from the user.
+type OrganizationMember struct {
+	UUID             uuid.UUID
+	OrganizationUUID uuid.UUID
+	UserUUID         uuid.UUID
+	Role             enums.MemberRoleType
+	RbacUUID         *uuid.UUID
+	Email            string
+	Name             string
+	UpdatedAt        *time.Time
+	CreatedAt        time.Time
+}
+
+// OrganizationInvitation is sent by email; only the hash of its token is
+// stored.
+type OrganizationInvitation struct {
+	UUID             uuid.UUID
+	OrganizationUUID uuid.UUID
+	Email            string
+	Role             enums.MemberRoleType
+	RbacUUID         *uuid.UUID
+	TokenHash        string
+	InvitedBy        uuid.UUID
+	Status           enums.InvitationStatusType
+	ExpiresAt        time.Time
+	RespondedAt      *time.Time
+	CreatedAt        time.Time
+}
+
+// WebsitePermissions is what a user may do on a website.
+// TwoFactorRequired is set when the website requires two-factor
+// authentication from the role and everything but reading is withheld
+// until the user enables it.
+type WebsitePermissions struct {
+	WebsiteUUID       uuid.UUID
+	Role              enums.MemberRoleType
+	CanRead           bool
+	CanWrite          bool
+	CanUpdate         bool
+	CanUpgrade        bool
+	CanDelete         bool
+	TwoFactorRequired bool
+}
+
+func NewOrganizationInvitation(organizationUUID uuid.UUID, email string, role string, rbacUUID string, invitedBy uuid.UUID, tokenHash string) (*OrganizationInvitation, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := validate.Email(email); err != nil {
+		return nil, err
+	}
+
+	memberRole, err := parseInvitedRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	rbac, err := parseMemberRbac(memberRole, rbacUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrganizationInvitation{
+		UUID:             uuid.Nil,
+		OrganizationUUID: organizationUUID,
+		Email:            email,
+		Role:             memberRole,
+		RbacUUID:         rbac,
+		TokenHash:        tokenHash,
+		InvitedBy:        invitedBy,
+		Status:           enums.InvitationPending,
+		ExpiresAt:        time.Now().Add(InvitationTTL),
+	}, nil
+}
+
+// SetRole changes the role of a member other than the owner.
+func (m *OrganizationMember) SetRole(role string, rbacUUID string) error {
+	if m.Role == enums.MemberOwner {
+		return ErrOwnerMember
+	}
+
+	memberRole, err := parseInvitedRole(role)
+	if err != nil {
+		return err
+	}
+
+	rbac, err := parseMemberRbac(memberRole, rbacUUID)
+	if err != nil {
+		return err
+	}
+
+	m.Role = memberRole
+	m.RbacUUID = rbac
+	return nil
+}
+
+// CanManage tells whether the member may invite, remove and change the
+// role of other members.
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == enums.MemberOwner || m.Role == enums.MemberAdmin
+}
+
+// Answerable reports why an invitation can no longer be accepted or
+// declined, if it cannot.
+func (i *OrganizationInvitation) Answerable(now time.Time) error {
+	if i.Status != enums.InvitationPending {
+		return ErrInvitationAnswered
+	}
+
+	if !now.Before(i.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	return nil
+}
+
+// Member turns an accepted invitation into the membership of userUUID.
+func (i *OrganizationInvitation) Member(userUUID uuid.UUID) *OrganizationMember {
+	return &OrganizationMember{
+		UUID:             uuid.Nil,
+		OrganizationUUID: i.OrganizationUUID,
+		UserUUID:         userUUID,
+		Role:             i.Role,
+		RbacUUID:         i.RbacUUID,
+	}
+}
+
+// OwnerPermissions grants everything, to website owners and to owners and
+// admins of the organization owning the website.
+func OwnerPermissions(websiteUUID uuid.UUID, role enums.MemberRoleType) *WebsitePermissions {
+	return &WebsitePermissions{
+		WebsiteUUID: websiteUUID,
+		Role:        role,
+		CanRead:     true,
+		CanWrite:    true,
+		CanUpdate:   true,
+		CanUpgrade:  true,
+		CanDelete:   true,
+	}
+}
+
+// MemberPermissions are the permissions of a member of the organization
+// owning the website: those of its rbac role, or read only without one.
+func MemberPermissions(websiteUUID uuid.UUID, rbac *Rbac) *WebsitePermissions {
+	if rbac == nil {
+		return &WebsitePermissions{
+			WebsiteUUID: websiteUUID,
+			Role:        enums.MemberMember,
+			CanRead:     true,
+		}
+	}
+
+	return &WebsitePermissions{
+		WebsiteUUID: websiteUUID,
+		Role:        enums.MemberMember,
+		CanRead:     rbac.CanRead,
+		CanWrite:    rbac.CanWrite,
+		CanUpdate:   rbac.CanUpdate,
+		CanUpgrade:  rbac.CanUpgrade,
+		CanDelete:   rbac.CanDelete,
+	}
+}
+
+// WithholdForTwoFactor applies a website requirement of two-factor
+// authentication to a user without it: roles that may write or delete
+// keep only reading.
+func (p *WebsitePermissions) WithholdForTwoFactor() {
+	if !p.CanWrite && !p.CanDelete {
+		return
+	}
+
+	p.CanWrite = false
+	p.CanUpdate = false
+	p.CanUpgrade = false
+	p.CanDelete = false
+	p.TwoFactorRequired = true
+}
+
+func parseInvitedRole(role string) (enums.MemberRoleType, error) {
+	memberRole := enums.MemberRoleType(role)
+	if memberRole == "" {
+		memberRole = enums.MemberMember
+	}
+
+	if memberRole != enums.MemberAdmin && memberRole != enums.MemberMember {
+		return "", errors.New("Role must be 'admin' or 'member'.")
+	}
+
+	return memberRole, nil
+}
+
+func parseMemberRbac(role enums.MemberRoleType, rbacUUID string) (*uuid.UUID, error) {
+	if rbacUUID == "" {
+		return nil, nil
+	}
+
+	if role != enums.MemberMember {
+		return nil, errors.New("Only members can have a custom role.")
+	}
+
+	parsed, err := uuid.Parse(rbacUUID)
+	if err != nil {
+		return nil, errors.New("Rbac UUID is invalid.")
+	}
+
+	return &parsed, nil
+}
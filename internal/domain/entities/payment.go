@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+// PaymentCharge asks the payment provider to charge a stored payment
+// method. Charges with the same IdempotencyKey are only made once.
+type PaymentCharge struct {
+	IdempotencyKey string
+	CustomerUUID   string
+	PaymentMethod  string
+	Coin           enums.CoinType
+	Amount         int
+	Description    string
+}
+
+// PaymentRefund asks the payment provider to send part of a charge back.
+// Reference is the one the charge was answered with.
+type PaymentRefund struct {
+	IdempotencyKey string
+	Reference      string
+	Coin           enums.CoinType
+	Amount         int
+}
+
+// PaymentResult is the provider's answer. A declined charge is not an
+// error: Paid is false and FailureReason says why.
+type PaymentResult struct {
+	Reference     string
+	Paid          bool
+	FailureReason string
+}
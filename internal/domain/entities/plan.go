@@ -8,6 +8,11 @@ import (
 	"github.com/google/uuid"
 )
 
+// VerkoupePlan is what an owner subscribes to. MaxWebsites, MaxRouters and
+// MaxProducts cap what the owner can create across all its websites; zero
+// means unlimited. CostPerSaleRate is the percent of every sale taken as a
+// platform fee. Price is monthly; new subscribers try the plan for free
+// for TrialDays.
 type VerkoupePlan struct {
 	UUID            uuid.UUID
 	Name            string
@@ -18,11 +23,12 @@ type VerkoupePlan struct {
 	CostPerSaleRate int
 	Coin            enums.CoinType
 	Price           int
+	TrialDays       int
 	UpdatedAt       *time.Time
 	CreatedAt       time.Time
 }
 
-func NewPlan(name string, description string, maxWebsites int, maxRouters int, maxProducts int, costPerSaleRate int, coin string, price int) (*VerkoupePlan, error) {
+func NewPlan(name string, description string, maxWebsites int, maxRouters int, maxProducts int, costPerSaleRate int, coin string, price int, trialDays int) (*VerkoupePlan, error) {
 
 	if name == "" {
 		return nil, errors.New("Name cannot be null.")
@@ -33,10 +39,22 @@ func NewPlan(name string, description string, maxWebsites int, maxRouters int, m
 		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
 	}
 
+	if maxWebsites < 0 || maxRouters < 0 || maxProducts < 0 {
+		return nil, errors.New("Limits cannot be negative.")
+	}
+
+	if costPerSaleRate < 0 || costPerSaleRate > 100 {
+		return nil, errors.New("CostPerSaleRate must be between 0 and 100.")
+	}
+
 	if price <= 0 {
 		return nil, errors.New("Price must be greater than 0.")
 	}
 
+	if trialDays < 0 {
+		return nil, errors.New("TrialDays cannot be negative.")
+	}
+
 	return &VerkoupePlan{
 		UUID:            uuid.Nil,
 		Name:            name,
@@ -47,5 +65,16 @@ func NewPlan(name string, description string, maxWebsites int, maxRouters int, m
 		CostPerSaleRate: costPerSaleRate,
 		Coin:            coinType,
 		Price:           price,
+		TrialDays:       trialDays,
 	}, nil
 }
+
+// CyclePrice is what one period of cycle costs. Annual subscriptions pay
+// twelve months up front.
+func (p *VerkoupePlan) CyclePrice(cycle enums.BillingCycleType) int {
+	if cycle == enums.AnnualCycle {
+		return p.Price * 12
+	}
+
+	return p.Price
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+// PlatformFee is one entry of the fee ledger. For a sale, Amount is what
+// the order paid and Fee what Verkoupe takes at Rate percent; for a
+// refund, Amount is what was refunded and Fee the part of the sale's fee
+// given back. PlanUUID is nil when the merchant had no active plan.
+type PlatformFee struct {
+	UUID        uuid.UUID
+	OwnerUUID   uuid.UUID
+	WebsiteUUID uuid.UUID
+	OrderUUID   uuid.UUID
+	PlanUUID    *uuid.UUID
+	Type        enums.PlatformFeeType
+	Coin        enums.CoinType
+	Amount      int
+	Rate        int
+	Fee         int
+	CreatedAt   time.Time
+}
+
+func NewPlatformFee(website *Website, orderUUID string, coin string, amount int, plan *VerkoupePlan) (*PlatformFee, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("Amount must be greater than zero.")
+	}
+
+	fee := &PlatformFee{
+		OwnerUUID:   website.OwnerUUID,
+		WebsiteUUID: website.UUID,
+		OrderUUID:   orderUUIDParsed,
+		Type:        enums.PlatformFeeCharge,
+		Coin:        coinType,
+		Amount:      amount,
+	}
+	if plan != nil {
+		fee.PlanUUID = &plan.UUID
+		fee.Rate = plan.CostPerSaleRate
+		fee.Fee = PlatformFeeFor(amount, plan.CostPerSaleRate)
+	}
+
+	return fee, nil
+}
+
+// PlatformFeeFor is rate percent of amount, rounded half up.
+func PlatformFeeFor(amount int, rate int) int {
+	if amount <= 0 || rate <= 0 {
+		return 0
+	}
+
+	return int((int64(amount)*int64(rate) + 50) / 100)
+}
+
+// PlatformFeeRefund is how much of a sale and its fee is left to give back
+// once refundedTo of the order was refunded in total, given what earlier
+// refunds of the same order already did. It is computed on the running
+// total so that refunding a sale in parts gives back exactly the whole
+// fee, and recording the same refund twice gives nothing more.
+func PlatformFeeRefund(sale *PlatformFee, refunded int, refundedTo int) (amount int, fee int) {
+	amount = min(refundedTo, sale.Amount) - refunded
+	if amount <= 0 || sale.Amount <= 0 {
+		return 0, 0
+	}
+
+	share := func(refunded int) int {
+		return int(int64(sale.Fee) * int64(refunded) / int64(sale.Amount))
+	}
+
+	return amount, share(refunded+amount) - share(refunded)
+}
+
+// StatementTotals sums one currency of a merchant statement. Net is what
+// the merchant keeps: sales less refunds and the fees not given back.
+type StatementTotals struct {
+	Coin       enums.CoinType
+	Sales      int
+	GrossSales int
+	Fees       int
+	Refunds    int
+	FeeRefunds int
+	Net        int
+}
+
+// MerchantStatement is a merchant's fee ledger for one calendar month,
+// from Month (the first instant of the month, UTC) to the next.
+type MerchantStatement struct {
+	OwnerUUID uuid.UUID
+	Month     time.Time
+	Totals    []*StatementTotals
+	Entries   []*PlatformFee
+}
+
+func NewMerchantStatement(ownerUUID uuid.UUID, month time.Time, entries []*PlatformFee) *MerchantStatement {
+	byCoin := make(map[enums.CoinType]*StatementTotals)
+	var totals []*StatementTotals
+	for _, entry := range entries {
+		t, ok := byCoin[entry.Coin]
+		if !ok {
+			t = &StatementTotals{Coin: entry.Coin}
+			byCoin[entry.Coin] = t
+			totals = append(totals, t)
+		}
+
+		switch entry.Type {
+		case enums.PlatformFeeCharge:
+			t.Sales++
+			t.GrossSales += entry.Amount
+			t.Fees += entry.Fee
+		case enums.PlatformFeeRefund:
+			t.Refunds += entry.Amount
+			t.FeeRefunds += entry.Fee
+		}
+	}
+
+	for _, t := range totals {
+		t.Net = t.GrossSales - t.Refunds - (t.Fees - t.FeeRefunds)
+	}
+	slices.SortFunc(totals, func(a, b *StatementTotals) int {
+		return cmp.Compare(a.Coin, b.Coin)
+	})
+
+	return &MerchantStatement{
+		OwnerUUID: ownerUUID,
+		Month:     month,
+		Totals:    totals,
+		Entries:   entries,
+	}
+}
+
+// ParseStatementMonth reads a month written as 2006-01.
+func ParseStatementMonth(month string) (time.Time, error) {
+	start, err := time.ParseInLocation("2006-01", month, time.UTC)
+	if err != nil {
+		return time.Time{}, errors.New("Month must be written as YYYY-MM.")
+	}
+
+	return start, nil
+}
+
+// End is the first instant after the statement's month.
+func (s *MerchantStatement) End() time.Time {
+	return s.Month.AddDate(0, 1, 0)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestPlatformFeeRefund(t *testing.T) {
+	sale := &PlatformFee{Amount: 10000, Fee: 333}
+
+	tests := []struct {
+		name       string
+		refunded   int
+		refundedTo int
+		wantAmount int
+		wantFee    int
+	}{
+		{name: "first partial refund", refunded: 0, refundedTo: 5000, wantAmount: 5000, wantFee: 166},
+		{name: "same refund again", refunded: 5000, refundedTo: 5000, wantAmount: 0, wantFee: 0},
+		{name: "rest of the sale", refunded: 5000, refundedTo: 10000, wantAmount: 5000, wantFee: 167},
+		{name: "past the sale", refunded: 10000, refundedTo: 12000, wantAmount: 0, wantFee: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, fee := PlatformFeeRefund(sale, tt.refunded, tt.refundedTo)
+			if amount != tt.wantAmount || fee != tt.wantFee {
+				t.Fatalf("PlatformFeeRefund() = %d, %d, want %d, %d", amount, fee, tt.wantAmount, tt.wantFee)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+type PriceList struct {
+	UUID          uuid.UUID
+	WebsiteUUID   uuid.UUID
+	Label         string
+	Coin          enums.CoinType
+	CustomerGroup *string
+	Priority      int
+	Active        bool
+	UpdatedAt     *time.Time
+	CreatedAt     time.Time
+}
+
+func NewPriceList(websiteUUID string, label string, coin string, customerGroup string, priority int, active bool) (*PriceList, error) {
+	if label == "" {
+		return nil, errors.New("Label cannot be null.")
+	}
+
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var groupPtr *string
+	if customerGroup != "" {
+		groupPtr = &customerGroup
+	}
+
+	return &PriceList{
+		UUID:          uuid.Nil,
+		WebsiteUUID:   websiteUUIDParsed,
+		Label:         label,
+		Coin:          coinType,
+		CustomerGroup: groupPtr,
+		Priority:      priority,
+		Active:        active,
+	}, nil
+}
+
+// CustomerGroup puts a customer of a website in the group whose price lists
+// they get.
+type CustomerGroup struct {
+	WebsiteUUID uuid.UUID
+	UserUUID    uuid.UUID
+	Group       string
+	UpdatedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func NewCustomerGroup(websiteUUID string, userUUID string, group string) (*CustomerGroup, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, errors.New("User UUID is invalid.")
+	}
+
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return nil, errors.New("Customer group cannot be null.")
+	}
+
+	if len(group) > 100 {
+		return nil, errors.New("Customer group must have at most 100 characters.")
+	}
+
+	return &CustomerGroup{
+		WebsiteUUID: websiteUUIDParsed,
+		UserUUID:    userUUIDParsed,
+		Group:       group,
+	}, nil
+}
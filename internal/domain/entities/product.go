@@ -4,14 +4,23 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
 	"github.com/google/uuid"
 )
 
 type Products struct {
 	UUID             uuid.UUID
+	WebsiteUUID      *uuid.UUID
 	Name             string
 	Description      string
 	ShortDescription string
+	Brand            string
+	GTIN             string
+	Type             enums.ProductType
+	DownloadLimit    int
+	DownloadDays     int
+	RatingCount      int
+	RatingAverage    float64
 	height           int
 	width            int
 	thickness        int
@@ -20,12 +29,57 @@ type Products struct {
 	CreatedAt        time.Time
 }
 
-func NewProduct(name string, description string, shortDescription string, height int, width int, thickness int, active bool) (*Products, error) {
+// Digital products are delivered as files instead of being shipped. Each
+// paid order may download every file DownloadLimit times within
+// DownloadDays; zero picks the defaults.
+const (
+	DefaultDownloadLimit = 5
+	DefaultDownloadDays  = 30
+)
+
+func NewProduct(name string, description string, shortDescription string, brand string, gtin string, productType string, height int, width int, thickness int, downloadLimit int, downloadDays int, active bool) (*Products, error) {
 
 	if name == "" {
 		return nil, errors.New("Name cannot be null.")
 	}
 
+	if len([]rune(brand)) > 120 {
+		return nil, errors.New("Brand cannot be longer than 120 characters.")
+	}
+
+	if err := ValidateGTIN(gtin); err != nil {
+		return nil, err
+	}
+
+	ptype := enums.ProductType(productType)
+	if ptype == "" {
+		ptype = enums.PhysicalProduct
+	}
+	if ptype != enums.PhysicalProduct && ptype != enums.DigitalProduct && ptype != enums.GiftCardProduct {
+		return nil, errors.New("Type must be 'physical', 'digital' or 'gift_card'.")
+	}
+
+	if downloadLimit < 0 {
+		return nil, errors.New("Download limit cannot be negative.")
+	}
+
+	if downloadDays < 0 {
+		return nil, errors.New("Download days cannot be negative.")
+	}
+
+	if ptype != enums.DigitalProduct {
+		if downloadLimit > 0 || downloadDays > 0 {
+			return nil, errors.New("Only digital products have download limits.")
+		}
+	} else {
+		if downloadLimit == 0 {
+			downloadLimit = DefaultDownloadLimit
+		}
+		if downloadDays == 0 {
+			downloadDays = DefaultDownloadDays
+		}
+	}
+
 	if height < 0 {
 		return nil, errors.New("Height cannot be negative.")
 	}
@@ -43,9 +97,71 @@ func NewProduct(name string, description string, shortDescription string, height
 		Name:             name,
 		Description:      description,
 		ShortDescription: shortDescription,
+		Brand:            brand,
+		GTIN:             gtin,
+		Type:             ptype,
+		DownloadLimit:    downloadLimit,
+		DownloadDays:     downloadDays,
 		height:           height,
 		width:            width,
 		thickness:        thickness,
 		Active:           active,
 	}, nil
 }
+
+func (p *Products) IsDigital() bool {
+	return p.Type == enums.DigitalProduct
+}
+
+// IsGiftCard reports whether buying the product issues a gift card for the
+// amount paid instead of delivering goods.
+func (p *Products) IsGiftCard() bool {
+	return p.Type == enums.GiftCardProduct
+}
+
+func (p *Products) Shippable() bool {
+	return p.Type == enums.PhysicalProduct
+}
+
+func (p *Products) Dimensions() (height int, width int, thickness int) {
+	return p.height, p.width, p.thickness
+}
+
+// ValidateGTIN accepts an empty value or a GTIN-8, 12 (UPC), 13 (EAN) or
+// 14 with a valid check digit.
+func ValidateGTIN(gtin string) error {
+	if gtin == "" {
+		return nil
+	}
+
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return errors.New("GTIN must have 8, 12, 13 or 14 digits.")
+	}
+
+	sum := 0
+	for i := 0; i < len(gtin); i++ {
+		c := gtin[i]
+		if c < '0' || c > '9' {
+			return errors.New("GTIN must contain only digits.")
+		}
+		if i == len(gtin)-1 {
+			break
+		}
+
+		// Weights alternate 3 and 1 starting from the digit next to the
+		// check digit.
+		digit := int(c - '0')
+		if (len(gtin)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	if int(gtin[len(gtin)-1]-'0') != (10-sum%10)%10 {
+		return errors.New("GTIN check digit is invalid.")
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+// FeedProduct is one sellable row of a product feed: an active product, or
+// one of its active variants when it has any.
+type FeedProduct struct {
+	ProductUUID      uuid.UUID
+	VariantUUID      *uuid.UUID
+	Name             string
+	Description      string
+	ShortDescription string
+	Brand            string
+	GTIN             string
+	SKU              string
+	VariantLabel     string
+	Digital          bool
+	Stock            int
+	ImageKeys        []string
+	Category         string
+}
+
+// ID is the offer id sent to the channels, stable across feed generations.
+func (p *FeedProduct) ID() string {
+	if p.VariantUUID != nil {
+		return p.VariantUUID.String()
+	}
+	return p.ProductUUID.String()
+}
+
+func (p *FeedProduct) Title() string {
+	if p.VariantLabel != "" {
+		return p.Name + " - " + p.VariantLabel
+	}
+	return p.Name
+}
+
+// InStock treats digital products as always available; they have no
+// inventory.
+// PriceTarget is what the offer is priced as.
+func (p *FeedProduct) PriceTarget() PriceTarget {
+	target := PriceTarget{ProductUUID: p.ProductUUID}
+	if p.VariantUUID != nil {
+		target.VariantUUID = *p.VariantUUID
+	}
+	return target
+}
+
+func (p *FeedProduct) InStock() bool {
+	return p.Digital || p.Stock > 0
+}
+
+// ProductFeedFile is a generated feed, kept as bytes so it can be cached and
+// served with conditional requests.
+type ProductFeedFile struct {
+	Format      enums.FeedFormatType
+	Data        []byte
+	GeneratedAt time.Time
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var fileExtension = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+var (
+	ErrDownloadExpired      = errors.New("download expired")
+	ErrDownloadLimitReached = errors.New("download limit reached")
+	ErrProductFileInUse     = errors.New("product file has active downloads")
+)
+
+// ProductFile is a file delivered to buyers of a digital product. Files live
+// under downloads/, outside the public media prefix, and are only served
+// through signed download links.
+type ProductFile struct {
+	UUID        uuid.UUID
+	ProductUUID uuid.UUID
+	BlobKey     string
+	Filename    string
+	ContentType string
+	SizeBytes   int64
+	CreatedAt   time.Time
+}
+
+func NewProductFile(productUUID string, filename string, contentType string, sizeBytes int64) (*ProductFile, error) {
+	filename = cleanFilename(filename)
+	if filename == "" {
+		return nil, errors.New("Filename cannot be null.")
+	}
+
+	if len([]rune(filename)) > 255 {
+		return nil, errors.New("Filename cannot be longer than 255 characters.")
+	}
+
+	if sizeBytes <= 0 {
+		return nil, errors.New("SizeBytes must be greater than zero.")
+	}
+
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(path.Ext(filename))
+	if !fileExtension.MatchString(ext) {
+		ext = ""
+	}
+
+	return &ProductFile{
+		UUID:        uuid.Nil,
+		ProductUUID: productUUIDParsed,
+		BlobKey:     "downloads/" + productUUIDParsed.String() + "/" + uuid.NewString() + ext,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   sizeBytes,
+	}, nil
+}
+
+// cleanFilename keeps the base name the client sent, without directories,
+// control characters or quotes, since it ends up in a Content-Disposition
+// header.
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+
+	filename = strings.TrimSpace(filename)
+	if filename == "." || filename == "/" {
+		return ""
+	}
+	return filename
+}
+
+// ProductDownload grants an order access to one product file. The grant is
+// created when the order is paid and allows DownloadLimit downloads until
+// ExpiresAt; each download goes through a short-lived signed link.
+type ProductDownload struct {
+	UUID             uuid.UUID
+	OrderUUID        uuid.UUID
+	ProductUUID      uuid.UUID
+	FileUUID         uuid.UUID
+	Filename         string
+	ContentType      string
+	SizeBytes        int64
+	BlobKey          string
+	DownloadLimit    int
+	DownloadCount    int
+	ExpiresAt        time.Time
+	LastDownloadedAt *time.Time
+	CreatedAt        time.Time
+}
+
+func NewProductDownload(orderUUID string, product *Products, file *ProductFile, now time.Time) (*ProductDownload, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	if !product.IsDigital() {
+		return nil, errors.New("Only digital products can be downloaded.")
+	}
+
+	if file.ProductUUID != product.UUID {
+		return nil, errors.New("File does not belong to product.")
+	}
+
+	return &ProductDownload{
+		UUID:          uuid.Nil,
+		OrderUUID:     orderUUIDParsed,
+		ProductUUID:   product.UUID,
+		FileUUID:      file.UUID,
+		Filename:      file.Filename,
+		ContentType:   file.ContentType,
+		SizeBytes:     file.SizeBytes,
+		BlobKey:       file.BlobKey,
+		DownloadLimit: product.DownloadLimit,
+		ExpiresAt:     now.AddDate(0, 0, product.DownloadDays),
+	}, nil
+}
+
+func (d *ProductDownload) Remaining() int {
+	return max(d.DownloadLimit-d.DownloadCount, 0)
+}
+
+// Check reports why the grant can no longer be used, if it can't.
+func (d *ProductDownload) Check(now time.Time) error {
+	if !now.Before(d.ExpiresAt) {
+		return ErrDownloadExpired
+	}
+	if d.Remaining() == 0 {
+		return ErrDownloadLimitReached
+	}
+	return nil
+}
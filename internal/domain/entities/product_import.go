@@ -0,0 +1,534 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const (
+	ImportFieldHandle         = "handle"
+	ImportFieldName           = "name"
+	ImportFieldDescription    = "description"
+	ImportFieldShortDesc      = "short_description"
+	ImportFieldBrand          = "brand"
+	ImportFieldHeight         = "height"
+	ImportFieldWidth          = "width"
+	ImportFieldThickness      = "thickness"
+	ImportFieldActive         = "active"
+	ImportFieldTags           = "tags"
+	ImportFieldSKU            = "sku"
+	ImportFieldGTIN           = "gtin"
+	ImportFieldVariantLabel   = "variant_label"
+	ImportFieldVariantActive  = "variant_active"
+	ImportFieldCoin           = "coin"
+	ImportFieldPrice          = "price"
+	ImportFieldCompareAtPrice = "compare_at_price"
+	ImportFieldStock          = "stock"
+)
+
+// ProductImportFields lists the columns understood by the importer, in the
+// order the exporter writes them, so an export can be imported back as is.
+var ProductImportFields = []string{
+	ImportFieldHandle,
+	ImportFieldName,
+	ImportFieldDescription,
+	ImportFieldShortDesc,
+	ImportFieldBrand,
+	ImportFieldHeight,
+	ImportFieldWidth,
+	ImportFieldThickness,
+	ImportFieldActive,
+	ImportFieldTags,
+	ImportFieldSKU,
+	ImportFieldGTIN,
+	ImportFieldVariantLabel,
+	ImportFieldVariantActive,
+	ImportFieldCoin,
+	ImportFieldPrice,
+	ImportFieldCompareAtPrice,
+	ImportFieldStock,
+}
+
+const MaxImportStock = 10000
+
+type ProductImportJob struct {
+	UUID          uuid.UUID
+	WebsiteUUID   uuid.UUID
+	Filename      string
+	Format        string
+	Source        enums.ImportSourceType
+	Coin          enums.CoinType
+	Status        enums.ImportStatusType
+	DryRun        bool
+	Mapping       map[string]string
+	BlobKey       string
+	TotalRows     int
+	ProcessedRows int
+	CreatedCount  int
+	FailedCount   int
+	WarningCount  int
+	Error         string
+	StartedAt     *time.Time
+	FinishedAt    *time.Time
+	UpdatedAt     *time.Time
+	CreatedAt     time.Time
+}
+
+type ProductImportError struct {
+	UUID       uuid.UUID
+	ImportUUID uuid.UUID
+	Row        int
+	Level      enums.ImportIssueLevelType
+	Field      string
+	Message    string
+	CreatedAt  time.Time
+}
+
+func (e *ProductImportError) IsWarning() bool {
+	return e.Level == enums.ImportIssueWarning
+}
+
+// HasImportErrors reports whether any of the problems blocks the product;
+// warnings alone let it through.
+func HasImportErrors(problems []*ProductImportError) bool {
+	for _, p := range problems {
+		if !p.IsWarning() {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductImportRow is one spreadsheet record keyed by import field. Number is
+// the row as shown by spreadsheet tools, header included.
+type ProductImportRow struct {
+	Number int
+	Values map[string]string
+}
+
+func (r ProductImportRow) Get(field string) string {
+	return strings.TrimSpace(r.Values[field])
+}
+
+// ProductImportItem is a product with everything hanging off it, built from
+// one or more consecutive rows sharing a handle.
+type ProductImportItem struct {
+	Row      int
+	Handle   string
+	Product  *Products
+	Variants []*ProductVariant
+	Tags     []*ProductsTags
+	Prices   []*ProductPrice
+	Stock    int
+	Medias   []*ProductImportMedia
+}
+
+// ProductImportMedia is an image referenced by URL in the file. It is
+// downloaded into the product gallery after the product is written.
+type ProductImportMedia struct {
+	Row int
+	URL string
+	Alt string
+}
+
+// ProductExportRow is the flattened shape written by the catalog export: one
+// row per variant, or one per product when it has none.
+type ProductExportRow struct {
+	Handle           string
+	Name             string
+	Description      string
+	ShortDescription string
+	Brand            string
+	GTIN             string
+	Height           int
+	Width            int
+	Thickness        int
+	Active           bool
+	Tags             []string
+	SKU              string
+	VariantGTIN      string
+	VariantLabel     string
+	VariantActive    *bool
+	Coin             string
+	Price            *int
+	CompareAtPrice   *int
+	Stock            int
+}
+
+func NewProductImportJob(websiteUUID string, filename string, format string, source string, coin string, dryRun bool, mapping map[string]string) (*ProductImportJob, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filename == "" {
+		return nil, errors.New("Filename cannot be null.")
+	}
+
+	if format != "csv" && format != "xlsx" {
+		return nil, errors.New("Format must be 'csv' or 'xlsx'.")
+	}
+
+	sourceType := enums.ImportSourceType(source)
+	if source == "" {
+		sourceType = enums.ImportSourceNative
+	}
+	if sourceType != enums.ImportSourceNative && sourceType != enums.ImportSourceShopify {
+		return nil, errors.New("Source must be 'native' or 'shopify'.")
+	}
+
+	if sourceType == enums.ImportSourceShopify && len(mapping) > 0 {
+		return nil, errors.New("Mapping is not supported for Shopify files.")
+	}
+
+	coinType := enums.CoinType(strings.ToUpper(coin))
+	if coin == "" {
+		coinType = enums.BRCoin
+	}
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	cleaned := make(map[string]string, len(mapping))
+	for field, column := range mapping {
+		if !isImportField(field) {
+			return nil, fmt.Errorf("Mapping field %q is not supported.", field)
+		}
+		if strings.TrimSpace(column) != "" {
+			cleaned[field] = strings.TrimSpace(column)
+		}
+	}
+
+	jobUUID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductImportJob{
+		UUID:        jobUUID,
+		WebsiteUUID: websiteUUIDParsed,
+		Filename:    filename,
+		Format:      format,
+		Source:      sourceType,
+		Coin:        coinType,
+		Status:      enums.ImportPending,
+		DryRun:      dryRun,
+		Mapping:     cleaned,
+		BlobKey:     "imports/" + jobUUID.String() + "." + format,
+	}, nil
+}
+
+// ResolveColumns finds the header index of every mapped field. Fields left
+// out of the mapping fall back to a header with the same name.
+func (j *ProductImportJob) ResolveColumns(header []string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		key := strings.ToLower(strings.TrimSpace(column))
+		if _, seen := positions[key]; !seen {
+			positions[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for _, field := range ProductImportFields {
+		source, mapped := j.Mapping[field]
+		if !mapped {
+			source = field
+		}
+
+		i, ok := positions[strings.ToLower(source)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s is not in the header", source, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns[ImportFieldName]; !ok {
+		return nil, errors.New("the file has no column for the product name")
+	}
+
+	return columns, nil
+}
+
+// Progress is the share of rows already handled, between 0 and 1.
+func (j *ProductImportJob) Progress() float64 {
+	if j.TotalRows == 0 {
+		if j.Status == enums.ImportCompleted {
+			return 1
+		}
+		return 0
+	}
+	return float64(j.ProcessedRows) / float64(j.TotalRows)
+}
+
+// BuildProductImportItem validates the rows of one product through the
+// entity constructors. It keeps going after a failure so every problem in
+// the group is reported at once; the item is nil when any row failed. Rows
+// without a coin are priced in defaultCoin.
+func BuildProductImportItem(rows []ProductImportRow, defaultCoin enums.CoinType) (*ProductImportItem, []*ProductImportError) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var problems []*ProductImportError
+	fail := func(row int, field string, err error) {
+		problems = append(problems, &ProductImportError{Row: row, Level: enums.ImportIssueError, Field: field, Message: err.Error()})
+	}
+
+	first := rows[0]
+	height, err := parseImportInt(first.Get(ImportFieldHeight))
+	if err != nil {
+		fail(first.Number, ImportFieldHeight, err)
+	}
+	width, err := parseImportInt(first.Get(ImportFieldWidth))
+	if err != nil {
+		fail(first.Number, ImportFieldWidth, err)
+	}
+	thickness, err := parseImportInt(first.Get(ImportFieldThickness))
+	if err != nil {
+		fail(first.Number, ImportFieldThickness, err)
+	}
+	active, err := parseImportBool(first.Get(ImportFieldActive), true)
+	if err != nil {
+		fail(first.Number, ImportFieldActive, err)
+	}
+
+	// A GTIN on a row that defines a variant belongs to the variant.
+	gtin := ""
+	if first.Get(ImportFieldSKU) == "" && first.Get(ImportFieldVariantLabel) == "" {
+		gtin = first.Get(ImportFieldGTIN)
+	}
+	if err := ValidateGTIN(gtin); err != nil {
+		fail(first.Number, ImportFieldGTIN, err)
+		return nil, problems
+	}
+
+	product, err := NewProduct(first.Get(ImportFieldName), first.Get(ImportFieldDescription), first.Get(ImportFieldShortDesc), first.Get(ImportFieldBrand), gtin, string(enums.PhysicalProduct), height, width, thickness, 0, 0, active)
+	if err != nil {
+		fail(first.Number, ImportFieldName, err)
+		return nil, problems
+	}
+
+	product.UUID, err = uuid.NewV7()
+	if err != nil {
+		fail(first.Number, "", err)
+		return nil, problems
+	}
+	productUUID := product.UUID.String()
+
+	item := &ProductImportItem{
+		Row:     first.Number,
+		Handle:  first.Get(ImportFieldHandle),
+		Product: product,
+	}
+
+	seenTags := make(map[string]bool)
+	seenSKUs := make(map[string]bool)
+	seenPrices := make(map[string]bool)
+
+	for _, row := range rows {
+		for _, label := range splitImportList(row.Get(ImportFieldTags)) {
+			key := strings.ToLower(label)
+			if seenTags[key] {
+				continue
+			}
+			seenTags[key] = true
+
+			tag, err := NewProductTag(productUUID, label)
+			if err != nil {
+				fail(row.Number, ImportFieldTags, err)
+				continue
+			}
+			item.Tags = append(item.Tags, tag)
+		}
+
+		stock, err := parseImportInt(row.Get(ImportFieldStock))
+		if err != nil {
+			fail(row.Number, ImportFieldStock, err)
+		}
+		item.Stock += stock
+
+		variantUUID := ""
+		sku, label := row.Get(ImportFieldSKU), row.Get(ImportFieldVariantLabel)
+		if sku != "" || label != "" {
+			if label == "" {
+				label = sku
+			}
+			if sku != "" && seenSKUs[strings.ToLower(sku)] {
+				fail(row.Number, ImportFieldSKU, fmt.Errorf("SKU %q is repeated in this product.", sku))
+				continue
+			}
+			seenSKUs[strings.ToLower(sku)] = true
+
+			variantActive, err := parseImportBool(row.Get(ImportFieldVariantActive), true)
+			if err != nil {
+				fail(row.Number, ImportFieldVariantActive, err)
+			}
+
+			if err := ValidateGTIN(row.Get(ImportFieldGTIN)); err != nil {
+				fail(row.Number, ImportFieldGTIN, err)
+				continue
+			}
+
+			variant, err := NewProductVariant(productUUID, sku, label, row.Get(ImportFieldGTIN), variantActive)
+			if err != nil {
+				fail(row.Number, ImportFieldVariantLabel, err)
+				continue
+			}
+			variant.UUID, err = uuid.NewV7()
+			if err != nil {
+				fail(row.Number, "", err)
+				continue
+			}
+			item.Variants = append(item.Variants, variant)
+			variantUUID = variant.UUID.String()
+		}
+
+		priceText := row.Get(ImportFieldPrice)
+		if priceText == "" {
+			continue
+		}
+
+		amount, err := ParseImportAmount(priceText)
+		if err != nil {
+			fail(row.Number, ImportFieldPrice, err)
+			continue
+		}
+
+		var compareAt *int
+		if text := row.Get(ImportFieldCompareAtPrice); text != "" {
+			parsed, err := ParseImportAmount(text)
+			if err != nil {
+				fail(row.Number, ImportFieldCompareAtPrice, err)
+				continue
+			}
+			compareAt = &parsed
+		}
+
+		coin := strings.ToUpper(row.Get(ImportFieldCoin))
+		if coin == "" {
+			coin = string(defaultCoin)
+		}
+
+		if seenPrices[variantUUID+coin] {
+			fail(row.Number, ImportFieldPrice, fmt.Errorf("Price in %s is repeated in this product.", coin))
+			continue
+		}
+		seenPrices[variantUUID+coin] = true
+
+		price, err := NewProductPrice(productUUID, variantUUID, "", coin, amount, compareAt, nil, nil, nil)
+		if err != nil {
+			fail(row.Number, ImportFieldPrice, err)
+			continue
+		}
+		item.Prices = append(item.Prices, price)
+	}
+
+	if item.Stock > MaxImportStock {
+		fail(first.Number, ImportFieldStock, fmt.Errorf("Stock cannot be greater than %d.", MaxImportStock))
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	return item, nil
+}
+
+// ParseImportAmount reads a decimal price such as "19.90", "19,90" or
+// "1.299,90" and returns it in cents.
+func ParseImportAmount(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	lastComma, lastDot := strings.LastIndex(text, ","), strings.LastIndex(text, ".")
+
+	switch {
+	case lastComma > lastDot:
+		text = strings.ReplaceAll(text, ".", "")
+		text = strings.Replace(text, ",", ".", 1)
+	case lastDot > lastComma:
+		text = strings.ReplaceAll(text, ",", "")
+	}
+
+	value, ok := new(big.Rat).SetString(text)
+	if !ok || strings.ContainsAny(text, "/eE") {
+		return 0, fmt.Errorf("Price %q is not a number.", text)
+	}
+	if value.Sign() < 0 {
+		return 0, errors.New("Price cannot be negative.")
+	}
+
+	value.Mul(value, big.NewRat(100, 1))
+	if !value.IsInt() {
+		return 0, fmt.Errorf("Price %q has more than two decimals.", text)
+	}
+	if !value.Num().IsInt64() || value.Num().Int64() > 1<<31-1 {
+		return 0, fmt.Errorf("Price %q is too large.", text)
+	}
+
+	return int(value.Num().Int64()), nil
+}
+
+// FormatImportAmount is the inverse of ParseImportAmount for exports.
+func FormatImportAmount(cents int) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func parseImportInt(text string) (int, error) {
+	if text == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number.", text)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("%q cannot be negative.", text)
+	}
+	return value, nil
+}
+
+func parseImportBool(text string, fallback bool) (bool, error) {
+	switch strings.ToLower(text) {
+	case "":
+		return fallback, nil
+	case "true", "1", "yes", "y", "sim", "s", "active":
+		return true, nil
+	case "false", "0", "no", "n", "nao", "não", "inactive", "draft":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a yes/no value.", text)
+}
+
+// splitImportList accepts tags separated by commas, semicolons or pipes.
+func splitImportList(text string) []string {
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+
+	var values []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func isImportField(field string) bool {
+	for _, f := range ProductImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,355 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+// Columns of Shopify's product CSV, lowercased. Shopify writes one row per
+// variant and one per extra image, all sharing the product handle; only the
+// first row of a product carries its title, body and tags.
+const (
+	ShopifyHandle         = ImportFieldHandle
+	ShopifyTitle          = "title"
+	ShopifyBody           = "body (html)"
+	ShopifyVendor         = "vendor"
+	ShopifyTags           = "tags"
+	ShopifyPublished      = "published"
+	ShopifyStatus         = "status"
+	ShopifyGiftCard       = "gift card"
+	ShopifySEODescription = "seo description"
+	ShopifySKU            = "variant sku"
+	ShopifyBarcode        = "variant barcode"
+	ShopifyInventoryQty   = "variant inventory qty"
+	ShopifyPrice          = "variant price"
+	ShopifyCompareAtPrice = "variant compare at price"
+	ShopifyVariantImage   = "variant image"
+	ShopifyImageSrc       = "image src"
+	ShopifyImagePosition  = "image position"
+	ShopifyImageAlt       = "image alt text"
+)
+
+const (
+	maxImportNameLength             = 250
+	maxImportBrandLength            = 120
+	maxImportDescriptionLength      = 3000
+	maxImportShortDescriptionLength = 500
+	maxImportSKULength              = 100
+
+	shopifyDefaultVariant = "Default Title"
+	shopifyOptions        = 3
+)
+
+var shopifyColumns = []string{
+	ShopifyHandle,
+	ShopifyTitle,
+	ShopifyBody,
+	ShopifyVendor,
+	ShopifyTags,
+	ShopifyPublished,
+	ShopifyStatus,
+	ShopifyGiftCard,
+	ShopifySEODescription,
+	ShopifySKU,
+	ShopifyBarcode,
+	ShopifyInventoryQty,
+	ShopifyPrice,
+	ShopifyCompareAtPrice,
+	ShopifyVariantImage,
+	ShopifyImageSrc,
+	ShopifyImagePosition,
+	ShopifyImageAlt,
+}
+
+// shopifyFieldNames turns the fields reported by BuildProductImportItem back
+// into the Shopify headers the merchant sees in their file.
+var shopifyFieldNames = map[string]string{
+	ImportFieldName:           "Title",
+	ImportFieldDescription:    "Body (HTML)",
+	ImportFieldShortDesc:      "SEO Description",
+	ImportFieldBrand:          "Vendor",
+	ImportFieldGTIN:           "Variant Barcode",
+	ImportFieldActive:         "Status",
+	ImportFieldTags:           "Tags",
+	ImportFieldSKU:            "Variant SKU",
+	ImportFieldVariantLabel:   "Option1 Value",
+	ImportFieldPrice:          "Variant Price",
+	ImportFieldCompareAtPrice: "Variant Compare At Price",
+	ImportFieldStock:          "Variant Inventory Qty",
+}
+
+func ShopifyOptionValue(n int) string {
+	return "option" + strconv.Itoa(n) + " value"
+}
+
+// ResolveShopifyColumns finds the Shopify columns in the header. Every other
+// column is returned as unmapped, with its original name, so the import
+// report can say what was left behind.
+func ResolveShopifyColumns(header []string) (map[string]int, map[int]string, error) {
+	known := make(map[string]bool, len(shopifyColumns)+shopifyOptions*2)
+	for _, column := range shopifyColumns {
+		known[column] = true
+	}
+	for n := 1; n <= shopifyOptions; n++ {
+		known["option"+strconv.Itoa(n)+" name"] = true
+		known[ShopifyOptionValue(n)] = true
+	}
+
+	columns := make(map[string]int)
+	unmapped := make(map[int]string)
+	for i, column := range header {
+		key := strings.ToLower(strings.TrimSpace(column))
+		if !known[key] {
+			if key != "" {
+				unmapped[i] = strings.TrimSpace(column)
+			}
+			continue
+		}
+		if _, seen := columns[key]; !seen {
+			columns[key] = i
+		}
+	}
+
+	if _, ok := columns[ShopifyHandle]; !ok {
+		return nil, nil, errors.New("the file has no Handle column; is it a Shopify product export?")
+	}
+	if _, ok := columns[ShopifyTitle]; !ok {
+		return nil, nil, errors.New("the file has no Title column; is it a Shopify product export?")
+	}
+
+	return columns, unmapped, nil
+}
+
+// BuildShopifyImportItem maps the rows of one Shopify product onto the
+// native import fields and validates them with BuildProductImportItem.
+// Option values become the variant label, variant stock is added up into the
+// product stock and every image, variant images included, goes to the
+// product gallery. What has no place in the catalog is reported as a
+// warning and does not stop the product.
+func BuildShopifyImportItem(rows []ProductImportRow, defaultCoin enums.CoinType) (*ProductImportItem, []*ProductImportError) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var warnings []*ProductImportError
+	warn := func(row int, field string, message string) {
+		warnings = append(warnings, &ProductImportError{Row: row, Level: enums.ImportIssueWarning, Field: field, Message: message})
+	}
+
+	first := rows[0]
+	title := first.Get(ShopifyTitle)
+	if len([]rune(title)) > maxImportNameLength {
+		return nil, []*ProductImportError{{
+			Row:     first.Number,
+			Level:   enums.ImportIssueError,
+			Field:   shopifyFieldNames[ImportFieldName],
+			Message: fmt.Sprintf("Title cannot be longer than %d characters.", maxImportNameLength),
+		}}
+	}
+
+	description, cut := shortenImportText(shopifyHTMLToText(first.Get(ShopifyBody)), maxImportDescriptionLength)
+	if cut {
+		warn(first.Number, shopifyFieldNames[ImportFieldDescription], fmt.Sprintf("Description was shortened to %d characters.", maxImportDescriptionLength))
+	}
+
+	shortDescription, cut := shortenImportText(first.Get(ShopifySEODescription), maxImportShortDescriptionLength)
+	if cut {
+		warn(first.Number, shopifyFieldNames[ImportFieldShortDesc], fmt.Sprintf("SEO description was shortened to %d characters.", maxImportShortDescriptionLength))
+	}
+
+	brand, cut := shortenImportText(first.Get(ShopifyVendor), maxImportBrandLength)
+	if cut {
+		warn(first.Number, shopifyFieldNames[ImportFieldBrand], fmt.Sprintf("Vendor was shortened to %d characters.", maxImportBrandLength))
+	}
+
+	active := "true"
+	switch status := strings.ToLower(first.Get(ShopifyStatus)); status {
+	case "":
+		if published := first.Get(ShopifyPublished); published != "" {
+			active = published
+		}
+	case "active":
+	case "draft", "archived":
+		active = "false"
+	default:
+		warn(first.Number, shopifyFieldNames[ImportFieldActive], fmt.Sprintf("Status %q is unknown; the product was imported as active.", status))
+	}
+
+	if giftCard, _ := parseImportBool(first.Get(ShopifyGiftCard), false); giftCard {
+		warn(first.Number, "Gift Card", "Gift cards are imported as regular products.")
+	}
+
+	native := make([]ProductImportRow, 0, len(rows))
+	var images []shopifyImage
+	seenImages := make(map[string]bool)
+	stockedVariants := 0
+
+	addImage := func(row ProductImportRow, src string, position int, alt string) {
+		if src == "" || seenImages[src] {
+			return
+		}
+		seenImages[src] = true
+
+		parsed, err := url.Parse(src)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			warn(row.Number, "Image Src", fmt.Sprintf("Image URL %q is not valid and was skipped.", src))
+			return
+		}
+		images = append(images, shopifyImage{row: row.Number, url: src, alt: alt, position: position})
+	}
+
+	for i, row := range rows {
+		values := make(map[string]string)
+		if i == 0 {
+			values[ImportFieldName] = title
+			values[ImportFieldDescription] = description
+			values[ImportFieldShortDesc] = shortDescription
+			values[ImportFieldBrand] = brand
+			values[ImportFieldActive] = active
+			values[ImportFieldTags] = first.Get(ShopifyTags)
+		}
+
+		sku := row.Get(ShopifySKU)
+		label := shopifyVariantLabel(row)
+		if sku != "" || label != "" || row.Get(ShopifyPrice) != "" {
+			if len([]rune(sku)) > maxImportSKULength {
+				return nil, append(warnings, &ProductImportError{
+					Row:     row.Number,
+					Level:   enums.ImportIssueError,
+					Field:   shopifyFieldNames[ImportFieldSKU],
+					Message: fmt.Sprintf("SKU cannot be longer than %d characters.", maxImportSKULength),
+				})
+			}
+
+			values[ImportFieldSKU] = sku
+			if barcode := row.Get(ShopifyBarcode); barcode != "" {
+				if err := ValidateGTIN(barcode); err != nil {
+					warn(row.Number, shopifyFieldNames[ImportFieldGTIN], fmt.Sprintf("Barcode %q is not a valid GTIN and was not imported.", barcode))
+				} else {
+					values[ImportFieldGTIN] = barcode
+				}
+			}
+			values[ImportFieldVariantLabel] = label
+			values[ImportFieldPrice] = row.Get(ShopifyPrice)
+			values[ImportFieldCompareAtPrice] = row.Get(ShopifyCompareAtPrice)
+
+			// Shopify lets stock go negative when overselling is allowed;
+			// there is nothing to hold in storage for those.
+			qty := row.Get(ShopifyInventoryQty)
+			if n, err := strconv.Atoi(qty); err == nil && n < 0 {
+				warn(row.Number, shopifyFieldNames[ImportFieldStock], "Negative stock was imported as zero.")
+				qty = ""
+			} else if err == nil && n > 0 {
+				stockedVariants++
+			}
+			values[ImportFieldStock] = qty
+		}
+
+		position, _ := strconv.Atoi(row.Get(ShopifyImagePosition))
+		addImage(row, row.Get(ShopifyImageSrc), position, row.Get(ShopifyImageAlt))
+
+		if src := row.Get(ShopifyVariantImage); src != "" && !seenImages[src] {
+			warn(row.Number, "Variant Image", "Variant image was added to the product gallery; images are not linked to variants.")
+			addImage(row, src, 0, "")
+		}
+
+		native = append(native, ProductImportRow{Number: row.Number, Values: values})
+	}
+
+	if stockedVariants > 1 {
+		warn(first.Number, shopifyFieldNames[ImportFieldStock], fmt.Sprintf("Stock of %d variants was added up into the product stock.", stockedVariants))
+	}
+
+	item, problems := BuildProductImportItem(native, defaultCoin)
+	for _, p := range problems {
+		if name, ok := shopifyFieldNames[p.Field]; ok {
+			p.Field = name
+		}
+	}
+	problems = append(problems, warnings...)
+
+	if item == nil {
+		return nil, problems
+	}
+
+	// Images without a position keep file order after the numbered ones.
+	sort.SliceStable(images, func(i, j int) bool {
+		if (images[i].position == 0) != (images[j].position == 0) {
+			return images[j].position == 0
+		}
+		return images[i].position < images[j].position
+	})
+	for _, image := range images {
+		item.Medias = append(item.Medias, &ProductImportMedia{Row: image.row, URL: image.url, Alt: image.alt})
+	}
+
+	return item, problems
+}
+
+type shopifyImage struct {
+	row      int
+	url      string
+	alt      string
+	position int
+}
+
+// shopifyVariantLabel joins the option values like Shopify titles variants,
+// "M / Red". Products without options get a single "Default Title" variant
+// in Shopify, which maps to no variant at all here.
+func shopifyVariantLabel(row ProductImportRow) string {
+	var values []string
+	for n := 1; n <= shopifyOptions; n++ {
+		if value := row.Get(ShopifyOptionValue(n)); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 1 && values[0] == shopifyDefaultVariant {
+		return ""
+	}
+	return strings.Join(values, " / ")
+}
+
+var (
+	htmlHiddenPattern = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)\s*>`)
+	htmlBreakPattern  = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/li|/h[1-6]|/tr|/ul|/ol)\b[^>]*>`)
+	htmlItemPattern   = regexp.MustCompile(`(?i)<\s*li\b[^>]*>`)
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// shopifyHTMLToText flattens the product body to plain text, keeping line
+// breaks between blocks and list items.
+func shopifyHTMLToText(body string) string {
+	text := htmlHiddenPattern.ReplaceAllString(body, "")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlItemPattern.ReplaceAllString(text, "- ")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func shortenImportText(text string, max int) (string, bool) {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text, false
+	}
+	return strings.TrimSpace(string(runes[:max])), true
+}
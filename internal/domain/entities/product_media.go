@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+type ProductMedia struct {
+	UUID        uuid.UUID
+	ProductUUID uuid.UUID
+	BlobKey     string
+	ContentType string
+	SizeBytes   int64
+	Alt         string
+	Position    int
+	Status      enums.MediaStatusType
+	Error       string
+	Width       *int
+	Height      *int
+	Derivatives []*ProductMediaDerivative
+	UpdatedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+type ProductMediaDerivative struct {
+	UUID      uuid.UUID
+	MediaUUID uuid.UUID
+	BlobKey   string
+	Format    string
+	Width     int
+	Height    int
+	SizeBytes int64
+	CreatedAt time.Time
+}
+
+// MediaExtensions maps the sniffed content types accepted for product media
+// to the extension used when building blob keys.
+var MediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+}
+
+func NewProductMedia(productUUID string, contentType string, sizeBytes int64, alt string) (*ProductMedia, error) {
+	ext, ok := MediaExtensions[contentType]
+	if !ok {
+		return nil, errors.New("ContentType is not supported.")
+	}
+
+	if sizeBytes <= 0 {
+		return nil, errors.New("SizeBytes must be greater than zero.")
+	}
+
+	if len(alt) > 250 {
+		return nil, errors.New("Alt cannot be longer than 250 characters.")
+	}
+
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductMedia{
+		UUID:        uuid.Nil,
+		ProductUUID: productUUIDParsed,
+		BlobKey:     "products/" + productUUIDParsed.String() + "/" + uuid.NewString() + ext,
+		ContentType: contentType,
+		SizeBytes:   sizeBytes,
+		Alt:         alt,
+		Status:      enums.MediaPending,
+	}, nil
+}
+
+func (m *ProductMedia) IsImage() bool {
+	return strings.HasPrefix(m.ContentType, "image/")
+}
+
+// DerivativeKey places resized copies next to the original, e.g.
+// "products/<uuid>/<file>_640w.jpg".
+func (m *ProductMedia) DerivativeKey(width int, format string) string {
+	base := m.BlobKey
+	if dot := strings.LastIndex(base, "."); dot > strings.LastIndex(base, "/") {
+		base = base[:dot]
+	}
+
+	ext := format
+	if format == "jpeg" {
+		ext = "jpg"
+	}
+
+	return base + "_" + strconv.Itoa(width) + "w." + ext
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+type ProductPrice struct {
+	UUID            uuid.UUID
+	ProductUUID     uuid.UUID
+	VariantUUID     *uuid.UUID
+	PriceListUUID   *uuid.UUID
+	Coin            enums.CoinType
+	Amount          int
+	CompareAtAmount *int
+	SaleAmount      *int
+	SaleStartsAt    *time.Time
+	SaleEndsAt      *time.Time
+	UpdatedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+type ResolvedPrice struct {
+	PriceUUID       uuid.UUID
+	PriceListUUID   *uuid.UUID
+	Coin            enums.CoinType
+	Amount          int
+	CompareAtAmount *int
+	OnSale          bool
+}
+
+type DisplayPrice struct {
+	Resolved               *ResolvedPrice
+	DisplayCoin            enums.CoinType
+	DisplayAmount          int
+	DisplayCompareAtAmount *int
+	Converted              bool
+}
+
+// PriceTarget is a product, or one of its variants, to price. VariantUUID
+// is uuid.Nil for the product itself.
+type PriceTarget struct {
+	ProductUUID uuid.UUID
+	VariantUUID uuid.UUID
+}
+
+func NewProductPrice(productUUID string, variantUUID string, priceListUUID string, coin string, amount int, compareAtAmount *int, saleAmount *int, saleStartsAt *time.Time, saleEndsAt *time.Time) (*ProductPrice, error) {
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	if amount < 0 {
+		return nil, errors.New("Amount cannot be negative.")
+	}
+
+	if compareAtAmount != nil && *compareAtAmount <= amount {
+		return nil, errors.New("CompareAtAmount must be greater than Amount.")
+	}
+
+	if saleAmount != nil {
+		if *saleAmount < 0 || *saleAmount >= amount {
+			return nil, errors.New("SaleAmount must be between 0 and Amount.")
+		}
+
+		if saleStartsAt == nil {
+			return nil, errors.New("SaleStartsAt cannot be null when SaleAmount is set.")
+		}
+	}
+
+	if saleStartsAt != nil && saleEndsAt != nil && !saleEndsAt.After(*saleStartsAt) {
+		return nil, errors.New("SaleEndsAt must be after SaleStartsAt.")
+	}
+
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variantPtr *uuid.UUID
+	if variantUUID != "" {
+		parsed, err := uuid.Parse(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		variantPtr = &parsed
+	}
+
+	var listPtr *uuid.UUID
+	if priceListUUID != "" {
+		parsed, err := uuid.Parse(priceListUUID)
+		if err != nil {
+			return nil, err
+		}
+		listPtr = &parsed
+	}
+
+	return &ProductPrice{
+		UUID:            uuid.Nil,
+		ProductUUID:     productUUIDParsed,
+		VariantUUID:     variantPtr,
+		PriceListUUID:   listPtr,
+		Coin:            coinType,
+		Amount:          amount,
+		CompareAtAmount: compareAtAmount,
+		SaleAmount:      saleAmount,
+		SaleStartsAt:    saleStartsAt,
+		SaleEndsAt:      saleEndsAt,
+	}, nil
+}
+
+func (p *ProductPrice) SaleActive(at time.Time) bool {
+	if p.SaleAmount == nil || p.SaleStartsAt == nil {
+		return false
+	}
+
+	if at.Before(*p.SaleStartsAt) {
+		return false
+	}
+
+	if p.SaleEndsAt != nil && !at.Before(*p.SaleEndsAt) {
+		return false
+	}
+
+	return true
+}
+
+// ResolvePrice picks the price that applies to a shopper. A matching price
+// list always wins over the base price (customer group lists before plain
+// currency lists, then by list priority), and inside the same list a
+// variant-specific price wins over the product-level one.
+func ResolvePrice(prices []*ProductPrice, lists map[uuid.UUID]*PriceList, variantUUID *uuid.UUID, coin enums.CoinType, customerGroup string, at time.Time) (*ResolvedPrice, error) {
+	var best *ProductPrice
+	bestRank := [3]int{-1, -1, -1}
+
+	for _, p := range prices {
+		if p.Coin != coin {
+			continue
+		}
+
+		variantRank := 0
+		if p.VariantUUID != nil {
+			if variantUUID == nil || *p.VariantUUID != *variantUUID {
+				continue
+			}
+			variantRank = 1
+		}
+
+		listRank, listPriority := 0, 0
+		if p.PriceListUUID != nil {
+			list, ok := lists[*p.PriceListUUID]
+			if !ok || !list.Active || list.Coin != coin {
+				continue
+			}
+
+			if list.CustomerGroup != nil {
+				if *list.CustomerGroup != customerGroup {
+					continue
+				}
+				listRank = 2
+			} else {
+				listRank = 1
+			}
+			listPriority = list.Priority
+		}
+
+		rank := [3]int{listRank, listPriority, variantRank}
+		if best == nil || rankGreater(rank, bestRank) {
+			best = p
+			bestRank = rank
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("price not found")
+	}
+
+	resolved := &ResolvedPrice{
+		PriceUUID:       best.UUID,
+		PriceListUUID:   best.PriceListUUID,
+		Coin:            best.Coin,
+		Amount:          best.Amount,
+		CompareAtAmount: best.CompareAtAmount,
+	}
+
+	if best.SaleActive(at) {
+		regular := best.Amount
+		resolved.Amount = *best.SaleAmount
+		resolved.OnSale = true
+		if resolved.CompareAtAmount == nil {
+			resolved.CompareAtAmount = &regular
+		}
+	}
+
+	return resolved, nil
+}
+
+func rankGreater(a [3]int, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func TestResolvePrice(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ptr := func(v int) *int { return &v }
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	group := "wholesale"
+
+	variant := uuid.New()
+	otherVariant := uuid.New()
+
+	currencyList := &PriceList{UUID: uuid.New(), Coin: enums.BRCoin, Priority: 1, Active: true}
+	urgentList := &PriceList{UUID: uuid.New(), Coin: enums.BRCoin, Priority: 5, Active: true}
+	groupList := &PriceList{UUID: uuid.New(), Coin: enums.BRCoin, CustomerGroup: &group, Priority: 0, Active: true}
+	inactiveList := &PriceList{UUID: uuid.New(), Coin: enums.BRCoin, Priority: 9, Active: false}
+	lists := map[uuid.UUID]*PriceList{
+		currencyList.UUID: currencyList,
+		urgentList.UUID:   urgentList,
+		groupList.UUID:    groupList,
+		inactiveList.UUID: inactiveList,
+	}
+
+	price := func(amount int, variantUUID *uuid.UUID, list *PriceList) *ProductPrice {
+		p := &ProductPrice{UUID: uuid.New(), VariantUUID: variantUUID, Coin: enums.BRCoin, Amount: amount}
+		if list != nil {
+			p.PriceListUUID = &list.UUID
+		}
+		return p
+	}
+
+	base := price(1000, nil, nil)
+	baseVariant := price(1100, &variant, nil)
+	baseOtherVariant := price(1200, &otherVariant, nil)
+	baseUSD := &ProductPrice{UUID: uuid.New(), Coin: enums.EUACoin, Amount: 300}
+	currency := price(900, nil, currencyList)
+	urgent := price(850, nil, urgentList)
+	wholesale := price(700, nil, groupList)
+	inactive := price(100, nil, inactiveList)
+	unknownList := &ProductPrice{UUID: uuid.New(), PriceListUUID: &variant, Coin: enums.BRCoin, Amount: 50}
+
+	onSale := price(1000, nil, nil)
+	onSale.CompareAtAmount = ptr(1500)
+	onSale.SaleAmount, onSale.SaleStartsAt = ptr(800), at(-time.Hour)
+
+	saleNoCompare := price(1000, nil, nil)
+	saleNoCompare.SaleAmount, saleNoCompare.SaleStartsAt, saleNoCompare.SaleEndsAt = ptr(800), at(-time.Hour), at(time.Hour)
+
+	saleEnded := price(1000, nil, nil)
+	saleEnded.SaleAmount, saleEnded.SaleStartsAt, saleEnded.SaleEndsAt = ptr(800), at(-2*time.Hour), at(0)
+
+	saleAhead := price(1000, nil, nil)
+	saleAhead.SaleAmount, saleAhead.SaleStartsAt = ptr(800), at(time.Hour)
+
+	tests := []struct {
+		name         string
+		prices       []*ProductPrice
+		variant      *uuid.UUID
+		coin         enums.CoinType
+		group        string
+		want         *ProductPrice
+		amount       int
+		compareAt    *int
+		wantOnSale   bool
+		wantNotFound bool
+	}{
+		{name: "base price", prices: []*ProductPrice{base, baseUSD}, coin: enums.BRCoin, want: base, amount: 1000},
+		{name: "other coin", prices: []*ProductPrice{base, baseUSD}, coin: enums.EUACoin, want: baseUSD, amount: 300},
+		{name: "no price in coin", prices: []*ProductPrice{base}, coin: enums.EURCoin, wantNotFound: true},
+		{name: "variant over product", prices: []*ProductPrice{base, baseVariant, baseOtherVariant}, variant: &variant, coin: enums.BRCoin, want: baseVariant, amount: 1100},
+		{name: "product for a variant without one", prices: []*ProductPrice{base, baseOtherVariant}, variant: &variant, coin: enums.BRCoin, want: base, amount: 1000},
+		{name: "variant prices skipped for the product", prices: []*ProductPrice{baseVariant}, coin: enums.BRCoin, wantNotFound: true},
+		{name: "list over base", prices: []*ProductPrice{base, baseVariant, currency}, variant: &variant, coin: enums.BRCoin, want: currency, amount: 900},
+		{name: "list priority", prices: []*ProductPrice{currency, urgent}, coin: enums.BRCoin, want: urgent, amount: 850},
+		{name: "group list for the group", prices: []*ProductPrice{urgent, wholesale}, coin: enums.BRCoin, group: group, want: wholesale, amount: 700},
+		{name: "group list for others", prices: []*ProductPrice{base, wholesale}, coin: enums.BRCoin, want: base, amount: 1000},
+		{name: "inactive list", prices: []*ProductPrice{base, inactive}, coin: enums.BRCoin, want: base, amount: 1000},
+		{name: "unknown list", prices: []*ProductPrice{base, unknownList}, coin: enums.BRCoin, want: base, amount: 1000},
+		{name: "sale keeps compare at", prices: []*ProductPrice{onSale}, coin: enums.BRCoin, want: onSale, amount: 800, compareAt: ptr(1500), wantOnSale: true},
+		{name: "sale compares to regular", prices: []*ProductPrice{saleNoCompare}, coin: enums.BRCoin, want: saleNoCompare, amount: 800, compareAt: ptr(1000), wantOnSale: true},
+		{name: "sale ended", prices: []*ProductPrice{saleEnded}, coin: enums.BRCoin, want: saleEnded, amount: 1000},
+		{name: "sale not started", prices: []*ProductPrice{saleAhead}, coin: enums.BRCoin, want: saleAhead, amount: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePrice(tt.prices, lists, tt.variant, tt.coin, tt.group, now)
+			if tt.wantNotFound {
+				if err == nil {
+					t.Fatalf("ResolvePrice() = %+v, want not found", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.PriceUUID != tt.want.UUID || got.Amount != tt.amount || got.OnSale != tt.wantOnSale {
+				t.Fatalf("ResolvePrice() = %s %d on sale %v, want %s %d on sale %v", got.PriceUUID, got.Amount, got.OnSale, tt.want.UUID, tt.amount, tt.wantOnSale)
+			}
+			if (got.CompareAtAmount == nil) != (tt.compareAt == nil) || (got.CompareAtAmount != nil && *got.CompareAtAmount != *tt.compareAt) {
+				t.Fatalf("CompareAtAmount = %v, want %v", got.CompareAtAmount, tt.compareAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxReviewPhotos = 5
+
+	// A shipment counts as a delivered order item once its status is this.
+	ShipmentDelivered = "delivered"
+)
+
+var (
+	ErrReviewExists        = errors.New("product already reviewed")
+	ErrReviewOwnModeration = errors.New("review moderated by its author")
+)
+
+// ProductReview is a customer review. It stays out of the storefront until
+// a merchant approves it; Verified marks reviews whose author received the
+// product, with ShippedUUID pointing at that delivery.
+type ProductReview struct {
+	UUID            uuid.UUID
+	ProductUUID     uuid.UUID
+	UserUUID        uuid.UUID
+	ShippedUUID     *uuid.UUID
+	Rating          int
+	Title           string
+	Body            string
+	Verified        bool
+	Status          enums.ReviewStatusType
+	RejectionReason string
+	ModeratedBy     *uuid.UUID
+	ModeratedAt     *time.Time
+	Photos          []*ProductReviewPhoto
+	UpdatedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+type ProductReviewPhoto struct {
+	UUID        uuid.UUID
+	ReviewUUID  uuid.UUID
+	BlobKey     string
+	ContentType string
+	SizeBytes   int64
+	Position    int
+	CreatedAt   time.Time
+}
+
+// ProductRatingSummary is the approved rating breakdown of a product.
+// Distribution is indexed by stars, Distribution[0] being unused.
+type ProductRatingSummary struct {
+	ProductUUID  uuid.UUID
+	Count        int
+	Average      float64
+	Distribution [6]int
+}
+
+func NewProductReview(productUUID string, userUUID string, rating int, title string, body string) (*ProductReview, error) {
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("Rating must be between 1 and 5.")
+	}
+
+	title = strings.TrimSpace(title)
+	if len([]rune(title)) > 150 {
+		return nil, errors.New("Title cannot be longer than 150 characters.")
+	}
+
+	body = strings.TrimSpace(body)
+	if len([]rune(body)) > 5000 {
+		return nil, errors.New("Body cannot be longer than 5000 characters.")
+	}
+
+	return &ProductReview{
+		UUID:        uuid.Nil,
+		ProductUUID: productUUIDParsed,
+		UserUUID:    userUUIDParsed,
+		Rating:      rating,
+		Title:       title,
+		Body:        body,
+		Status:      enums.ReviewPending,
+	}, nil
+}
+
+// AddPhoto keeps the photo under reviews/, out of the public media, until
+// the review is approved and the photo published.
+func (r *ProductReview) AddPhoto(contentType string, sizeBytes int64) (*ProductReviewPhoto, error) {
+	if len(r.Photos) >= MaxReviewPhotos {
+		return nil, errors.New("A review can have at most 5 photos.")
+	}
+
+	ext, ok := MediaExtensions[contentType]
+	if !ok || !strings.HasPrefix(contentType, "image/") {
+		return nil, errors.New("ContentType is not supported.")
+	}
+
+	if sizeBytes <= 0 {
+		return nil, errors.New("SizeBytes must be greater than zero.")
+	}
+
+	photo := &ProductReviewPhoto{
+		UUID:        uuid.Nil,
+		BlobKey:     "reviews/" + r.ProductUUID.String() + "/" + uuid.NewString() + ext,
+		ContentType: contentType,
+		SizeBytes:   sizeBytes,
+		Position:    len(r.Photos),
+	}
+	r.Photos = append(r.Photos, photo)
+	return photo, nil
+}
+
+// Published tells whether the photo sits under the public product media.
+func (p *ProductReviewPhoto) Published() bool {
+	return strings.HasPrefix(p.BlobKey, "products/")
+}
+
+// PublishPhotos moves the photos under the product media prefix so they are
+// served like the rest of the product gallery. It returns the new key of
+// every photo moved, keyed by the one it had before.
+func (r *ProductReview) PublishPhotos() map[string]string {
+	moved := make(map[string]string)
+	for _, p := range r.Photos {
+		if p.Published() {
+			continue
+		}
+		key := "products/" + r.ProductUUID.String() + "/reviews/" + path.Base(p.BlobKey)
+		moved[p.BlobKey] = key
+		p.BlobKey = key
+	}
+	return moved
+}
+
+// Moderate approves or rejects the review. A rejection needs a reason the
+// author can be told about, and nobody moderates their own review.
+func (r *ProductReview) Moderate(status enums.ReviewStatusType, reason string, moderatorUUID string) error {
+	moderator, err := uuid.Parse(moderatorUUID)
+	if err != nil {
+		return errors.New("Moderator UUID is invalid.")
+	}
+
+	if moderator == r.UserUUID {
+		return ErrReviewOwnModeration
+	}
+
+	reason = strings.TrimSpace(reason)
+	switch status {
+	case enums.ReviewApproved:
+		reason = ""
+	case enums.ReviewRejected:
+		if reason == "" {
+			return errors.New("A rejection needs a reason.")
+		}
+		if len([]rune(reason)) > 500 {
+			return errors.New("Reason cannot be longer than 500 characters.")
+		}
+	default:
+		return errors.New("Status must be 'approved' or 'rejected'.")
+	}
+
+	now := time.Now()
+	r.Status = status
+	r.RejectionReason = reason
+	r.ModeratedBy = &moderator
+	r.ModeratedAt = &now
+	return nil
+}
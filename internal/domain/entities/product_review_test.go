@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func TestProductReviewModerate(t *testing.T) {
+	author := uuid.NewString()
+	moderator := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		status    enums.ReviewStatusType
+		reason    string
+		moderator string
+		err       error
+	}{
+		{name: "approve", status: enums.ReviewApproved, moderator: moderator},
+		{name: "reject", status: enums.ReviewRejected, reason: "spam", moderator: moderator},
+		{name: "approve own review", status: enums.ReviewApproved, moderator: author, err: ErrReviewOwnModeration},
+		{name: "reject own review", status: enums.ReviewRejected, reason: "spam", moderator: author, err: ErrReviewOwnModeration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review, err := NewProductReview(uuid.NewString(), author, 5, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = review.Moderate(tt.status, tt.reason, tt.moderator)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Moderate() = %v, want %v", err, tt.err)
+			}
+			if err == nil && review.Status != tt.status {
+				t.Fatalf("Status = %s, want %s", review.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestProductReviewPublishPhotos(t *testing.T) {
+	review, err := NewProductReview(uuid.NewString(), uuid.NewString(), 5, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photo, err := review.AddPhoto("image/jpeg", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.Published() || strings.HasPrefix(photo.BlobKey, "products/") {
+		t.Fatalf("new photo is published at %s", photo.BlobKey)
+	}
+
+	pending := photo.BlobKey
+	moved := review.PublishPhotos()
+	if !photo.Published() || moved[pending] != photo.BlobKey {
+		t.Fatalf("PublishPhotos() = %v, photo at %s", moved, photo.BlobKey)
+	}
+	if want := "products/" + review.ProductUUID.String() + "/reviews/"; !strings.HasPrefix(photo.BlobKey, want) {
+		t.Fatalf("BlobKey = %s, want prefix %s", photo.BlobKey, want)
+	}
+
+	if moved := review.PublishPhotos(); len(moved) != 0 {
+		t.Fatalf("second PublishPhotos() = %v, want none", moved)
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type ProductSearchQuery struct {
+	WebsiteUUID  uuid.UUID
+	Text         string
+	Tags         []string
+	CategoryUUID *uuid.UUID
+	Coin         enums.CoinType
+	MinPrice     *int
+	MaxPrice     *int
+	InStock      *bool
+	Limit        int
+	Offset       int
+}
+
+type ProductSearchHit struct {
+	Product       *Products
+	Rank          float64
+	NameHighlight string
+	Snippet       string
+	Price         *int
+	InStock       bool
+}
+
+type FacetCount struct {
+	Value string
+	Label string
+	Count int
+}
+
+type ProductSearchFacets struct {
+	Tags       []FacetCount
+	Categories []FacetCount
+	PriceMin   *int
+	PriceMax   *int
+	InStock    int
+	OutOfStock int
+}
+
+type ProductSearchResult struct {
+	Query  *ProductSearchQuery
+	Hits   []*ProductSearchHit
+	Facets ProductSearchFacets
+	Total  int
+}
+
+func NewProductSearchQuery(websiteUUID string, text string, tags []string, categoryUUID string, coin string, minPrice *int, maxPrice *int, inStock *bool, limit int, offset int) (*ProductSearchQuery, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, errors.New("Website UUID is invalid.")
+	}
+
+	text = strings.TrimSpace(text)
+	if len(text) > 200 {
+		return nil, errors.New("Query cannot be longer than 200 characters.")
+	}
+
+	coinType := enums.BRCoin
+	if coin != "" {
+		coinType = enums.CoinType(coin)
+		if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+			return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+		}
+	}
+
+	if minPrice != nil && *minPrice < 0 {
+		return nil, errors.New("MinPrice cannot be negative.")
+	}
+
+	if minPrice != nil && maxPrice != nil && *maxPrice < *minPrice {
+		return nil, errors.New("MaxPrice must be greater than MinPrice.")
+	}
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	if offset < 0 {
+		return nil, errors.New("Offset cannot be negative.")
+	}
+
+	var categoryPtr *uuid.UUID
+	if categoryUUID != "" {
+		parsed, err := uuid.Parse(categoryUUID)
+		if err != nil {
+			return nil, err
+		}
+		categoryPtr = &parsed
+	}
+
+	var normalizedTags []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			normalizedTags = append(normalizedTags, tag)
+		}
+	}
+
+	return &ProductSearchQuery{
+		WebsiteUUID:  websiteUUIDParsed,
+		Text:         text,
+		Tags:         normalizedTags,
+		CategoryUUID: categoryPtr,
+		Coin:         coinType,
+		MinPrice:     minPrice,
+		MaxPrice:     maxPrice,
+		InStock:      inStock,
+		Limit:        limit,
+		Offset:       offset,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProductVariant struct {
+	UUID        uuid.UUID
+	ProductUUID uuid.UUID
+	SKU         string
+	GTIN        string
+	Label       string
+	Active      bool
+	UpdatedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func NewProductVariant(productUUID string, sku string, label string, gtin string, active bool) (*ProductVariant, error) {
+	if label == "" {
+		return nil, errors.New("Label cannot be null.")
+	}
+
+	if err := ValidateGTIN(gtin); err != nil {
+		return nil, err
+	}
+
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductVariant{
+		UUID:        uuid.Nil,
+		ProductUUID: productUUIDParsed,
+		SKU:         sku,
+		GTIN:        gtin,
+		Label:       label,
+		Active:      active,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MinSuggestionLength = 2
+
+type ProductSuggestion struct {
+	UUID uuid.UUID
+	Name string
+}
+
+type CategorySuggestion struct {
+	UUID uuid.UUID
+	Name string
+	Slug string
+}
+
+// SearchQuery counts the searches for Query on one website.
+type SearchQuery struct {
+	WebsiteUUID    uuid.UUID
+	Query          string
+	Searches       int
+	LastResults    int
+	LastSearchedAt time.Time
+}
+
+type SearchSuggestions struct {
+	Query      string
+	Products   []*ProductSuggestion
+	Categories []*CategorySuggestion
+	Queries    []*SearchQuery
+}
+
+// NormalizeSearchQuery lowercases and collapses whitespace so "Camiseta  "
+// and "camiseta" count as the same search.
+func NormalizeSearchQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if runes := []rune(query); len(runes) > 200 {
+		query = string(runes[:200])
+	}
+	return query
+}
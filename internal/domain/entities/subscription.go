@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoActivePlan       = errors.New("no active plan")
+	ErrQuotaExceeded      = errors.New("plan limit reached")
+	ErrSubscriptionExists = errors.New("subscription already exists")
+	ErrInvoiceNotOpen     = errors.New("invoice already paid or void")
+)
+
+// A renewal that fails is retried after each of these delays, counted from
+// the previous attempt. The owner keeps using the plan for
+// SubscriptionGracePeriod after the first failure; past it the
+// subscription and the owner's websites are suspended until paid.
+var InvoiceRetryDelays = []time.Duration{
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	5 * 24 * time.Hour,
+}
+
+const SubscriptionGracePeriod = 14 * 24 * time.Hour
+
+// Subscription puts an owner, a user or an organization, on a plan. It is
+// billed every Cycle for the period ending at CurrentPeriodEnd; Credit is
+// what downgrades left over, taken off the next invoices.
+type Subscription struct {
+	UUID               uuid.UUID
+	OwnerUUID          uuid.UUID
+	OwnerType          enums.OwnerType
+	PlanUUID           uuid.UUID
+	Status             enums.SubscriptionStatusType
+	Cycle              enums.BillingCycleType
+	PaymentMethod      string
+	TrialEndsAt        *time.Time
+	CurrentPeriodStart *time.Time
+	CurrentPeriodEnd   *time.Time
+	CancelAtPeriodEnd  bool
+	PastDueSince       *time.Time
+	Credit             int
+	UpdatedAt          *time.Time
+	CreatedAt          time.Time
+}
+
+// SubscriptionInvoice is one charge of a subscription. PlanUUID and Cycle
+// are what the invoice pays for, which for a plan change is the new plan.
+type SubscriptionInvoice struct {
+	UUID              uuid.UUID
+	SubscriptionUUID  uuid.UUID
+	OwnerUUID         uuid.UUID
+	PlanUUID          uuid.UUID
+	Reason            enums.InvoiceReasonType
+	Cycle             enums.BillingCycleType
+	Coin              enums.CoinType
+	Amount            int
+	CreditApplied     int
+	Status            enums.InvoiceStatusType
+	Attempts          int
+	NextAttemptAt     *time.Time
+	PeriodStart       time.Time
+	PeriodEnd         time.Time
+	ProviderReference string
+	FailureReason     string
+	PaidAt            *time.Time
+	UpdatedAt         *time.Time
+	CreatedAt         time.Time
+}
+
+// Quota is the room an owner has for one kind of resource. Limit comes
+// from the owner's plan; zero means unlimited.
+type Quota struct {
+	OwnerUUID uuid.UUID
+	Limit     int
+}
+
+// Allows reports whether one more resource fits next to used ones.
+func (q Quota) Allows(used int) bool {
+	return q.Limit == 0 || used < q.Limit
+}
+
+// PlanUsage is what an owner consumes against its plan's limits.
+type PlanUsage struct {
+	OwnerUUID uuid.UUID
+	Plan      *VerkoupePlan
+	Websites  int
+	Routers   int
+	Products  int
+}
+
+func NewSubscription(ownerUUID string, ownerType string, planUUID string, cycle string, paymentMethod string) (*Subscription, error) {
+	otype := enums.OwnerType(ownerType)
+	if otype != enums.UserOwner && otype != enums.OrganizationOwner {
+		return nil, errors.New("OwnerType must be 'User' or 'Organization'.")
+	}
+
+	ownerUUIDParsed, err := uuid.Parse(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	planUUIDParsed, err := uuid.Parse(planUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cycleType, err := ParseBillingCycle(cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		UUID:          uuid.Nil,
+		OwnerUUID:     ownerUUIDParsed,
+		OwnerType:     otype,
+		PlanUUID:      planUUIDParsed,
+		Status:        enums.SubscriptionIncomplete,
+		Cycle:         cycleType,
+		PaymentMethod: paymentMethod,
+	}, nil
+}
+
+// ParseBillingCycle defaults to monthly.
+func ParseBillingCycle(cycle string) (enums.BillingCycleType, error) {
+	if cycle == "" {
+		return enums.MonthlyCycle, nil
+	}
+
+	cycleType := enums.BillingCycleType(cycle)
+	if cycleType != enums.MonthlyCycle && cycleType != enums.AnnualCycle {
+		return "", errors.New("Cycle must be 'monthly' or 'annual'.")
+	}
+
+	return cycleType, nil
+}
+
+// CycleEnd is when a period of cycle starting at start ends. Periods
+// starting on a day the next month lacks end on its last day, so one
+// starting on January 31 ends on February 28 rather than in March.
+func CycleEnd(start time.Time, cycle enums.BillingCycleType) time.Time {
+	months := 1
+	if cycle == enums.AnnualCycle {
+		months = 12
+	}
+
+	year, month, day := start.Date()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, start.Location()).Day()
+	hour, minute, second := start.Clock()
+
+	return time.Date(year, month+time.Month(months), min(day, lastDay), hour, minute, second, start.Nanosecond(), start.Location())
+}
+
+// Active reports whether the owner can use its plan: during the trial,
+// while paid up and through the grace period of a failed renewal.
+func (s *Subscription) Active() bool {
+	return s.Status == enums.SubscriptionTrialing || s.Status == enums.SubscriptionActive || s.Status == enums.SubscriptionPastDue
+}
+
+// Unused is the share of price left for the rest of the current period.
+func (s *Subscription) Unused(price int, now time.Time) int {
+	if s.CurrentPeriodStart == nil || s.CurrentPeriodEnd == nil || !now.Before(*s.CurrentPeriodEnd) {
+		return 0
+	}
+
+	total := s.CurrentPeriodEnd.Sub(*s.CurrentPeriodStart)
+	left := s.CurrentPeriodEnd.Sub(now)
+	if total <= 0 || left >= total {
+		return price
+	}
+
+	return int(int64(price) * int64(left/time.Second) / int64(total/time.Second))
+}
+
+// NextInvoiceAttempt is when an invoice that failed attempts times is
+// tried again, or nil once the retries are used up.
+func NextInvoiceAttempt(attempts int, now time.Time) *time.Time {
+	if attempts < 1 || attempts > len(InvoiceRetryDelays) {
+		return nil
+	}
+
+	next := now.Add(InvoiceRetryDelays[attempts-1])
+	return &next
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+func TestSubscriptionUnused(t *testing.T) {
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := CycleEnd(start, enums.MonthlyCycle)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	tests := []struct {
+		name  string
+		start *time.Time
+		end   *time.Time
+		price int
+		now   time.Time
+		want  int
+	}{
+		{name: "no period", price: 3000, now: at(0), want: 0},
+		{name: "before the period", start: &start, end: &end, price: 3000, now: at(-time.Hour), want: 3000},
+		{name: "period start", start: &start, end: &end, price: 3000, now: start, want: 3000},
+		{name: "a third in", start: &start, end: &end, price: 3000, now: at(10 * 24 * time.Hour), want: 2000},
+		{name: "halfway", start: &start, end: &end, price: 3000, now: at(15 * 24 * time.Hour), want: 1500},
+		{name: "rounds down", start: &start, end: &end, price: 1000, now: at(time.Hour), want: 998},
+		{name: "last second", start: &start, end: &end, price: 3000, now: end.Add(-time.Second), want: 0},
+		{name: "period end", start: &start, end: &end, price: 3000, now: end, want: 0},
+		{name: "after the period", start: &start, end: &end, price: 3000, now: end.Add(time.Hour), want: 0},
+		{name: "empty period", start: &end, end: &end, price: 3000, now: start, want: 3000},
+		{name: "free plan", start: &start, end: &end, price: 0, now: at(time.Hour), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Subscription{CurrentPeriodStart: tt.start, CurrentPeriodEnd: tt.end}
+			if got := s.Unused(tt.price, tt.now); got != tt.want {
+				t.Fatalf("Unused(%d) = %d, want %d", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCycleEnd(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		cycle enums.BillingCycleType
+		want  time.Time
+	}{
+		{name: "monthly", start: time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), cycle: enums.MonthlyCycle, want: time.Date(2026, 5, 15, 9, 0, 0, 0, time.UTC)},
+		{name: "annual", start: time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), cycle: enums.AnnualCycle, want: time.Date(2027, 4, 15, 9, 0, 0, 0, time.UTC)},
+		{name: "month end clamps", start: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), cycle: enums.MonthlyCycle, want: time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "december rolls over", start: time.Date(2026, 12, 31, 9, 0, 0, 0, time.UTC), cycle: enums.MonthlyCycle, want: time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC)},
+		{name: "leap day annual", start: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), cycle: enums.AnnualCycle, want: time.Date(2029, 2, 28, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CycleEnd(tt.start, tt.cycle); !got.Equal(tt.want) {
+				t.Fatalf("CycleEnd() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
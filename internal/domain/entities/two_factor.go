@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// TwoFactorChallengeTTL is how long after the password the code of a
+	// two-factor sign in may be sent.
+	TwoFactorChallengeTTL = 5 * time.Minute
+
+	// RecoveryCodeCount recovery codes are handed out when two-factor
+	// authentication is enabled, each usable once instead of a code.
+	RecoveryCodeCount = 10
+
+	// TrustedDeviceTTL is how long a remembered device skips the code.
+	TrustedDeviceTTL = 30 * 24 * time.Hour
+
+	// TwoFactorIssuer names the account in authenticator apps when the
+	// website of the user has no label.
+	TwoFactorIssuer = "Verkoupe"
+
+	recoveryCodeLength    = 10
+	recoveryCodeAlphabet  = "abcdefghjkmnpqrstuvwxyz23456789"
+	trustedDeviceNameSize = 100
+)
+
+var (
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication not enabled")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor enrollment not started")
+	ErrTwoFactorCode        = errors.New("invalid two-factor code")
+	ErrTwoFactorRequired    = errors.New("two-factor authentication required")
+)
+
+// TwoFactor is the TOTP enrollment of a user. Secret is sealed while
+// stored. EnabledAt stays nil until the user confirms a first code, and
+// LastStep is the time step of the last accepted code, so a code only
+// works once.
+type TwoFactor struct {
+	UserUUID  uuid.UUID
+	Secret    string
+	EnabledAt *time.Time
+	LastStep  int64
+	UpdatedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (t *TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// TwoFactorEnrollment is what a user needs to add the account to an
+// authenticator app: the secret, its otpauth URI and the URI as a PNG QR
+// code.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+	QR     []byte
+}
+
+// TrustedDevice is a device that skips the two-factor code until
+// ExpiresAt. Like user tokens, only the hash of its token is stored.
+type TrustedDevice struct {
+	UUID       uuid.UUID
+	UserUUID   uuid.UUID
+	Name       string
+	TokenHash  string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// LoginResult is a sign in that passed the password. When the user has
+// two-factor authentication on, Challenge is set instead and the sign in
+// finishes by sending it back with a code. DeviceToken is set when a
+// device was just remembered.
+type LoginResult struct {
+	User               *User
+	Challenge          string
+	ChallengeExpiresAt time.Time
+	DeviceToken        string
+}
+
+// NewTrustedDevice returns the device to store and the token to hand to
+// it.
+func NewTrustedDevice(userUUID uuid.UUID, name string) (*TrustedDevice, string, error) {
+	secret, err := NewSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > trustedDeviceNameSize {
+		name = string(runes[:trustedDeviceNameSize])
+	}
+
+	return &TrustedDevice{
+		UUID:      uuid.Nil,
+		UserUUID:  userUUID,
+		Name:      name,
+		TokenHash: HashSecretToken(secret),
+		ExpiresAt: time.Now().Add(TrustedDeviceTTL),
+	}, secret, nil
+}
+
+// NewRecoveryCodes returns RecoveryCodeCount codes formatted as
+// xxxxx-xxxxx, to show once, and the hashes to store.
+func NewRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		var code strings.Builder
+		for j, c := range b {
+			if j == recoveryCodeLength/2 {
+				code.WriteByte('-')
+			}
+			code.WriteByte(recoveryCodeAlphabet[int(c)%len(recoveryCodeAlphabet)])
+		}
+
+		codes[i] = code.String()
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode ignores case, dashes and spaces, as users retype codes
+// from paper.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
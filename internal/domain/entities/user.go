@@ -1,12 +1,18 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ViitoJooj/go-sdk/validate"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrPasswordTooWeak    = errors.New("password too weak")
+)
+
 type User struct {
 	UUID        uuid.UUID
 	WebSiteUUID uuid.UUID
@@ -19,8 +25,29 @@ type User struct {
 	GithubOauth bool
 	GoogleOauth bool
 	AppleOauth  bool
-	UpdatedAt   *time.Time
-	CreatedAt   time.Time
+	// EmailVerifiedAt is nil until the user follows the link mailed on
+	// registration.
+	EmailVerifiedAt *time.Time
+	// SessionsRevokedAt rejects access tokens issued before it.
+	SessionsRevokedAt *time.Time
+	// FailedLogins counts failed sign ins since the last success; see
+	// LoginWait.
+	FailedLogins      int
+	LastFailedLoginAt *time.Time
+	LockedUntil       *time.Time
+	UpdatedAt         *time.Time
+	CreatedAt         time.Time
+}
+
+// SessionValid tells whether an access token issued at issuedAt is still
+// accepted for the user.
+func (u *User) SessionValid(issuedAt time.Time) bool {
+	return u.SessionsRevokedAt == nil || !issuedAt.Before(*u.SessionsRevokedAt)
+}
+
+// ValidatePassword checks a plain password before it is hashed.
+func ValidatePassword(password string) error {
+	return validate.Password(password)
 }
 
 func NewUser(websiteUUID string, imageURL string, name string, email string, role string, password string, cpf string, github bool, google bool, apple bool) (*User, error) {
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const (
+	// EmailVerificationTTL is how long a verification link works.
+	EmailVerificationTTL = 24 * time.Hour
+	// EmailVerificationCooldown is the least time between two resends, and
+	// EmailVerificationHourlyLimit how many links a user gets per hour.
+	EmailVerificationCooldown    = time.Minute
+	EmailVerificationHourlyLimit = 5
+
+	// PasswordResetTTL is how long a reset link works. Resets are
+	// throttled like verification links.
+	PasswordResetTTL         = 30 * time.Minute
+	PasswordResetCooldown    = time.Minute
+	PasswordResetHourlyLimit = 5
+)
+
+var (
+	ErrUserTokenInvalid = errors.New("invalid verification code")
+	ErrUserTokenExpired = errors.New("verification code expired")
+	ErrEmailNotVerified = errors.New("email not verified")
+)
+
+// UserToken is a single-use secret mailed to a user. The token itself only
+// travels in the email; TokenHash is what is stored.
+type UserToken struct {
+	UUID      uuid.UUID
+	UserUUID  uuid.UUID
+	Purpose   enums.UserTokenPurposeType
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewUserToken returns the token to store and the secret to mail.
+func NewUserToken(userUUID uuid.UUID, purpose enums.UserTokenPurposeType, ttl time.Duration) (*UserToken, string, error) {
+	secret, err := NewSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &UserToken{
+		UUID:      uuid.Nil,
+		UserUUID:  userUUID,
+		Purpose:   purpose,
+		TokenHash: HashSecretToken(secret),
+		ExpiresAt: time.Now().Add(ttl),
+	}, secret, nil
+}
+
+// Usable reports why the token can no longer be used, if it cannot.
+func (t *UserToken) Usable(now time.Time) error {
+	if t.UsedAt != nil {
+		return ErrUserTokenInvalid
+	}
+
+	if !now.Before(t.ExpiresAt) {
+		return ErrUserTokenExpired
+	}
+
+	return nil
+}
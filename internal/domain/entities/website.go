@@ -8,19 +8,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// Website is a storefront. SuspendedAt is set while its owner's
+// subscription is suspended for not paying. RequireEmailVerification
+// keeps its users from signing in before verifying their email, and
+// RequireTwoFactor keeps roles that may write or delete on it from doing
+// so without two-factor authentication.
 type Website struct {
-	UUID        uuid.UUID
-	OwnerUUID   uuid.UUID
-	OwnerType   enums.OwnerType
-	Label       string
-	URL         string
-	WriteIn     enums.LanguageType
-	Description string
-	UpdatedAt   *time.Time
-	CreatedAt   time.Time
+	UUID                     uuid.UUID
+	OwnerUUID                uuid.UUID
+	OwnerType                enums.OwnerType
+	Label                    string
+	URL                      string
+	WriteIn                  enums.LanguageType
+	Description              string
+	BaseCoin                 enums.CoinType
+	SuspendedAt              *time.Time
+	RequireEmailVerification bool
+	RequireTwoFactor         bool
+	UpdatedAt                *time.Time
+	CreatedAt                time.Time
 }
 
-func NewWebsite(ownerUUID string, ownerType string, label string, url string, writeIn string, description string) (*Website, error) {
+func NewWebsite(ownerUUID string, ownerType string, label string, url string, writeIn string, description string, baseCoin string) (*Website, error) {
 	otype := enums.OwnerType(ownerType)
 	if otype != enums.UserOwner && otype != enums.OrganizationOwner {
 		return nil, errors.New("OwnerType must be 'User' or 'Organization'.")
@@ -35,6 +44,15 @@ func NewWebsite(ownerUUID string, ownerType string, label string, url string, wr
 		return nil, errors.New("WriteIn must be 'Component', 'React' or 'Svelte'.")
 	}
 
+	if baseCoin == "" {
+		baseCoin = string(enums.BRCoin)
+	}
+
+	coinType := enums.CoinType(baseCoin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("BaseCoin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
 	ownerUUIDParsed, err := uuid.Parse(ownerUUID)
 	if err != nil {
 		return nil, err
@@ -48,5 +66,6 @@ func NewWebsite(ownerUUID string, ownerType string, label string, url string, wr
 		URL:         url,
 		WriteIn:     lang,
 		Description: description,
+		BaseCoin:    coinType,
 	}, nil
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+const MaxWishlistsPerUser = 20
+
+var (
+	ErrWishlistExists     = errors.New("wishlist already exists")
+	ErrWishlistItemExists = errors.New("product already in wishlist")
+)
+
+// Wishlist is a named list of products a shopper saved on a website. It is
+// private until ShareToken is set, after which anyone with the token can
+// read it.
+type Wishlist struct {
+	UUID        uuid.UUID
+	UserUUID    uuid.UUID
+	WebsiteUUID uuid.UUID
+	Name        string
+	ShareToken  string
+	ItemCount   int
+	UpdatedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// WishlistItem keeps the stock and sale state last seen by the alert job.
+type WishlistItem struct {
+	UUID         uuid.UUID
+	WishlistUUID uuid.UUID
+	ProductUUID  uuid.UUID
+	VariantUUID  *uuid.UUID
+	InStock      bool
+	OnSale       bool
+	CreatedAt    time.Time
+}
+
+// WishlistAlert records that a wished product came back in stock or went
+// on sale.
+type WishlistAlert struct {
+	UUID         uuid.UUID
+	Type         enums.WishlistAlertType
+	WishlistUUID uuid.UUID
+	ItemUUID     uuid.UUID
+	UserUUID     uuid.UUID
+	WebsiteUUID  uuid.UUID
+	ProductUUID  uuid.UUID
+	VariantUUID  *uuid.UUID
+	CreatedAt    time.Time
+}
+
+func NewWishlist(userUUID string, websiteUUID string, name string) (*Wishlist, error) {
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist := &Wishlist{
+		UUID:        uuid.Nil,
+		UserUUID:    userUUIDParsed,
+		WebsiteUUID: websiteUUIDParsed,
+	}
+	if err := wishlist.Rename(name); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+func (w *Wishlist) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("Name cannot be null.")
+	}
+
+	if len([]rune(name)) > 100 {
+		return errors.New("Name cannot be longer than 100 characters.")
+	}
+
+	w.Name = name
+	return nil
+}
+
+func (w *Wishlist) Shared() bool {
+	return w.ShareToken != ""
+}
+
+func NewWishlistItem(wishlistUUID uuid.UUID, productUUID string, variantUUID string) (*WishlistItem, error) {
+	productUUIDParsed, err := uuid.Parse(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variantPtr *uuid.UUID
+	if variantUUID != "" {
+		parsed, err := uuid.Parse(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		variantPtr = &parsed
+	}
+
+	return &WishlistItem{
+		UUID:         uuid.Nil,
+		WishlistUUID: wishlistUUID,
+		ProductUUID:  productUUIDParsed,
+		VariantUUID:  variantPtr,
+	}, nil
+}
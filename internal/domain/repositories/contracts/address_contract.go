@@ -8,9 +8,9 @@ type AddressContract interface {
 	CreateAddress(address *domain.AddressBR) (*domain.AddressBR, error)
 	FindAddressByUUID(uuid string) (*domain.AddressBR, error)
 	FindDefaultAddressByOwner(ownerUUID string, websiteUUID string) (*domain.AddressBR, error)
-	GetAddressesFromOwner(ownerUUID string, websiteUUID string) ([]*domain.AddressBR, error)
-	GetAddressesFromWebsite(websiteUUID string) ([]*domain.AddressBR, error)
-	GetAddresses() ([]*domain.AddressBR, error)
+	GetAddressesFromOwner(ownerUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error)
+	GetAddressesFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error)
+	GetAddresses(query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error)
 	UpdateAddressByUUID(uuid string) error
 	DeleteAddressByUUID(uuid string) error
 	DeleteAddressesByUUIDS(uuid []string) error
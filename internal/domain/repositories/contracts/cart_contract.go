@@ -0,0 +1,24 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type CartContract interface {
+	CreateCart(cart *domain.Cart) (*domain.Cart, error)
+	FindCartByUUID(uuid string) (*domain.Cart, error)
+	FindOpenCart(websiteUUID string, userUUID string) (*domain.Cart, error)
+	SaveCartItem(item *domain.CartItem) (*domain.CartItem, error)
+	UpdateCartItemQuantity(cartUUID string, itemUUID string, quantity int) error
+	RemoveCartItem(cartUUID string, itemUUID string) error
+	MarkCartRestored(cartUUID string) error
+	GetCartsDueReminder(ctx context.Context, limit int) ([]*domain.Cart, error)
+	ClaimCartReminder(cartUUID uuid.UUID, sent int, cupomUUID *uuid.UUID) (bool, error)
+	FindCartRecoverySettings(websiteUUID string) (*domain.CartRecoverySettings, error)
+	SaveCartRecoverySettings(settings *domain.CartRecoverySettings) (*domain.CartRecoverySettings, error)
+	GetCartRecoveryTotals(websiteUUID string, start time.Time, end time.Time) ([]*domain.CartRecoveryTotal, error)
+}
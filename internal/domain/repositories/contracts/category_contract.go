@@ -0,0 +1,18 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type CategoryContract interface {
+	CreateCategory(category *domain.Category) (*domain.Category, error)
+	FindCategoryByUUID(uuid string) (*domain.Category, error)
+	FindCategoryBySlugAndWebsite(slug string, websiteUUID string) (*domain.Category, error)
+	GetCategoriesFromWebsite(websiteUUID string) ([]*domain.Category, error)
+	CountCategoryChildren(uuid string) (int, error)
+	MoveCategory(category *domain.Category, parent *domain.Category, position int) error
+	DeleteCategoryByUUID(uuid string) error
+	AssignProductToCategory(productCategory *domain.ProductCategory) error
+	RemoveProductFromCategory(productUUID string, categoryUUID string) error
+	GetProductsFromCategory(category *domain.Category, withDescendants bool, query *domain.ListQuery) (*domain.Page[*domain.CategoryProduct], error)
+}
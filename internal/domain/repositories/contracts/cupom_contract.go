@@ -8,8 +8,10 @@ type CupomContract interface {
 	CreateCupom(cupom *domain.Cupons) (*domain.Cupons, error)
 	FindCupomByUUID(uuid string) (*domain.Cupons, error)
 	FindCupomByLabel(label string) (*domain.Cupons, error)
-	GetCuponsFromTag(tagUUID string) ([]*domain.Cupons, error)
-	GetCupons() ([]*domain.Cupons, error)
+	GetCuponsFromTag(tagUUID string, query *domain.ListQuery) (*domain.Page[*domain.Cupons], error)
+	GetCupons(query *domain.ListQuery) (*domain.Page[*domain.Cupons], error)
+	UseCupom(uuid string) error
+	ReleaseCupom(uuid string) error
 	UpdateCupomByUUID(uuid string) error
 	DeleteCupomByUUID(uuid string) error
 	DeleteCupomsByUUIDS(uuid []string) error
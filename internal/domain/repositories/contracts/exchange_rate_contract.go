@@ -0,0 +1,28 @@
+package contracts
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+type ExchangeRateContract interface {
+	UpsertExchangeRate(rate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	FindLatestExchangeRate(baseCoin string, quoteCoin string, at time.Time) (*domain.ExchangeRate, error)
+	GetExchangeRatesByDate(rateDate time.Time, query *domain.ListQuery) (*domain.Page[*domain.ExchangeRate], error)
+}
+
+type CurrencyRoundingContract interface {
+	UpsertCurrencyRounding(rounding *domain.CurrencyRounding) (*domain.CurrencyRounding, error)
+	FindCurrencyRounding(websiteUUID string, coin string) (*domain.CurrencyRounding, error)
+	GetCurrencyRoundingsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.CurrencyRounding], error)
+}
+
+// ExchangeRateSourceContract is implemented by anything able to publish the
+// daily rates, from a static file to a remote API.
+type ExchangeRateSourceContract interface {
+	Name() string
+	FetchRates(baseCoin enums.CoinType, rateDate time.Time) (map[enums.CoinType]*big.Rat, error)
+}
@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type GiftCardContract interface {
+	CreateGiftCard(card *domain.GiftCard) (*domain.GiftCard, error)
+	FindGiftCardByUUID(uuid string) (*domain.GiftCard, error)
+	FindGiftCardByCode(websiteUUID string, codeHash string) (*domain.GiftCard, error)
+	GetGiftCards(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCard], error)
+	GetGiftCardsByUser(websiteUUID string, userUUID string) ([]*domain.GiftCard, error)
+	CountOrderGiftCards(orderUUID string, productUUID string) (int, error)
+	GetGiftCardTransactions(giftCardUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCardTransaction], error)
+	RedeemGiftCards(orderUUID uuid.UUID, applications []*domain.GiftCardApplication) ([]*domain.GiftCardTransaction, error)
+	ReleaseGiftCards(orderUUID string) ([]*domain.GiftCardTransaction, error)
+	ExpireGiftCards(ctx context.Context) (int64, error)
+}
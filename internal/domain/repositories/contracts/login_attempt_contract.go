@@ -0,0 +1,13 @@
+package contracts
+
+import (
+	"time"
+)
+
+type LoginAttemptContract interface {
+	RecordIPLoginFailure(ip string) error
+	CountIPLoginFailures(ip string, since time.Time) (int, *time.Time, error)
+	RecordUserLoginFailure(userUUID string, lockAfter int, lockFor time.Duration) (int, *time.Time, error)
+	ResetUserLoginFailures(userUUID string) error
+	PruneLoginFailures(before time.Time) (int, error)
+}
@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+type LoyaltyContract interface {
+	FindLoyaltyProgram(websiteUUID string) (*domain.LoyaltyProgram, error)
+	SaveLoyaltyProgram(program *domain.LoyaltyProgram) (*domain.LoyaltyProgram, error)
+	AddLoyaltyPoints(transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error)
+	SpendLoyaltyPoints(transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error)
+	ReverseLoyaltyEarning(orderUUID string, refundedTo int, refundAmount int) (*domain.LoyaltyTransaction, error)
+	FindLoyaltyTransactionByOrder(orderUUID string, transactionType enums.LoyaltyTransactionType) (*domain.LoyaltyTransaction, error)
+	CountLoyaltyEarnings(websiteUUID string, userUUID string) (int, error)
+	GetLoyaltyBalance(websiteUUID string, userUUID string) (*domain.LoyaltyBalance, error)
+	GetLoyaltyTransactions(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.LoyaltyTransaction], error)
+	ExpireLoyaltyPoints(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,13 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+// MailerContract is implemented by anything able to deliver an email, from
+// an SMTP relay to the log mailer used in development.
+type MailerContract interface {
+	Send(ctx context.Context, email domain.Email) error
+}
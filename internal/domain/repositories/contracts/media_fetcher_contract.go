@@ -0,0 +1,9 @@
+package contracts
+
+import "context"
+
+// MediaFetcherContract downloads files referenced by URL in imported
+// catalogs, refusing anything larger than maxBytes.
+type MediaFetcherContract interface {
+	Fetch(ctx context.Context, url string, maxBytes int64) ([]byte, error)
+}
@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type OrderContract interface {
+	CreateOrder(order *domain.Order) (*domain.Order, error)
+	FindOrderByUUID(uuid string) (*domain.Order, error)
+	GetOrdersByUser(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error)
+	GetOrders(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error)
+	UpdateOrderTotals(order *domain.Order) error
+	MarkOrderPaid(order *domain.Order, hooks []string) error
+	MarkOrderFailed(order *domain.Order) error
+	RefundOrder(order *domain.Order, amount int, providerAmount int, hooks []string, runAt time.Time) error
+	ReleaseOrderEvents(orderUUID string, refundedTo int) error
+	ClaimOrderEvents(orderUUID string, limit int) ([]*domain.OrderEvent, error)
+	UpdateOrderEvent(event *domain.OrderEvent) error
+}
@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+type OrganizationMemberContract interface {
+	AddOrganizationMember(member *domain.OrganizationMember) (*domain.OrganizationMember, error)
+	FindOrganizationMember(organizationUUID string, userUUID string) (*domain.OrganizationMember, error)
+	GetOrganizationMembers(organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationMember], error)
+	UpdateOrganizationMember(member *domain.OrganizationMember) error
+	RemoveOrganizationMember(organizationUUID string, userUUID string) error
+	TransferOrganizationOwnership(organizationUUID string, fromUserUUID string, toUserUUID string) error
+	CreateOrganizationInvitation(invitation *domain.OrganizationInvitation) (*domain.OrganizationInvitation, error)
+	FindOrganizationInvitationByUUID(uuid string) (*domain.OrganizationInvitation, error)
+	FindOrganizationInvitationByToken(tokenHash string) (*domain.OrganizationInvitation, error)
+	GetOrganizationInvitations(organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationInvitation], error)
+	AcceptOrganizationInvitation(invitation *domain.OrganizationInvitation, member *domain.OrganizationMember) (*domain.OrganizationMember, error)
+	AnswerOrganizationInvitation(uuid string, status enums.InvitationStatusType) error
+}
@@ -0,0 +1,16 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+// PaymentProviderContract is implemented by anything able to charge a
+// stored payment method, from a card gateway to the fake provider used in
+// development. A refund answers Paid when the money went back.
+type PaymentProviderContract interface {
+	Name() string
+	Charge(ctx context.Context, charge domain.PaymentCharge) (*domain.PaymentResult, error)
+	Refund(ctx context.Context, refund domain.PaymentRefund) (*domain.PaymentResult, error)
+}
@@ -8,9 +8,9 @@ type PhoneContract interface {
 	CreatePhone(phone *domain.Phone) (*domain.Phone, error)
 	FindPhoneByUUID(uuid string) (*domain.Phone, error)
 	FindDefaultPhoneByOwner(ownerUUID string, websiteUUID string) (*domain.Phone, error)
-	GetPhonesFromOwner(ownerUUID string, websiteUUID string) ([]*domain.Phone, error)
-	GetPhonesFromWebsite(websiteUUID string) ([]*domain.Phone, error)
-	GetPhones() ([]*domain.Phone, error)
+	GetPhonesFromOwner(ownerUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Phone], error)
+	GetPhonesFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Phone], error)
+	GetPhones(query *domain.ListQuery) (*domain.Page[*domain.Phone], error)
 	UpdatePhoneByUUID(uuid string) error
 	DeletePhoneByUUID(uuid string) error
 	DeletePhonesByUUIDS(uuid []string) error
@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type PlatformFeeContract interface {
+	RecordPlatformFee(fee *domain.PlatformFee) (*domain.PlatformFee, error)
+	RefundPlatformFee(orderUUID string, refundedTo int) (*domain.PlatformFee, error)
+	FindPlatformFeeByOrder(orderUUID string) (*domain.PlatformFee, error)
+	GetPlatformFees(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.PlatformFee], error)
+	GetPlatformFeesBetween(ownerUUID string, from time.Time, to time.Time) ([]*domain.PlatformFee, error)
+}
@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type PriceListContract interface {
+	CreatePriceList(list *domain.PriceList) (*domain.PriceList, error)
+	FindPriceListByUUID(uuid string) (*domain.PriceList, error)
+	GetPriceListsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.PriceList], error)
+	DeletePriceListByUUID(uuid string) error
+	SaveCustomerGroup(group *domain.CustomerGroup) (*domain.CustomerGroup, error)
+	FindCustomerGroup(websiteUUID string, userUUID string) (string, error)
+	DeleteCustomerGroup(websiteUUID string, userUUID string) error
+}
@@ -5,11 +5,12 @@ import (
 )
 
 type ProductContract interface {
-	CreateProduct(product *domain.Products) (*domain.Products, error)
+	CreateProduct(product *domain.Products, quota domain.Quota) (*domain.Products, error)
 	FindProductByUUID(uuid string) (*domain.Products, error)
 	FindProductByName(name string) (*domain.Products, error)
-	GetProducts() ([]*domain.Products, error)
-	GetActiveProducts() ([]*domain.Products, error)
+	GetProductsByUUIDS(uuids []string) ([]*domain.Products, error)
+	GetProducts(query *domain.ListQuery) (*domain.Page[*domain.Products], error)
+	GetActiveProducts(query *domain.ListQuery) (*domain.Page[*domain.Products], error)
 	UpdateProductByUUID(uuid string) error
 	DeleteProductByUUID(uuid string) error
 	DeleteProductsByUUIDS(uuid []string) error
@@ -0,0 +1,12 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductDownloadContract interface {
+	CreateProductDownloads(downloads []*domain.ProductDownload) error
+	FindProductDownloadByUUID(uuid string) (*domain.ProductDownload, error)
+	GetProductDownloadsFromOrder(orderUUID string) ([]*domain.ProductDownload, error)
+	ConsumeProductDownload(uuid string) (*domain.ProductDownload, error)
+}
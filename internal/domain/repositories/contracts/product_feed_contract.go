@@ -0,0 +1,11 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductFeedContract interface {
+	GetFeedProducts(ctx context.Context, websiteUUID string) ([]*domain.FeedProduct, error)
+}
@@ -0,0 +1,12 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductFileContract interface {
+	CreateProductFile(file *domain.ProductFile) (*domain.ProductFile, error)
+	FindProductFileByUUID(uuid string) (*domain.ProductFile, error)
+	GetProductFilesFromProduct(productUUID string) ([]*domain.ProductFile, error)
+	DeleteProductFileByUUID(uuid string) error
+}
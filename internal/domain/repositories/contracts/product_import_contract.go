@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductImportContract interface {
+	CreateProductImportJob(job *domain.ProductImportJob) (*domain.ProductImportJob, error)
+	FindProductImportJobByUUID(uuid string) (*domain.ProductImportJob, error)
+	GetProductImportJobs(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportJob], error)
+	GetProductImportErrors(importUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportError], error)
+	ClaimPendingProductImportJobs(limit int) ([]*domain.ProductImportJob, error)
+	UpdateProductImportProgress(job *domain.ProductImportJob, problems []*domain.ProductImportError) error
+	CompleteProductImportJob(job *domain.ProductImportJob) error
+	ImportProduct(item *domain.ProductImportItem, quota domain.Quota) error
+	StreamProductExport(ctx context.Context, websiteUUID string, fn func(row *domain.ProductExportRow) error) error
+}
@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+type ProductMediaContract interface {
+	CreateProductMedia(media *domain.ProductMedia) (*domain.ProductMedia, error)
+	FindProductMediaByUUID(uuid string) (*domain.ProductMedia, error)
+	GetProductMediasFromProduct(productUUID string) ([]*domain.ProductMedia, error)
+	GetProductMediaDerivativesFromProduct(productUUID string) ([]*domain.ProductMediaDerivative, error)
+	GetProductMediaDerivativesFromMedia(mediaUUID string) ([]*domain.ProductMediaDerivative, error)
+	ReorderProductMedias(productUUID string, mediaUUIDs []string) error
+	ClaimPendingProductMedias(limit int) ([]*domain.ProductMedia, error)
+	CompleteProductMediaProcessing(media *domain.ProductMedia, derivatives []*domain.ProductMediaDerivative) error
+	UpdateProductMediaStatus(uuid string, status enums.MediaStatusType, reason string) error
+	DeleteProductMediaByUUID(uuid string) error
+}
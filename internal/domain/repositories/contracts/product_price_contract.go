@@ -0,0 +1,17 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductPriceContract interface {
+	CreateProductPrice(price *domain.ProductPrice) (*domain.ProductPrice, error)
+	FindProductPriceByUUID(uuid string) (*domain.ProductPrice, error)
+	GetProductPricesFromProduct(productUUID string) ([]*domain.ProductPrice, error)
+	GetProductPricesFromProducts(productUUIDs []string) ([]*domain.ProductPrice, error)
+	ListProductPricesFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductPrice], error)
+	GetPriceListsFromProduct(productUUID string) ([]*domain.PriceList, error)
+	GetPriceListsFromProducts(productUUIDs []string) ([]*domain.PriceList, error)
+	UpdateProductPrice(price *domain.ProductPrice) error
+	DeleteProductPriceByUUID(uuid string) error
+}
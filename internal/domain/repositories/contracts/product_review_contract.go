@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type ProductReviewContract interface {
+	CreateProductReview(review *domain.ProductReview) (*domain.ProductReview, error)
+	FindProductReviewByUUID(uuid string) (*domain.ProductReview, error)
+	FindDeliveredProductShipment(productUUID string, userUUID string) (*uuid.UUID, error)
+	CountProductReviewsByUserSince(userUUID string, since time.Time) (int, error)
+	GetApprovedProductReviews(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error)
+	GetProductReviews(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error)
+	GetProductReviewPhotos(reviewUUIDs []uuid.UUID) ([]*domain.ProductReviewPhoto, error)
+	FindProductReviewPhotoByUUID(uuid string) (*domain.ProductReviewPhoto, error)
+	ModerateProductReview(review *domain.ProductReview) error
+	GetProductRatingSummary(productUUID string) (*domain.ProductRatingSummary, error)
+}
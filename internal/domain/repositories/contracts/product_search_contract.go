@@ -0,0 +1,9 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductSearchContract interface {
+	SearchProducts(query *domain.ProductSearchQuery) (*domain.ProductSearchResult, error)
+}
@@ -9,7 +9,7 @@ type ProductShippedContract interface {
 	FindProductShippedByUUID(uuid string) (*domain.ProductShipped, error)
 	FindProductShippedByProductUUID(productUUID string) ([]*domain.ProductShipped, error)
 	FindProductShippedByStatus(status string) ([]*domain.ProductShipped, error)
-	GetProductsShipped() ([]*domain.ProductShipped, error)
+	GetProductsShipped(query *domain.ListQuery) (*domain.Page[*domain.ProductShipped], error)
 	UpdateProductShippedByUUID(uuid string) error
 	DeleteProductShippedByUUID(uuid string) error
 	DeleteProductsShippedByUUIDS(uuid []string) error
@@ -8,8 +8,8 @@ type ProductTagContract interface {
 	CreateProductTag(tag *domain.ProductsTags) (*domain.ProductsTags, error)
 	FindProductTagByUUID(uuid string) (*domain.ProductsTags, error)
 	FindProductTagsByLabel(label string) ([]*domain.ProductsTags, error)
-	GetProductTagsFromProduct(productUUID string) ([]*domain.ProductsTags, error)
-	GetProductTags() ([]*domain.ProductsTags, error)
+	GetProductTagsFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductsTags], error)
+	GetProductTags(query *domain.ListQuery) (*domain.Page[*domain.ProductsTags], error)
 	UpdateProductTagByUUID(uuid string) error
 	DeleteProductTagByUUID(uuid string) error
 	DeleteProductTagsByUUIDS(uuid []string) error
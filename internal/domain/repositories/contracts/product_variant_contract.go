@@ -0,0 +1,12 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type ProductVariantContract interface {
+	CreateProductVariant(variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	FindProductVariantByUUID(uuid string) (*domain.ProductVariant, error)
+	GetProductVariantsFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductVariant], error)
+	DeleteProductVariantByUUID(uuid string) error
+}
@@ -0,0 +1,13 @@
+package contracts
+
+import (
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type SearchSuggestionContract interface {
+	SuggestProducts(prefix string, websiteUUID string, limit int) ([]*domain.ProductSuggestion, error)
+	SuggestCategories(prefix string, websiteUUID string, limit int) ([]*domain.CategorySuggestion, error)
+	SuggestSearchQueries(prefix string, websiteUUID string, limit int) ([]*domain.SearchQuery, error)
+	GetPopularSearchQueries(websiteUUID string, limit int) ([]*domain.SearchQuery, error)
+	RecordSearchQueries(queries []*domain.SearchQuery) error
+}
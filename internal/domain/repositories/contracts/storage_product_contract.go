@@ -8,7 +8,7 @@ type StorageProductContract interface {
 	CreateStorageProduct(sp *domain.StorageProducts) (*domain.StorageProducts, error)
 	FindStorageProductByUUID(uuid string) (*domain.StorageProducts, error)
 	FindStorageProductByProductUUID(productUUID string) (*domain.StorageProducts, error)
-	GetStorageProducts() ([]*domain.StorageProducts, error)
+	GetStorageProducts(query *domain.ListQuery) (*domain.Page[*domain.StorageProducts], error)
 	UpdateStorageProductByUUID(uuid string) error
 	DeleteStorageProductByUUID(uuid string) error
 	DeleteStorageProductsByUUIDS(uuid []string) error
@@ -0,0 +1,25 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type SubscriptionContract interface {
+	CreateSubscription(subscription *domain.Subscription) (*domain.Subscription, error)
+	UpdateSubscription(subscription *domain.Subscription) (*domain.Subscription, error)
+	CancelSubscription(subscription *domain.Subscription) (*domain.Subscription, error)
+	FindSubscriptionByOwner(ownerUUID string) (*domain.Subscription, error)
+	FindActivePlanByOwner(ownerUUID string) (*domain.VerkoupePlan, error)
+	GetPlanUsage(ownerUUID string) (*domain.PlanUsage, error)
+	GetDueSubscriptions(now time.Time) ([]*domain.Subscription, error)
+	SuspendOverdueSubscriptions(cutoff time.Time) (int, error)
+
+	CreateInvoice(invoice *domain.SubscriptionInvoice) (*domain.SubscriptionInvoice, error)
+	FindInvoiceByUUID(invoiceUUID string) (*domain.SubscriptionInvoice, error)
+	GetInvoicesByOwner(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.SubscriptionInvoice], error)
+	GetDueInvoices(now time.Time) ([]*domain.SubscriptionInvoice, error)
+	SettleInvoice(invoice *domain.SubscriptionInvoice, subscription *domain.Subscription) error
+	FailInvoice(invoice *domain.SubscriptionInvoice, subscription *domain.Subscription) error
+}
@@ -0,0 +1,23 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type TwoFactorContract interface {
+	FindTwoFactor(userUUID string) (*domain.TwoFactor, error)
+	SaveTwoFactorSecret(userUUID string, secret string) error
+	EnableTwoFactor(userUUID string, step int64, recoveryHashes []string) error
+	DisableTwoFactor(userUUID string) error
+	UseTwoFactorStep(userUUID string, step int64) error
+	ReplaceRecoveryCodes(userUUID string, recoveryHashes []string) error
+	UseRecoveryCode(userUUID string, codeHash string) error
+	CountRecoveryCodes(userUUID string) (int, error)
+	CreateTrustedDevice(device *domain.TrustedDevice) (*domain.TrustedDevice, error)
+	UseTrustedDevice(userUUID string, tokenHash string) (bool, error)
+	FindTrustedDevices(userUUID string) ([]*domain.TrustedDevice, error)
+	DeleteTrustedDevice(userUUID string, deviceUUID string) error
+	PruneTrustedDevices(before time.Time) (int, error)
+}
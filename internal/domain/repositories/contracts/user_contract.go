@@ -9,8 +9,8 @@ type UserContract interface {
 	FindUserByUUID(uuid string) (*domain.User, error)
 	FindUserByEmailAndWebsite(email string, websiteUUID string) (*domain.User, error)
 	UserExists(email string, websiteUUID string) (bool, error)
-	GetUsersFromWebsite(websiteUUID string) ([]*domain.User, error)
-	GetUsers() ([]*domain.User, error)
+	GetUsersFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.User], error)
+	GetUsers(query *domain.ListQuery) (*domain.Page[*domain.User], error)
 	UpdateUserByUUID(uuid string) error
 	DeleteUserByUUID(uuid string) error
 	DeleteUsersByUUIDS(uuid []string) error
@@ -0,0 +1,18 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+)
+
+type UserTokenContract interface {
+	CreateUserToken(token *domain.UserToken) (*domain.UserToken, error)
+	FindUserTokenByHash(purpose enums.UserTokenPurposeType, tokenHash string) (*domain.UserToken, error)
+	CountUserTokensSince(userUUID string, purpose enums.UserTokenPurposeType, since time.Time) (int, error)
+	VerifyUserEmail(token *domain.UserToken) error
+	ResetUserPassword(token *domain.UserToken, passwordHash string) error
+	UnlockUser(token *domain.UserToken) error
+	UseUserToken(token *domain.UserToken) error
+}
@@ -5,11 +5,11 @@ import (
 )
 
 type WebsiteComponentContract interface {
-	CreateWebsiteComponent(component *domain.ComponentWebsites) (*domain.ComponentWebsites, error)
+	CreateWebsiteComponent(component *domain.ComponentWebsites, quota domain.Quota) (*domain.ComponentWebsites, error)
 	FindWebsiteComponentByUUID(uuid string) (*domain.ComponentWebsites, error)
 	FindWebsiteComponentByPath(path string) (*domain.ComponentWebsites, error)
-	GetWebsiteComponentsFromWebsite(websiteUUID string) ([]*domain.ComponentWebsites, error)
-	GetWebsiteComponents() ([]*domain.ComponentWebsites, error)
+	GetWebsiteComponentsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ComponentWebsites], error)
+	GetWebsiteComponents(query *domain.ListQuery) (*domain.Page[*domain.ComponentWebsites], error)
 	UpdateWebsiteComponentByUUID(uuid string) error
 	DeleteWebsiteComponentByUUID(uuid string) error
 	DeleteWebsiteComponentsByUUIDS(uuid []string) error
@@ -5,12 +5,15 @@ import (
 )
 
 type WebsiteContract interface {
-	CreateWebsite(website *domain.Website) (*domain.Website, error)
+	CreateWebsite(website *domain.Website, quota domain.Quota) (*domain.Website, error)
 	FindWebsiteByUUID(uuid string) (*domain.Website, error)
 	FindWebsiteByLabel(label string) (*domain.Website, error)
-	FindWebsitesByOwner(ownerUUID string) ([]*domain.Website, error)
-	GetWebsites() ([]*domain.Website, error)
+	FindWebsitesByOwner(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.Website], error)
+	FindWebsitesByUser(userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Website], error)
+	GetWebsites(query *domain.ListQuery) (*domain.Page[*domain.Website], error)
 	UpdateWebsiteByUUID(uuid string) error
+	SetWebsiteEmailVerification(uuid string, required bool) error
+	SetWebsiteTwoFactor(uuid string, required bool) error
 	DeleteWebsiteByUUID(uuid string) error
 	DeleteWebsitesByUUIDS(uuid []string) error
 }
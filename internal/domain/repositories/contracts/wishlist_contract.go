@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type WishlistContract interface {
+	CreateWishlist(wishlist *domain.Wishlist) (*domain.Wishlist, error)
+	FindWishlistByUUID(uuid string) (*domain.Wishlist, error)
+	FindWishlistByShareToken(token string) (*domain.Wishlist, error)
+	GetWishlistsByUser(userUUID string, websiteUUID string) ([]*domain.Wishlist, error)
+	CountWishlistsByUser(userUUID string, websiteUUID string) (int, error)
+	UpdateWishlist(wishlist *domain.Wishlist) error
+	DeleteWishlist(uuid string) error
+	AddWishlistItem(item *domain.WishlistItem) (*domain.WishlistItem, error)
+	GetWishlistItems(wishlistUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistItem], error)
+	RemoveWishlistItem(wishlistUUID string, itemUUID string) error
+	DetectWishlistAlerts(ctx context.Context) ([]*domain.WishlistAlert, error)
+	GetWishlistAlertsByUser(userUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistAlert], error)
+}
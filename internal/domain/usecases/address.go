@@ -50,6 +50,6 @@ func (u *CreateAddressUseCase) GetByUUID(uuidStr string) (*domain.AddressBR, err
 	return u.addressRepo.FindAddressByUUID(uuidStr)
 }
 
-func (u *CreateAddressUseCase) GetAll(websiteUUIDStr string) ([]*domain.AddressBR, error) {
-	return u.addressRepo.GetAddressesFromWebsite(websiteUUIDStr)
+func (u *CreateAddressUseCase) GetAll(websiteUUIDStr string, query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error) {
+	return u.addressRepo.GetAddressesFromWebsite(websiteUUIDStr, query)
 }
@@ -1,24 +1,47 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/services"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
 	"github.com/google/uuid"
 )
 
 type AuthUseCase struct {
-	userRepo contracts.UserContract
+	userRepo      contracts.UserContract
+	websiteRepo   contracts.WebsiteContract
+	tokenRepo     contracts.UserTokenContract
+	attemptRepo   contracts.LoginAttemptContract
+	twoFactorRepo contracts.TwoFactorContract
+	mailer        contracts.MailerContract
+	secret        []byte
+	viewURL       string
 }
 
-func NewAuthUseCase(userRepo contracts.UserContract) *AuthUseCase {
+func NewAuthUseCase(userRepo contracts.UserContract, websiteRepo contracts.WebsiteContract, tokenRepo contracts.UserTokenContract, attemptRepo contracts.LoginAttemptContract, twoFactorRepo contracts.TwoFactorContract, mailer contracts.MailerContract, secret string, viewURL string) *AuthUseCase {
 	return &AuthUseCase{
-		userRepo: userRepo,
+		userRepo:      userRepo,
+		websiteRepo:   websiteRepo,
+		tokenRepo:     tokenRepo,
+		attemptRepo:   attemptRepo,
+		twoFactorRepo: twoFactorRepo,
+		mailer:        mailer,
+		secret:        []byte(secret),
+		viewURL:       strings.TrimSuffix(viewURL, "/"),
 	}
 }
 
+// Register creates the user and mails them a link to verify their email.
+// A failed email does not fail the registration; the user can ask for
+// another link.
 func (u *AuthUseCase) Register(input *domain.User, website uuid.UUID) (*domain.User, error) {
 	hashedPassword, err := services.HashPassword(input.Password)
 	if err != nil {
@@ -43,18 +66,491 @@ func (u *AuthUseCase) Register(input *domain.User, website uuid.UUID) (*domain.U
 		return nil, err
 	}
 
+	if u.mailer != nil {
+		if err := u.sendVerification(createdUser); err != nil {
+			logger.Warn(fmt.Errorf("email verification for %s: %w", createdUser.UUID, err)).Print()
+		}
+	}
+
 	return createdUser, nil
 }
 
-func (u *AuthUseCase) Login(email, password, websiteUUID string) (*domain.User, error) {
+// Login throttles failed sign ins per account and ip, and hashes the
+// password even for unknown emails so timing does not tell them apart.
+// Users with two-factor authentication get a challenge for VerifyTwoFactor
+// unless deviceToken is a device they remembered.
+func (u *AuthUseCase) Login(email, password, websiteUUID string, ip string, deviceToken string) (*domain.LoginResult, error) {
+	now := time.Now()
+
+	if err := u.ipWait(ip, now); err != nil {
+		return nil, err
+	}
+
 	user, err := u.userRepo.FindUserByEmailAndWebsite(email, websiteUUID)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		services.CheckNoPassword(password)
+		u.loginFailed(nil, ip)
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	valid := services.CheckPassword(password, user.Password)
+
+	if err := user.LoginWait(now); err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		if err := u.loginFailed(user, ip); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	needsCode, err := u.needsTwoFactor(user, deviceToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// With a code still due, failures only reset once it passes, so a
+	// known password does not give endless tries at codes.
+	if !needsCode && user.FailedLogins > 0 {
+		if err := u.attemptRepo.ResetUserLoginFailures(user.UUID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.EmailVerifiedAt == nil {
+		website, err := u.websiteRepo.FindWebsiteByUUID(websiteUUID)
+		if err == nil && website.RequireEmailVerification {
+			return nil, domain.ErrEmailNotVerified
+		}
+	}
+
+	if needsCode {
+		return u.challenge(user)
+	}
+
+	return &domain.LoginResult{User: user}, nil
+}
+
+// VerifyTwoFactor finishes a sign in challenged for a code, with a code of
+// the authenticator app or a recovery code. Wrong codes count as failed
+// sign ins toward the same backoff and lockout. With remember, the device
+// gets a token that skips codes for domain.TrustedDeviceTTL.
+func (u *AuthUseCase) VerifyTwoFactor(challenge string, code string, ip string, remember bool, deviceName string) (*domain.LoginResult, error) {
+	now := time.Now()
+
+	if err := u.ipWait(ip, now); err != nil {
+		return nil, err
+	}
+
+	pending, err := u.tokenRepo.FindUserTokenByHash(enums.TwoFactorChallengeToken, domain.HashSecretToken(challenge))
+	if err != nil {
+		return nil, domain.ErrUserTokenInvalid
+	}
+
+	if err := pending.Usable(now); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindUserByUUID(pending.UserUUID.String())
+	if err != nil {
+		return nil, domain.ErrUserTokenInvalid
+	}
+
+	if err := user.LoginWait(now); err != nil {
+		return nil, err
+	}
+
+	tf, err := openTwoFactor(u.twoFactorRepo, u.secret, user.UUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := useTwoFactorCode(u.twoFactorRepo, tf, code, now); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCode) {
+			if err := u.loginFailed(user, ip); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := u.tokenRepo.UseUserToken(pending); err != nil {
+		return nil, err
+	}
+
+	if user.FailedLogins > 0 {
+		if err := u.attemptRepo.ResetUserLoginFailures(user.UUID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &domain.LoginResult{User: user}
+	if remember {
+		device, secret, err := domain.NewTrustedDevice(user.UUID, deviceName)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := u.twoFactorRepo.CreateTrustedDevice(device); err != nil {
+			return nil, err
+		}
+		result.DeviceToken = secret
+	}
+
+	return result, nil
+}
+
+// CheckCode runs check, proving a two-factor code of a signed in user,
+// under the backoff and lockout of signing in: wrong codes count as failed
+// sign ins.
+func (u *AuthUseCase) CheckCode(userUUID string, ip string, check func() error) error {
+	now := time.Now()
+
+	if err := u.ipWait(ip, now); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.FindUserByUUID(userUUID)
+	if err != nil {
+		return err
+	}
+
+	if err := user.LoginWait(now); err != nil {
+		return err
+	}
+
+	if err := check(); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCode) {
+			if err := u.loginFailed(user, ip); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	if user.FailedLogins > 0 {
+		return u.attemptRepo.ResetUserLoginFailures(userUUID)
+	}
+
+	return nil
+}
+
+// ipWait refuses sign ins from an ip backing off after failing too often.
+func (u *AuthUseCase) ipWait(ip string, now time.Time) error {
+	failures, last, err := u.attemptRepo.CountIPLoginFailures(ip, now.Add(-domain.LoginIPWindow))
+	if err != nil {
+		return err
+	}
+
+	if last != nil {
+		if next := last.Add(domain.LoginBackoff(failures, domain.LoginIPFreeAttempts)); now.Before(next) {
+			return &domain.LoginThrottleError{Err: domain.ErrTooManyLoginAttempts, RetryAfter: next.Sub(now)}
+		}
+	}
+
+	return nil
+}
+
+// needsTwoFactor tells whether the user still has to send a code: they
+// have two-factor authentication on and deviceToken is not of a device
+// they remembered.
+func (u *AuthUseCase) needsTwoFactor(user *domain.User, deviceToken string) (bool, error) {
+	tf, err := u.twoFactorRepo.FindTwoFactor(user.UUID.String())
+	if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !tf.Enabled() {
+		return false, nil
+	}
+
+	if deviceToken == "" {
+		return true, nil
+	}
+
+	trusted, err := u.twoFactorRepo.UseTrustedDevice(user.UUID.String(), domain.HashSecretToken(deviceToken))
+	if err != nil {
+		return false, err
+	}
+
+	return !trusted, nil
+}
+
+func (u *AuthUseCase) challenge(user *domain.User) (*domain.LoginResult, error) {
+	token, secret, err := domain.NewUserToken(user.UUID, enums.TwoFactorChallengeToken, domain.TwoFactorChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.tokenRepo.CreateUserToken(token); err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResult{
+		User:               user,
+		Challenge:          secret,
+		ChallengeExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// loginFailed records a failed sign in from ip, for user when the email
+// exists. When it locks the account it mails an unlock link and returns
+// the lockout.
+func (u *AuthUseCase) loginFailed(user *domain.User, ip string) error {
+	if err := u.attemptRepo.RecordIPLoginFailure(ip); err != nil {
+		logger.Warn(fmt.Errorf("login failure of %s: %w", ip, err)).Print()
 	}
 
-	if !services.CheckPassword(password, user.Password) {
-		return nil, errors.New("invalid credentials")
+	if user == nil {
+		return nil
 	}
 
-	return user, nil
+	failures, lockedUntil, err := u.attemptRepo.RecordUserLoginFailure(user.UUID.String(), domain.LoginLockAttempts, domain.LoginLockDuration)
+	if err != nil {
+		logger.Warn(fmt.Errorf("login failure of %s: %w", user.UUID, err)).Print()
+		return nil
+	}
+
+	if failures < domain.LoginLockAttempts || lockedUntil == nil {
+		return nil
+	}
+
+	if u.mailer != nil {
+		if err := u.sendUnlock(user); err != nil {
+			logger.Warn(fmt.Errorf("account unlock for %s: %w", user.UUID, err)).Print()
+		}
+	}
+
+	return &domain.LoginThrottleError{Err: domain.ErrAccountLocked, RetryAfter: time.Until(*lockedUntil)}
+}
+
+// UnlockAccount lifts a lockout with the token of the link mailed when the
+// account was locked.
+func (u *AuthUseCase) UnlockAccount(token string) error {
+	unlock, err := u.tokenRepo.FindUserTokenByHash(enums.AccountUnlockToken, domain.HashSecretToken(token))
+	if err != nil {
+		return domain.ErrUserTokenInvalid
+	}
+
+	if err := unlock.Usable(time.Now()); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.UnlockUser(unlock)
+}
+
+// VerifyEmail uses the token of a verification link.
+func (u *AuthUseCase) VerifyEmail(token string) error {
+	verification, err := u.tokenRepo.FindUserTokenByHash(enums.EmailVerificationToken, domain.HashSecretToken(token))
+	if err != nil {
+		return domain.ErrUserTokenInvalid
+	}
+
+	if err := verification.Usable(time.Now()); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.VerifyUserEmail(verification)
+}
+
+// ResendVerification mails a new verification link, at most once per
+// domain.EmailVerificationCooldown and domain.EmailVerificationHourlyLimit
+// times an hour. Unknown, already verified and throttled emails are skipped
+// silently, so callers cannot tell registered emails apart.
+func (u *AuthUseCase) ResendVerification(email string, websiteUUID string) error {
+	if u.mailer == nil {
+		return ErrMailerMissing
+	}
+
+	user, err := u.userRepo.FindUserByEmailAndWebsite(email, websiteUUID)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	throttled, err := u.throttled(user, enums.EmailVerificationToken, domain.EmailVerificationCooldown, domain.EmailVerificationHourlyLimit)
+	if err != nil || throttled {
+		return err
+	}
+
+	return u.sendVerification(user)
+}
+
+// RequestPasswordReset mails a reset link to the user with this email on
+// the website. It answers the same whether the email exists or resets are
+// throttled, so callers cannot tell registered emails apart.
+func (u *AuthUseCase) RequestPasswordReset(email string, websiteUUID string) error {
+	if u.mailer == nil {
+		return ErrMailerMissing
+	}
+
+	user, err := u.userRepo.FindUserByEmailAndWebsite(email, websiteUUID)
+	if err != nil {
+		return nil
+	}
+
+	throttled, err := u.throttled(user, enums.PasswordResetToken, domain.PasswordResetCooldown, domain.PasswordResetHourlyLimit)
+	if err != nil || throttled {
+		return err
+	}
+
+	token, secret, err := domain.NewUserToken(user.UUID, enums.PasswordResetToken, domain.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.tokenRepo.CreateUserToken(token); err != nil {
+		return err
+	}
+
+	err = u.send(user, "Reset your password", fmt.Sprintf(
+		"Hi %s,\n\nSomeone asked to reset your password. Choose a new one at %s/reset-password/%s\n\nThe link works once and expires in %d minutes. If it was not you, ignore this email.",
+		user.Name,
+		u.viewURL,
+		secret,
+		int(domain.PasswordResetTTL.Minutes()),
+	))
+	if err != nil {
+		logger.Warn(fmt.Errorf("password reset for %s: %w", user.UUID, err)).Print()
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password with the token of a reset link of the
+// website. Every session and remembered device of the user is revoked and
+// they are told by email.
+func (u *AuthUseCase) ResetPassword(token string, websiteUUID string, password string) error {
+	reset, err := u.tokenRepo.FindUserTokenByHash(enums.PasswordResetToken, domain.HashSecretToken(token))
+	if err != nil {
+		return domain.ErrUserTokenInvalid
+	}
+
+	if err := reset.Usable(time.Now()); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.FindUserByUUID(reset.UserUUID.String())
+	if err != nil || user.WebSiteUUID.String() != websiteUUID {
+		return domain.ErrUserTokenInvalid
+	}
+
+	if err := domain.ValidatePassword(password); err != nil {
+		return domain.ErrPasswordTooWeak
+	}
+
+	hashedPassword, err := services.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := u.tokenRepo.ResetUserPassword(reset, hashedPassword); err != nil {
+		return err
+	}
+
+	if u.mailer != nil {
+		err = u.send(user, "Your password was changed", fmt.Sprintf(
+			"Hi %s,\n\nThe password of your account was just reset and every device was signed out. If it was not you, reset it again at %s/forgot-password and contact support.",
+			user.Name,
+			u.viewURL,
+		))
+		if err != nil {
+			logger.Warn(fmt.Errorf("password reset notice for %s: %w", user.UUID, err)).Print()
+		}
+	}
+
+	return nil
+}
+
+// SessionValid tells the auth middleware whether an access token issued
+// at issuedAt still works for the user.
+func (u *AuthUseCase) SessionValid(userUUID string, issuedAt time.Time) bool {
+	user, err := u.userRepo.FindUserByUUID(userUUID)
+	if err != nil {
+		return false
+	}
+
+	return user.SessionValid(issuedAt)
+}
+
+// PruneLoginFailures forgets failed sign ins per ip that no longer count.
+func (u *AuthUseCase) PruneLoginFailures() {
+	if _, err := u.attemptRepo.PruneLoginFailures(time.Now().Add(-domain.LoginIPWindow)); err != nil {
+		logger.Warn(fmt.Errorf("login failures pruning: %w", err)).Print()
+	}
+}
+
+// throttled tells whether a user got a token for purpose within cooldown
+// or hourlyLimit of them in the last hour.
+func (u *AuthUseCase) throttled(user *domain.User, purpose enums.UserTokenPurposeType, cooldown time.Duration, hourlyLimit int) (bool, error) {
+	now := time.Now()
+	recent, err := u.tokenRepo.CountUserTokensSince(user.UUID.String(), purpose, now.Add(-cooldown))
+	if err != nil {
+		return false, err
+	}
+
+	hourly, err := u.tokenRepo.CountUserTokensSince(user.UUID.String(), purpose, now.Add(-time.Hour))
+	if err != nil {
+		return false, err
+	}
+
+	return recent > 0 || hourly >= hourlyLimit, nil
+}
+
+func (u *AuthUseCase) sendVerification(user *domain.User) error {
+	token, secret, err := domain.NewUserToken(user.UUID, enums.EmailVerificationToken, domain.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.tokenRepo.CreateUserToken(token); err != nil {
+		return err
+	}
+
+	return u.send(user, "Verify your email", fmt.Sprintf(
+		"Hi %s,\n\nConfirm this is your email at %s/verify-email/%s\n\nThe link works once and expires in %d hours.",
+		user.Name,
+		u.viewURL,
+		secret,
+		int(domain.EmailVerificationTTL.Hours()),
+	))
+}
+
+func (u *AuthUseCase) sendUnlock(user *domain.User) error {
+	throttled, err := u.throttled(user, enums.AccountUnlockToken, time.Minute, 5)
+	if err != nil || throttled {
+		return err
+	}
+
+	token, secret, err := domain.NewUserToken(user.UUID, enums.AccountUnlockToken, domain.AccountUnlockTTL)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.tokenRepo.CreateUserToken(token); err != nil {
+		return err
+	}
+
+	return u.send(user, "Your account was locked", fmt.Sprintf(
+		"Hi %s,\n\nYour account was locked for %d minutes after too many failed sign ins. If it was you, unlock it now at %s/unlock-account/%s\n\nIf it was not you, consider resetting your password.",
+		user.Name,
+		int(domain.LoginLockDuration.Minutes()),
+		u.viewURL,
+		secret,
+	))
+}
+
+func (u *AuthUseCase) send(user *domain.User, subject string, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.mailer.Send(ctx, domain.Email{
+		To:      user.Email,
+		Subject: subject,
+		Body:    body,
+	})
 }
@@ -0,0 +1,372 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/ViitoJooj/verkoupe/pkg/token"
+	"github.com/google/uuid"
+)
+
+// How long the link of a recovery reminder brings the cart back.
+const CartRestoreTTL = 7 * 24 * time.Hour
+
+// How many carts one SendReminders run goes through.
+const cartReminderBatch = 200
+
+var (
+	ErrCartNotFound           = errors.New("cart not found")
+	ErrCartItemNotFound       = errors.New("cart item not found")
+	ErrCartRestoreLinkInvalid = errors.New("invalid cart restore link")
+	ErrCartRestoreLinkExpired = errors.New("cart restore link expired")
+)
+
+type CartUseCase struct {
+	repository        contracts.CartContract
+	productRepository contracts.ProductContract
+	variantRepository contracts.ProductVariantContract
+	websiteRepository contracts.WebsiteContract
+	userRepository    contracts.UserContract
+	cupomRepository   contracts.CupomContract
+	priceUseCase      *ResolveProductPriceUseCase
+	mailer            contracts.MailerContract
+	secret            []byte
+	baseURL           string
+}
+
+func NewCartUseCase(repository contracts.CartContract, productRepository contracts.ProductContract, variantRepository contracts.ProductVariantContract, websiteRepository contracts.WebsiteContract, userRepository contracts.UserContract, cupomRepository contracts.CupomContract, priceUseCase *ResolveProductPriceUseCase, mailer contracts.MailerContract, secret string, baseURL string) *CartUseCase {
+	return &CartUseCase{
+		repository:        repository,
+		productRepository: productRepository,
+		variantRepository: variantRepository,
+		websiteRepository: websiteRepository,
+		userRepository:    userRepository,
+		cupomRepository:   cupomRepository,
+		priceUseCase:      priceUseCase,
+		mailer:            mailer,
+		secret:            []byte(secret),
+		baseURL:           strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Get returns the shopper's open cart on the website, creating an empty
+// one the first time.
+func (u *CartUseCase) Get(websiteUUID string, userUUID string) (*domain.Cart, error) {
+	if cart, err := u.repository.FindOpenCart(websiteUUID, userUUID); err == nil {
+		return cart, nil
+	}
+
+	user, err := u.userRepository.FindUserByUUID(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := domain.NewCart(websiteUUID, userUUID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.CreateCart(cart)
+}
+
+func (u *CartUseCase) AddItem(websiteUUID string, userUUID string, productUUID string, variantUUID string, quantity int) (*domain.Cart, error) {
+	cart, err := u.Get(websiteUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := domain.NewCartItem(cart.UUID, productUUID, variantUUID, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := u.productRepository.FindProductByUUID(productUUID)
+	if err != nil {
+		return nil, err
+	}
+	if product.WebsiteUUID == nil || *product.WebsiteUUID != cart.WebsiteUUID || !product.Active {
+		return nil, domain.ErrCartItemUnavailable
+	}
+
+	if item.VariantUUID != nil {
+		variant, err := u.variantRepository.FindProductVariantByUUID(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductUUID != item.ProductUUID {
+			return nil, errors.New("variant does not belong to product")
+		}
+	}
+
+	inCart := false
+	for _, existing := range cart.Items {
+		if existing.Target() == item.Target() {
+			inCart = true
+			break
+		}
+	}
+	if !inCart && len(cart.Items) >= domain.MaxCartItems {
+		return nil, domain.ErrCartFull
+	}
+
+	if _, err := u.repository.SaveCartItem(item); err != nil {
+		return nil, err
+	}
+
+	return u.repository.FindCartByUUID(cart.UUID.String())
+}
+
+func (u *CartUseCase) UpdateItem(websiteUUID string, userUUID string, itemUUID string, quantity int) (*domain.Cart, error) {
+	if err := domain.ValidateCartQuantity(quantity); err != nil {
+		return nil, err
+	}
+
+	cart, err := u.repository.FindOpenCart(websiteUUID, userUUID)
+	if err != nil {
+		return nil, ErrCartItemNotFound
+	}
+
+	if err := u.repository.UpdateCartItemQuantity(cart.UUID.String(), itemUUID, quantity); err != nil {
+		return nil, ErrCartItemNotFound
+	}
+
+	return u.repository.FindCartByUUID(cart.UUID.String())
+}
+
+func (u *CartUseCase) RemoveItem(websiteUUID string, userUUID string, itemUUID string) (*domain.Cart, error) {
+	cart, err := u.repository.FindOpenCart(websiteUUID, userUUID)
+	if err != nil {
+		return nil, ErrCartItemNotFound
+	}
+
+	if err := u.repository.RemoveCartItem(cart.UUID.String(), itemUUID); err != nil {
+		return nil, ErrCartItemNotFound
+	}
+
+	return u.repository.FindCartByUUID(cart.UUID.String())
+}
+
+// Quote prices the cart in coin, the website's base currency when empty.
+// Items whose product was deactivated, moved or lost its price make the
+// whole quote fail with domain.ErrCartItemUnavailable.
+func (u *CartUseCase) Quote(cart *domain.Cart, coin string) (*domain.CartQuote, error) {
+	if len(cart.Items) == 0 {
+		return nil, domain.ErrCartEmpty
+	}
+
+	productUUIDs := make([]string, 0, len(cart.Items))
+	targets := make([]domain.PriceTarget, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		productUUIDs = append(productUUIDs, item.ProductUUID.String())
+		targets = append(targets, item.Target())
+	}
+
+	products, err := u.productRepository.GetProductsByUUIDS(productUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byUUID := make(map[uuid.UUID]*domain.Products, len(products))
+	for _, product := range products {
+		byUUID[product.UUID] = product
+	}
+
+	customerGroup, err := u.priceUseCase.CustomerGroup(cart.WebsiteUUID.String(), cart.UserUUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := u.priceUseCase.ResolveManyForDisplay(cart.WebsiteUUID.String(), targets, coin, customerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.CartQuote{Cart: cart}
+	for _, item := range cart.Items {
+		product := byUUID[item.ProductUUID]
+		price := prices[item.Target()]
+		if product == nil || !product.Active || product.WebsiteUUID == nil || *product.WebsiteUUID != cart.WebsiteUUID || price == nil {
+			return nil, domain.ErrCartItemUnavailable
+		}
+
+		quote.Coin = price.DisplayCoin
+		line := &domain.CartLine{
+			Item:       item,
+			Product:    product,
+			UnitAmount: price.DisplayAmount,
+			Amount:     price.DisplayAmount * item.Quantity,
+		}
+		quote.Lines = append(quote.Lines, line)
+		quote.Subtotal += line.Amount
+	}
+
+	return quote, nil
+}
+
+// Restore opens the cart behind the link of a recovery reminder. Carts
+// already ordered are reported as not found.
+func (u *CartUseCase) Restore(tokenStr string) (*domain.Cart, error) {
+	cartUUID, err := token.ParseCartRestore(tokenStr, u.secret)
+	if err != nil {
+		if errors.Is(err, token.ErrExpired) {
+			return nil, ErrCartRestoreLinkExpired
+		}
+		return nil, ErrCartRestoreLinkInvalid
+	}
+
+	cart, err := u.repository.FindCartByUUID(cartUUID)
+	if err != nil || cart.Status != enums.CartOpen {
+		return nil, ErrCartNotFound
+	}
+
+	if err := u.repository.MarkCartRestored(cartUUID); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+func (u *CartUseCase) GetRecoverySettings(websiteUUID string) (*domain.CartRecoverySettings, error) {
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := u.repository.FindCartRecoverySettings(websiteUUID)
+	if err != nil {
+		return domain.DefaultCartRecoverySettings(websiteUUIDParsed), nil
+	}
+
+	return settings, nil
+}
+
+func (u *CartUseCase) SaveRecoverySettings(websiteUUID string, active bool, idleMinutes int, reminders int, intervalHours int, cupomValue string, cupomValueType string, cupomValidDays int) (*domain.CartRecoverySettings, error) {
+	settings, err := domain.NewCartRecoverySettings(websiteUUID, active, idleMinutes, reminders, intervalHours, cupomValue, cupomValueType, cupomValidDays)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.SaveCartRecoverySettings(settings)
+}
+
+// RecoveryReport sums the orders paid from reminded carts during month,
+// written YYYY-MM.
+func (u *CartUseCase) RecoveryReport(websiteUUID string, month string) ([]*domain.CartRecoveryTotal, error) {
+	start, err := domain.ParseStatementMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetCartRecoveryTotals(websiteUUID, start, start.AddDate(0, 1, 0))
+}
+
+// SendReminders is meant to be handed to the scheduler. A reminder is
+// counted before it is mailed, so a failing mailer loses the email rather
+// than sending it twice.
+func (u *CartUseCase) SendReminders() {
+	if u.mailer == nil {
+		return
+	}
+
+	carts, err := u.repository.GetCartsDueReminder(context.Background(), cartReminderBatch)
+	if err != nil {
+		logger.Warn(err).Print()
+		return
+	}
+
+	settings := make(map[uuid.UUID]*domain.CartRecoverySettings)
+	websites := make(map[uuid.UUID]*domain.Website)
+	for _, cart := range carts {
+		if _, ok := settings[cart.WebsiteUUID]; !ok {
+			found, err := u.repository.FindCartRecoverySettings(cart.WebsiteUUID.String())
+			if err != nil {
+				logger.Warn(err).Print()
+				continue
+			}
+			settings[cart.WebsiteUUID] = found
+		}
+
+		if _, ok := websites[cart.WebsiteUUID]; !ok {
+			found, err := u.websiteRepository.FindWebsiteByUUID(cart.WebsiteUUID.String())
+			if err != nil {
+				logger.Warn(err).Print()
+				continue
+			}
+			websites[cart.WebsiteUUID] = found
+		}
+
+		if err := u.remind(cart, settings[cart.WebsiteUUID], websites[cart.WebsiteUUID]); err != nil {
+			logger.Warn(fmt.Errorf("cart %s reminder: %w", cart.UUID, err)).Print()
+		}
+	}
+}
+
+func (u *CartUseCase) remind(cart *domain.Cart, settings *domain.CartRecoverySettings, website *domain.Website) error {
+	n := cart.RemindersSent + 1
+
+	var cupom *domain.Cupons
+	if settings.OffersCupom(n) {
+		created, err := u.cupomRepository.CreateCupom(settings.RecoveryCupom(cart, website.BaseCoin, time.Now()))
+		if err != nil {
+			return err
+		}
+		cupom = created
+	}
+
+	var cupomUUID *uuid.UUID
+	if cupom != nil {
+		cupomUUID = &cupom.UUID
+	}
+
+	claimed, err := u.repository.ClaimCartReminder(cart.UUID, cart.RemindersSent, cupomUUID)
+	if err != nil || !claimed {
+		if cupom != nil {
+			if err := u.cupomRepository.DeleteCupomByUUID(cupom.UUID.String()); err != nil {
+				logger.Warn(err).Print()
+			}
+		}
+		return err
+	}
+
+	link, err := token.GenerateCartRestore(u.secret, cart.UUID.String(), CartRestoreTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"You left %d item(s) in your cart at %s.\n\nPick up where you left off at %s/carts/restore/%s",
+		len(cart.Items),
+		website.Label,
+		u.baseURL,
+		link,
+	)
+	if cupom != nil {
+		off := cupom.Value + "%"
+		if cupom.ValueType != enums.CupomPercentage {
+			off = cupom.Value + " " + string(cupom.Coin)
+		}
+
+		body += fmt.Sprintf(
+			"\n\nUse the coupon %s at checkout for %s off, valid until %s.",
+			cupom.UUID,
+			off,
+			cupom.ExpiresAt.Format(time.DateOnly),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.mailer.Send(ctx, domain.Email{
+		To:      cart.Email,
+		Subject: fmt.Sprintf("Your cart at %s is waiting", website.Label),
+		Body:    body,
+	})
+}
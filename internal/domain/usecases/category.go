@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"errors"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/services"
+)
+
+type CreateCategoryUseCase struct {
+	changeNotifier
+	repository        contracts.CategoryContract
+	productRepository contracts.ProductContract
+}
+
+func NewCreateCategoryUseCase(repository contracts.CategoryContract, productRepository contracts.ProductContract) *CreateCategoryUseCase {
+	return &CreateCategoryUseCase{repository: repository, productRepository: productRepository}
+}
+
+func (u *CreateCategoryUseCase) Create(websiteUUID string, parentUUID string, name string, slug string, position int, seoTitle string, seoDescription string, active bool) (*domain.Category, error) {
+	if slug == "" {
+		slug = services.Slugify(name)
+	}
+
+	category, err := domain.NewCategory(websiteUUID, parentUUID, name, slug, position, seoTitle, seoDescription, active)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repository.FindCategoryBySlugAndWebsite(slug, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("category already exists for this slug and website")
+	}
+
+	created, err := u.repository.CreateCategory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return created, nil
+}
+
+func (u *CreateCategoryUseCase) GetByUUID(uuidStr string, websiteUUID string) (*domain.Category, error) {
+	category, err := u.repository.FindCategoryByUUID(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.WebsiteUUID.String() != websiteUUID {
+		return nil, errors.New("category not found")
+	}
+
+	return category, nil
+}
+
+func (u *CreateCategoryUseCase) GetTree(websiteUUID string) ([]*domain.CategoryNode, error) {
+	categories, err := u.repository.GetCategoriesFromWebsite(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildCategoryTree(categories), nil
+}
+
+func (u *CreateCategoryUseCase) Move(uuidStr string, parentUUID string, position int, websiteUUID string) (*domain.Category, error) {
+	if position < 0 {
+		return nil, errors.New("Position cannot be negative.")
+	}
+
+	category, err := u.GetByUUID(uuidStr, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *domain.Category
+	if parentUUID != "" {
+		parent, err = u.GetByUUID(parentUUID, websiteUUID)
+		if err != nil {
+			return nil, errors.New("parent category not found")
+		}
+
+		if category.IsAncestorOf(parent) {
+			return nil, errors.New("category cannot be moved into itself or its descendants")
+		}
+	}
+
+	if err := u.repository.MoveCategory(category, parent, position); err != nil {
+		return nil, err
+	}
+
+	return u.repository.FindCategoryByUUID(uuidStr)
+}
+
+func (u *CreateCategoryUseCase) Delete(uuidStr string, websiteUUID string) error {
+	if _, err := u.GetByUUID(uuidStr, websiteUUID); err != nil {
+		return err
+	}
+
+	children, err := u.repository.CountCategoryChildren(uuidStr)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category has subcategories")
+	}
+
+	if err := u.repository.DeleteCategoryByUUID(uuidStr); err != nil {
+		return err
+	}
+
+	u.notifyChange()
+	return nil
+}
+
+func (u *CreateCategoryUseCase) AssignProduct(categoryUUID string, productUUID string, position int, websiteUUID string) (*domain.ProductCategory, error) {
+	productCategory, err := domain.NewProductCategory(productUUID, categoryUUID, position)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.GetByUUID(categoryUUID, websiteUUID); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.AssignProductToCategory(productCategory); err != nil {
+		return nil, err
+	}
+
+	return productCategory, nil
+}
+
+func (u *CreateCategoryUseCase) RemoveProduct(categoryUUID string, productUUID string, websiteUUID string) error {
+	if _, err := u.GetByUUID(categoryUUID, websiteUUID); err != nil {
+		return err
+	}
+	return u.repository.RemoveProductFromCategory(productUUID, categoryUUID)
+}
+
+func (u *CreateCategoryUseCase) ListProducts(categoryUUID string, withDescendants bool, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.CategoryProduct], error) {
+	category, err := u.GetByUUID(categoryUUID, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	return u.repository.GetProductsFromCategory(category, withDescendants, query)
+}
@@ -0,0 +1,26 @@
+package usecases
+
+import "sync"
+
+// changeNotifier lets read-side caches subscribe to writes made through a
+// use case without the use case knowing who is listening.
+type changeNotifier struct {
+	mu        sync.RWMutex
+	listeners []func()
+}
+
+func (n *changeNotifier) OnChange(listener func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.listeners = append(n.listeners, listener)
+}
+
+func (n *changeNotifier) notifyChange() {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for _, listener := range n.listeners {
+		listener()
+	}
+}
@@ -1,25 +1,45 @@
 package usecases
 
 import (
+	"errors"
 
 	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 )
 
+var ErrProductTagNotFound = errors.New("product tag not found")
+
 type CreateCupomUseCase struct {
-	repository contracts.CupomContract
+	repository        contracts.CupomContract
+	tagRepository     contracts.ProductTagContract
+	productRepository contracts.ProductContract
 }
 
-func NewCreateCupomUseCase(repository contracts.CupomContract) *CreateCupomUseCase {
-	return &CreateCupomUseCase{repository: repository}
+func NewCreateCupomUseCase(repository contracts.CupomContract, tagRepository contracts.ProductTagContract, productRepository contracts.ProductContract) *CreateCupomUseCase {
+	return &CreateCupomUseCase{
+		repository:        repository,
+		tagRepository:     tagRepository,
+		productRepository: productRepository,
+	}
 }
 
-func (u *CreateCupomUseCase) Create(tagUUID string, label string, description string, value string, valueType string) (*domain.Cupons, error) {
-	cupom, err := domain.NewCupom(tagUUID, label, description, value, valueType)
+// Create makes a coupon of the website, for a tag of one of its products.
+func (u *CreateCupomUseCase) Create(websiteUUID string, tagUUID string, coin string, label string, description string, value string, valueType string) (*domain.Cupons, error) {
+	cupom, err := domain.NewCupom(websiteUUID, tagUUID, coin, label, description, value, valueType)
 	if err != nil {
 		return nil, err
 	}
 
+	tag, err := u.tagRepository.FindProductTagByUUID(tagUUID)
+	if err != nil {
+		return nil, ErrProductTagNotFound
+	}
+
+	product, err := u.productRepository.FindProductByUUID(tag.ProductUUID.String())
+	if err != nil || product.WebsiteUUID == nil || *product.WebsiteUUID != cupom.WebsiteUUID {
+		return nil, ErrProductTagNotFound
+	}
+
 	createdCupom, err := u.repository.CreateCupom(cupom)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+var supportedCoins = []enums.CoinType{enums.BRCoin, enums.EUACoin, enums.EURCoin}
+
+type CurrencyUseCase struct {
+	rateRepository     contracts.ExchangeRateContract
+	roundingRepository contracts.CurrencyRoundingContract
+	source             contracts.ExchangeRateSourceContract
+}
+
+func NewCurrencyUseCase(rateRepository contracts.ExchangeRateContract, roundingRepository contracts.CurrencyRoundingContract, source contracts.ExchangeRateSourceContract) *CurrencyUseCase {
+	return &CurrencyUseCase{
+		rateRepository:     rateRepository,
+		roundingRepository: roundingRepository,
+		source:             source,
+	}
+}
+
+// SyncRates pulls the rates for every supported pair from the configured
+// source and stores them under rateDate.
+func (u *CurrencyUseCase) SyncRates(rateDate time.Time) error {
+	if u.source == nil {
+		return errors.New("exchange rate source not configured")
+	}
+
+	for _, base := range supportedCoins {
+		rates, err := u.source.FetchRates(base, rateDate)
+		if err != nil {
+			return err
+		}
+
+		for quote, value := range rates {
+			rate, err := domain.NewExchangeRate(string(base), string(quote), value.FloatString(10), rateDate, u.source.Name())
+			if err != nil {
+				return err
+			}
+
+			if _, err := u.rateRepository.UpsertExchangeRate(rate); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SyncDaily is meant to be handed to the scheduler.
+func (u *CurrencyUseCase) SyncDaily() {
+	if u.source == nil {
+		return
+	}
+
+	if err := u.SyncRates(time.Now().UTC()); err != nil {
+		logger.Warn(fmt.Errorf("exchange rates sync failed: %w", err)).Print()
+	}
+}
+
+func (u *CurrencyUseCase) SetRate(baseCoin string, quoteCoin string, rate string, rateDate time.Time) (*domain.ExchangeRate, error) {
+	exchangeRate, err := domain.NewExchangeRate(baseCoin, quoteCoin, rate, rateDate, "manual")
+	if err != nil {
+		return nil, err
+	}
+	return u.rateRepository.UpsertExchangeRate(exchangeRate)
+}
+
+func (u *CurrencyUseCase) ListRates(rateDate time.Time, query *domain.ListQuery) (*domain.Page[*domain.ExchangeRate], error) {
+	return u.rateRepository.GetExchangeRatesByDate(rateDate, query)
+}
+
+func (u *CurrencyUseCase) GetRate(baseCoin enums.CoinType, quoteCoin enums.CoinType, at time.Time) (*big.Rat, error) {
+	if baseCoin == quoteCoin {
+		return big.NewRat(1, 1), nil
+	}
+
+	rate, err := u.rateRepository.FindLatestExchangeRate(string(baseCoin), string(quoteCoin), at)
+	if err != nil {
+		return nil, err
+	}
+
+	return rate.Rate, nil
+}
+
+// Convert turns amount (in cents of from) into cents of to, applying the
+// website rounding rule configured for the target currency.
+func (u *CurrencyUseCase) Convert(websiteUUID string, amount int, from enums.CoinType, to enums.CoinType) (int, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := u.GetRate(from, to, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	converted := domain.ConvertAmount(amount, rate)
+
+	rounding, err := u.roundingRepository.FindCurrencyRounding(websiteUUID, string(to))
+	if err != nil {
+		return 0, err
+	}
+
+	return rounding.Apply(converted), nil
+}
+
+func (u *CurrencyUseCase) SetRounding(websiteUUID string, coin string, mode string, increment int, ending int) (*domain.CurrencyRounding, error) {
+	rounding, err := domain.NewCurrencyRounding(websiteUUID, coin, mode, increment, ending)
+	if err != nil {
+		return nil, err
+	}
+	return u.roundingRepository.UpsertCurrencyRounding(rounding)
+}
+
+func (u *CurrencyUseCase) ListRoundings(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.CurrencyRounding], error) {
+	return u.roundingRepository.GetCurrencyRoundingsFromWebsite(websiteUUID, query)
+}
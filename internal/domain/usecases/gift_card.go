@@ -0,0 +1,302 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Codes are random enough that a collision is unlikely; a few retries make
+// it practically impossible.
+const giftCardCodeAttempts = 3
+
+var (
+	ErrGiftCardNotFound    = errors.New("gift card not found")
+	ErrGiftCardCodeMissing = errors.New("gift card code cannot be empty")
+)
+
+type GiftCardUseCase struct {
+	repository      contracts.GiftCardContract
+	cupomRepository contracts.CupomContract
+	mailer          contracts.MailerContract
+}
+
+func NewGiftCardUseCase(repository contracts.GiftCardContract, cupomRepository contracts.CupomContract, mailer contracts.MailerContract) *GiftCardUseCase {
+	return &GiftCardUseCase{
+		repository:      repository,
+		cupomRepository: cupomRepository,
+		mailer:          mailer,
+	}
+}
+
+// IssueManual creates a card by hand, e.g. for a giveaway. The code is
+// returned only here.
+func (u *GiftCardUseCase) IssueManual(websiteUUID string, coin string, amount int, expiresAt *time.Time, recipientEmail string, note string) (*domain.GiftCard, string, error) {
+	return u.issue(websiteUUID, enums.GiftCard, enums.GiftCardManual, coin, amount, expiresAt, func(card *domain.GiftCard) {
+		card.RecipientEmail = strings.TrimSpace(recipientEmail)
+		card.Note = strings.TrimSpace(note)
+	})
+}
+
+// IssueOrder is meant to be handed to OrderUseCase.OnPaid: it creates the
+// cards bought as gift card products, one per unit at the price paid for
+// it, valid for DefaultGiftCardValidityDays, and mails their codes to the
+// buyer. Codes are not stored in clear, so nothing is issued without a
+// mailer, and a card whose email fails is logged for the merchant to
+// replace. Cards the order already issued are skipped, so a failed run can
+// be tried again.
+func (u *GiftCardUseCase) IssueOrder(order *domain.Order) error {
+	if !order.Paid() {
+		return nil
+	}
+
+	if u.mailer == nil && slices.ContainsFunc(order.Items, func(item *domain.OrderItem) bool {
+		return item.ProductType == enums.GiftCardProduct
+	}) {
+		return ErrMailerMissing
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, domain.DefaultGiftCardValidityDays)
+	issued := make(map[uuid.UUID]int)
+	for _, item := range order.Items {
+		if item.ProductType != enums.GiftCardProduct {
+			continue
+		}
+
+		if _, ok := issued[item.ProductUUID]; !ok {
+			count, err := u.repository.CountOrderGiftCards(order.UUID.String(), item.ProductUUID.String())
+			if err != nil {
+				return err
+			}
+			issued[item.ProductUUID] = count
+		}
+
+		skip := min(issued[item.ProductUUID], item.Quantity)
+		issued[item.ProductUUID] -= skip
+
+		for range item.Quantity - skip {
+			card, code, err := u.issue(order.WebsiteUUID.String(), enums.GiftCard, enums.GiftCardOrder, string(order.Coin), item.UnitAmount, &expiresAt, func(card *domain.GiftCard) {
+				card.ProductUUID = &item.ProductUUID
+				card.OrderUUID = &order.UUID
+				card.RecipientEmail = order.Email
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := u.sendCode(card, code, item.Name); err != nil {
+				logger.Warn(fmt.Errorf("order %s gift card %s email: %w", order.UUID, card.UUID, err)).Print()
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *GiftCardUseCase) sendCode(card *domain.GiftCard, code string, name string) error {
+	if u.mailer == nil {
+		return ErrMailerMissing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return u.mailer.Send(ctx, domain.Email{
+		To:      card.RecipientEmail,
+		Subject: fmt.Sprintf("Your %s", name),
+		Body: fmt.Sprintf(
+			"Your gift card code is %s\n\nIt is worth %s %s and expires on %s.",
+			code,
+			formatStatementAmount(card.Balance),
+			card.Coin,
+			card.ExpiresAt.Format(time.DateOnly),
+		),
+	})
+}
+
+// IssueStoreCredit refunds part of an order as credit only userUUID can
+// spend.
+func (u *GiftCardUseCase) IssueStoreCredit(websiteUUID string, orderUUID string, userUUID string, coin string, amount int, note string) (*domain.GiftCard, string, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, "", errors.New("Order UUID is invalid.")
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, "", errors.New("User UUID is invalid.")
+	}
+
+	return u.issue(websiteUUID, enums.StoreCredit, enums.GiftCardRefund, coin, amount, nil, func(card *domain.GiftCard) {
+		card.UserUUID = &userUUIDParsed
+		card.OrderUUID = &orderUUIDParsed
+		card.Note = strings.TrimSpace(note)
+	})
+}
+
+func (u *GiftCardUseCase) issue(websiteUUID string, cardType enums.GiftCardType, source enums.GiftCardSourceType, coin string, amount int, expiresAt *time.Time, fill func(*domain.GiftCard)) (*domain.GiftCard, string, error) {
+	for attempt := 1; ; attempt++ {
+		code, err := domain.NewGiftCardCode()
+		if err != nil {
+			return nil, "", err
+		}
+
+		card, err := domain.NewGiftCard(websiteUUID, cardType, source, code, coin, amount, expiresAt)
+		if err != nil {
+			return nil, "", err
+		}
+		fill(card)
+
+		created, err := u.repository.CreateGiftCard(card)
+		if errors.Is(err, domain.ErrGiftCardCodeExists) && attempt < giftCardCodeAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		return created, code, nil
+	}
+}
+
+func (u *GiftCardUseCase) Get(uuidStr string, websiteUUID string) (*domain.GiftCard, error) {
+	card, err := u.repository.FindGiftCardByUUID(uuidStr)
+	if err != nil || card.WebsiteUUID.String() != websiteUUID {
+		return nil, ErrGiftCardNotFound
+	}
+
+	return card, nil
+}
+
+func (u *GiftCardUseCase) List(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCard], error) {
+	return u.repository.GetGiftCards(websiteUUID, query)
+}
+
+func (u *GiftCardUseCase) Transactions(uuidStr string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCardTransaction], error) {
+	if _, err := u.Get(uuidStr, websiteUUID); err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetGiftCardTransactions(uuidStr, query)
+}
+
+// ListStoreCredit returns the customer's store credit on the website.
+func (u *GiftCardUseCase) ListStoreCredit(websiteUUID string, userUUID string) ([]*domain.GiftCard, error) {
+	return u.repository.GetGiftCardsByUser(websiteUUID, userUUID)
+}
+
+// Balance looks a card up by its code, for shoppers checking what is left.
+func (u *GiftCardUseCase) Balance(websiteUUID string, code string) (*domain.GiftCard, error) {
+	return u.findByCode(websiteUUID, code)
+}
+
+func (u *GiftCardUseCase) findByCode(websiteUUID string, code string) (*domain.GiftCard, error) {
+	if strings.TrimSpace(code) == "" {
+		return nil, ErrGiftCardCodeMissing
+	}
+
+	card, err := u.repository.FindGiftCardByCode(websiteUUID, domain.HashGiftCardCode(code))
+	if err != nil {
+		return nil, ErrGiftCardNotFound
+	}
+
+	return card, nil
+}
+
+// Quote works out the checkout totals without touching any balance. With
+// useStoreCredit the customer's usable store credit is applied after the
+// entered codes, without them having to type its code.
+func (u *GiftCardUseCase) Quote(websiteUUID string, userUUID string, coin string, subtotal int, cupomUUID string, codes []string, useStoreCredit bool) (*domain.CheckoutTotals, error) {
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	var cupom *domain.Cupons
+	if cupomUUID != "" {
+		found, err := u.cupomRepository.FindCupomByUUID(cupomUUID)
+		if err != nil {
+			return nil, err
+		}
+		cupom = found
+	}
+
+	cards := make([]*domain.GiftCard, 0, len(codes))
+	for _, code := range codes {
+		card, err := u.findByCode(websiteUUID, code)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	if useStoreCredit && userUUID != "" {
+		credits, err := u.repository.GetGiftCardsByUser(websiteUUID, userUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		for _, credit := range credits {
+			if credit.Usable(coinType, userUUID, now) != nil || slices.ContainsFunc(cards, func(c *domain.GiftCard) bool { return c.UUID == credit.UUID }) {
+				continue
+			}
+			cards = append(cards, credit)
+		}
+	}
+
+	return domain.ComputeCheckoutTotals(websiteUUID, coinType, subtotal, cupom, cards, userUUID, time.Now())
+}
+
+// Redeem quotes the order and takes the gift card share off the cards in
+// one go. The returned totals say what is left for the other payment
+// methods; if they fail, Release gives the money back.
+func (u *GiftCardUseCase) Redeem(websiteUUID string, userUUID string, orderUUID string, coin string, subtotal int, cupomUUID string, codes []string, useStoreCredit bool) (*domain.CheckoutTotals, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	if len(codes) == 0 && !useStoreCredit {
+		return nil, ErrGiftCardCodeMissing
+	}
+
+	totals, err := u.Quote(websiteUUID, userUUID, coin, subtotal, cupomUUID, codes, useStoreCredit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(totals.GiftCards) == 0 {
+		return totals, nil
+	}
+
+	if _, err := u.repository.RedeemGiftCards(orderUUIDParsed, totals.GiftCards); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+func (u *GiftCardUseCase) Release(orderUUID string) ([]*domain.GiftCardTransaction, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	return u.repository.ReleaseGiftCards(orderUUID)
+}
+
+// ExpireDue is meant to be handed to the scheduler.
+func (u *GiftCardUseCase) ExpireDue() {
+	if _, err := u.repository.ExpireGiftCards(context.Background()); err != nil {
+		logger.Warn(fmt.Errorf("gift card expiry: %w", err)).Print()
+	}
+}
@@ -0,0 +1,339 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLoyaltyProgramNotFound    = errors.New("loyalty program not found")
+	ErrLoyaltyRedemptionNotFound = errors.New("loyalty redemption not found")
+	ErrLoyaltyEarningNotFound    = errors.New("loyalty earning not found")
+)
+
+type LoyaltyUseCase struct {
+	repository     contracts.LoyaltyContract
+	userRepository contracts.UserContract
+}
+
+func NewLoyaltyUseCase(repository contracts.LoyaltyContract, userRepository contracts.UserContract) *LoyaltyUseCase {
+	return &LoyaltyUseCase{
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+func (u *LoyaltyUseCase) GetProgram(websiteUUID string) (*domain.LoyaltyProgram, error) {
+	program, err := u.repository.FindLoyaltyProgram(websiteUUID)
+	if err != nil {
+		return nil, ErrLoyaltyProgramNotFound
+	}
+
+	return program, nil
+}
+
+func (u *LoyaltyUseCase) SaveProgram(websiteUUID string, active bool, firstOrderBonus int, reviewBonus int, expiryDays int, minRedeemPoints int, rates []*domain.LoyaltyRate) (*domain.LoyaltyProgram, error) {
+	program, err := domain.NewLoyaltyProgram(websiteUUID, active, firstOrderBonus, reviewBonus, expiryDays, minRedeemPoints, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.SaveLoyaltyProgram(program)
+}
+
+// activeProgram is the website's program, as long as it is switched on.
+func (u *LoyaltyUseCase) activeProgram(websiteUUID string) (*domain.LoyaltyProgram, error) {
+	program, err := u.GetProgram(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !program.Active {
+		return nil, domain.ErrLoyaltyInactive
+	}
+
+	return program, nil
+}
+
+// EarnOrder is meant to be handed to OrderUseCase.OnPaid: the order earns
+// points on its total, discounts taken off.
+func (u *LoyaltyUseCase) EarnOrder(order *domain.Order) error {
+	if !order.Paid() || order.Total <= 0 {
+		return nil
+	}
+
+	_, err := u.earn(order.WebsiteUUID.String(), order.UUID.String(), order.UserUUID.String(), string(order.Coin), order.Total)
+	if errors.Is(err, ErrLoyaltyProgramNotFound) || errors.Is(err, domain.ErrLoyaltyInactive) || errors.Is(err, domain.ErrLoyaltyCoin) || errors.Is(err, domain.ErrLoyaltyApplied) {
+		return nil
+	}
+
+	return err
+}
+
+// earn credits the points for a paid order of amount cents, plus the first
+// order bonus when the customer never earned on this website before. An
+// order only earns once.
+func (u *LoyaltyUseCase) earn(websiteUUID string, orderUUID string, userUUID string, coin string, amount int) ([]*domain.LoyaltyTransaction, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("Amount must be greater than zero.")
+	}
+
+	program, err := u.activeProgram(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	coinType := enums.CoinType(coin)
+	points, err := program.PointsFor(coinType, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	earnings, err := u.repository.CountLoyaltyEarnings(websiteUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.repository.FindLoyaltyTransactionByOrder(orderUUID, enums.LoyaltyEarn); err == nil {
+		return nil, domain.ErrLoyaltyApplied
+	}
+
+	now := time.Now()
+	transactions := []*domain.LoyaltyTransaction{}
+
+	earn := &domain.LoyaltyTransaction{
+		WebsiteUUID: program.WebsiteUUID,
+		UserUUID:    userUUIDParsed,
+		Type:        enums.LoyaltyEarn,
+		Points:      points,
+		Coin:        &coinType,
+		Amount:      &amount,
+		OrderUUID:   &orderUUIDParsed,
+		ExpiresAt:   program.ExpiresAt(now),
+	}
+	if points > 0 {
+		if _, err := u.repository.AddLoyaltyPoints(earn); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, earn)
+	}
+
+	if earnings == 0 && program.FirstOrderBonus > 0 {
+		bonus := &domain.LoyaltyTransaction{
+			WebsiteUUID: program.WebsiteUUID,
+			UserUUID:    userUUIDParsed,
+			Type:        enums.LoyaltyFirstOrderBonus,
+			Points:      program.FirstOrderBonus,
+			OrderUUID:   &orderUUIDParsed,
+			ExpiresAt:   program.ExpiresAt(now),
+		}
+		_, err := u.repository.AddLoyaltyPoints(bonus)
+		if err != nil && !errors.Is(err, domain.ErrLoyaltyApplied) {
+			return nil, err
+		}
+		if err == nil {
+			transactions = append(transactions, bonus)
+		}
+	}
+
+	return transactions, nil
+}
+
+// AwardReviewBonus is meant to be registered with
+// ProductReviewUseCase.OnApprove. Each approved review earns its author
+// the bonus once, on the website they shop at.
+func (u *LoyaltyUseCase) AwardReviewBonus(review *domain.ProductReview) {
+	if review.Status != enums.ReviewApproved {
+		return
+	}
+
+	user, err := u.userRepository.FindUserByUUID(review.UserUUID.String())
+	if err != nil {
+		logger.Warn(fmt.Errorf("loyalty review bonus %s: %w", review.UUID, err)).Print()
+		return
+	}
+
+	program, err := u.activeProgram(user.WebSiteUUID.String())
+	if err != nil || program.ReviewBonus == 0 {
+		return
+	}
+
+	bonus := &domain.LoyaltyTransaction{
+		WebsiteUUID: program.WebsiteUUID,
+		UserUUID:    user.UUID,
+		Type:        enums.LoyaltyReviewBonus,
+		Points:      program.ReviewBonus,
+		ReviewUUID:  &review.UUID,
+		ExpiresAt:   program.ExpiresAt(time.Now()),
+	}
+	if _, err := u.repository.AddLoyaltyPoints(bonus); err != nil && !errors.Is(err, domain.ErrLoyaltyApplied) {
+		logger.Warn(fmt.Errorf("loyalty review bonus %s: %w", review.UUID, err)).Print()
+	}
+}
+
+// Quote works out the discount offered points give on subtotal cents,
+// which should already have any coupon taken off. Nothing is spent.
+func (u *LoyaltyUseCase) Quote(websiteUUID string, userUUID string, coin string, points int, subtotal int) (*domain.LoyaltyQuote, error) {
+	program, err := u.activeProgram(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	coinType := enums.CoinType(coin)
+	used, discount, err := program.Redemption(coinType, points, subtotal)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := u.repository.GetLoyaltyBalance(websiteUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if points > balance.Points {
+		return nil, domain.ErrLoyaltyInsufficient
+	}
+
+	return &domain.LoyaltyQuote{
+		Coin:      coinType,
+		Points:    used,
+		Discount:  discount,
+		Available: balance.Points,
+	}, nil
+}
+
+// Redeem quotes the order and spends the points. An order redeems once; if
+// it is canceled or payment fails, Release gives the points back.
+func (u *LoyaltyUseCase) Redeem(websiteUUID string, userUUID string, orderUUID string, coin string, points int, subtotal int) (*domain.LoyaltyQuote, error) {
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	quote, err := u.Quote(websiteUUID, userUUID, coin, points, subtotal)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.Points == 0 {
+		return quote, nil
+	}
+
+	websiteUUIDParsed, err := uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, errors.New("Website UUID is invalid.")
+	}
+
+	userUUIDParsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return nil, errors.New("User UUID is invalid.")
+	}
+
+	redeem := &domain.LoyaltyTransaction{
+		WebsiteUUID: websiteUUIDParsed,
+		UserUUID:    userUUIDParsed,
+		Type:        enums.LoyaltyRedeem,
+		Points:      -quote.Points,
+		Coin:        &quote.Coin,
+		Amount:      &quote.Discount,
+		OrderUUID:   &orderUUIDParsed,
+	}
+	if _, err := u.repository.SpendLoyaltyPoints(redeem); err != nil {
+		return nil, err
+	}
+
+	quote.Available -= quote.Points
+	return quote, nil
+}
+
+// Release returns the points an order redeemed. They come back as a new
+// entry, expiring under the program's current policy.
+func (u *LoyaltyUseCase) Release(orderUUID string) (*domain.LoyaltyTransaction, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	redeem, err := u.repository.FindLoyaltyTransactionByOrder(orderUUID, enums.LoyaltyRedeem)
+	if err != nil {
+		return nil, ErrLoyaltyRedemptionNotFound
+	}
+
+	var expiresAt *time.Time
+	if program, err := u.repository.FindLoyaltyProgram(redeem.WebsiteUUID.String()); err == nil {
+		expiresAt = program.ExpiresAt(time.Now())
+	}
+
+	return u.repository.AddLoyaltyPoints(&domain.LoyaltyTransaction{
+		WebsiteUUID: redeem.WebsiteUUID,
+		UserUUID:    redeem.UserUUID,
+		Type:        enums.LoyaltyRelease,
+		Points:      -redeem.Points,
+		Coin:        redeem.Coin,
+		Amount:      redeem.Amount,
+		OrderUUID:   redeem.OrderUUID,
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// ReverseOrder is meant to be handed to OrderUseCase.OnRefund.
+func (u *LoyaltyUseCase) ReverseOrder(order *domain.Order, refundAmount int) error {
+	_, err := u.reverse(order.UUID.String(), order.RefundedAmount, refundAmount)
+	if errors.Is(err, ErrLoyaltyEarningNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// reverse takes back the points an order earned in proportion to what it
+// refunded in total, refundedTo cents after a refund of refundAmount. A nil
+// transaction means nothing was left to take: the points were already
+// reversed or spent.
+func (u *LoyaltyUseCase) reverse(orderUUID string, refundedTo int, refundAmount int) (*domain.LoyaltyTransaction, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	if refundAmount <= 0 {
+		return nil, errors.New("Amount must be greater than zero.")
+	}
+
+	if _, err := u.repository.FindLoyaltyTransactionByOrder(orderUUID, enums.LoyaltyEarn); err != nil {
+		return nil, ErrLoyaltyEarningNotFound
+	}
+
+	return u.repository.ReverseLoyaltyEarning(orderUUID, refundedTo, refundAmount)
+}
+
+func (u *LoyaltyUseCase) Balance(websiteUUID string, userUUID string) (*domain.LoyaltyBalance, error) {
+	return u.repository.GetLoyaltyBalance(websiteUUID, userUUID)
+}
+
+func (u *LoyaltyUseCase) History(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.LoyaltyTransaction], error) {
+	return u.repository.GetLoyaltyTransactions(websiteUUID, userUUID, query)
+}
+
+// ExpireDue is meant to be handed to the scheduler.
+func (u *LoyaltyUseCase) ExpireDue() {
+	if _, err := u.repository.ExpireLoyaltyPoints(context.Background()); err != nil {
+		logger.Warn(fmt.Errorf("loyalty expiry: %w", err)).Print()
+	}
+}
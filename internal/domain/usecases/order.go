@@ -0,0 +1,477 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// How long a single charge may take at the payment provider.
+const paymentTimeout = 30 * time.Second
+
+// The events of a refund wait this long for the provider to make it, and
+// run anyway if the process stops before it answered.
+const refundEventHold = 2 * paymentTimeout
+
+// How many order events one RunEvents round claims.
+const orderEventBatch = 50
+
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrPaymentMethodMissing   = errors.New("payment method missing")
+	ErrPaymentDeclined        = errors.New("payment declined")
+	ErrPaymentProviderMissing = errors.New("payment provider not configured")
+)
+
+// OrderUseCase turns carts into paid orders. Listeners registered with
+// OnPaid and OnRefund run after an order is paid or refunded, and are how
+// downloads, gift cards, loyalty points and platform fees follow the order.
+// Each run is an event stored with the payment or refund, retried until the
+// listener succeeds, so listeners must be safe to run again.
+type OrderUseCase struct {
+	repository      contracts.OrderContract
+	cupomRepository contracts.CupomContract
+	tagRepository   contracts.ProductTagContract
+	cartUseCase     *CartUseCase
+	loyaltyUseCase  *LoyaltyUseCase
+	giftCardUseCase *GiftCardUseCase
+	provider        contracts.PaymentProviderContract
+
+	mu              sync.RWMutex
+	paidHooks       []string
+	paidListeners   map[string]func(*domain.Order) error
+	refundHooks     []string
+	refundListeners map[string]func(*domain.Order, int) error
+}
+
+func NewOrderUseCase(repository contracts.OrderContract, cupomRepository contracts.CupomContract, tagRepository contracts.ProductTagContract, cartUseCase *CartUseCase, loyaltyUseCase *LoyaltyUseCase, giftCardUseCase *GiftCardUseCase, provider contracts.PaymentProviderContract) *OrderUseCase {
+	return &OrderUseCase{
+		repository:      repository,
+		cupomRepository: cupomRepository,
+		tagRepository:   tagRepository,
+		cartUseCase:     cartUseCase,
+		loyaltyUseCase:  loyaltyUseCase,
+		giftCardUseCase: giftCardUseCase,
+		provider:        provider,
+		paidListeners:   make(map[string]func(*domain.Order) error),
+		refundListeners: make(map[string]func(*domain.Order, int) error),
+	}
+}
+
+// Checkout orders the shopper's open cart in the base coin of the website.
+// The coupon, loyalty points and gift cards are taken before charging and
+// given back if the charge fails, in which case the order is left failed
+// and the cart open. A cart has one order in progress at a time, so a
+// second checkout of it fails with domain.ErrCartCheckingOut.
+func (u *OrderUseCase) Checkout(websiteUUID string, userUUID string, cupomUUID string, giftCardCodes []string, useStoreCredit bool, loyaltyPoints int, paymentMethod string) (*domain.Order, error) {
+	cart, err := u.cartUseCase.repository.FindOpenCart(websiteUUID, userUUID)
+	if err != nil {
+		return nil, domain.ErrCartEmpty
+	}
+
+	website, err := u.cartUseCase.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := u.cartUseCase.Quote(cart, string(website.BaseCoin))
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := u.repository.CreateOrder(domain.NewOrder(quote, paymentMethod))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.redeem(order, cupomUUID, giftCardCodes, useStoreCredit, loyaltyPoints); err != nil {
+		u.fail(order, err.Error())
+		return nil, err
+	}
+
+	if err := u.repository.UpdateOrderTotals(order); err != nil {
+		u.fail(order, err.Error())
+		return nil, err
+	}
+
+	if err := u.charge(order); err != nil {
+		u.fail(order, order.FailureReason)
+		return nil, err
+	}
+
+	u.mu.RLock()
+	hooks := u.paidHooks
+	u.mu.RUnlock()
+
+	if err := u.repository.MarkOrderPaid(order, hooks); err != nil {
+		u.void(order)
+		u.fail(order, err.Error())
+		return nil, err
+	}
+
+	u.runEvents(order.UUID.String())
+	return order, nil
+}
+
+// redeem takes the coupon, then the loyalty points, then the gift cards
+// off the order, each applying to what the previous ones left. Coupons and
+// points leave gift card items alone.
+func (u *OrderUseCase) redeem(order *domain.Order, cupomUUID string, giftCardCodes []string, useStoreCredit bool, loyaltyPoints int) error {
+	websiteUUID := order.WebsiteUUID.String()
+	userUUID := order.UserUUID.String()
+	orderUUID := order.UUID.String()
+
+	discount := 0
+	if cupomUUID != "" {
+		cupom, err := u.cupomRepository.FindCupomByUUID(cupomUUID)
+		if err != nil {
+			return domain.ErrCupomNotFound
+		}
+
+		if err := cupom.Usable(websiteUUID, order.Coin, time.Now()); err != nil {
+			return err
+		}
+
+		base := order.Discountable(nil)
+		if cupom.TagUUID != nil {
+			base, err = u.taggedAmount(order, cupom.TagUUID.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		discount, err = cupom.Discount(base)
+		if err != nil {
+			return err
+		}
+
+		if err := u.cupomRepository.UseCupom(cupomUUID); err != nil {
+			return err
+		}
+		order.CupomUUID = &cupom.UUID
+	}
+
+	loyaltyDiscount := 0
+	if loyaltyPoints > 0 {
+		quote, err := u.loyaltyUseCase.Redeem(websiteUUID, userUUID, orderUUID, string(order.Coin), loyaltyPoints, order.Discountable(nil)-discount)
+		if err != nil {
+			return err
+		}
+		loyaltyPoints, loyaltyDiscount = quote.Points, quote.Discount
+	}
+	order.ApplyDiscounts(discount, loyaltyPoints, loyaltyDiscount)
+
+	if len(giftCardCodes) > 0 || useStoreCredit {
+		totals, err := u.giftCardUseCase.Redeem(websiteUUID, userUUID, orderUUID, string(order.Coin), order.Total, "", giftCardCodes, useStoreCredit)
+		if err != nil {
+			return err
+		}
+		order.ApplyGiftCards(order.Total - totals.AmountDue)
+	}
+
+	return nil
+}
+
+// taggedAmount is what the items tagged like tagUUID cost, the part of the
+// order a tag coupon discounts. Tags are labels on each product, so every
+// product with the label of tagUUID counts.
+func (u *OrderUseCase) taggedAmount(order *domain.Order, tagUUID string) (int, error) {
+	tag, err := u.tagRepository.FindProductTagByUUID(tagUUID)
+	if err != nil {
+		return 0, nil
+	}
+
+	tags, err := u.tagRepository.FindProductTagsByLabel(tag.Label)
+	if err != nil {
+		return 0, err
+	}
+
+	products := map[uuid.UUID]bool{tag.ProductUUID: true}
+	for _, t := range tags {
+		products[t.ProductUUID] = true
+	}
+
+	return order.Discountable(products), nil
+}
+
+// charge takes what gift cards did not cover from the payment method.
+func (u *OrderUseCase) charge(order *domain.Order) error {
+	if order.AmountPaid == 0 {
+		return nil
+	}
+
+	if u.provider == nil {
+		order.FailureReason = ErrPaymentProviderMissing.Error()
+		return ErrPaymentProviderMissing
+	}
+
+	if order.PaymentMethod == "" {
+		order.FailureReason = ErrPaymentMethodMissing.Error()
+		return ErrPaymentMethodMissing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	result, err := u.provider.Charge(ctx, domain.PaymentCharge{
+		IdempotencyKey: order.UUID.String(),
+		CustomerUUID:   order.UserUUID.String(),
+		PaymentMethod:  order.PaymentMethod,
+		Coin:           order.Coin,
+		Amount:         order.AmountPaid,
+		Description:    fmt.Sprintf("Order %s", order.UUID),
+	})
+	if err != nil {
+		result = &domain.PaymentResult{FailureReason: err.Error()}
+	}
+
+	order.PaymentReference = result.Reference
+	if !result.Paid {
+		order.FailureReason = result.FailureReason
+		return ErrPaymentDeclined
+	}
+
+	return nil
+}
+
+// void gives a charge back when the order could not be settled after it.
+// Errors are logged: the shopper is told about the original failure.
+func (u *OrderUseCase) void(order *domain.Order) {
+	if order.AmountPaid == 0 || u.provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	result, err := u.provider.Refund(ctx, domain.PaymentRefund{
+		IdempotencyKey: order.UUID.String() + "-void",
+		Reference:      order.PaymentReference,
+		Coin:           order.Coin,
+		Amount:         order.AmountPaid,
+	})
+	if err == nil && !result.Paid {
+		err = errors.New(result.FailureReason)
+	}
+	if err != nil {
+		logger.Warn(fmt.Errorf("order %s void: %w", order.UUID, err)).Print()
+	}
+}
+
+// fail gives back whatever the order took and marks it failed. Errors are
+// logged: the shopper is told about the original failure.
+func (u *OrderUseCase) fail(order *domain.Order, reason string) {
+	orderUUID := order.UUID.String()
+
+	if order.GiftCardAmount > 0 {
+		if _, err := u.giftCardUseCase.Release(orderUUID); err != nil {
+			logger.Warn(fmt.Errorf("order %s gift card release: %w", orderUUID, err)).Print()
+		}
+	}
+
+	if order.LoyaltyPoints > 0 {
+		if _, err := u.loyaltyUseCase.Release(orderUUID); err != nil {
+			logger.Warn(fmt.Errorf("order %s loyalty release: %w", orderUUID, err)).Print()
+		}
+	}
+
+	if order.CupomUUID != nil {
+		if err := u.cupomRepository.ReleaseCupom(order.CupomUUID.String()); err != nil {
+			logger.Warn(fmt.Errorf("order %s cupom release: %w", orderUUID, err)).Print()
+		}
+	}
+
+	order.FailureReason = reason
+	if err := u.repository.MarkOrderFailed(order); err != nil {
+		logger.Warn(fmt.Errorf("order %s: %w", orderUUID, err)).Print()
+	}
+}
+
+// Get returns the order for its website's staff; permission is checked by
+// the caller against order.WebsiteUUID.
+func (u *OrderUseCase) Get(uuidStr string) (*domain.Order, error) {
+	order, err := u.repository.FindOrderByUUID(uuidStr)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// GetForUser returns the order only to the shopper who placed it.
+func (u *OrderUseCase) GetForUser(uuidStr string, userUUID string) (*domain.Order, error) {
+	order, err := u.Get(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserUUID.String() != userUUID {
+		return nil, ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+func (u *OrderUseCase) ListByUser(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error) {
+	return u.repository.GetOrdersByUser(websiteUUID, userUUID, query)
+}
+
+func (u *OrderUseCase) List(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error) {
+	return u.repository.GetOrders(websiteUUID, query)
+}
+
+// Refund gives amount of a paid order back, to the payment method or, with
+// toStoreCredit, as store credit of the shopper. The amount is reserved on
+// the order first so concurrent refunds cannot exceed it, and the
+// reservation undone if the provider declines.
+func (u *OrderUseCase) Refund(order *domain.Order, amount int, toStoreCredit bool) (*domain.Order, error) {
+	if err := order.CheckRefund(amount, toStoreCredit); err != nil {
+		return nil, err
+	}
+
+	providerAmount := amount
+	if toStoreCredit {
+		providerAmount = 0
+	}
+
+	if providerAmount > 0 && u.provider == nil {
+		return nil, ErrPaymentProviderMissing
+	}
+
+	u.mu.RLock()
+	hooks := u.refundHooks
+	u.mu.RUnlock()
+
+	if err := u.repository.RefundOrder(order, amount, providerAmount, hooks, time.Now().Add(refundEventHold)); err != nil {
+		return nil, err
+	}
+
+	if toStoreCredit {
+		_, _, err := u.giftCardUseCase.IssueStoreCredit(order.WebsiteUUID.String(), order.UUID.String(), order.UserUUID.String(), string(order.Coin), amount, fmt.Sprintf("Refund of order %s", order.UUID))
+		if err != nil {
+			u.undoRefund(order, amount, providerAmount)
+			return nil, err
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+		result, err := u.provider.Refund(ctx, domain.PaymentRefund{
+			IdempotencyKey: fmt.Sprintf("%s-refund-%d", order.UUID, order.RefundedAmount),
+			Reference:      order.PaymentReference,
+			Coin:           order.Coin,
+			Amount:         providerAmount,
+		})
+		cancel()
+		if err != nil || !result.Paid {
+			u.undoRefund(order, amount, providerAmount)
+			return nil, ErrPaymentDeclined
+		}
+	}
+
+	if err := u.repository.ReleaseOrderEvents(order.UUID.String(), order.RefundedAmount); err != nil {
+		logger.Warn(fmt.Errorf("order %s refund events: %w", order.UUID, err)).Print()
+	}
+	u.runEvents(order.UUID.String())
+
+	return order, nil
+}
+
+func (u *OrderUseCase) undoRefund(order *domain.Order, amount int, providerAmount int) {
+	if err := u.repository.RefundOrder(order, -amount, -providerAmount, nil, time.Now()); err != nil {
+		logger.Warn(fmt.Errorf("order %s refund undo: %w", order.UUID, err)).Print()
+	}
+}
+
+// OnPaid registers the listener called for every order paid under name,
+// which is what its events are stored by and so must not change.
+func (u *OrderUseCase) OnPaid(name string, listener func(*domain.Order) error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.paidHooks = append(u.paidHooks, name)
+	u.paidListeners[name] = listener
+}
+
+// OnRefund registers the listener called for every refund under name, with
+// the amount refunded. The order it gets is as it was after that refund.
+func (u *OrderUseCase) OnRefund(name string, listener func(*domain.Order, int) error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.refundHooks = append(u.refundHooks, name)
+	u.refundListeners[name] = listener
+}
+
+// RunEvents is meant to be handed to the scheduler: it runs the order
+// events that are due, retrying the ones that failed.
+func (u *OrderUseCase) RunEvents() {
+	u.runEvents("")
+}
+
+// runEvents runs the due events of orderUUID, or of every order when it is
+// empty.
+func (u *OrderUseCase) runEvents(orderUUID string) {
+	for {
+		events, err := u.repository.ClaimOrderEvents(orderUUID, orderEventBatch)
+		if err != nil {
+			logger.Warn(err).Print()
+			return
+		}
+
+		for _, event := range events {
+			u.runEvent(event)
+		}
+
+		if len(events) < orderEventBatch {
+			return
+		}
+	}
+}
+
+func (u *OrderUseCase) runEvent(event *domain.OrderEvent) {
+	if err := u.dispatch(event); err != nil {
+		logger.Warn(fmt.Errorf("order %s %s %s hook: %w", event.OrderUUID, event.Type, event.Hook, err)).Print()
+		event.Retry(err, time.Now())
+	} else {
+		event.Status = enums.OrderEventDone
+		event.LastError = ""
+	}
+
+	if err := u.repository.UpdateOrderEvent(event); err != nil {
+		logger.Warn(fmt.Errorf("order event %s: %w", event.UUID, err)).Print()
+	}
+}
+
+func (u *OrderUseCase) dispatch(event *domain.OrderEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+
+	order, err := u.repository.FindOrderByUUID(event.OrderUUID.String())
+	if err != nil {
+		return err
+	}
+
+	u.mu.RLock()
+	paid, refund := u.paidListeners[event.Hook], u.refundListeners[event.Hook]
+	u.mu.RUnlock()
+
+	switch {
+	case event.Type == enums.OrderEventPaid && paid != nil:
+		return paid(order)
+	case event.Type == enums.OrderEventRefund && refund != nil:
+		order.RefundedAmount = event.RefundedTo
+		return refund(order, event.Amount)
+	default:
+		return errors.New("no listener")
+	}
+}
@@ -45,6 +45,6 @@ func (u *CreateOrganizationUseCase) GetByUUID(uuidStr string) (*domain.Organizat
 	return u.orgRepo.FindOrganizationByUUID(uuidStr)
 }
 
-func (u *CreateOrganizationUseCase) GetAll(websiteUUIDStr string) ([]*domain.OrganizationBR, error) {
-	return u.orgRepo.GetOrganizationsFromWebsite(websiteUUIDStr)
+func (u *CreateOrganizationUseCase) GetAll(websiteUUIDStr string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationBR], error) {
+	return u.orgRepo.GetOrganizationsFromWebsite(websiteUUIDStr, query)
 }
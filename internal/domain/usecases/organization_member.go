@@ -0,0 +1,427 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrMemberNotFound        = errors.New("organization member not found")
+	ErrMemberForbidden       = errors.New("permission denied")
+	ErrOwnerRequired         = errors.New("owner required")
+	ErrMemberRoleNotFound    = errors.New("role not found")
+	ErrMemberWebsiteNotFound = errors.New("website not found")
+	ErrInvitationNotFound    = errors.New("invitation not found")
+	ErrInvitationEmail       = errors.New("invitation was sent to another email")
+	ErrInvitationDelivery    = errors.New("invitation email could not be delivered")
+	ErrMailerMissing         = errors.New("mailer not configured")
+)
+
+// OrganizationMemberUseCase manages who belongs to an organization and
+// what they may do on the websites the organization owns.
+type OrganizationMemberUseCase struct {
+	repository             contracts.OrganizationMemberContract
+	organizationRepository contracts.OrganizationContract
+	userRepository         contracts.UserContract
+	rbacRepository         contracts.RbacContract
+	websiteRepository      contracts.WebsiteContract
+	twoFactorRepository    contracts.TwoFactorContract
+	mailer                 contracts.MailerContract
+	viewURL                string
+}
+
+func NewOrganizationMemberUseCase(repository contracts.OrganizationMemberContract, organizationRepository contracts.OrganizationContract, userRepository contracts.UserContract, rbacRepository contracts.RbacContract, websiteRepository contracts.WebsiteContract, twoFactorRepository contracts.TwoFactorContract, mailer contracts.MailerContract, viewURL string) *OrganizationMemberUseCase {
+	return &OrganizationMemberUseCase{
+		repository:             repository,
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+		rbacRepository:         rbacRepository,
+		websiteRepository:      websiteRepository,
+		twoFactorRepository:    twoFactorRepository,
+		mailer:                 mailer,
+		viewURL:                strings.TrimSuffix(viewURL, "/"),
+	}
+}
+
+func (u *OrganizationMemberUseCase) Members(actorUUID string, organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationMember], error) {
+	if _, err := u.member(organizationUUID, actorUUID); err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetOrganizationMembers(organizationUUID, query)
+}
+
+// Invite emails a link to join the organization. Owners and admins may
+// invite; a custom role must be one of the organization website's rbac
+// roles.
+func (u *OrganizationMemberUseCase) Invite(actorUUID string, organizationUUID string, email string, role string, rbacUUID string) (*domain.OrganizationInvitation, error) {
+	if u.mailer == nil {
+		return nil, ErrMailerMissing
+	}
+
+	org, actor, err := u.manager(organizationUUID, actorUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := domain.NewSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := domain.NewOrganizationInvitation(org.UUID, email, role, rbacUUID, actor.UserUUID, domain.HashSecretToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.RbacUUID != nil {
+		if err := u.checkRbac(org, invitation.RbacUUID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if user, err := u.userRepository.FindUserByEmailAndWebsite(invitation.Email, org.WebSiteUUID.String()); err == nil {
+		if _, err := u.repository.FindOrganizationMember(organizationUUID, user.UUID.String()); err == nil {
+			return nil, domain.ErrMemberExists
+		}
+	}
+
+	created, err := u.repository.CreateOrganizationInvitation(invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = u.mailer.Send(ctx, domain.Email{
+		To:      created.Email,
+		Subject: fmt.Sprintf("You were invited to join %s", org.TradeName),
+		Body: fmt.Sprintf(
+			"You were invited to join %s on Verkoupe.\n\nAccept or decline the invitation at %s/invitations/%s\n\nThe link expires on %s.",
+			org.TradeName,
+			u.viewURL,
+			token,
+			created.ExpiresAt.UTC().Format(time.DateOnly),
+		),
+	})
+	if err != nil {
+		u.repository.AnswerOrganizationInvitation(created.UUID.String(), enums.InvitationRevoked)
+		return nil, ErrInvitationDelivery
+	}
+
+	return created, nil
+}
+
+func (u *OrganizationMemberUseCase) Invitations(actorUUID string, organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationInvitation], error) {
+	if _, _, err := u.manager(organizationUUID, actorUUID); err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetOrganizationInvitations(organizationUUID, query)
+}
+
+func (u *OrganizationMemberUseCase) RevokeInvitation(actorUUID string, organizationUUID string, invitationUUID string) error {
+	if _, _, err := u.manager(organizationUUID, actorUUID); err != nil {
+		return err
+	}
+
+	invitation, err := u.repository.FindOrganizationInvitationByUUID(invitationUUID)
+	if err != nil || invitation.OrganizationUUID.String() != organizationUUID {
+		return ErrInvitationNotFound
+	}
+
+	return u.repository.AnswerOrganizationInvitation(invitation.UUID.String(), enums.InvitationRevoked)
+}
+
+// Accept joins the organization with the invitation behind token. The
+// invitation must have been sent to the email of the signed in user.
+func (u *OrganizationMemberUseCase) Accept(userUUID string, token string) (*domain.OrganizationMember, error) {
+	invitation, user, err := u.invitation(userUUID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.AcceptOrganizationInvitation(invitation, invitation.Member(user.UUID))
+}
+
+func (u *OrganizationMemberUseCase) Decline(userUUID string, token string) error {
+	invitation, _, err := u.invitation(userUUID, token)
+	if err != nil {
+		return err
+	}
+
+	return u.repository.AnswerOrganizationInvitation(invitation.UUID.String(), enums.InvitationDeclined)
+}
+
+func (u *OrganizationMemberUseCase) UpdateMember(actorUUID string, organizationUUID string, userUUID string, role string, rbacUUID string) (*domain.OrganizationMember, error) {
+	org, actor, err := u.manager(organizationUUID, actorUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := u.repository.FindOrganizationMember(organizationUUID, userUUID)
+	if err != nil {
+		return nil, ErrMemberNotFound
+	}
+
+	if member.Role == enums.MemberAdmin && actor.Role != enums.MemberOwner {
+		return nil, ErrOwnerRequired
+	}
+
+	if err := member.SetRole(role, rbacUUID); err != nil {
+		return nil, err
+	}
+
+	if member.RbacUUID != nil {
+		if err := u.checkRbac(org, member.RbacUUID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.repository.UpdateOrganizationMember(member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes a member, or lets a member leave when userUUID is
+// the actor. Only the owner removes admins, and the owner can only leave
+// after transferring ownership.
+func (u *OrganizationMemberUseCase) RemoveMember(actorUUID string, organizationUUID string, userUUID string) error {
+	actor, err := u.member(organizationUUID, actorUUID)
+	if err != nil {
+		return err
+	}
+
+	member, err := u.repository.FindOrganizationMember(organizationUUID, userUUID)
+	if err != nil {
+		return ErrMemberNotFound
+	}
+
+	switch {
+	case member.Role == enums.MemberOwner:
+		return domain.ErrOwnerMember
+	case member.UserUUID == actor.UserUUID:
+	case !actor.CanManage():
+		return ErrMemberForbidden
+	case member.Role == enums.MemberAdmin && actor.Role != enums.MemberOwner:
+		return ErrOwnerRequired
+	default:
+		if _, _, err := u.manager(organizationUUID, actorUUID); err != nil {
+			return err
+		}
+	}
+
+	return u.repository.RemoveOrganizationMember(organizationUUID, userUUID)
+}
+
+// TransferOwnership hands the organization, and with it its websites, to
+// another member. The previous owner stays on as an admin.
+func (u *OrganizationMemberUseCase) TransferOwnership(actorUUID string, organizationUUID string, userUUID string) error {
+	actor, err := u.member(organizationUUID, actorUUID)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role != enums.MemberOwner {
+		return ErrOwnerRequired
+	}
+
+	if _, _, err := u.manager(organizationUUID, actorUUID); err != nil {
+		return err
+	}
+
+	member, err := u.repository.FindOrganizationMember(organizationUUID, userUUID)
+	if err != nil {
+		return ErrMemberNotFound
+	}
+
+	if member.UserUUID == actor.UserUUID {
+		return nil
+	}
+
+	return u.repository.TransferOrganizationOwnership(organizationUUID, actorUUID, userUUID)
+}
+
+// WebsitePermissions tells what userUUID may do on a website: everything
+// on their own websites, and on websites of an organization what their
+// membership grants. On websites requiring two-factor authentication,
+// users without it only keep reading when their role may write or delete.
+func (u *OrganizationMemberUseCase) WebsitePermissions(userUUID string, websiteUUID string) (*domain.WebsitePermissions, error) {
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, ErrMemberWebsiteNotFound
+	}
+
+	permissions, err := u.websitePermissions(website, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if permissions.CanWrite || permissions.CanDelete {
+		missing, err := u.twoFactorMissing(website, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if missing {
+			permissions.WithholdForTwoFactor()
+		}
+	}
+
+	return permissions, nil
+}
+
+// ManageOwner tells whether the user may act for ownerUUID, the owner of
+// websites, their plan and their fees: the user themselves, or an owner or
+// admin of the organization ownerUUID names.
+func (u *OrganizationMemberUseCase) ManageOwner(userUUID string, ownerUUID string) error {
+	if ownerUUID == userUUID {
+		return nil
+	}
+
+	_, _, err := u.manager(ownerUUID, userUUID)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return ErrMemberForbidden
+	}
+
+	return err
+}
+
+// twoFactorMissing tells whether the website requires two-factor
+// authentication that the user has not enabled.
+func (u *OrganizationMemberUseCase) twoFactorMissing(website *domain.Website, userUUID string) (bool, error) {
+	if !website.RequireTwoFactor {
+		return false, nil
+	}
+
+	tf, err := u.twoFactorRepository.FindTwoFactor(userUUID)
+	if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !tf.Enabled(), nil
+}
+
+// checkTwoFactor holds back managing the members of an organization whose
+// website requires two-factor authentication the user has not enabled.
+func (u *OrganizationMemberUseCase) checkTwoFactor(org *domain.OrganizationBR, userUUID string) error {
+	website, err := u.websiteRepository.FindWebsiteByUUID(org.WebSiteUUID.String())
+	if err != nil {
+		return ErrMemberWebsiteNotFound
+	}
+
+	missing, err := u.twoFactorMissing(website, userUUID)
+	if err != nil {
+		return err
+	}
+	if missing {
+		return domain.ErrTwoFactorRequired
+	}
+
+	return nil
+}
+
+func (u *OrganizationMemberUseCase) websitePermissions(website *domain.Website, userUUID string) (*domain.WebsitePermissions, error) {
+	if website.OwnerType != enums.OrganizationOwner {
+		if website.OwnerUUID.String() != userUUID {
+			return nil, ErrMemberForbidden
+		}
+		return domain.OwnerPermissions(website.UUID, enums.MemberOwner), nil
+	}
+
+	member, err := u.repository.FindOrganizationMember(website.OwnerUUID.String(), userUUID)
+	if err != nil {
+		return nil, ErrMemberForbidden
+	}
+
+	if member.CanManage() {
+		return domain.OwnerPermissions(website.UUID, member.Role), nil
+	}
+
+	var rbac *domain.Rbac
+	if member.RbacUUID != nil {
+		rbac, err = u.rbacRepository.FindRbacByUUID(member.RbacUUID.String())
+		if err != nil {
+			rbac = nil
+		}
+	}
+
+	return domain.MemberPermissions(website.UUID, rbac), nil
+}
+
+func (u *OrganizationMemberUseCase) member(organizationUUID string, userUUID string) (*domain.OrganizationMember, error) {
+	if _, err := u.organizationRepository.FindOrganizationByUUID(organizationUUID); err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	member, err := u.repository.FindOrganizationMember(organizationUUID, userUUID)
+	if err != nil {
+		return nil, ErrMemberForbidden
+	}
+
+	return member, nil
+}
+
+// manager returns the organization and the actor when the actor is an
+// owner or admin of it.
+func (u *OrganizationMemberUseCase) manager(organizationUUID string, userUUID string) (*domain.OrganizationBR, *domain.OrganizationMember, error) {
+	org, err := u.organizationRepository.FindOrganizationByUUID(organizationUUID)
+	if err != nil {
+		return nil, nil, ErrOrganizationNotFound
+	}
+
+	member, err := u.repository.FindOrganizationMember(organizationUUID, userUUID)
+	if err != nil || !member.CanManage() {
+		return nil, nil, ErrMemberForbidden
+	}
+
+	if err := u.checkTwoFactor(org, userUUID); err != nil {
+		return nil, nil, err
+	}
+
+	return org, member, nil
+}
+
+func (u *OrganizationMemberUseCase) checkRbac(org *domain.OrganizationBR, rbacUUID string) error {
+	rbac, err := u.rbacRepository.FindRbacByUUID(rbacUUID)
+	if err != nil || rbac.WebSiteUUID != org.WebSiteUUID {
+		return ErrMemberRoleNotFound
+	}
+
+	return nil
+}
+
+func (u *OrganizationMemberUseCase) invitation(userUUID string, token string) (*domain.OrganizationInvitation, *domain.User, error) {
+	invitation, err := u.repository.FindOrganizationInvitationByToken(domain.HashSecretToken(token))
+	if err != nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+
+	if err := invitation.Answerable(time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := u.userRepository.FindUserByUUID(userUUID)
+	if err != nil {
+		return nil, nil, ErrMemberForbidden
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, nil, ErrInvitationEmail
+	}
+
+	return invitation, user, nil
+}
@@ -43,6 +43,6 @@ func (u *CreatePhoneUseCase) GetByUUID(uuidStr string) (*domain.Phone, error) {
 	return u.phoneRepo.FindPhoneByUUID(uuidStr)
 }
 
-func (u *CreatePhoneUseCase) GetAll(websiteUUIDStr string) ([]*domain.Phone, error) {
-	return u.phoneRepo.GetPhonesFromWebsite(websiteUUIDStr)
+func (u *CreatePhoneUseCase) GetAll(websiteUUIDStr string, query *domain.ListQuery) (*domain.Page[*domain.Phone], error) {
+	return u.phoneRepo.GetPhonesFromWebsite(websiteUUIDStr, query)
 }
@@ -14,8 +14,8 @@ func NewCreatePlanUseCase(repository contracts.PlanContract) *CreatePlanUseCase
 	return &CreatePlanUseCase{repository: repository}
 }
 
-func (u *CreatePlanUseCase) Create(name string, description string, maxWebsites int, maxRouters int, maxProducts int, costPerSaleRate int, coin string, price int) (*domain.VerkoupePlan, error) {
-	plan, err := domain.NewPlan(name, description, maxWebsites, maxRouters, maxProducts, costPerSaleRate, coin, price)
+func (u *CreatePlanUseCase) Create(name string, description string, maxWebsites int, maxRouters int, maxProducts int, costPerSaleRate int, coin string, price int, trialDays int) (*domain.VerkoupePlan, error) {
+	plan, err := domain.NewPlan(name, description, maxWebsites, maxRouters, maxProducts, costPerSaleRate, coin, price, trialDays)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/pdf"
+	"github.com/ViitoJooj/verkoupe/pkg/spreadsheet"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPlatformFeeNotFound        = errors.New("platform fee not found")
+	ErrPlatformFeeWebsiteNotFound = errors.New("website not found")
+	ErrStatementFormat            = errors.New("statement format must be csv or pdf")
+)
+
+// PlatformFeeUseCase keeps the ledger of what Verkoupe takes from each
+// sale and turns it into monthly merchant statements.
+type PlatformFeeUseCase struct {
+	repository             contracts.PlatformFeeContract
+	websiteRepository      contracts.WebsiteContract
+	subscriptionRepository contracts.SubscriptionContract
+}
+
+func NewPlatformFeeUseCase(repository contracts.PlatformFeeContract, websiteRepository contracts.WebsiteContract, subscriptionRepository contracts.SubscriptionContract) *PlatformFeeUseCase {
+	return &PlatformFeeUseCase{
+		repository:             repository,
+		websiteRepository:      websiteRepository,
+		subscriptionRepository: subscriptionRepository,
+	}
+}
+
+// RecordOrder is meant to be handed to OrderUseCase.OnPaid.
+func (u *PlatformFeeUseCase) RecordOrder(order *domain.Order) error {
+	if !order.Paid() {
+		return nil
+	}
+
+	_, err := u.record(order.WebsiteUUID.String(), order.UUID.String(), string(order.Coin), order.Total)
+	return err
+}
+
+// RefundOrder is meant to be handed to OrderUseCase.OnRefund. It works
+// from what the order refunded in total, so running it again for the same
+// refund gives nothing more back.
+func (u *PlatformFeeUseCase) RefundOrder(order *domain.Order, refundAmount int) error {
+	_, err := u.refund(order.WebsiteUUID.String(), order.UUID.String(), order.RefundedAmount)
+	return err
+}
+
+// record takes the fee of a paid order at the rate of the website owner's
+// active plan. Sales of a merchant without an active plan are recorded
+// without a fee. Recording an order twice returns the first entry.
+func (u *PlatformFeeUseCase) record(websiteUUID string, orderUUID string, coin string, amount int) (*domain.PlatformFee, error) {
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, ErrPlatformFeeWebsiteNotFound
+	}
+
+	plan, err := u.subscriptionRepository.FindActivePlanByOwner(website.OwnerUUID.String())
+	if err != nil {
+		plan = nil
+	}
+
+	fee, err := domain.NewPlatformFee(website, orderUUID, coin, amount, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.RecordPlatformFee(fee)
+}
+
+// refund gives back the share of an order's fee not given back yet, now
+// that refundedTo of it was refunded in total. It returns nil without
+// error when there is nothing left to give back.
+func (u *PlatformFeeUseCase) refund(websiteUUID string, orderUUID string, refundedTo int) (*domain.PlatformFee, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, errors.New("Order UUID is invalid.")
+	}
+
+	if refundedTo <= 0 {
+		return nil, errors.New("Amount must be greater than zero.")
+	}
+
+	sale, err := u.repository.FindPlatformFeeByOrder(orderUUID)
+	if err != nil || sale.WebsiteUUID.String() != websiteUUID {
+		return nil, ErrPlatformFeeNotFound
+	}
+
+	return u.repository.RefundPlatformFee(orderUUID, refundedTo)
+}
+
+func (u *PlatformFeeUseCase) List(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.PlatformFee], error) {
+	return u.repository.GetPlatformFees(ownerUUID, query)
+}
+
+// Statement sums the owner's ledger for month, written as YYYY-MM.
+func (u *PlatformFeeUseCase) Statement(ownerUUID string, month string) (*domain.MerchantStatement, error) {
+	ownerUUIDParsed, err := uuid.Parse(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := domain.ParseStatementMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.repository.GetPlatformFeesBetween(ownerUUID, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewMerchantStatement(ownerUUIDParsed, start, entries), nil
+}
+
+// WriteStatement renders a statement as csv or pdf: the totals of each
+// currency followed by every ledger entry of the month.
+func (u *PlatformFeeUseCase) WriteStatement(statement *domain.MerchantStatement, format string, w io.Writer) error {
+	switch format {
+	case spreadsheet.FormatCSV:
+		return writeStatementCSV(statement, w)
+	case pdf.Format:
+		return writeStatementPDF(statement, w)
+	default:
+		return ErrStatementFormat
+	}
+}
+
+func writeStatementCSV(statement *domain.MerchantStatement, w io.Writer) error {
+	writer, err := spreadsheet.NewWriter(spreadsheet.FormatCSV, w)
+	if err != nil {
+		return err
+	}
+
+	month := statement.Month.Format("2006-01")
+	records := [][]string{{"month", "coin", "sales", "gross_sales", "fees", "refunds", "fee_refunds", "net"}}
+	for _, t := range statement.Totals {
+		records = append(records, []string{
+			month,
+			string(t.Coin),
+			strconv.Itoa(t.Sales),
+			formatStatementAmount(t.GrossSales),
+			formatStatementAmount(t.Fees),
+			formatStatementAmount(t.Refunds),
+			formatStatementAmount(t.FeeRefunds),
+			formatStatementAmount(t.Net),
+		})
+	}
+
+	records = append(records, []string{}, []string{"created_at", "type", "order_uuid", "website_uuid", "coin", "amount", "rate", "fee"})
+	for _, e := range statement.Entries {
+		records = append(records, []string{
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			string(e.Type),
+			e.OrderUUID.String(),
+			e.WebsiteUUID.String(),
+			string(e.Coin),
+			formatStatementAmount(e.Amount),
+			strconv.Itoa(e.Rate),
+			formatStatementAmount(e.Fee),
+		})
+	}
+
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeStatementPDF(statement *domain.MerchantStatement, w io.Writer) error {
+	doc := pdf.New()
+	doc.Heading("Verkoupe merchant statement")
+	doc.Text("Merchant: " + statement.OwnerUUID.String())
+	doc.Text(fmt.Sprintf("Period:   %s to %s", statement.Month.Format(time.DateOnly), statement.End().AddDate(0, 0, -1).Format(time.DateOnly)))
+	doc.Blank()
+
+	doc.Heading("Totals")
+	doc.Text(fmt.Sprintf("%-4s %6s %13s %11s %13s %11s %13s", "COIN", "SALES", "GROSS", "FEES", "REFUNDS", "FEES BACK", "NET"))
+	if len(statement.Totals) == 0 {
+		doc.Text("No sales this month.")
+	}
+	for _, t := range statement.Totals {
+		doc.Text(fmt.Sprintf("%-4s %6d %13s %11s %13s %11s %13s",
+			t.Coin,
+			t.Sales,
+			formatStatementAmount(t.GrossSales),
+			formatStatementAmount(t.Fees),
+			formatStatementAmount(t.Refunds),
+			formatStatementAmount(t.FeeRefunds),
+			formatStatementAmount(t.Net),
+		))
+	}
+
+	if len(statement.Entries) > 0 {
+		doc.Blank()
+		doc.Heading("Entries")
+		doc.Text(fmt.Sprintf("%-10s %-6s %-36s %-4s %11s %4s %9s", "DATE", "TYPE", "ORDER", "COIN", "AMOUNT", "RATE", "FEE"))
+		for _, e := range statement.Entries {
+			doc.Text(fmt.Sprintf("%-10s %-6s %-36s %-4s %11s %3d%% %9s",
+				e.CreatedAt.UTC().Format(time.DateOnly),
+				e.Type,
+				e.OrderUUID,
+				e.Coin,
+				formatStatementAmount(e.Amount),
+				e.Rate,
+				formatStatementAmount(e.Fee),
+			))
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// formatStatementAmount writes cents as a decimal amount.
+func formatStatementAmount(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
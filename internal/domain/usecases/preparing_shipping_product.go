@@ -1,17 +1,21 @@
 package usecases
 
 import (
+	"errors"
 
 	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 )
 
+var ErrProductNotShippable = errors.New("only physical products are shipped")
+
 type CreatePreparingShippingProductUseCase struct {
-	repository contracts.PreparingShippingProductContract
+	repository        contracts.PreparingShippingProductContract
+	productRepository contracts.ProductContract
 }
 
-func NewCreatePreparingShippingProductUseCase(repository contracts.PreparingShippingProductContract) *CreatePreparingShippingProductUseCase {
-	return &CreatePreparingShippingProductUseCase{repository: repository}
+func NewCreatePreparingShippingProductUseCase(repository contracts.PreparingShippingProductContract, productRepository contracts.ProductContract) *CreatePreparingShippingProductUseCase {
+	return &CreatePreparingShippingProductUseCase{repository: repository, productRepository: productRepository}
 }
 
 func (u *CreatePreparingShippingProductUseCase) Create(productUUID string, addressUUID string) (*domain.PreparingShippingProducts, error) {
@@ -20,6 +24,15 @@ func (u *CreatePreparingShippingProductUseCase) Create(productUUID string, addre
 		return nil, err
 	}
 
+	product, err := u.productRepository.FindProductByUUID(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.Shippable() {
+		return nil, ErrProductNotShippable
+	}
+
 	createdPSP, err := u.repository.CreatePreparingShippingProduct(psp)
 	if err != nil {
 		return nil, err
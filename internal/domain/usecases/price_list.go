@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+type CreatePriceListUseCase struct {
+	repository contracts.PriceListContract
+}
+
+func NewCreatePriceListUseCase(repository contracts.PriceListContract) *CreatePriceListUseCase {
+	return &CreatePriceListUseCase{repository: repository}
+}
+
+func (u *CreatePriceListUseCase) Create(websiteUUID string, label string, coin string, customerGroup string, priority int, active bool) (*domain.PriceList, error) {
+	list, err := domain.NewPriceList(websiteUUID, label, coin, customerGroup, priority, active)
+	if err != nil {
+		return nil, err
+	}
+	return u.repository.CreatePriceList(list)
+}
+
+func (u *CreatePriceListUseCase) GetByUUID(uuidStr string) (*domain.PriceList, error) {
+	return u.repository.FindPriceListByUUID(uuidStr)
+}
+
+func (u *CreatePriceListUseCase) GetAll(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.PriceList], error) {
+	return u.repository.GetPriceListsFromWebsite(websiteUUID, query)
+}
+
+func (u *CreatePriceListUseCase) Delete(uuidStr string) error {
+	return u.repository.DeletePriceListByUUID(uuidStr)
+}
+
+// SetCustomerGroup puts a customer of the website in group, so they get the
+// price lists made for it.
+func (u *CreatePriceListUseCase) SetCustomerGroup(websiteUUID string, userUUID string, group string) (*domain.CustomerGroup, error) {
+	customerGroup, err := domain.NewCustomerGroup(websiteUUID, userUUID, group)
+	if err != nil {
+		return nil, err
+	}
+	return u.repository.SaveCustomerGroup(customerGroup)
+}
+
+func (u *CreatePriceListUseCase) RemoveCustomerGroup(websiteUUID string, userUUID string) error {
+	return u.repository.DeleteCustomerGroup(websiteUUID, userUUID)
+}
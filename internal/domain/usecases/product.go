@@ -1,29 +1,53 @@
 package usecases
 
 import (
-
 	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 )
 
 type CreateProductUseCase struct {
-	repository contracts.ProductContract
+	changeNotifier
+	repository             contracts.ProductContract
+	websiteRepository      contracts.WebsiteContract
+	subscriptionRepository contracts.SubscriptionContract
 }
 
-func NewCreateProductUseCase(repository contracts.ProductContract) *CreateProductUseCase {
-	return &CreateProductUseCase{repository: repository}
+func NewCreateProductUseCase(repository contracts.ProductContract, websiteRepository contracts.WebsiteContract, subscriptionRepository contracts.SubscriptionContract) *CreateProductUseCase {
+	return &CreateProductUseCase{repository: repository, websiteRepository: websiteRepository, subscriptionRepository: subscriptionRepository}
 }
 
-func (u *CreateProductUseCase) Create(name string, description string, shortDescription string, height int, width int, thickness int, active bool) (*domain.Products, error) {
-	product, err := domain.NewProduct(name, description, shortDescription, height, width, thickness, active)
+// Create adds a product to websiteUUID, counted against the plan of the
+// website's owner.
+func (u *CreateProductUseCase) Create(websiteUUID string, name string, description string, shortDescription string, brand string, gtin string, productType string, height int, width int, thickness int, downloadLimit int, downloadDays int, active bool) (*domain.Products, error) {
+	product, err := domain.NewProduct(name, description, shortDescription, brand, gtin, productType, height, width, thickness, downloadLimit, downloadDays, active)
 	if err != nil {
 		return nil, err
 	}
 
-	createdProduct, err := u.repository.CreateProduct(product)
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
 	if err != nil {
 		return nil, err
 	}
+	product.WebsiteUUID = &website.UUID
 
+	quota, err := planQuota(u.subscriptionRepository, website.OwnerUUID, func(p *domain.VerkoupePlan) int { return p.MaxProducts })
+	if err != nil {
+		return nil, err
+	}
+
+	createdProduct, err := u.repository.CreateProduct(product, quota)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
 	return createdProduct, nil
 }
+
+func (u *CreateProductUseCase) FindByUUID(uuidStr string) (*domain.Products, error) {
+	return u.repository.FindProductByUUID(uuidStr)
+}
+
+func (u *CreateProductUseCase) List(query *domain.ListQuery) (*domain.Page[*domain.Products], error) {
+	return u.repository.GetProducts(query)
+}
@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+	"github.com/ViitoJooj/verkoupe/pkg/token"
+)
+
+// Links are short lived on purpose: a shared link stops working soon, and
+// the buyer can always ask for a fresh one while the grant lasts.
+const DownloadLinkTTL = time.Hour
+
+var (
+	ErrDownloadLinkInvalid = errors.New("invalid download link")
+	ErrDownloadLinkExpired = errors.New("download link expired")
+)
+
+type ProductDownloadUseCase struct {
+	repository        contracts.ProductDownloadContract
+	fileRepository    contracts.ProductFileContract
+	productRepository contracts.ProductContract
+	orderRepository   contracts.OrderContract
+	store             blob.BlobStore
+	secret            []byte
+	baseURL           string
+}
+
+func NewProductDownloadUseCase(repository contracts.ProductDownloadContract, fileRepository contracts.ProductFileContract, productRepository contracts.ProductContract, orderRepository contracts.OrderContract, store blob.BlobStore, secret string, baseURL string) *ProductDownloadUseCase {
+	return &ProductDownloadUseCase{
+		repository:        repository,
+		fileRepository:    fileRepository,
+		productRepository: productRepository,
+		orderRepository:   orderRepository,
+		store:             store,
+		secret:            []byte(secret),
+		baseURL:           strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GrantOrder is meant to be handed to OrderUseCase.OnPaid: it gives the
+// order access to the files of its digital products.
+func (u *ProductDownloadUseCase) GrantOrder(order *domain.Order) error {
+	if !order.Paid() {
+		return nil
+	}
+
+	_, err := u.grant(order.UUID.String(), order.ProductUUIDs())
+	return err
+}
+
+// grant gives an order access to the files of its digital products.
+// Physical products are skipped, they go through the shipping stages
+// instead. Granting the same order again keeps the existing grants.
+func (u *ProductDownloadUseCase) grant(orderUUID string, productUUIDs []string) ([]*domain.ProductDownload, error) {
+
+	now := time.Now()
+	seen := make(map[string]bool, len(productUUIDs))
+	var downloads []*domain.ProductDownload
+
+	for _, productUUID := range productUUIDs {
+		if seen[productUUID] {
+			continue
+		}
+		seen[productUUID] = true
+
+		product, err := u.productRepository.FindProductByUUID(productUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !product.IsDigital() {
+			continue
+		}
+
+		files, err := u.fileRepository.GetProductFilesFromProduct(productUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("digital product %s has no files", productUUID)
+		}
+
+		for _, file := range files {
+			download, err := domain.NewProductDownload(orderUUID, product, file, now)
+			if err != nil {
+				return nil, err
+			}
+			downloads = append(downloads, download)
+		}
+	}
+
+	if err := u.repository.CreateProductDownloads(downloads); err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetProductDownloadsFromOrder(orderUUID)
+}
+
+// ListByOrder returns the grants of a paid order to the shopper who placed
+// it.
+func (u *ProductDownloadUseCase) ListByOrder(orderUUID string, userUUID string) ([]*domain.ProductDownload, error) {
+	order, err := u.orderRepository.FindOrderByUUID(orderUUID)
+	if err != nil || order.UserUUID.String() != userUUID {
+		return nil, ErrOrderNotFound
+	}
+
+	if !order.Paid() {
+		return nil, domain.ErrOrderNotPaid
+	}
+
+	return u.repository.GetProductDownloadsFromOrder(orderUUID)
+}
+
+// Link signs a fresh download link for the grant. It returns an empty link
+// once the grant is used up or expired.
+func (u *ProductDownloadUseCase) Link(download *domain.ProductDownload) (string, *time.Time, error) {
+	if download.Check(time.Now()) != nil {
+		return "", nil, nil
+	}
+
+	tokenStr, expiresAt, err := token.GenerateDownload(u.secret, download.UUID.String(), DownloadLinkTTL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if expiresAt.After(download.ExpiresAt) {
+		expiresAt = download.ExpiresAt
+	}
+
+	return u.baseURL + "/downloads/" + tokenStr, &expiresAt, nil
+}
+
+// Open checks a signed link and counts the download. The blob is opened
+// before the download is counted so a storage failure does not cost the
+// buyer one of their downloads.
+func (u *ProductDownloadUseCase) Open(ctx context.Context, tokenStr string) (*domain.ProductDownload, io.ReadCloser, error) {
+	downloadUUID, err := token.ParseDownload(tokenStr, u.secret)
+	if err != nil {
+		if errors.Is(err, token.ErrExpired) {
+			return nil, nil, ErrDownloadLinkExpired
+		}
+		return nil, nil, ErrDownloadLinkInvalid
+	}
+
+	download, err := u.repository.FindProductDownloadByUUID(downloadUUID)
+	if err != nil {
+		return nil, nil, ErrDownloadLinkInvalid
+	}
+
+	if err := download.Check(time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	body, err := u.store.Get(ctx, download.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consumed, err := u.repository.ConsumeProductDownload(downloadUUID)
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+
+	return consumed, body, nil
+}
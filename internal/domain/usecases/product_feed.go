@@ -0,0 +1,252 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+	"github.com/ViitoJooj/verkoupe/pkg/cache"
+	"github.com/ViitoJooj/verkoupe/pkg/feed"
+)
+
+// Feeds are dropped as soon as the catalog changes; the TTL only bounds how
+// late a sale window starting or ending on its own is picked up.
+const (
+	feedCacheSize = 64
+	feedCacheTTL  = 15 * time.Minute
+)
+
+var (
+	ErrFeedWebsiteNotFound = errors.New("website not found")
+	ErrFeedWebsiteURL      = errors.New("website has no URL")
+	ErrWebsiteSuspended    = errors.New("website suspended")
+)
+
+type ProductFeedUseCase struct {
+	repository          contracts.ProductFeedContract
+	websiteRepository   contracts.WebsiteContract
+	resolvePriceUseCase *ResolveProductPriceUseCase
+	store               blob.BlobStore
+	cache               *cache.LRU[string, *domain.ProductFeedFile]
+
+	// version is bumped on every invalidation so a feed built from data read
+	// before a change is not cached after it.
+	version atomic.Uint64
+
+	mu    sync.Mutex
+	locks map[string]*feedLock
+}
+
+// feedLock serializes the builds of one feed; it is dropped once nobody
+// holds or waits for it.
+type feedLock struct {
+	sync.Mutex
+	refs int
+}
+
+func NewProductFeedUseCase(repository contracts.ProductFeedContract, websiteRepository contracts.WebsiteContract, resolvePriceUseCase *ResolveProductPriceUseCase, store blob.BlobStore) *ProductFeedUseCase {
+	return &ProductFeedUseCase{
+		repository:          repository,
+		websiteRepository:   websiteRepository,
+		resolvePriceUseCase: resolvePriceUseCase,
+		store:               store,
+		cache:               cache.NewLRU[string, *domain.ProductFeedFile](feedCacheSize, feedCacheTTL),
+		locks:               make(map[string]*feedLock),
+	}
+}
+
+// Invalidate drops every cached feed. It is registered as a change listener
+// on the use cases that write products, prices, stock and media.
+func (u *ProductFeedUseCase) Invalidate() {
+	u.version.Add(1)
+	u.cache.Purge()
+}
+
+// Google returns the website catalog as a Google Merchant Center RSS feed.
+func (u *ProductFeedUseCase) Google(ctx context.Context, websiteUUID string) (*domain.ProductFeedFile, error) {
+	return u.get(ctx, websiteUUID, enums.FeedGoogle)
+}
+
+// Meta returns the website catalog as a Meta catalog CSV.
+func (u *ProductFeedUseCase) Meta(ctx context.Context, websiteUUID string) (*domain.ProductFeedFile, error) {
+	return u.get(ctx, websiteUUID, enums.FeedMeta)
+}
+
+// get serves a cached feed or builds it. Builds of the same feed are
+// serialized so a burst of crawler requests after a change costs one build.
+func (u *ProductFeedUseCase) get(ctx context.Context, websiteUUID string, format enums.FeedFormatType) (*domain.ProductFeedFile, error) {
+	key := websiteUUID + "|" + string(format)
+	if cached, ok := u.cache.Get(key); ok {
+		return cached, nil
+	}
+
+	website, err := u.website(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := u.lock(key)
+	lock.Lock()
+	defer u.unlock(key, lock)
+
+	if cached, ok := u.cache.Get(key); ok {
+		return cached, nil
+	}
+
+	version := u.version.Load()
+	file, err := u.build(ctx, website, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.version.Load() == version {
+		u.cache.Set(key, file)
+	}
+
+	return file, nil
+}
+
+func (u *ProductFeedUseCase) lock(key string) *feedLock {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lock, ok := u.locks[key]
+	if !ok {
+		lock = &feedLock{}
+		u.locks[key] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+func (u *ProductFeedUseCase) unlock(key string, lock *feedLock) {
+	lock.Unlock()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(u.locks, key)
+	}
+}
+
+func (u *ProductFeedUseCase) website(websiteUUID string) (*domain.Website, error) {
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, ErrFeedWebsiteNotFound
+	}
+
+	if website.SuspendedAt != nil {
+		return nil, ErrWebsiteSuspended
+	}
+
+	if strings.TrimRight(website.URL, "/") == "" {
+		return nil, ErrFeedWebsiteURL
+	}
+
+	return website, nil
+}
+
+func (u *ProductFeedUseCase) build(ctx context.Context, website *domain.Website, format enums.FeedFormatType) (*domain.ProductFeedFile, error) {
+	websiteUUID := website.UUID.String()
+	baseURL := strings.TrimRight(website.URL, "/")
+
+	products, err := u.repository.GetFeedProducts(ctx, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]domain.PriceTarget, len(products))
+	for i, p := range products {
+		targets[i] = p.PriceTarget()
+	}
+
+	prices, err := u.resolvePriceUseCase.ResolveManyForDisplay(websiteUUID, targets, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*feed.Item, 0, len(products))
+	for i, p := range products {
+		price, ok := prices[targets[i]]
+		if !ok {
+			continue
+		}
+		items = append(items, u.item(baseURL, p, price))
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case enums.FeedGoogle:
+		err = feed.WriteGoogleRSS(&buf, feed.Channel{
+			Title:       website.Label,
+			Link:        baseURL,
+			Description: website.Description,
+		}, items)
+	case enums.FeedMeta:
+		err = feed.WriteMetaCSV(&buf, items)
+	default:
+		err = errors.New("unknown feed format")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ProductFeedFile{
+		Format:      format,
+		Data:        buf.Bytes(),
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// item prices the offer in the website base currency. Channels show a
+// discount as price plus sale_price, so a compare-at amount above the
+// price becomes the regular price. Offers without a price are left out.
+func (u *ProductFeedUseCase) item(baseURL string, p *domain.FeedProduct, price *domain.DisplayPrice) *feed.Item {
+	item := &feed.Item{
+		ID:           p.ID(),
+		Title:        p.Title(),
+		Description:  p.Description,
+		Link:         baseURL + "/products/" + p.ProductUUID.String(),
+		Availability: feed.OutOfStock,
+		Price:        feed.Money{Amount: price.DisplayAmount, Currency: string(price.DisplayCoin)},
+		Brand:        p.Brand,
+		GTIN:         p.GTIN,
+		MPN:          p.SKU,
+		ProductType:  p.Category,
+	}
+
+	if item.Description == "" {
+		item.Description = p.ShortDescription
+	}
+	if p.VariantUUID != nil {
+		item.GroupID = p.ProductUUID.String()
+		item.Link += "?variant=" + p.VariantUUID.String()
+	}
+	if p.InStock() {
+		item.Availability = feed.InStock
+	}
+	if compareAt := price.DisplayCompareAtAmount; compareAt != nil && *compareAt > price.DisplayAmount {
+		item.SalePrice = &feed.Money{Amount: price.DisplayAmount, Currency: string(price.DisplayCoin)}
+		item.Price.Amount = *compareAt
+	}
+
+	for i, key := range p.ImageKeys {
+		if i == 0 {
+			item.ImageLink = u.store.URL(key)
+			continue
+		}
+		item.AdditionalImageLinks = append(item.AdditionalImageLinks, u.store.URL(key))
+	}
+
+	return item
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+)
+
+const DefaultMaxProductFileBytes int64 = 500 << 20
+
+var ErrProductNotDigital = errors.New("product is not digital")
+
+type ProductFileUseCase struct {
+	repository        contracts.ProductFileContract
+	productRepository contracts.ProductContract
+	store             blob.BlobStore
+	maxBytes          int64
+}
+
+func NewProductFileUseCase(repository contracts.ProductFileContract, productRepository contracts.ProductContract, store blob.BlobStore, maxBytes int64) *ProductFileUseCase {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxProductFileBytes
+	}
+
+	return &ProductFileUseCase{
+		repository:        repository,
+		productRepository: productRepository,
+		store:             store,
+		maxBytes:          maxBytes,
+	}
+}
+
+func (u *ProductFileUseCase) MaxBytes() int64 {
+	return u.maxBytes
+}
+
+// Upload attaches a file to a digital product. Any type is accepted; the
+// content type is still sniffed so downloads are served with a sane one.
+func (u *ProductFileUseCase) Upload(ctx context.Context, productUUID string, file io.Reader, size int64, filename string) (*domain.ProductFile, error) {
+	if size > u.maxBytes {
+		return nil, ErrMediaTooLarge
+	}
+
+	product, err := u.productRepository.FindProductByUUID(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.IsDigital() {
+		return nil, ErrProductNotDigital
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, ErrMediaUpload
+	}
+	head = head[:n]
+
+	productFile, err := domain.NewProductFile(productUUID, filename, http.DetectContentType(head), size)
+	if err != nil {
+		return nil, err
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := u.store.Put(ctx, productFile.BlobKey, body, size, productFile.ContentType); err != nil {
+		return nil, ErrMediaUpload
+	}
+
+	created, err := u.repository.CreateProductFile(productFile)
+	if err != nil {
+		u.store.Delete(ctx, productFile.BlobKey)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (u *ProductFileUseCase) ListByProduct(productUUID string) ([]*domain.ProductFile, error) {
+	return u.repository.GetProductFilesFromProduct(productUUID)
+}
+
+func (u *ProductFileUseCase) Delete(ctx context.Context, uuidStr string) error {
+	file, err := u.repository.FindProductFileByUUID(uuidStr)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repository.DeleteProductFileByUUID(uuidStr); err != nil {
+		return err
+	}
+
+	return u.store.Delete(ctx, file.BlobKey)
+}
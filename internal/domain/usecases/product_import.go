@@ -0,0 +1,494 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/ViitoJooj/verkoupe/pkg/spreadsheet"
+)
+
+const (
+	DefaultMaxImportBytes int64 = 20 << 20
+
+	productImportBatch    = 2
+	productImportProgress = 100
+	productImportInterval = 30 * time.Second
+)
+
+var (
+	ErrImportTooLarge    = errors.New("file too large")
+	ErrImportInvalidType = errors.New("invalid file type")
+	ErrImportUpload      = errors.New("upload failed")
+)
+
+type ProductImportUseCase struct {
+	changeNotifier
+	repository             contracts.ProductImportContract
+	websiteRepository      contracts.WebsiteContract
+	subscriptionRepository contracts.SubscriptionContract
+	mediaUseCase           *CreateProductMediaUseCase
+	fetcher                contracts.MediaFetcherContract
+	store                  blob.BlobStore
+	maxBytes               int64
+}
+
+// NewProductImportUseCase takes an optional fetcher; without one, images
+// referenced by imported files are reported as skipped.
+func NewProductImportUseCase(repository contracts.ProductImportContract, websiteRepository contracts.WebsiteContract, subscriptionRepository contracts.SubscriptionContract, mediaUseCase *CreateProductMediaUseCase, fetcher contracts.MediaFetcherContract, store blob.BlobStore, maxBytes int64) *ProductImportUseCase {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxImportBytes
+	}
+
+	return &ProductImportUseCase{
+		repository:             repository,
+		websiteRepository:      websiteRepository,
+		subscriptionRepository: subscriptionRepository,
+		mediaUseCase:           mediaUseCase,
+		fetcher:                fetcher,
+		store:                  store,
+		maxBytes:               maxBytes,
+	}
+}
+
+func (u *ProductImportUseCase) MaxBytes() int64 {
+	return u.maxBytes
+}
+
+// Create stores the uploaded spreadsheet and queues it for websiteUUID.
+// Nothing is parsed here; the file is validated row by row by the
+// background job.
+func (u *ProductImportUseCase) Create(ctx context.Context, websiteUUID string, filename string, file io.Reader, size int64, source string, coin string, dryRun bool, mapping map[string]string) (*domain.ProductImportJob, error) {
+	if size > u.maxBytes {
+		return nil, ErrImportTooLarge
+	}
+
+	format, err := spreadsheet.FormatFromFilename(filename)
+	if err != nil {
+		return nil, ErrImportInvalidType
+	}
+
+	job, err := domain.NewProductImportJob(websiteUUID, filename, format, source, coin, dryRun, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.store.Put(ctx, job.BlobKey, file, size, spreadsheet.ContentType(format)); err != nil {
+		return nil, ErrImportUpload
+	}
+
+	created, err := u.repository.CreateProductImportJob(job)
+	if err != nil {
+		u.store.Delete(ctx, job.BlobKey)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Find returns the import only when it belongs to websiteUUID.
+func (u *ProductImportUseCase) Find(websiteUUID string, uuidStr string) (*domain.ProductImportJob, error) {
+	job, err := u.repository.FindProductImportJobByUUID(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.WebsiteUUID.String() != websiteUUID {
+		return nil, errors.New("product import not found")
+	}
+
+	return job, nil
+}
+
+func (u *ProductImportUseCase) List(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportJob], error) {
+	return u.repository.GetProductImportJobs(websiteUUID, query)
+}
+
+func (u *ProductImportUseCase) Errors(websiteUUID string, uuidStr string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportError], error) {
+	if _, err := u.Find(websiteUUID, uuidStr); err != nil {
+		return nil, err
+	}
+	return u.repository.GetProductImportErrors(uuidStr, query)
+}
+
+// ProcessPending is meant to run on a schedule. Imports are long, so only a
+// couple are claimed per tick.
+func (u *ProductImportUseCase) ProcessPending() {
+	jobs, err := u.repository.ClaimPendingProductImportJobs(productImportBatch)
+	if err != nil {
+		logger.Warn(err).Print()
+		return
+	}
+
+	for _, job := range jobs {
+		if err := u.Process(job); err != nil {
+			logger.Warn(fmt.Errorf("product import %s: %w", job.UUID, err)).Print()
+		}
+	}
+}
+
+// Process reads the spreadsheet and imports it one product at a time.
+// Consecutive rows sharing a handle make up one product with its variants;
+// rows without a handle are products of their own. A failing product is
+// reported and skipped, the rest of the file still goes through; warnings
+// are reported without holding the product back. In a dry run every row is
+// validated but nothing is written or downloaded. Products go to the
+// website of the job and count against its owner's plan.
+func (u *ProductImportUseCase) Process(job *domain.ProductImportJob) error {
+	website, err := u.websiteRepository.FindWebsiteByUUID(job.WebsiteUUID.String())
+	if err != nil {
+		return u.fail(job, "website not found", err)
+	}
+
+	var quota domain.Quota
+	if !job.DryRun {
+		quota, err = planQuota(u.subscriptionRepository, website.OwnerUUID, func(p *domain.VerkoupePlan) int { return p.MaxProducts })
+		if err != nil {
+			return u.fail(job, "no active plan", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	data, err := u.read(ctx, job.BlobKey)
+	cancel()
+	if err != nil {
+		return u.fail(job, "could not read file", err)
+	}
+
+	rows, problems, err := readImportRows(job, data)
+	if err != nil {
+		return u.fail(job, err.Error(), err)
+	}
+
+	build := func(group []domain.ProductImportRow) (*domain.ProductImportItem, []*domain.ProductImportError) {
+		return domain.BuildProductImportItem(group, job.Coin)
+	}
+	if job.Source == enums.ImportSourceShopify {
+		build = func(group []domain.ProductImportRow) (*domain.ProductImportItem, []*domain.ProductImportError) {
+			return domain.BuildShopifyImportItem(group, job.Coin)
+		}
+	}
+
+	job.TotalRows = len(rows)
+	job.WarningCount = len(problems)
+	if err := u.repository.UpdateProductImportProgress(job, problems); err != nil {
+		return err
+	}
+	problems = nil
+
+	seenNames := make(map[string]int)
+	lastSaved, lastSavedAt := 0, time.Now()
+
+	for start := 0; start < len(rows); {
+		end := start + 1
+		if handle := rows[start].Get(domain.ImportFieldHandle); handle != "" {
+			for end < len(rows) && rows[end].Get(domain.ImportFieldHandle) == handle {
+				end++
+			}
+		}
+
+		group := rows[start:end]
+		start = end
+
+		item, itemProblems := build(group)
+		if item != nil {
+			key := strings.ToLower(item.Product.Name)
+			if first, seen := seenNames[key]; seen {
+				itemProblems = append(itemProblems, &domain.ProductImportError{
+					Row:     item.Row,
+					Level:   enums.ImportIssueError,
+					Field:   domain.ImportFieldName,
+					Message: "Product name is repeated from row " + strconv.Itoa(first) + ".",
+				})
+			} else {
+				seenNames[key] = item.Row
+			}
+		}
+
+		failed := item == nil || domain.HasImportErrors(itemProblems)
+		if !failed && !job.DryRun {
+			item.Product.WebsiteUUID = &website.UUID
+			if err := u.repository.ImportProduct(item, quota); err != nil {
+				failed = true
+				itemProblems = append(itemProblems, &domain.ProductImportError{
+					Row:     item.Row,
+					Level:   enums.ImportIssueError,
+					Field:   domain.ImportFieldName,
+					Message: err.Error(),
+				})
+			} else {
+				itemProblems = append(itemProblems, u.importMedias(item)...)
+			}
+		}
+
+		job.ProcessedRows += len(group)
+		if failed {
+			job.FailedCount++
+		} else {
+			job.CreatedCount++
+		}
+		for _, p := range itemProblems {
+			if p.IsWarning() {
+				job.WarningCount++
+			}
+		}
+		problems = append(problems, itemProblems...)
+
+		// Image downloads can make a batch slow; saving on time as well keeps
+		// the job from looking stale to other workers.
+		if job.ProcessedRows-lastSaved >= productImportProgress || time.Since(lastSavedAt) >= productImportInterval {
+			if err := u.repository.UpdateProductImportProgress(job, problems); err != nil {
+				return err
+			}
+			problems = nil
+			lastSaved, lastSavedAt = job.ProcessedRows, time.Now()
+		}
+	}
+
+	if err := u.repository.UpdateProductImportProgress(job, problems); err != nil {
+		return err
+	}
+
+	job.Status = enums.ImportCompleted
+	if err := u.repository.CompleteProductImportJob(job); err != nil {
+		return err
+	}
+
+	u.discard(job)
+	if !job.DryRun && job.CreatedCount > 0 {
+		u.notifyChange()
+	}
+
+	return nil
+}
+
+// Export streams the website's catalog in the importer's own layout. Tags
+// and stock belong to the product, so they are only written on its first
+// row.
+func (u *ProductImportUseCase) Export(ctx context.Context, websiteUUID string, format string, w io.Writer) error {
+	writer, err := spreadsheet.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Write(domain.ProductImportFields); err != nil {
+		return err
+	}
+
+	previous := ""
+	err = u.repository.StreamProductExport(ctx, websiteUUID, func(row *domain.ProductExportRow) error {
+		first := row.Handle != previous
+		previous = row.Handle
+		return writer.Write(exportRecord(row, first))
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func exportRecord(row *domain.ProductExportRow, first bool) []string {
+	optionalInt := func(v int) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+
+	record := make(map[string]string, len(domain.ProductImportFields))
+	record[domain.ImportFieldHandle] = row.Handle
+	record[domain.ImportFieldName] = row.Name
+	record[domain.ImportFieldDescription] = row.Description
+	record[domain.ImportFieldShortDesc] = row.ShortDescription
+	record[domain.ImportFieldBrand] = row.Brand
+	record[domain.ImportFieldHeight] = optionalInt(row.Height)
+	record[domain.ImportFieldWidth] = optionalInt(row.Width)
+	record[domain.ImportFieldThickness] = optionalInt(row.Thickness)
+	record[domain.ImportFieldActive] = strconv.FormatBool(row.Active)
+	record[domain.ImportFieldSKU] = row.SKU
+	record[domain.ImportFieldGTIN] = row.GTIN
+	if row.SKU != "" || row.VariantLabel != "" {
+		record[domain.ImportFieldGTIN] = row.VariantGTIN
+	}
+	record[domain.ImportFieldVariantLabel] = row.VariantLabel
+	if row.VariantActive != nil {
+		record[domain.ImportFieldVariantActive] = strconv.FormatBool(*row.VariantActive)
+	}
+	if row.Price != nil {
+		record[domain.ImportFieldCoin] = row.Coin
+		record[domain.ImportFieldPrice] = domain.FormatImportAmount(*row.Price)
+	}
+	if row.CompareAtPrice != nil {
+		record[domain.ImportFieldCompareAtPrice] = domain.FormatImportAmount(*row.CompareAtPrice)
+	}
+	if first {
+		record[domain.ImportFieldTags] = strings.Join(row.Tags, ", ")
+		record[domain.ImportFieldStock] = strconv.Itoa(row.Stock)
+	}
+
+	values := make([]string, len(domain.ProductImportFields))
+	for i, field := range domain.ProductImportFields {
+		values[i] = record[field]
+	}
+	return values
+}
+
+// readImportRows turns the file into rows keyed by import field, or by
+// Shopify column for Shopify files. Blank rows are dropped without
+// renumbering, so errors point at the spreadsheet row. Columns the importer
+// does not understand are reported once each, when they hold any value.
+func readImportRows(job *domain.ProductImportJob, data []byte) ([]domain.ProductImportRow, []*domain.ProductImportError, error) {
+	reader, err := spreadsheet.NewReader(job.Format, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("the file is empty")
+		}
+		return nil, nil, err
+	}
+
+	var columns map[string]int
+	var unmapped map[int]string
+	if job.Source == enums.ImportSourceShopify {
+		columns, unmapped, err = domain.ResolveShopifyColumns(header)
+	} else {
+		columns, err = job.ResolveColumns(header)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []domain.ProductImportRow
+	var warnings []*domain.ProductImportError
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %w", number, err)
+		}
+
+		for i, column := range unmapped {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				warnings = append(warnings, &domain.ProductImportError{
+					Row:     number,
+					Level:   enums.ImportIssueWarning,
+					Field:   column,
+					Message: fmt.Sprintf("Column %q has no equivalent in the catalog and was not imported.", column),
+				})
+				delete(unmapped, i)
+			}
+		}
+
+		row := domain.ProductImportRow{Number: number, Values: make(map[string]string, len(columns))}
+		blank := true
+		for field, i := range columns {
+			if i < len(record) {
+				row.Values[field] = record[i]
+				if strings.TrimSpace(record[i]) != "" {
+					blank = false
+				}
+			}
+		}
+		if !blank {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Row < warnings[j].Row || (warnings[i].Row == warnings[j].Row && warnings[i].Field < warnings[j].Field)
+	})
+
+	return rows, warnings, nil
+}
+
+// importMedias downloads the images of a freshly imported product into its
+// gallery. A failed image is only a warning: the product is already saved.
+func (u *ProductImportUseCase) importMedias(item *domain.ProductImportItem) []*domain.ProductImportError {
+	var warnings []*domain.ProductImportError
+	skip := func(media *domain.ProductImportMedia, reason string) {
+		warnings = append(warnings, &domain.ProductImportError{
+			Row:     media.Row,
+			Level:   enums.ImportIssueWarning,
+			Field:   "Image Src",
+			Message: fmt.Sprintf("Image %s was not imported: %s.", media.URL, reason),
+		})
+	}
+
+	for _, media := range item.Medias {
+		if u.fetcher == nil || u.mediaUseCase == nil {
+			skip(media, "image downloads are disabled")
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		data, err := u.fetcher.Fetch(ctx, media.URL, u.mediaUseCase.MaxBytes())
+		if err == nil {
+			_, err = u.mediaUseCase.Upload(ctx, item.Product.UUID.String(), bytes.NewReader(data), int64(len(data)), media.Alt)
+		}
+		cancel()
+
+		if err != nil {
+			skip(media, err.Error())
+		}
+	}
+
+	return warnings
+}
+
+func (u *ProductImportUseCase) read(ctx context.Context, key string) ([]byte, error) {
+	body, err := u.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, u.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > u.maxBytes {
+		return nil, ErrImportTooLarge
+	}
+
+	return data, nil
+}
+
+// discard removes the uploaded file once the job is over; the errors table
+// keeps everything needed to fix and resubmit it.
+func (u *ProductImportUseCase) discard(job *domain.ProductImportJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := u.store.Delete(ctx, job.BlobKey); err != nil {
+		logger.Warn(fmt.Errorf("product import %s: %w", job.UUID, err)).Print()
+	}
+}
+
+func (u *ProductImportUseCase) fail(job *domain.ProductImportJob, reason string, cause error) error {
+	job.Status = enums.ImportFailed
+	job.Error = reason
+	if err := u.repository.CompleteProductImportJob(job); err != nil {
+		return err
+	}
+	u.discard(job)
+	return fmt.Errorf("%s: %w", reason, cause)
+}
@@ -0,0 +1,181 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+)
+
+const DefaultMaxMediaBytes int64 = 10 << 20
+
+var (
+	ErrMediaTooLarge    = errors.New("file too large")
+	ErrMediaInvalidType = errors.New("invalid file type")
+	ErrMediaUpload      = errors.New("upload failed")
+)
+
+type CreateProductMediaUseCase struct {
+	changeNotifier
+
+	repository        contracts.ProductMediaContract
+	productRepository contracts.ProductContract
+	store             blob.BlobStore
+	maxBytes          int64
+}
+
+func NewCreateProductMediaUseCase(repository contracts.ProductMediaContract, productRepository contracts.ProductContract, store blob.BlobStore, maxBytes int64) *CreateProductMediaUseCase {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMediaBytes
+	}
+
+	return &CreateProductMediaUseCase{
+		repository:        repository,
+		productRepository: productRepository,
+		store:             store,
+		maxBytes:          maxBytes,
+	}
+}
+
+func (u *CreateProductMediaUseCase) MaxBytes() int64 {
+	return u.maxBytes
+}
+
+// Upload sniffs the real content type from the first bytes of the file
+// instead of trusting the client header, stores the blob and records it
+// at the end of the product gallery.
+func (u *CreateProductMediaUseCase) Upload(ctx context.Context, productUUID string, file io.Reader, size int64, alt string) (*domain.ProductMedia, error) {
+	if size > u.maxBytes {
+		return nil, ErrMediaTooLarge
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, ErrMediaUpload
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if _, ok := domain.MediaExtensions[contentType]; !ok {
+		return nil, ErrMediaInvalidType
+	}
+
+	media, err := domain.NewProductMedia(productUUID, contentType, size, alt)
+	if err != nil {
+		return nil, err
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := u.store.Put(ctx, media.BlobKey, body, size, contentType); err != nil {
+		return nil, ErrMediaUpload
+	}
+
+	created, err := u.repository.CreateProductMedia(media)
+	if err != nil {
+		u.store.Delete(ctx, media.BlobKey)
+		return nil, err
+	}
+
+	u.notifyChange()
+	return created, nil
+}
+
+// ListByProduct returns the gallery in display order with the processed
+// derivatives attached to each media.
+func (u *CreateProductMediaUseCase) ListByProduct(productUUID string) ([]*domain.ProductMedia, error) {
+	medias, err := u.repository.GetProductMediasFromProduct(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	derivatives, err := u.repository.GetProductMediaDerivativesFromProduct(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	byMedia := make(map[string][]*domain.ProductMediaDerivative, len(medias))
+	for _, d := range derivatives {
+		byMedia[d.MediaUUID.String()] = append(byMedia[d.MediaUUID.String()], d)
+	}
+
+	for _, m := range medias {
+		m.Derivatives = byMedia[m.UUID.String()]
+	}
+
+	return medias, nil
+}
+
+func (u *CreateProductMediaUseCase) Reorder(productUUID string, mediaUUIDs []string) ([]*domain.ProductMedia, error) {
+	seen := make(map[string]bool, len(mediaUUIDs))
+	for _, mediaUUID := range mediaUUIDs {
+		if seen[mediaUUID] {
+			return nil, errors.New("media list contains duplicates")
+		}
+		seen[mediaUUID] = true
+	}
+
+	if err := u.repository.ReorderProductMedias(productUUID, mediaUUIDs); err != nil {
+		return nil, err
+	}
+	u.notifyChange()
+
+	return u.ListByProduct(productUUID)
+}
+
+// Reprocess queues a media again, e.g. after the configured sizes changed.
+func (u *CreateProductMediaUseCase) Reprocess(uuidStr string) (*domain.ProductMedia, error) {
+	media, err := u.repository.FindProductMediaByUUID(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !media.IsImage() {
+		return nil, errors.New("only images can be processed")
+	}
+
+	if err := u.repository.UpdateProductMediaStatus(uuidStr, enums.MediaPending, ""); err != nil {
+		return nil, err
+	}
+
+	media.Status = enums.MediaPending
+	media.Error = ""
+	return media, nil
+}
+
+func (u *CreateProductMediaUseCase) Delete(ctx context.Context, uuidStr string) error {
+	media, err := u.repository.FindProductMediaByUUID(uuidStr)
+	if err != nil {
+		return err
+	}
+
+	derivatives, err := u.repository.GetProductMediaDerivativesFromMedia(uuidStr)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repository.DeleteProductMediaByUUID(uuidStr); err != nil {
+		return err
+	}
+	u.notifyChange()
+
+	for _, d := range derivatives {
+		u.store.Delete(ctx, d.BlobKey)
+	}
+
+	return u.store.Delete(ctx, media.BlobKey)
+}
+
+func (u *CreateProductMediaUseCase) URL(key string) string {
+	return u.store.URL(key)
+}
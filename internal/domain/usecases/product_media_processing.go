@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+	"github.com/ViitoJooj/verkoupe/pkg/imaging"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+const (
+	mediaProcessingBatch   = 10
+	maxProcessedImageBytes = 64 << 20
+)
+
+var (
+	DefaultMediaWidths  = []int{320, 640, 1024, 1600}
+	DefaultMediaFormats = []string{"jpeg"}
+)
+
+type ProcessProductMediaUseCase struct {
+	repository  contracts.ProductMediaContract
+	store       blob.BlobStore
+	widths      []int
+	formats     []string
+	jpegQuality int
+}
+
+func NewProcessProductMediaUseCase(repository contracts.ProductMediaContract, store blob.BlobStore, widths []int, formats []string, jpegQuality int) *ProcessProductMediaUseCase {
+	if len(widths) == 0 {
+		widths = DefaultMediaWidths
+	}
+	widths = append([]int(nil), widths...)
+	sort.Ints(widths)
+
+	var supported []string
+	for _, format := range formats {
+		if format == "jpeg" {
+			supported = append(supported, format)
+		}
+	}
+	if len(supported) == 0 {
+		supported = DefaultMediaFormats
+	}
+
+	if jpegQuality < 1 || jpegQuality > 100 {
+		jpegQuality = 82
+	}
+
+	return &ProcessProductMediaUseCase{
+		repository:  repository,
+		store:       store,
+		widths:      widths,
+		formats:     supported,
+		jpegQuality: jpegQuality,
+	}
+}
+
+// ProcessPending is meant to run on a schedule. It drains the queue of
+// uploaded medias in small batches.
+func (u *ProcessProductMediaUseCase) ProcessPending() {
+	for {
+		medias, err := u.repository.ClaimPendingProductMedias(mediaProcessingBatch)
+		if err != nil {
+			logger.Warn(err).Print()
+			return
+		}
+
+		for _, media := range medias {
+			if err := u.Process(media); err != nil {
+				logger.Warn(fmt.Errorf("media %s: %w", media.UUID, err)).Print()
+			}
+		}
+
+		if len(medias) < mediaProcessingBatch {
+			return
+		}
+	}
+}
+
+// Process strips metadata from the original, then stores one derivative per
+// configured width and format. Widths above the original are dropped and the
+// original width is used instead, so images are never upscaled.
+func (u *ProcessProductMediaUseCase) Process(media *domain.ProductMedia) error {
+	if !media.IsImage() {
+		return u.repository.UpdateProductMediaStatus(media.UUID.String(), enums.MediaSkipped, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	data, err := u.read(ctx, media.BlobKey)
+	if err != nil {
+		return u.fail(media, "could not read original", err)
+	}
+
+	img, _, err := imaging.Decode(data)
+	if err != nil {
+		return u.fail(media, "could not decode image", err)
+	}
+
+	if stripped := imaging.StripMetadata(data); len(stripped) != len(data) {
+		if err := u.store.Put(ctx, media.BlobKey, bytes.NewReader(stripped), int64(len(stripped)), media.ContentType); err != nil {
+			return u.fail(media, "could not store original", err)
+		}
+		media.SizeBytes = int64(len(stripped))
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	media.Width = &width
+	media.Height = &height
+
+	previous, err := u.repository.GetProductMediaDerivativesFromMedia(media.UUID.String())
+	if err != nil {
+		return u.fail(media, "could not load derivatives", err)
+	}
+
+	var derivatives []*domain.ProductMediaDerivative
+	for _, target := range u.targetWidths(width) {
+		resized := imaging.Resize(img, target)
+		for _, format := range u.formats {
+			derivative, err := u.storeDerivative(ctx, media, resized, format)
+			if err != nil {
+				return u.fail(media, "could not generate "+format+" derivative", err)
+			}
+			derivatives = append(derivatives, derivative)
+		}
+	}
+
+	if err := u.repository.CompleteProductMediaProcessing(media, derivatives); err != nil {
+		return err
+	}
+
+	current := make(map[string]bool, len(derivatives))
+	for _, d := range derivatives {
+		current[d.BlobKey] = true
+	}
+	for _, d := range previous {
+		if !current[d.BlobKey] {
+			u.store.Delete(ctx, d.BlobKey)
+		}
+	}
+
+	return nil
+}
+
+func (u *ProcessProductMediaUseCase) targetWidths(original int) []int {
+	var targets []int
+	for _, w := range u.widths {
+		if w >= original {
+			return append(targets, original)
+		}
+		targets = append(targets, w)
+	}
+	return targets
+}
+
+func (u *ProcessProductMediaUseCase) storeDerivative(ctx context.Context, media *domain.ProductMedia, img image.Image, format string) (*domain.ProductMediaDerivative, error) {
+	var buf bytes.Buffer
+	contentType := ""
+
+	switch format {
+	case "jpeg":
+		contentType = "image/jpeg"
+		if err := imaging.EncodeJPEG(&buf, img, u.jpegQuality); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported derivative format")
+	}
+
+	bounds := img.Bounds()
+	derivative := &domain.ProductMediaDerivative{
+		MediaUUID: media.UUID,
+		BlobKey:   media.DerivativeKey(bounds.Dx(), format),
+		Format:    format,
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+		SizeBytes: int64(buf.Len()),
+	}
+
+	if err := u.store.Put(ctx, derivative.BlobKey, &buf, derivative.SizeBytes, contentType); err != nil {
+		return nil, err
+	}
+
+	return derivative, nil
+}
+
+func (u *ProcessProductMediaUseCase) read(ctx context.Context, key string) ([]byte, error) {
+	body, err := u.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxProcessedImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxProcessedImageBytes {
+		return nil, ErrMediaTooLarge
+	}
+
+	return data, nil
+}
+
+func (u *ProcessProductMediaUseCase) fail(media *domain.ProductMedia, reason string, cause error) error {
+	if err := u.repository.UpdateProductMediaStatus(media.UUID.String(), enums.MediaFailed, reason); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s: %w", reason, cause)
+}
@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/google/uuid"
+)
+
+type CreateProductPriceUseCase struct {
+	changeNotifier
+
+	repository          contracts.ProductPriceContract
+	productRepository   contracts.ProductContract
+	variantRepository   contracts.ProductVariantContract
+	priceListRepository contracts.PriceListContract
+}
+
+func NewCreateProductPriceUseCase(repository contracts.ProductPriceContract, productRepository contracts.ProductContract, variantRepository contracts.ProductVariantContract, priceListRepository contracts.PriceListContract) *CreateProductPriceUseCase {
+	return &CreateProductPriceUseCase{
+		repository:          repository,
+		productRepository:   productRepository,
+		variantRepository:   variantRepository,
+		priceListRepository: priceListRepository,
+	}
+}
+
+func (u *CreateProductPriceUseCase) Create(productUUID string, variantUUID string, priceListUUID string, coin string, amount int, compareAtAmount *int, saleAmount *int, saleStartsAt *time.Time, saleEndsAt *time.Time) (*domain.ProductPrice, error) {
+	price, err := domain.NewProductPrice(productUUID, variantUUID, priceListUUID, coin, amount, compareAtAmount, saleAmount, saleStartsAt, saleEndsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	if price.VariantUUID != nil {
+		variant, err := u.variantRepository.FindProductVariantByUUID(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductUUID != price.ProductUUID {
+			return nil, errors.New("variant does not belong to product")
+		}
+	}
+
+	if price.PriceListUUID != nil {
+		list, err := u.priceListRepository.FindPriceListByUUID(priceListUUID)
+		if err != nil {
+			return nil, err
+		}
+		if list.Coin != price.Coin {
+			return nil, errors.New("price coin must match price list coin")
+		}
+	}
+
+	created, err := u.repository.CreateProductPrice(price)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return created, nil
+}
+
+func (u *CreateProductPriceUseCase) ListByProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductPrice], error) {
+	return u.repository.ListProductPricesFromProduct(productUUID, query)
+}
+
+func (u *CreateProductPriceUseCase) Update(uuidStr string, amount int, compareAtAmount *int, saleAmount *int, saleStartsAt *time.Time, saleEndsAt *time.Time) (*domain.ProductPrice, error) {
+	current, err := u.repository.FindProductPriceByUUID(uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	variantUUID := ""
+	if current.VariantUUID != nil {
+		variantUUID = current.VariantUUID.String()
+	}
+
+	priceListUUID := ""
+	if current.PriceListUUID != nil {
+		priceListUUID = current.PriceListUUID.String()
+	}
+
+	price, err := domain.NewProductPrice(current.ProductUUID.String(), variantUUID, priceListUUID, string(current.Coin), amount, compareAtAmount, saleAmount, saleStartsAt, saleEndsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	price.UUID = current.UUID
+	price.CreatedAt = current.CreatedAt
+
+	if err := u.repository.UpdateProductPrice(price); err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return price, nil
+}
+
+func (u *CreateProductPriceUseCase) Delete(uuidStr string) error {
+	if err := u.repository.DeleteProductPriceByUUID(uuidStr); err != nil {
+		return err
+	}
+
+	u.notifyChange()
+	return nil
+}
+
+type ResolveProductPriceUseCase struct {
+	repository          contracts.ProductPriceContract
+	websiteRepository   contracts.WebsiteContract
+	priceListRepository contracts.PriceListContract
+	currencyUseCase     *CurrencyUseCase
+}
+
+func NewResolveProductPriceUseCase(repository contracts.ProductPriceContract, websiteRepository contracts.WebsiteContract, priceListRepository contracts.PriceListContract, currencyUseCase *CurrencyUseCase) *ResolveProductPriceUseCase {
+	return &ResolveProductPriceUseCase{
+		repository:          repository,
+		websiteRepository:   websiteRepository,
+		priceListRepository: priceListRepository,
+		currencyUseCase:     currencyUseCase,
+	}
+}
+
+// CustomerGroup is the group whose price lists userUUID gets on the
+// website, empty for shoppers who are signed out or in no group.
+func (u *ResolveProductPriceUseCase) CustomerGroup(websiteUUID string, userUUID string) (string, error) {
+	if userUUID == "" {
+		return "", nil
+	}
+
+	return u.priceListRepository.FindCustomerGroup(websiteUUID, userUUID)
+}
+
+func (u *ResolveProductPriceUseCase) Resolve(productUUID string, variantUUID string, coin string, customerGroup string) (*domain.ResolvedPrice, error) {
+	return u.ResolveAt(productUUID, variantUUID, coin, customerGroup, time.Now())
+}
+
+func (u *ResolveProductPriceUseCase) ResolveAt(productUUID string, variantUUID string, coin string, customerGroup string, at time.Time) (*domain.ResolvedPrice, error) {
+	var variantPtr *uuid.UUID
+	if variantUUID != "" {
+		parsed, err := uuid.Parse(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		variantPtr = &parsed
+	}
+
+	prices, err := u.repository.GetProductPricesFromProduct(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := u.repository.GetPriceListsFromProduct(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolvePrice(prices, listsByUUID(lists), variantPtr, coin, customerGroup, at)
+}
+
+// ResolveForDisplay resolves the price a shopper sees in displayCoin. A price
+// explicitly set in that currency is used as is; otherwise the price in the
+// website base currency is converted, since orders settle in the base currency.
+func (u *ResolveProductPriceUseCase) ResolveForDisplay(websiteUUID string, productUUID string, variantUUID string, displayCoin string, customerGroup string) (*domain.DisplayPrice, error) {
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.display(website, displayCoin, func(coin string) (*domain.ResolvedPrice, error) {
+		return u.Resolve(productUUID, variantUUID, coin, customerGroup)
+	})
+}
+
+// ResolveManyForDisplay is ResolveForDisplay for a whole catalog, loading
+// every price in two queries. Targets without a price are left out.
+func (u *ResolveProductPriceUseCase) ResolveManyForDisplay(websiteUUID string, targets []domain.PriceTarget, displayCoin string, customerGroup string) (map[domain.PriceTarget]*domain.DisplayPrice, error) {
+	website, err := u.websiteRepository.FindWebsiteByUUID(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(targets))
+	productUUIDs := make([]string, 0, len(targets))
+	for _, t := range targets {
+		if !seen[t.ProductUUID] {
+			seen[t.ProductUUID] = true
+			productUUIDs = append(productUUIDs, t.ProductUUID.String())
+		}
+	}
+
+	prices, err := u.repository.GetProductPricesFromProducts(productUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := u.repository.GetPriceListsFromProducts(productUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uuid.UUID][]*domain.ProductPrice, len(productUUIDs))
+	for _, p := range prices {
+		byProduct[p.ProductUUID] = append(byProduct[p.ProductUUID], p)
+	}
+
+	listsMap := listsByUUID(lists)
+	now := time.Now()
+
+	displays := make(map[domain.PriceTarget]*domain.DisplayPrice, len(targets))
+	for _, t := range targets {
+		var variantPtr *uuid.UUID
+		if t.VariantUUID != uuid.Nil {
+			variantPtr = &t.VariantUUID
+		}
+
+		display, err := u.display(website, displayCoin, func(coin string) (*domain.ResolvedPrice, error) {
+			return resolvePrice(byProduct[t.ProductUUID], listsMap, variantPtr, coin, customerGroup, now)
+		})
+		if err != nil {
+			continue
+		}
+		displays[t] = display
+	}
+
+	return displays, nil
+}
+
+func (u *ResolveProductPriceUseCase) display(website *domain.Website, displayCoin string, resolve func(coin string) (*domain.ResolvedPrice, error)) (*domain.DisplayPrice, error) {
+	if displayCoin == "" {
+		displayCoin = string(website.BaseCoin)
+	}
+
+	if resolved, err := resolve(displayCoin); err == nil {
+		return &domain.DisplayPrice{
+			Resolved:               resolved,
+			DisplayCoin:            resolved.Coin,
+			DisplayAmount:          resolved.Amount,
+			DisplayCompareAtAmount: resolved.CompareAtAmount,
+		}, nil
+	}
+
+	resolved, err := resolve(string(website.BaseCoin))
+	if err != nil {
+		return nil, err
+	}
+
+	websiteUUID := website.UUID.String()
+	target := enums.CoinType(displayCoin)
+	amount, err := u.currencyUseCase.Convert(websiteUUID, resolved.Amount, resolved.Coin, target)
+	if err != nil {
+		return nil, err
+	}
+
+	display := &domain.DisplayPrice{
+		Resolved:      resolved,
+		DisplayCoin:   target,
+		DisplayAmount: amount,
+		Converted:     true,
+	}
+
+	if resolved.CompareAtAmount != nil {
+		compareAt, err := u.currencyUseCase.Convert(websiteUUID, *resolved.CompareAtAmount, resolved.Coin, target)
+		if err != nil {
+			return nil, err
+		}
+		display.DisplayCompareAtAmount = &compareAt
+	}
+
+	return display, nil
+}
+
+func resolvePrice(prices []*domain.ProductPrice, lists map[uuid.UUID]*domain.PriceList, variantUUID *uuid.UUID, coin string, customerGroup string, at time.Time) (*domain.ResolvedPrice, error) {
+	coinType := enums.CoinType(coin)
+	if coinType != enums.BRCoin && coinType != enums.EUACoin && coinType != enums.EURCoin {
+		return nil, errors.New("Coin must be 'BRL', 'USD' or 'EUR'.")
+	}
+
+	return domain.ResolvePrice(prices, lists, variantUUID, coinType, customerGroup, at)
+}
+
+func listsByUUID(lists []*domain.PriceList) map[uuid.UUID]*domain.PriceList {
+	byUUID := make(map[uuid.UUID]*domain.PriceList, len(lists))
+	for _, l := range lists {
+		byUUID[l.UUID] = l
+	}
+	return byUUID
+}
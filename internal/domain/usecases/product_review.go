@@ -0,0 +1,348 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/blob"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// A user may submit this many reviews per window. The count comes from the
+// database so the limit holds across instances.
+const (
+	reviewRateLimit  = 5
+	reviewRateWindow = time.Hour
+)
+
+var (
+	ErrReviewRateLimited = errors.New("too many reviews")
+	ErrReviewNotFound    = errors.New("product review not found")
+	ErrReviewPublish     = errors.New("could not publish review photos")
+)
+
+type ReviewPhotoUpload struct {
+	File io.Reader
+	Size int64
+}
+
+type ProductReviewUseCase struct {
+	repository        contracts.ProductReviewContract
+	productRepository contracts.ProductContract
+	store             blob.BlobStore
+	maxPhotoBytes     int64
+
+	mu               sync.RWMutex
+	approveListeners []func(*domain.ProductReview)
+}
+
+func NewProductReviewUseCase(repository contracts.ProductReviewContract, productRepository contracts.ProductContract, store blob.BlobStore, maxPhotoBytes int64) *ProductReviewUseCase {
+	if maxPhotoBytes <= 0 {
+		maxPhotoBytes = DefaultMaxMediaBytes
+	}
+
+	return &ProductReviewUseCase{
+		repository:        repository,
+		productRepository: productRepository,
+		store:             store,
+		maxPhotoBytes:     maxPhotoBytes,
+	}
+}
+
+func (u *ProductReviewUseCase) MaxPhotoBytes() int64 {
+	return u.maxPhotoBytes
+}
+
+// Submit queues a review for moderation. It is marked as a verified
+// purchase when the product was delivered to one of the user's addresses.
+func (u *ProductReviewUseCase) Submit(ctx context.Context, productUUID string, userUUID string, rating int, title string, body string, photos []ReviewPhotoUpload) (*domain.ProductReview, error) {
+	review, err := domain.NewProductReview(productUUID, userUUID, rating, title, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(photos) > domain.MaxReviewPhotos {
+		return nil, errors.New("A review can have at most 5 photos.")
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	recent, err := u.repository.CountProductReviewsByUserSince(userUUID, time.Now().Add(-reviewRateWindow))
+	if err != nil {
+		return nil, err
+	}
+	if recent >= reviewRateLimit {
+		return nil, ErrReviewRateLimited
+	}
+
+	shipped, err := u.repository.FindDeliveredProductShipment(productUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	review.ShippedUUID = shipped
+	review.Verified = shipped != nil
+
+	stored, err := u.storePhotos(ctx, review, photos)
+	if err != nil {
+		u.deleteBlobs(ctx, stored)
+		return nil, err
+	}
+
+	created, err := u.repository.CreateProductReview(review)
+	if err != nil {
+		u.deleteBlobs(ctx, stored)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// storePhotos uploads the photos and returns the keys written so far, also
+// on error, so the caller can clean them up.
+func (u *ProductReviewUseCase) storePhotos(ctx context.Context, review *domain.ProductReview, photos []ReviewPhotoUpload) ([]string, error) {
+	var stored []string
+	for _, upload := range photos {
+		if upload.Size > u.maxPhotoBytes {
+			return stored, ErrMediaTooLarge
+		}
+
+		head := make([]byte, 512)
+		n, err := io.ReadFull(upload.File, head)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return stored, ErrMediaUpload
+		}
+		head = head[:n]
+
+		photo, err := review.AddPhoto(http.DetectContentType(head), upload.Size)
+		if err != nil {
+			return stored, ErrMediaInvalidType
+		}
+
+		body := io.MultiReader(bytes.NewReader(head), upload.File)
+		if err := u.store.Put(ctx, photo.BlobKey, body, upload.Size, photo.ContentType); err != nil {
+			return stored, ErrMediaUpload
+		}
+		stored = append(stored, photo.BlobKey)
+	}
+
+	return stored, nil
+}
+
+func (u *ProductReviewUseCase) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		u.store.Delete(ctx, key)
+	}
+}
+
+// ListByProduct returns the approved reviews of a product.
+func (u *ProductReviewUseCase) ListByProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error) {
+	page, err := u.repository.GetApprovedProductReviews(productUUID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, u.attachPhotos(page.Items)
+}
+
+// ListForModeration returns reviews of every status on the products of
+// websiteUUID, filtered by the merchant, pending ones first in line.
+func (u *ProductReviewUseCase) ListForModeration(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error) {
+	page, err := u.repository.GetProductReviews(websiteUUID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, u.attachPhotos(page.Items)
+}
+
+func (u *ProductReviewUseCase) attachPhotos(reviews []*domain.ProductReview) error {
+	ids := make([]uuid.UUID, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r.UUID)
+	}
+
+	photos, err := u.repository.GetProductReviewPhotos(ids)
+	if err != nil {
+		return err
+	}
+
+	byReview := make(map[uuid.UUID][]*domain.ProductReviewPhoto, len(reviews))
+	for _, p := range photos {
+		byReview[p.ReviewUUID] = append(byReview[p.ReviewUUID], p)
+	}
+
+	for _, r := range reviews {
+		r.Photos = byReview[r.UUID]
+	}
+
+	return nil
+}
+
+// Approve publishes a review of a product of websiteUUID, its photos
+// included, and then runs the OnApprove hooks.
+func (u *ProductReviewUseCase) Approve(ctx context.Context, websiteUUID string, uuidStr string, moderatorUUID string) (*domain.ProductReview, error) {
+	review, err := u.moderate(ctx, websiteUUID, uuidStr, enums.ReviewApproved, "", moderatorUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	listeners := u.approveListeners
+	u.mu.RUnlock()
+
+	for _, listener := range listeners {
+		u.notifyApprove(listener, review)
+	}
+
+	return review, nil
+}
+
+// OnApprove registers a hook called for every approved review, e.g. to
+// reward its author.
+func (u *ProductReviewUseCase) OnApprove(listener func(*domain.ProductReview)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.approveListeners = append(u.approveListeners, listener)
+}
+
+func (u *ProductReviewUseCase) notifyApprove(listener func(*domain.ProductReview), review *domain.ProductReview) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Warn(fmt.Errorf("product review %s approve hook panicked: %v", review.UUID, r)).Print()
+		}
+	}()
+
+	listener(review)
+}
+
+func (u *ProductReviewUseCase) Reject(ctx context.Context, websiteUUID string, uuidStr string, reason string, moderatorUUID string) (*domain.ProductReview, error) {
+	return u.moderate(ctx, websiteUUID, uuidStr, enums.ReviewRejected, reason, moderatorUUID)
+}
+
+func (u *ProductReviewUseCase) moderate(ctx context.Context, websiteUUID string, uuidStr string, status enums.ReviewStatusType, reason string, moderatorUUID string) (*domain.ProductReview, error) {
+	review, err := u.find(websiteUUID, uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attachPhotos([]*domain.ProductReview{review}); err != nil {
+		return nil, err
+	}
+
+	if err := review.Moderate(status, reason, moderatorUUID); err != nil {
+		return nil, err
+	}
+
+	var moved map[string]string
+	if status == enums.ReviewApproved {
+		moved, err = u.publishPhotos(ctx, review)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.repository.ModerateProductReview(review); err != nil {
+		for _, key := range moved {
+			u.store.Delete(ctx, key)
+		}
+		return nil, err
+	}
+
+	for key := range moved {
+		u.store.Delete(ctx, key)
+	}
+
+	return review, nil
+}
+
+// find returns the review only when its product belongs to websiteUUID.
+func (u *ProductReviewUseCase) find(websiteUUID string, uuidStr string) (*domain.ProductReview, error) {
+	review, err := u.repository.FindProductReviewByUUID(uuidStr)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	product, err := u.productRepository.FindProductByUUID(review.ProductUUID.String())
+	if err != nil || product.WebsiteUUID == nil || product.WebsiteUUID.String() != websiteUUID {
+		return nil, ErrReviewNotFound
+	}
+
+	return review, nil
+}
+
+// publishPhotos copies the photos of review to their public keys. It
+// returns the copies by the key they were copied from; on error none is
+// left behind.
+func (u *ProductReviewUseCase) publishPhotos(ctx context.Context, review *domain.ProductReview) (map[string]string, error) {
+	moved := review.PublishPhotos()
+
+	byKey := make(map[string]*domain.ProductReviewPhoto, len(review.Photos))
+	for _, p := range review.Photos {
+		byKey[p.BlobKey] = p
+	}
+
+	copied := make(map[string]string, len(moved))
+	for from, to := range moved {
+		photo := byKey[to]
+		if err := u.copyBlob(ctx, from, to, photo.SizeBytes, photo.ContentType); err != nil {
+			for _, key := range copied {
+				u.store.Delete(ctx, key)
+			}
+			return nil, ErrReviewPublish
+		}
+		copied[from] = to
+	}
+
+	return copied, nil
+}
+
+func (u *ProductReviewUseCase) copyBlob(ctx context.Context, from string, to string, size int64, contentType string) error {
+	body, err := u.store.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return u.store.Put(ctx, to, body, size, contentType)
+}
+
+// Photo opens a photo of a review of a product of websiteUUID, published
+// or not, for its moderators.
+func (u *ProductReviewUseCase) Photo(ctx context.Context, websiteUUID string, uuidStr string) (*domain.ProductReviewPhoto, io.ReadCloser, error) {
+	photo, err := u.repository.FindProductReviewPhotoByUUID(uuidStr)
+	if err != nil {
+		return nil, nil, ErrReviewNotFound
+	}
+
+	if _, err := u.find(websiteUUID, photo.ReviewUUID.String()); err != nil {
+		return nil, nil, err
+	}
+
+	body, err := u.store.Get(ctx, photo.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return photo, body, nil
+}
+
+func (u *ProductReviewUseCase) Summary(productUUID string) (*domain.ProductRatingSummary, error) {
+	return u.repository.GetProductRatingSummary(productUUID)
+}
+
+func (u *ProductReviewUseCase) URL(key string) string {
+	return u.store.URL(key)
+}
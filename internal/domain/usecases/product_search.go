@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/google/uuid"
+)
+
+// SearchRecorder receives every first-page search so popular queries can be
+// ranked.
+type SearchRecorder interface {
+	RecordSearch(websiteUUID uuid.UUID, query string, results int)
+}
+
+type SearchProductsUseCase struct {
+	repository contracts.ProductSearchContract
+	recorder   SearchRecorder
+}
+
+func NewSearchProductsUseCase(repository contracts.ProductSearchContract, recorder SearchRecorder) *SearchProductsUseCase {
+	return &SearchProductsUseCase{repository: repository, recorder: recorder}
+}
+
+func (u *SearchProductsUseCase) Search(websiteUUID string, text string, tags []string, categoryUUID string, coin string, minPrice *int, maxPrice *int, inStock *bool, limit int, offset int) (*domain.ProductSearchResult, error) {
+	query, err := domain.NewProductSearchQuery(websiteUUID, text, tags, categoryUUID, coin, minPrice, maxPrice, inStock, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.repository.SearchProducts(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.recorder != nil && query.Text != "" && query.Offset == 0 {
+		u.recorder.RecordSearch(query.WebsiteUUID, query.Text, result.Total)
+	}
+
+	result.Query = query
+	return result, nil
+}
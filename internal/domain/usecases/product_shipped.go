@@ -7,11 +7,12 @@ import (
 )
 
 type CreateProductShippedUseCase struct {
-	repository contracts.ProductShippedContract
+	repository        contracts.ProductShippedContract
+	productRepository contracts.ProductContract
 }
 
-func NewCreateProductShippedUseCase(repository contracts.ProductShippedContract) *CreateProductShippedUseCase {
-	return &CreateProductShippedUseCase{repository: repository}
+func NewCreateProductShippedUseCase(repository contracts.ProductShippedContract, productRepository contracts.ProductContract) *CreateProductShippedUseCase {
+	return &CreateProductShippedUseCase{repository: repository, productRepository: productRepository}
 }
 
 func (u *CreateProductShippedUseCase) Create(productUUID string, addressUUID string, status string) (*domain.ProductShipped, error) {
@@ -19,5 +20,14 @@ func (u *CreateProductShippedUseCase) Create(productUUID string, addressUUID str
 	if err != nil {
 		return nil, err
 	}
+
+	product, err := u.productRepository.FindProductByUUID(productUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.Shippable() {
+		return nil, ErrProductNotShippable
+	}
 	return u.repository.CreateProductShipped(productShipped)
 }
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+type CreateProductVariantUseCase struct {
+	changeNotifier
+	repository        contracts.ProductVariantContract
+	productRepository contracts.ProductContract
+}
+
+func NewCreateProductVariantUseCase(repository contracts.ProductVariantContract, productRepository contracts.ProductContract) *CreateProductVariantUseCase {
+	return &CreateProductVariantUseCase{repository: repository, productRepository: productRepository}
+}
+
+func (u *CreateProductVariantUseCase) Create(productUUID string, sku string, label string, gtin string, active bool) (*domain.ProductVariant, error) {
+	variant, err := domain.NewProductVariant(productUUID, sku, label, gtin, active)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	created, err := u.repository.CreateProductVariant(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return created, nil
+}
+
+func (u *CreateProductVariantUseCase) ListByProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductVariant], error) {
+	return u.repository.GetProductVariantsFromProduct(productUUID, query)
+}
+
+func (u *CreateProductVariantUseCase) Delete(uuidStr string) error {
+	if err := u.repository.DeleteProductVariantByUUID(uuidStr); err != nil {
+		return err
+	}
+
+	u.notifyChange()
+	return nil
+}
@@ -45,6 +45,6 @@ func (u *CreateRbacUseCase) GetByUUID(uuidStr string) (*domain.Rbac, error) {
 	return u.rbacRepo.FindRbacByUUID(uuidStr)
 }
 
-func (u *CreateRbacUseCase) GetAll(websiteUUIDStr string) ([]*domain.Rbac, error) {
-	return u.rbacRepo.GetRbacFromWebsite(websiteUUIDStr)
+func (u *CreateRbacUseCase) GetAll(websiteUUIDStr string, query *domain.ListQuery) (*domain.Page[*domain.Rbac], error) {
+	return u.rbacRepo.GetRbacFromWebsite(websiteUUIDStr, query)
 }
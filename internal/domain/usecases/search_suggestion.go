@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"sync"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/cache"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	suggestionLimit     = 5
+	suggestionCacheSize = 2048
+	suggestionCacheTTL  = time.Minute
+)
+
+type SearchSuggestionUseCase struct {
+	repository contracts.SearchSuggestionContract
+	cache      *cache.LRU[string, *domain.SearchSuggestions]
+
+	mu      sync.Mutex
+	pending map[string]*domain.SearchQuery
+}
+
+func NewSearchSuggestionUseCase(repository contracts.SearchSuggestionContract) *SearchSuggestionUseCase {
+	return &SearchSuggestionUseCase{
+		repository: repository,
+		cache:      cache.NewLRU[string, *domain.SearchSuggestions](suggestionCacheSize, suggestionCacheTTL),
+		pending:    make(map[string]*domain.SearchQuery),
+	}
+}
+
+// Suggest returns the website's products, categories and popular past
+// searches for the typed prefix. Results are cached per prefix and website
+// until a product or category changes.
+func (u *SearchSuggestionUseCase) Suggest(prefix string, websiteUUID string) (*domain.SearchSuggestions, error) {
+	prefix = domain.NormalizeSearchQuery(prefix)
+	if len([]rune(prefix)) < domain.MinSuggestionLength {
+		return &domain.SearchSuggestions{Query: prefix}, nil
+	}
+
+	key := websiteUUID + "|" + prefix
+	if cached, ok := u.cache.Get(key); ok {
+		return cached, nil
+	}
+
+	suggestions := &domain.SearchSuggestions{Query: prefix}
+	var wg sync.WaitGroup
+	var productsErr, categoriesErr, queriesErr error
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		suggestions.Products, productsErr = u.repository.SuggestProducts(prefix, websiteUUID, suggestionLimit)
+	}()
+	go func() {
+		defer wg.Done()
+		suggestions.Categories, categoriesErr = u.repository.SuggestCategories(prefix, websiteUUID, suggestionLimit)
+	}()
+	go func() {
+		defer wg.Done()
+		suggestions.Queries, queriesErr = u.repository.SuggestSearchQueries(prefix, websiteUUID, suggestionLimit)
+	}()
+	wg.Wait()
+
+	for _, err := range []error{productsErr, categoriesErr, queriesErr} {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u.cache.Set(key, suggestions)
+	return suggestions, nil
+}
+
+// Invalidate drops every cached suggestion. It is registered as a change
+// listener on the product and category use cases.
+func (u *SearchSuggestionUseCase) Invalidate() {
+	u.cache.Purge()
+}
+
+// RecordSearch only aggregates in memory so searching never waits on an
+// analytics write; FlushSearchQueries persists the counters. Searches are
+// counted per website.
+func (u *SearchSuggestionUseCase) RecordSearch(websiteUUID uuid.UUID, query string, results int) {
+	query = domain.NormalizeSearchQuery(query)
+	if query == "" {
+		return
+	}
+
+	key := websiteUUID.String() + "|" + query
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entry, ok := u.pending[key]
+	if !ok {
+		entry = &domain.SearchQuery{WebsiteUUID: websiteUUID, Query: query}
+		u.pending[key] = entry
+	}
+	entry.Searches++
+	entry.LastResults = results
+	entry.LastSearchedAt = time.Now()
+}
+
+func (u *SearchSuggestionUseCase) FlushSearchQueries() {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = make(map[string]*domain.SearchQuery)
+	u.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	queries := make([]*domain.SearchQuery, 0, len(pending))
+	for _, q := range pending {
+		queries = append(queries, q)
+	}
+
+	if err := u.repository.RecordSearchQueries(queries); err != nil {
+		logger.Warn(err).Print()
+	}
+}
+
+// Popular returns the searches made most often on the website.
+func (u *SearchSuggestionUseCase) Popular(websiteUUID string, limit int) ([]*domain.SearchQuery, error) {
+	if limit <= 0 || limit > domain.MaxSearchLimit {
+		limit = domain.DefaultSearchLimit
+	}
+	return u.repository.GetPopularSearchQueries(websiteUUID, limit)
+}
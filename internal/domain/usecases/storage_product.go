@@ -7,6 +7,8 @@ import (
 )
 
 type CreateStorageProductUseCase struct {
+	changeNotifier
+
 	repository contracts.StorageProductContract
 }
 
@@ -25,5 +27,6 @@ func (u *CreateStorageProductUseCase) Create(productUUID string) (*domain.Storag
 		return nil, err
 	}
 
+	u.notifyChange()
 	return createdSP, nil
 }
@@ -0,0 +1,465 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrSubscriptionNotActive = errors.New("subscription not active")
+	ErrPlanNotFound          = errors.New("plan not found")
+	ErrPlanCoinMismatch      = errors.New("plan currency does not match")
+	ErrInvoiceNotFound       = errors.New("invoice not found")
+)
+
+// SubscriptionUseCase bills owners for their plans. Listeners registered
+// with OnChange run whenever a subscription starts, ends, or its websites
+// are suspended or back online.
+type SubscriptionUseCase struct {
+	changeNotifier
+	repository     contracts.SubscriptionContract
+	planRepository contracts.PlanContract
+	provider       contracts.PaymentProviderContract
+}
+
+func NewSubscriptionUseCase(repository contracts.SubscriptionContract, planRepository contracts.PlanContract, provider contracts.PaymentProviderContract) *SubscriptionUseCase {
+	return &SubscriptionUseCase{
+		repository:     repository,
+		planRepository: planRepository,
+		provider:       provider,
+	}
+}
+
+// Subscribe puts the owner on a plan, on trial the first time and otherwise
+// starting once the first period is paid.
+func (u *SubscriptionUseCase) Subscribe(ownerUUID string, ownerType string, planUUID string, cycle string, paymentMethod string) (*domain.Subscription, error) {
+	subscription, err := domain.NewSubscription(ownerUUID, ownerType, planUUID, cycle, paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := u.planRepository.FindPlanByUUID(planUUID)
+	if err != nil {
+		return nil, ErrPlanNotFound
+	}
+
+	if paymentMethod == "" && plan.CyclePrice(subscription.Cycle) > 0 {
+		return nil, ErrPaymentMethodMissing
+	}
+
+	trialed := false
+	if existing, err := u.repository.FindSubscriptionByOwner(ownerUUID); err == nil {
+		if existing.Status != enums.SubscriptionCanceled && existing.Status != enums.SubscriptionIncomplete {
+			return nil, domain.ErrSubscriptionExists
+		}
+		trialed = existing.TrialEndsAt != nil
+	}
+
+	now := time.Now()
+	if plan.TrialDays > 0 && !trialed {
+		trialEnd := now.AddDate(0, 0, plan.TrialDays)
+		subscription.Status = enums.SubscriptionTrialing
+		subscription.TrialEndsAt = &trialEnd
+		subscription.CurrentPeriodStart = &now
+		subscription.CurrentPeriodEnd = &trialEnd
+
+		subscription, err = u.repository.CreateSubscription(subscription)
+		if err != nil {
+			return nil, err
+		}
+
+		u.notifyChange()
+		return subscription, nil
+	}
+
+	subscription, err = u.repository.CreateSubscription(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := u.repository.CreateInvoice(newSubscriptionInvoice(subscription, plan, enums.InvoiceSignup, subscription.Cycle, plan.CyclePrice(subscription.Cycle), now, domain.CycleEnd(now, subscription.Cycle)))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.collect(subscription, invoice); err != nil {
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+func (u *SubscriptionUseCase) GetByOwner(ownerUUID string) (*domain.Subscription, error) {
+	subscription, err := u.repository.FindSubscriptionByOwner(ownerUUID)
+	if err != nil {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+// ChangePlan moves the owner to another plan and cycle, prorating what is
+// left of the period. Money owed back becomes credit; trials switch free.
+func (u *SubscriptionUseCase) ChangePlan(ownerUUID string, planUUID string, cycle string) (*domain.Subscription, error) {
+	subscription, err := u.GetByOwner(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status != enums.SubscriptionTrialing && subscription.Status != enums.SubscriptionActive {
+		return nil, ErrSubscriptionNotActive
+	}
+
+	cycleType := subscription.Cycle
+	if cycle != "" {
+		if cycleType, err = domain.ParseBillingCycle(cycle); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := u.planRepository.FindPlanByUUID(planUUID)
+	if err != nil {
+		return nil, ErrPlanNotFound
+	}
+
+	current, err := u.planRepository.FindPlanByUUID(subscription.PlanUUID.String())
+	if err != nil {
+		return nil, ErrPlanNotFound
+	}
+
+	if plan.Coin != current.Coin {
+		return nil, ErrPlanCoinMismatch
+	}
+
+	if subscription.PaymentMethod == "" && plan.CyclePrice(cycleType) > 0 {
+		return nil, ErrPaymentMethodMissing
+	}
+
+	if subscription.Status == enums.SubscriptionTrialing {
+		subscription.PlanUUID = plan.UUID
+		subscription.Cycle = cycleType
+		return u.update(subscription)
+	}
+
+	now := time.Now()
+	unused := subscription.Unused(current.CyclePrice(subscription.Cycle), now)
+	periodStart, periodEnd := now, domain.CycleEnd(now, cycleType)
+	due := plan.CyclePrice(cycleType) - unused
+	if cycleType == subscription.Cycle && subscription.CurrentPeriodStart != nil && subscription.CurrentPeriodEnd != nil {
+		periodStart, periodEnd = *subscription.CurrentPeriodStart, *subscription.CurrentPeriodEnd
+		due = subscription.Unused(plan.CyclePrice(cycleType), now) - unused
+	}
+
+	if due <= 0 {
+		subscription.PlanUUID = plan.UUID
+		subscription.Cycle = cycleType
+		subscription.CurrentPeriodStart = &periodStart
+		subscription.CurrentPeriodEnd = &periodEnd
+		subscription.Credit -= due
+		return u.update(subscription)
+	}
+
+	invoice, err := u.repository.CreateInvoice(newSubscriptionInvoice(subscription, plan, enums.InvoiceProration, cycleType, due, periodStart, periodEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.collect(subscription, invoice); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Cancel stops the renewal of a trial or a paid period, which the owner
+// keeps using until it ends. A subscription that is not paid up ends now.
+func (u *SubscriptionUseCase) Cancel(ownerUUID string) (*domain.Subscription, error) {
+	subscription, err := u.GetByOwner(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch subscription.Status {
+	case enums.SubscriptionCanceled:
+		return nil, ErrSubscriptionNotActive
+	case enums.SubscriptionTrialing, enums.SubscriptionActive:
+		subscription.CancelAtPeriodEnd = true
+		return u.repository.UpdateSubscription(subscription)
+	}
+
+	subscription, err = u.repository.CancelSubscription(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return subscription, nil
+}
+
+// UpdatePaymentMethod changes what the next charges use. Open invoices are
+// not retried right away; PayInvoice does that.
+func (u *SubscriptionUseCase) UpdatePaymentMethod(ownerUUID string, paymentMethod string) (*domain.Subscription, error) {
+	if paymentMethod == "" {
+		return nil, ErrPaymentMethodMissing
+	}
+
+	subscription, err := u.GetByOwner(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == enums.SubscriptionCanceled {
+		return nil, ErrSubscriptionNotActive
+	}
+
+	subscription.PaymentMethod = paymentMethod
+	return u.repository.UpdateSubscription(subscription)
+}
+
+func (u *SubscriptionUseCase) Invoices(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.SubscriptionInvoice], error) {
+	return u.repository.GetInvoicesByOwner(ownerUUID, query)
+}
+
+// PayInvoice charges an open invoice of the owner now, typically after the
+// owner fixed its payment method.
+func (u *SubscriptionUseCase) PayInvoice(ownerUUID string, invoiceUUID string) (*domain.SubscriptionInvoice, error) {
+	if _, err := uuid.Parse(invoiceUUID); err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	invoice, err := u.repository.FindInvoiceByUUID(invoiceUUID)
+	if err != nil || invoice.OwnerUUID.String() != ownerUUID {
+		return nil, ErrInvoiceNotFound
+	}
+
+	if invoice.Status != enums.InvoiceOpen {
+		return nil, domain.ErrInvoiceNotOpen
+	}
+
+	subscription, err := u.GetByOwner(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.UUID != invoice.SubscriptionUUID || subscription.Status == enums.SubscriptionCanceled {
+		return nil, ErrSubscriptionNotActive
+	}
+
+	if err := u.collect(subscription, invoice); err != nil {
+		return invoice, err
+	}
+
+	return invoice, nil
+}
+
+// RunDue is meant to be handed to the scheduler. It renews or ends
+// subscriptions whose period is over, retries failed renewals and
+// suspends the ones still unpaid after the grace period.
+func (u *SubscriptionUseCase) RunDue() {
+	now := time.Now()
+
+	subscriptions, err := u.repository.GetDueSubscriptions(now)
+	if err != nil {
+		logger.Warn(fmt.Errorf("subscription renewals: %w", err)).Print()
+	}
+	for _, subscription := range subscriptions {
+		if err := u.renew(subscription); err != nil && !errors.Is(err, ErrPaymentDeclined) {
+			logger.Warn(fmt.Errorf("subscription renewal %s: %w", subscription.UUID, err)).Print()
+		}
+	}
+
+	invoices, err := u.repository.GetDueInvoices(now)
+	if err != nil {
+		logger.Warn(fmt.Errorf("subscription invoice retries: %w", err)).Print()
+	}
+	for _, invoice := range invoices {
+		if err := u.retry(invoice); err != nil && !errors.Is(err, ErrPaymentDeclined) {
+			logger.Warn(fmt.Errorf("subscription invoice retry %s: %w", invoice.UUID, err)).Print()
+		}
+	}
+
+	suspended, err := u.repository.SuspendOverdueSubscriptions(now.Add(-domain.SubscriptionGracePeriod))
+	if err != nil {
+		logger.Warn(fmt.Errorf("subscription suspension: %w", err)).Print()
+	}
+	if suspended > 0 {
+		u.notifyChange()
+	}
+}
+
+// Usage counts what the owner consumes. Plan is nil when the owner has no
+// active plan, in which case nothing more can be created.
+func (u *SubscriptionUseCase) Usage(ownerUUID string) (*domain.PlanUsage, error) {
+	usage, err := u.repository.GetPlanUsage(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan, err := u.repository.FindActivePlanByOwner(ownerUUID); err == nil {
+		usage.Plan = plan
+	}
+
+	return usage, nil
+}
+
+// renew bills the period following the one that just ended, or ends the
+// subscription when it was canceled. The renewal invoice is created once
+// per period, so a run interrupted after creating it picks it up again.
+func (u *SubscriptionUseCase) renew(subscription *domain.Subscription) error {
+	if subscription.CancelAtPeriodEnd {
+		if _, err := u.repository.CancelSubscription(subscription); err != nil {
+			return err
+		}
+
+		u.notifyChange()
+		return nil
+	}
+
+	plan, err := u.planRepository.FindPlanByUUID(subscription.PlanUUID.String())
+	if err != nil {
+		return err
+	}
+
+	start := *subscription.CurrentPeriodEnd
+	invoice, err := u.repository.CreateInvoice(newSubscriptionInvoice(subscription, plan, enums.InvoiceRenewal, subscription.Cycle, plan.CyclePrice(subscription.Cycle), start, domain.CycleEnd(start, subscription.Cycle)))
+	if err != nil {
+		return err
+	}
+
+	if invoice.Status != enums.InvoiceOpen || invoice.Attempts > 0 {
+		return nil
+	}
+
+	return u.collect(subscription, invoice)
+}
+
+func (u *SubscriptionUseCase) retry(invoice *domain.SubscriptionInvoice) error {
+	subscription, err := u.repository.FindSubscriptionByOwner(invoice.OwnerUUID.String())
+	if err != nil {
+		return err
+	}
+
+	if subscription.UUID != invoice.SubscriptionUUID {
+		return nil
+	}
+
+	return u.collect(subscription, invoice)
+}
+
+// collect charges an open invoice and applies the outcome. A failed renewal
+// leaves the subscription past due, a failed plan change is voided.
+func (u *SubscriptionUseCase) collect(subscription *domain.Subscription, invoice *domain.SubscriptionInvoice) error {
+	result := &domain.PaymentResult{Paid: true}
+	if invoice.Amount > 0 {
+		if u.provider == nil {
+			return ErrPaymentProviderMissing
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+		charged, err := u.provider.Charge(ctx, domain.PaymentCharge{
+			IdempotencyKey: fmt.Sprintf("%s-%d", invoice.UUID, invoice.Attempts+1),
+			CustomerUUID:   subscription.OwnerUUID.String(),
+			PaymentMethod:  subscription.PaymentMethod,
+			Coin:           invoice.Coin,
+			Amount:         invoice.Amount,
+			Description:    fmt.Sprintf("Verkoupe %s %s", invoice.Reason, invoice.PeriodStart.Format(time.DateOnly)),
+		})
+		cancel()
+		if err != nil {
+			charged = &domain.PaymentResult{FailureReason: err.Error()}
+		}
+
+		result = charged
+		invoice.Attempts++
+	}
+	invoice.ProviderReference = result.Reference
+
+	if result.Paid {
+		periodStart, periodEnd := invoice.PeriodStart, invoice.PeriodEnd
+		subscription.PlanUUID = invoice.PlanUUID
+		subscription.Cycle = invoice.Cycle
+		subscription.Status = enums.SubscriptionActive
+		subscription.CurrentPeriodStart = &periodStart
+		subscription.CurrentPeriodEnd = &periodEnd
+		subscription.PastDueSince = nil
+		subscription.Credit = max(subscription.Credit-invoice.CreditApplied, 0)
+
+		if err := u.repository.SettleInvoice(invoice, subscription); err != nil {
+			return err
+		}
+
+		u.notifyChange()
+		return nil
+	}
+
+	now := time.Now()
+	invoice.FailureReason = result.FailureReason
+	switch invoice.Reason {
+	case enums.InvoiceRenewal:
+		invoice.NextAttemptAt = domain.NextInvoiceAttempt(invoice.Attempts, now)
+		if subscription.Status == enums.SubscriptionTrialing || subscription.Status == enums.SubscriptionActive {
+			subscription.Status = enums.SubscriptionPastDue
+		}
+		if subscription.PastDueSince == nil {
+			subscription.PastDueSince = &now
+		}
+	case enums.InvoiceProration:
+		invoice.Status = enums.InvoiceVoid
+	}
+
+	if err := u.repository.FailInvoice(invoice, subscription); err != nil {
+		return err
+	}
+
+	return ErrPaymentDeclined
+}
+
+func (u *SubscriptionUseCase) update(subscription *domain.Subscription) (*domain.Subscription, error) {
+	subscription, err := u.repository.UpdateSubscription(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifyChange()
+	return subscription, nil
+}
+
+// newSubscriptionInvoice bills total for the period, paying what it can
+// with the subscription's credit.
+func newSubscriptionInvoice(subscription *domain.Subscription, plan *domain.VerkoupePlan, reason enums.InvoiceReasonType, cycle enums.BillingCycleType, total int, start time.Time, end time.Time) *domain.SubscriptionInvoice {
+	credit := min(subscription.Credit, total)
+
+	return &domain.SubscriptionInvoice{
+		SubscriptionUUID: subscription.UUID,
+		OwnerUUID:        subscription.OwnerUUID,
+		PlanUUID:         plan.UUID,
+		Reason:           reason,
+		Cycle:            cycle,
+		Coin:             plan.Coin,
+		Amount:           total - credit,
+		CreditApplied:    credit,
+		Status:           enums.InvoiceOpen,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+	}
+}
+
+// planQuota picks one limit of the owner's active plan. The repository
+// doing the create enforces it, so concurrent creates cannot overshoot.
+func planQuota(subscriptions contracts.SubscriptionContract, ownerUUID uuid.UUID, limit func(*domain.VerkoupePlan) int) (domain.Quota, error) {
+	plan, err := subscriptions.FindActivePlanByOwner(ownerUUID.String())
+	if err != nil {
+		return domain.Quota{}, domain.ErrNoActivePlan
+	}
+
+	return domain.Quota{OwnerUUID: ownerUUID, Limit: limit(plan)}, nil
+}
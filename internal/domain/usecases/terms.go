@@ -44,6 +44,6 @@ func (u *CreateTermsUseCase) GetByUUID(uuidStr string) (*domain.Terms, error) {
 	return u.termsRepo.FindTermsByUUID(uuidStr)
 }
 
-func (u *CreateTermsUseCase) GetAll() ([]*domain.Terms, error) {
-	return u.termsRepo.GetTerms()
+func (u *CreateTermsUseCase) GetAll(query *domain.ListQuery) (*domain.Page[*domain.Terms], error) {
+	return u.termsRepo.GetTerms(query)
 }
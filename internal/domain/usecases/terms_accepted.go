@@ -49,6 +49,6 @@ func (u *CreateTermsAcceptedUseCase) GetByUUID(uuidStr string) (*domain.TermsAcc
 	return u.termsAcceptedRepo.FindTermsAcceptedByUUID(uuidStr)
 }
 
-func (u *CreateTermsAcceptedUseCase) GetAll(websiteUUIDStr string) ([]*domain.TermsAcceptedBy, error) {
-	return u.termsAcceptedRepo.GetTermsAcceptedFromWebsite(websiteUUIDStr)
+func (u *CreateTermsAcceptedUseCase) GetAll(websiteUUIDStr string, query *domain.ListQuery) (*domain.Page[*domain.TermsAcceptedBy], error) {
+	return u.termsAcceptedRepo.GetTermsAcceptedFromWebsite(websiteUUIDStr, query)
 }
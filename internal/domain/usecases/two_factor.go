@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/ViitoJooj/verkoupe/pkg/token"
+	"github.com/ViitoJooj/verkoupe/pkg/totp"
+)
+
+var ErrTrustedDeviceNotFound = errors.New("trusted device not found")
+
+// TwoFactorUseCase enrolls users in two-factor authentication with an
+// authenticator app and manages their recovery codes and remembered
+// devices. Signing in with a code is part of AuthUseCase.
+type TwoFactorUseCase struct {
+	repository        contracts.TwoFactorContract
+	userRepository    contracts.UserContract
+	websiteRepository contracts.WebsiteContract
+	authUseCase       *AuthUseCase
+	secret            []byte
+}
+
+func NewTwoFactorUseCase(repository contracts.TwoFactorContract, userRepository contracts.UserContract, websiteRepository contracts.WebsiteContract, authUseCase *AuthUseCase, secret string) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		repository:        repository,
+		userRepository:    userRepository,
+		websiteRepository: websiteRepository,
+		authUseCase:       authUseCase,
+		secret:            []byte(secret),
+	}
+}
+
+// Status returns when the user enabled two-factor authentication, nil
+// when they did not, and how many recovery codes they have left.
+func (u *TwoFactorUseCase) Status(userUUID string) (*time.Time, int, error) {
+	tf, err := u.repository.FindTwoFactor(userUUID)
+	if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !tf.Enabled() {
+		return nil, 0, nil
+	}
+
+	left, err := u.repository.CountRecoveryCodes(userUUID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tf.EnabledAt, left, nil
+}
+
+// Enroll starts, or starts over, an enrollment with a new secret. It is
+// not enabled until Enable gets a first code from the app.
+func (u *TwoFactorUseCase) Enroll(userUUID string) (*domain.TwoFactorEnrollment, error) {
+	user, err := u.userRepository.FindUserByUUID(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := token.SealTwoFactorSecret(u.secret, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.SaveTwoFactorSecret(userUUID, sealed); err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(u.issuer(user), user.Email, secret)
+	qr, err := totp.QR(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QR:     qr,
+	}, nil
+}
+
+// Enable confirms the enrollment with a code from the app and returns the
+// recovery codes, the only time they are shown.
+func (u *TwoFactorUseCase) Enable(userUUID string, code string) ([]string, error) {
+	tf, err := openTwoFactor(u.repository, u.secret, userUUID)
+	if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.Enabled() {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrTwoFactorCode
+	}
+
+	codes, hashes, err := domain.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.EnableTwoFactor(userUUID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off with a code or a recovery
+// code, forgetting remembered devices. Wrong codes count as failed sign
+// ins.
+func (u *TwoFactorUseCase) Disable(userUUID string, code string, ip string) error {
+	tf, err := u.enabled(userUUID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authUseCase.CheckCode(userUUID, ip, func() error {
+		return useTwoFactorCode(u.repository, tf, code, time.Now())
+	}); err != nil {
+		return err
+	}
+
+	return u.repository.DisableTwoFactor(userUUID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, used or
+// not, with new ones. Wrong codes count as failed sign ins.
+func (u *TwoFactorUseCase) RegenerateRecoveryCodes(userUUID string, code string, ip string) ([]string, error) {
+	tf, err := u.enabled(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.authUseCase.CheckCode(userUUID, ip, func() error {
+		return useTwoFactorCode(u.repository, tf, code, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := domain.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.ReplaceRecoveryCodes(userUUID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (u *TwoFactorUseCase) TrustedDevices(userUUID string) ([]*domain.TrustedDevice, error) {
+	return u.repository.FindTrustedDevices(userUUID)
+}
+
+// RevokeTrustedDevice makes a remembered device ask for codes again.
+func (u *TwoFactorUseCase) RevokeTrustedDevice(userUUID string, deviceUUID string) error {
+	if err := u.repository.DeleteTrustedDevice(userUUID, deviceUUID); err != nil {
+		return ErrTrustedDeviceNotFound
+	}
+
+	return nil
+}
+
+// PruneTrustedDevices forgets devices whose remember token expired.
+func (u *TwoFactorUseCase) PruneTrustedDevices() {
+	if _, err := u.repository.PruneTrustedDevices(time.Now()); err != nil {
+		logger.Warn(fmt.Errorf("trusted devices pruning: %w", err)).Print()
+	}
+}
+
+func (u *TwoFactorUseCase) enabled(userUUID string) (*domain.TwoFactor, error) {
+	tf, err := openTwoFactor(u.repository, u.secret, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tf.Enabled() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	return tf, nil
+}
+
+// issuer names the account in the authenticator app after the website the
+// user signs in to.
+func (u *TwoFactorUseCase) issuer(user *domain.User) string {
+	website, err := u.websiteRepository.FindWebsiteByUUID(user.WebSiteUUID.String())
+	if err != nil || website.Label == "" {
+		return domain.TwoFactorIssuer
+	}
+
+	return website.Label
+}
+
+// openTwoFactor finds the enrollment of the user with its secret unsealed.
+func openTwoFactor(repository contracts.TwoFactorContract, secret []byte, userUUID string) (*domain.TwoFactor, error) {
+	tf, err := repository.FindTwoFactor(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	tf.Secret, err = token.OpenTwoFactorSecret(tf.Secret, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+// useTwoFactorCode accepts a code of the app once, or spends a recovery
+// code, failing with domain.ErrTwoFactorCode otherwise.
+func useTwoFactorCode(repository contracts.TwoFactorContract, tf *domain.TwoFactor, code string, now time.Time) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return domain.ErrTwoFactorCode
+	}
+
+	if step, ok := totp.Validate(tf.Secret, code, now); ok {
+		return repository.UseTwoFactorStep(tf.UserUUID.String(), step)
+	}
+
+	return repository.UseRecoveryCode(tf.UserUUID.String(), domain.HashRecoveryCode(code))
+}
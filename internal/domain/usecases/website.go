@@ -7,37 +7,56 @@ import (
 )
 
 type CreateWebsiteUseCase struct {
-	repository contracts.WebsiteContract
+	repository             contracts.WebsiteContract
+	subscriptionRepository contracts.SubscriptionContract
 }
 
-func NewCreateWebsiteUseCase(repository contracts.WebsiteContract) *CreateWebsiteUseCase {
-	return &CreateWebsiteUseCase{repository: repository}
+func NewCreateWebsiteUseCase(repository contracts.WebsiteContract, subscriptionRepository contracts.SubscriptionContract) *CreateWebsiteUseCase {
+	return &CreateWebsiteUseCase{repository: repository, subscriptionRepository: subscriptionRepository}
 }
 
-func (u *CreateWebsiteUseCase) Create(ownerUUID string, ownerType string, label string, url string, writeIn string, description string) (*domain.Website, error) {
-	website, err := domain.NewWebsite(ownerUUID, ownerType, label, url, writeIn, description)
+func (u *CreateWebsiteUseCase) Create(ownerUUID string, ownerType string, label string, url string, writeIn string, description string, baseCoin string) (*domain.Website, error) {
+	website, err := domain.NewWebsite(ownerUUID, ownerType, label, url, writeIn, description, baseCoin)
 	if err != nil {
 		return nil, err
 	}
-	return u.repository.CreateWebsite(website)
+
+	quota, err := planQuota(u.subscriptionRepository, website.OwnerUUID, func(p *domain.VerkoupePlan) int { return p.MaxWebsites })
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.CreateWebsite(website, quota)
 }
 
 func (u *CreateWebsiteUseCase) GetByUUID(uuidStr string) (*domain.Website, error) {
 	return u.repository.FindWebsiteByUUID(uuidStr)
 }
 
-func (u *CreateWebsiteUseCase) ListByOwner(userUUID string) ([]*domain.Website, error) {
-	return u.repository.FindWebsitesByOwner(userUUID)
+func (u *CreateWebsiteUseCase) ListByOwner(userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Website], error) {
+	return u.repository.FindWebsitesByUser(userUUID, query)
 }
 
-func (u *CreateWebsiteUseCase) ListAll() ([]*domain.Website, error) {
-	return u.repository.GetWebsites()
+func (u *CreateWebsiteUseCase) ListAll(query *domain.ListQuery) (*domain.Page[*domain.Website], error) {
+	return u.repository.GetWebsites(query)
 }
 
 func (u *CreateWebsiteUseCase) Update(uuidStr string) error {
 	return u.repository.UpdateWebsiteByUUID(uuidStr)
 }
 
+// SetEmailVerification turns on or off blocking sign in to the website
+// until users verify their email.
+func (u *CreateWebsiteUseCase) SetEmailVerification(uuidStr string, required bool) error {
+	return u.repository.SetWebsiteEmailVerification(uuidStr, required)
+}
+
+// SetTwoFactor turns on or off requiring two-factor authentication from
+// the roles that may write or delete on the website.
+func (u *CreateWebsiteUseCase) SetTwoFactor(uuidStr string, required bool) error {
+	return u.repository.SetWebsiteTwoFactor(uuidStr, required)
+}
+
 func (u *CreateWebsiteUseCase) Delete(uuidStr string) error {
 	return u.repository.DeleteWebsiteByUUID(uuidStr)
 }
@@ -9,11 +9,13 @@ import (
 )
 
 type CreateWebsiteComponentUseCase struct {
-	repository contracts.WebsiteComponentContract
+	repository             contracts.WebsiteComponentContract
+	websiteRepository      contracts.WebsiteContract
+	subscriptionRepository contracts.SubscriptionContract
 }
 
-func NewCreateWebsiteComponentUseCase(repository contracts.WebsiteComponentContract) *CreateWebsiteComponentUseCase {
-	return &CreateWebsiteComponentUseCase{repository: repository}
+func NewCreateWebsiteComponentUseCase(repository contracts.WebsiteComponentContract, websiteRepository contracts.WebsiteContract, subscriptionRepository contracts.SubscriptionContract) *CreateWebsiteComponentUseCase {
+	return &CreateWebsiteComponentUseCase{repository: repository, websiteRepository: websiteRepository, subscriptionRepository: subscriptionRepository}
 }
 
 func (u *CreateWebsiteComponentUseCase) Create(websiteUUID string, logoURL string, tittle string, description string, path string, content json.RawMessage, visits int, tenantWebsiteUUID uuid.UUID) (*domain.ComponentWebsites, error) {
@@ -21,5 +23,16 @@ func (u *CreateWebsiteComponentUseCase) Create(websiteUUID string, logoURL strin
 	if err != nil {
 		return nil, err
 	}
-	return u.repository.CreateWebsiteComponent(component)
+
+	website, err := u.websiteRepository.FindWebsiteByUUID(component.WebsiteUUID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err := planQuota(u.subscriptionRepository, website.OwnerUUID, func(p *domain.VerkoupePlan) int { return p.MaxRouters })
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repository.CreateWebsiteComponent(component, quota)
 }
@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+var (
+	ErrWishlistNotFound = errors.New("wishlist not found")
+	ErrWishlistLimit    = errors.New("wishlist limit reached")
+)
+
+type WishlistUseCase struct {
+	repository        contracts.WishlistContract
+	productRepository contracts.ProductContract
+	variantRepository contracts.ProductVariantContract
+	baseURL           string
+
+	mu             sync.RWMutex
+	alertListeners []func(*domain.WishlistAlert)
+}
+
+func NewWishlistUseCase(repository contracts.WishlistContract, productRepository contracts.ProductContract, variantRepository contracts.ProductVariantContract, baseURL string) *WishlistUseCase {
+	return &WishlistUseCase{
+		repository:        repository,
+		productRepository: productRepository,
+		variantRepository: variantRepository,
+		baseURL:           strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (u *WishlistUseCase) Create(userUUID string, websiteUUID string, name string) (*domain.Wishlist, error) {
+	wishlist, err := domain.NewWishlist(userUUID, websiteUUID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := u.repository.CountWishlistsByUser(userUUID, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxWishlistsPerUser {
+		return nil, ErrWishlistLimit
+	}
+
+	return u.repository.CreateWishlist(wishlist)
+}
+
+func (u *WishlistUseCase) ListByUser(userUUID string, websiteUUID string) ([]*domain.Wishlist, error) {
+	return u.repository.GetWishlistsByUser(userUUID, websiteUUID)
+}
+
+// Get returns the wishlist only to its owner; to anyone else it does not
+// exist.
+func (u *WishlistUseCase) Get(uuidStr string, userUUID string) (*domain.Wishlist, error) {
+	wishlist, err := u.repository.FindWishlistByUUID(uuidStr)
+	if err != nil {
+		return nil, ErrWishlistNotFound
+	}
+
+	if wishlist.UserUUID.String() != userUUID {
+		return nil, ErrWishlistNotFound
+	}
+
+	return wishlist, nil
+}
+
+func (u *WishlistUseCase) Rename(uuidStr string, userUUID string, name string) (*domain.Wishlist, error) {
+	wishlist, err := u.Get(uuidStr, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wishlist.Rename(name); err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.UpdateWishlist(wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+func (u *WishlistUseCase) Delete(uuidStr string, userUUID string) error {
+	if _, err := u.Get(uuidStr, userUUID); err != nil {
+		return err
+	}
+
+	return u.repository.DeleteWishlist(uuidStr)
+}
+
+// Share gives the wishlist a share token. Sharing an already shared list
+// keeps its token so links handed out earlier keep working.
+func (u *WishlistUseCase) Share(uuidStr string, userUUID string) (*domain.Wishlist, error) {
+	wishlist, err := u.Get(uuidStr, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wishlist.Shared() {
+		return wishlist, nil
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	wishlist.ShareToken = token
+
+	if err := u.repository.UpdateWishlist(wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// Unshare drops the token, which invalidates every link to the list.
+func (u *WishlistUseCase) Unshare(uuidStr string, userUUID string) (*domain.Wishlist, error) {
+	wishlist, err := u.Get(uuidStr, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist.ShareToken = ""
+	if err := u.repository.UpdateWishlist(wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// ShareURL is the public address of a shared wishlist, or "" when the list
+// is private.
+func (u *WishlistUseCase) ShareURL(wishlist *domain.Wishlist) string {
+	if !wishlist.Shared() {
+		return ""
+	}
+
+	return u.baseURL + "/shared/wishlists/" + wishlist.ShareToken
+}
+
+func (u *WishlistUseCase) ListItems(uuidStr string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistItem], error) {
+	if _, err := u.Get(uuidStr, userUUID); err != nil {
+		return nil, err
+	}
+
+	return u.repository.GetWishlistItems(uuidStr, query)
+}
+
+// ListSharedItems reads a list through its share link.
+func (u *WishlistUseCase) ListSharedItems(token string, query *domain.ListQuery) (*domain.Wishlist, *domain.Page[*domain.WishlistItem], error) {
+	if token == "" {
+		return nil, nil, ErrWishlistNotFound
+	}
+
+	wishlist, err := u.repository.FindWishlistByShareToken(token)
+	if err != nil {
+		return nil, nil, ErrWishlistNotFound
+	}
+
+	page, err := u.repository.GetWishlistItems(wishlist.UUID.String(), query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return wishlist, page, nil
+}
+
+func (u *WishlistUseCase) AddItem(uuidStr string, userUUID string, productUUID string, variantUUID string) (*domain.WishlistItem, error) {
+	wishlist, err := u.Get(uuidStr, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := domain.NewWishlistItem(wishlist.UUID, productUUID, variantUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.productRepository.FindProductByUUID(productUUID); err != nil {
+		return nil, err
+	}
+
+	if item.VariantUUID != nil {
+		variant, err := u.variantRepository.FindProductVariantByUUID(variantUUID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductUUID != item.ProductUUID {
+			return nil, errors.New("variant does not belong to product")
+		}
+	}
+
+	return u.repository.AddWishlistItem(item)
+}
+
+func (u *WishlistUseCase) RemoveItem(uuidStr string, userUUID string, itemUUID string) error {
+	if _, err := u.Get(uuidStr, userUUID); err != nil {
+		return err
+	}
+
+	return u.repository.RemoveWishlistItem(uuidStr, itemUUID)
+}
+
+func (u *WishlistUseCase) ListAlerts(userUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistAlert], error) {
+	return u.repository.GetWishlistAlertsByUser(userUUID, websiteUUID, query)
+}
+
+// OnAlert registers a hook called for every alert raised by DetectAlerts,
+// e.g. to send an email or a push notification.
+func (u *WishlistUseCase) OnAlert(listener func(*domain.WishlistAlert)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.alertListeners = append(u.alertListeners, listener)
+}
+
+// DetectAlerts is meant to be handed to the scheduler. Alerts are stored
+// before the hooks run, so a failing hook loses the notification but not
+// the record the shopper sees in their alert list.
+func (u *WishlistUseCase) DetectAlerts() {
+	alerts, err := u.repository.DetectWishlistAlerts(context.Background())
+	if err != nil {
+		logger.Warn(err).Print()
+		return
+	}
+
+	u.mu.RLock()
+	listeners := u.alertListeners
+	u.mu.RUnlock()
+
+	for _, alert := range alerts {
+		for _, listener := range listeners {
+			u.notifyAlert(listener, alert)
+		}
+	}
+}
+
+func (u *WishlistUseCase) notifyAlert(listener func(*domain.WishlistAlert), alert *domain.WishlistAlert) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Warn(fmt.Errorf("wishlist alert %s hook panicked: %v", alert.UUID, r)).Print()
+		}
+	}()
+
+	listener(alert)
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
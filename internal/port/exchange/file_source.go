@@ -0,0 +1,79 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+var _ contracts.ExchangeRateSourceContract = (*FileSource)(nil)
+
+// FileSource reads rates from a JSON file shaped like
+// {"base": "USD", "rates": {"BRL": "5.42", "EUR": "0.92"}}. The file is read
+// on every fetch so operators can edit it without restarting the daemon.
+type FileSource struct {
+	path string
+}
+
+type fileRates struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{
+		path: path,
+	}
+}
+
+func (s *FileSource) Name() string {
+	return "file"
+}
+
+func (s *FileSource) FetchRates(baseCoin enums.CoinType, rateDate time.Time) (map[enums.CoinType]*big.Rat, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file fileRates
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	if file.Base == "" {
+		return nil, errors.New("exchange rates file without base")
+	}
+
+	parsed := map[enums.CoinType]*big.Rat{
+		enums.CoinType(file.Base): big.NewRat(1, 1),
+	}
+	for coin, value := range file.Rates {
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s", coin)
+		}
+		parsed[enums.CoinType(coin)] = rate
+	}
+
+	baseRate, ok := parsed[baseCoin]
+	if !ok {
+		return nil, fmt.Errorf("no rate for %s", baseCoin)
+	}
+
+	rates := make(map[enums.CoinType]*big.Rat, len(parsed))
+	for coin, rate := range parsed {
+		if coin == baseCoin {
+			continue
+		}
+		rates[coin] = new(big.Rat).Quo(rate, baseRate)
+	}
+
+	return rates, nil
+}
@@ -95,19 +95,19 @@ func (c *AddressController) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addresses, err := c.createUseCase.GetAll(websiteUUIDStr)
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.AddressResponse, 0, len(addresses))
-	for _, address := range addresses {
-		resp = append(resp, c.toResponse(address))
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *AddressController) toResponse(address *domain.AddressBR) dtos.AddressResponse {
@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 
 	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
@@ -76,12 +79,168 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := c.authUseCase.Login(req.Email, req.Password, websiteUUIDStr)
+	result, err := c.authUseCase.Login(req.Email, req.Password, websiteUUIDStr, clientIP(r), req.DeviceToken)
 	if err != nil {
-		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-001", "invalid credentials"))
+		writeLoginError(w, err)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// VerifyTwoFactor finishes a sign in answered with a two-factor challenge.
+func (c *AuthController) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req dtos.VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	result, err := c.authUseCase.VerifyTwoFactor(req.ChallengeToken, req.Code, clientIP(r), req.RememberDevice, req.DeviceName)
+	switch {
+	case errors.Is(err, domain.ErrUserTokenInvalid):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RBX-009", "invalid token"))
+		return
+	case errors.Is(err, domain.ErrUserTokenExpired):
+		writeJSON(w, http.StatusGone, errorResponse("RBX-010", "token expired"))
+		return
+	case errors.Is(err, domain.ErrTwoFactorCode):
+		writeJSON(w, http.StatusUnauthorized, errorResponse("R21-004", "invalid two-factor code"))
+		return
+	case err != nil:
+		writeLoginError(w, err)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+func (c *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req dtos.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if err := c.authUseCase.VerifyEmail(req.Token); err != nil {
+		writeAuthError(w, err)
 		return
 	}
 
+	writeJSON(w, http.StatusOK, map[string]string{"status": "verified"})
+}
+
+// ResendVerification answers accepted whether or not the email is
+// registered, verified or throttled.
+func (c *AuthController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if err := c.authUseCase.ResendVerification(req.Email, websiteUUID); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "sent"})
+}
+
+func (c *AuthController) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	var req dtos.UnlockAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	err := c.authUseCase.UnlockAccount(req.Token)
+	switch {
+	case errors.Is(err, domain.ErrUserTokenInvalid):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RBX-009", "invalid token"))
+		return
+	case errors.Is(err, domain.ErrUserTokenExpired):
+		writeJSON(w, http.StatusGone, errorResponse("RBX-010", "token expired"))
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "unlocked"})
+}
+
+// ForgotPassword answers accepted whether or not the email is registered.
+func (c *AuthController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if err := c.authUseCase.RequestPasswordReset(req.Email, websiteUUID); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "sent"})
+}
+
+func (c *AuthController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	err := c.authUseCase.ResetPassword(req.Token, websiteUUID, req.Password)
+	switch {
+	case errors.Is(err, domain.ErrUserTokenInvalid):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RBX-009", "invalid token"))
+		return
+	case errors.Is(err, domain.ErrUserTokenExpired):
+		writeJSON(w, http.StatusGone, errorResponse("RBX-010", "token expired"))
+		return
+	case errors.Is(err, domain.ErrPasswordTooWeak):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RBX-004", "password too weak"))
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reset"})
+}
+
+// writeLoginResult answers a sign in with an access token, or with the
+// challenge to send a two-factor code to.
+func writeLoginResult(w http.ResponseWriter, result *domain.LoginResult) {
+	if result.Challenge != "" {
+		writeJSON(w, http.StatusOK, dtos.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.Challenge,
+			ExpiresAt:         result.ChallengeExpiresAt.String(),
+		})
+		return
+	}
+
+	user := result.User
+
 	pasetoSecret := os.Getenv("PASETO_SECRET_KEY")
 	if pasetoSecret == "" {
 		pasetoSecret = "dev-secret-key-change-in-production-32b"
@@ -106,7 +265,43 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		WebsiteUUID: user.WebSiteUUID.String(),
 		Name:        user.Name,
 		Email:       user.Email,
+		DeviceToken: result.DeviceToken,
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
+
+func writeLoginError(w http.ResponseWriter, err error) {
+	var throttle *domain.LoginThrottleError
+	if errors.As(err, &throttle) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttle.RetryAfter.Seconds()))))
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		writeJSON(w, http.StatusForbidden, errorResponse("RBX-006", "email not verified"))
+	case errors.Is(err, domain.ErrAccountLocked):
+		writeJSON(w, http.StatusLocked, errorResponse("RBX-016", "account locked"))
+	case errors.Is(err, domain.ErrTooManyLoginAttempts):
+		writeJSON(w, http.StatusTooManyRequests, errorResponse("RBX-017", "too many login attempts"))
+	case errors.Is(err, domain.ErrInvalidCredentials), errors.Is(err, domain.ErrTwoFactorCode), errors.Is(err, domain.ErrUserTokenInvalid), errors.Is(err, domain.ErrUserTokenExpired):
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-001", "invalid credentials"))
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		writeJSON(w, http.StatusForbidden, errorResponse("RBX-006", "email not verified"))
+	case errors.Is(err, domain.ErrUserTokenInvalid):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RBX-007", "invalid verification code"))
+	case errors.Is(err, domain.ErrUserTokenExpired):
+		writeJSON(w, http.StatusGone, errorResponse("RBX-008", "verification code expired"))
+	case errors.Is(err, usecases.ErrMailerMissing):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse("RAX-009", "feature unavailable"))
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+	}
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func TestWriteLoginError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{name: "invalid credentials", err: domain.ErrInvalidCredentials, wantCode: http.StatusUnauthorized, wantBody: "RBX-001"},
+		{name: "wrong two-factor code", err: domain.ErrTwoFactorCode, wantCode: http.StatusUnauthorized, wantBody: "RBX-001"},
+		{name: "account locked", err: fmt.Errorf("wait: %w", domain.ErrAccountLocked), wantCode: http.StatusLocked, wantBody: "RBX-016"},
+		{name: "database down", err: errors.New("connection refused"), wantCode: http.StatusInternalServerError, wantBody: "RAX-001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			writeLoginError(w, tt.err)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Fatalf("body leaks the error: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+type CartController struct {
+	cartUseCase *usecases.CartUseCase
+	guard       *WebsiteGuard
+}
+
+func NewCartController(cartUseCase *usecases.CartUseCase, guard *WebsiteGuard) *CartController {
+	return &CartController{
+		cartUseCase: cartUseCase,
+		guard:       guard,
+	}
+}
+
+// Get returns the shopper's cart priced in the coin query parameter, the
+// website's base currency by default.
+func (c *CartController) Get(w http.ResponseWriter, r *http.Request) {
+	userUUID, websiteUUID, ok := c.requireShopper(w, r)
+	if !ok {
+		return
+	}
+
+	cart, err := c.cartUseCase.Get(websiteUUID, userUUID)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	c.writeCart(w, http.StatusOK, cart, r.URL.Query().Get("coin"))
+}
+
+func (c *CartController) AddItem(w http.ResponseWriter, r *http.Request) {
+	userUUID, websiteUUID, ok := c.requireShopper(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.AddCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	cart, err := c.cartUseCase.AddItem(websiteUUID, userUUID, req.ProductUUID, req.VariantUUID, req.Quantity)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	c.writeCart(w, http.StatusCreated, cart, r.URL.Query().Get("coin"))
+}
+
+func (c *CartController) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	userUUID, websiteUUID, ok := c.requireShopper(w, r)
+	if !ok {
+		return
+	}
+
+	itemUUID := r.PathValue("uuid")
+	if itemUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	var req dtos.UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	cart, err := c.cartUseCase.UpdateItem(websiteUUID, userUUID, itemUUID, req.Quantity)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	c.writeCart(w, http.StatusOK, cart, r.URL.Query().Get("coin"))
+}
+
+func (c *CartController) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userUUID, websiteUUID, ok := c.requireShopper(w, r)
+	if !ok {
+		return
+	}
+
+	itemUUID := r.PathValue("uuid")
+	if itemUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	cart, err := c.cartUseCase.RemoveItem(websiteUUID, userUUID, itemUUID)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	c.writeCart(w, http.StatusOK, cart, r.URL.Query().Get("coin"))
+}
+
+// Restore opens a cart through the link of a recovery reminder. The token
+// is the only credential, so this route is registered without the auth
+// middleware.
+func (c *CartController) Restore(w http.ResponseWriter, r *http.Request) {
+	cart, err := c.cartUseCase.Restore(r.PathValue("token"))
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	c.writeCart(w, http.StatusOK, cart, "")
+}
+
+func (c *CartController) GetRecoverySettings(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	settings, err := c.cartUseCase.GetRecoverySettings(websiteUUID)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cartRecoverySettingsToResponse(settings))
+}
+
+func (c *CartController) SaveRecoverySettings(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.SaveCartRecoverySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	settings, err := c.cartUseCase.SaveRecoverySettings(websiteUUID, req.Active, req.IdleMinutes, req.Reminders, req.IntervalHours, req.CupomValue, req.CupomValueType, req.CupomValidDays)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cartRecoverySettingsToResponse(settings))
+}
+
+// RecoveryReport sums the revenue recovered during the month query
+// parameter, written YYYY-MM.
+func (c *CartController) RecoveryReport(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	month := r.URL.Query().Get("month")
+	totals, err := c.cartUseCase.RecoveryReport(websiteUUID, month)
+	if err != nil {
+		writeCartError(w, err)
+		return
+	}
+
+	response := dtos.CartRecoveryReportResponse{
+		Month:  month,
+		Totals: make([]dtos.CartRecoveryTotalResponse, 0, len(totals)),
+	}
+	for _, total := range totals {
+		response.Totals = append(response.Totals, dtos.CartRecoveryTotalResponse{
+			Coin:    string(total.Coin),
+			Orders:  total.Orders,
+			Revenue: total.Revenue,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (c *CartController) requireShopper(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return "", "", false
+	}
+
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return "", "", false
+	}
+
+	return userUUID, websiteUUID, true
+}
+
+// writeCart answers with the cart priced in coin. A cart that cannot be
+// priced any more is still returned, flagged unavailable, so the shopper
+// can fix it.
+func (c *CartController) writeCart(w http.ResponseWriter, status int, cart *domain.Cart, coin string) {
+	quote, err := c.cartUseCase.Quote(cart, coin)
+	if err != nil && !errors.Is(err, domain.ErrCartEmpty) && !errors.Is(err, domain.ErrCartItemUnavailable) {
+		logger.Warn(err).Print()
+	}
+
+	response := dtos.CartResponse{
+		UUID:        cart.UUID.String(),
+		WebsiteUUID: cart.WebsiteUUID.String(),
+		Status:      string(cart.Status),
+		Available:   err == nil || errors.Is(err, domain.ErrCartEmpty),
+		Items:       make([]dtos.CartItemResponse, 0, len(cart.Items)),
+		CreatedAt:   cart.CreatedAt.String(),
+	}
+	if cart.UpdatedAt != nil {
+		response.UpdatedAt = cart.UpdatedAt.String()
+	}
+
+	lines := make(map[*domain.CartItem]*domain.CartLine, len(cart.Items))
+	if quote != nil {
+		response.Coin = string(quote.Coin)
+		response.Subtotal = quote.Subtotal
+		for _, line := range quote.Lines {
+			lines[line.Item] = line
+		}
+	}
+
+	for _, item := range cart.Items {
+		itemResponse := dtos.CartItemResponse{
+			UUID:        item.UUID.String(),
+			ProductUUID: item.ProductUUID.String(),
+			Quantity:    item.Quantity,
+			CreatedAt:   item.CreatedAt.String(),
+		}
+		if item.VariantUUID != nil {
+			itemResponse.VariantUUID = item.VariantUUID.String()
+		}
+		if line := lines[item]; line != nil {
+			itemResponse.Name = line.Product.Name
+			itemResponse.UnitAmount = line.UnitAmount
+			itemResponse.Amount = line.Amount
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	writeJSON(w, status, response)
+}
+
+func writeCartError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrCartNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R22-001", "cart not found"))
+	case errors.Is(err, usecases.ErrCartItemNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R22-002", "cart item not found"))
+	case errors.Is(err, domain.ErrCartEmpty):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-003", "cart is empty"))
+	case errors.Is(err, domain.ErrCartFull):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-004", "cart item limit reached"))
+	case errors.Is(err, domain.ErrCartItemUnavailable):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-005", "cart item is no longer available"))
+	case errors.Is(err, usecases.ErrCartRestoreLinkInvalid):
+		writeJSON(w, http.StatusNotFound, errorResponse("R22-006", "invalid cart restore link"))
+	case errors.Is(err, usecases.ErrCartRestoreLinkExpired):
+		writeJSON(w, http.StatusGone, errorResponse("R22-007", "cart restore link expired"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func cartRecoverySettingsToResponse(settings *domain.CartRecoverySettings) dtos.CartRecoverySettingsResponse {
+	updatedAt := ""
+	if settings.UpdatedAt != nil {
+		updatedAt = settings.UpdatedAt.String()
+	}
+
+	return dtos.CartRecoverySettingsResponse{
+		WebsiteUUID:    settings.WebsiteUUID.String(),
+		Active:         settings.Active,
+		IdleMinutes:    settings.IdleMinutes,
+		Reminders:      settings.Reminders,
+		IntervalHours:  settings.IntervalHours,
+		CupomValue:     settings.CupomValue,
+		CupomValueType: string(settings.CupomValueType),
+		CupomValidDays: settings.CupomValidDays,
+		UpdatedAt:      updatedAt,
+	}
+}
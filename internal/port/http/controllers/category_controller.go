@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type CategoryController struct {
+	createUseCase *usecases.CreateCategoryUseCase
+	guard         *WebsiteGuard
+}
+
+func NewCategoryController(createUseCase *usecases.CreateCategoryUseCase, guard *WebsiteGuard) *CategoryController {
+	return &CategoryController{
+		createUseCase: createUseCase,
+		guard:         guard,
+	}
+}
+
+func (c *CategoryController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	category, err := c.createUseCase.Create(websiteUUIDStr, req.ParentUUID, req.Name, req.Slug, req.Position, req.SEOTitle, req.SEODescription, req.Active)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, categoryToResponse(category))
+}
+
+func (c *CategoryController) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := c.createUseCase.GetByUUID(r.PathValue("uuid"), websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "category not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, categoryToResponse(category))
+}
+
+func (c *CategoryController) GetTree(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := c.createUseCase.GetTree(websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, categoryTreeToResponse(tree))
+}
+
+func (c *CategoryController) Move(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.MoveCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	category, err := c.createUseCase.Move(r.PathValue("uuid"), req.ParentUUID, req.Position, websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RAX-006", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, categoryToResponse(category))
+}
+
+func (c *CategoryController) Delete(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canDelete)
+	if !ok {
+		return
+	}
+
+	if err := c.createUseCase.Delete(r.PathValue("uuid"), websiteUUIDStr); err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RAX-006", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (c *CategoryController) AssignProduct(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.AssignProductCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	productCategory, err := c.createUseCase.AssignProduct(r.PathValue("uuid"), req.ProductUUID, req.Position, websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, dtos.ProductCategoryResponse{
+		ProductUUID:  productCategory.ProductUUID.String(),
+		CategoryUUID: productCategory.CategoryUUID.String(),
+		Position:     productCategory.Position,
+	})
+}
+
+func (c *CategoryController) RemoveProduct(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	if err := c.createUseCase.RemoveProduct(r.PathValue("uuid"), r.PathValue("product_uuid"), websiteUUIDStr); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (c *CategoryController) ListProducts(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	withDescendants := r.URL.Query().Get("descendants") != "false"
+
+	query, err := listQueryFromRequest(r, "descendants")
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.ListProducts(r.PathValue("uuid"), withDescendants, websiteUUIDStr, query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			writeListError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, func(p *domain.CategoryProduct) dtos.ProductResponse {
+		return productToResponse(p.Products)
+	}))
+}
+
+func categoryToResponse(category *domain.Category) dtos.CategoryResponse {
+	resp := dtos.CategoryResponse{
+		UUID:           category.UUID.String(),
+		WebsiteUUID:    category.WebsiteUUID.String(),
+		Depth:          category.Depth,
+		Name:           category.Name,
+		Slug:           category.Slug,
+		Position:       category.Position,
+		SEOTitle:       category.SEOTitle,
+		SEODescription: category.SEODescription,
+		Active:         category.Active,
+		CreatedAt:      category.CreatedAt.String(),
+	}
+
+	if category.ParentUUID != nil {
+		resp.ParentUUID = category.ParentUUID.String()
+	}
+
+	return resp
+}
+
+func categoryTreeToResponse(nodes []*domain.CategoryNode) []dtos.CategoryResponse {
+	responses := make([]dtos.CategoryResponse, 0, len(nodes))
+	for _, node := range nodes {
+		resp := categoryToResponse(node.Category)
+		resp.Children = categoryTreeToResponse(node.Children)
+		responses = append(responses, resp)
+	}
+	return responses
+}
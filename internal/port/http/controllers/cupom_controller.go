@@ -2,38 +2,57 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
 	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
 )
 
 type CupomController struct {
 	createUseCase *usecases.CreateCupomUseCase
+	guard         *WebsiteGuard
 }
 
-func NewCupomController(createUseCase *usecases.CreateCupomUseCase) *CupomController {
+func NewCupomController(createUseCase *usecases.CreateCupomUseCase, guard *WebsiteGuard) *CupomController {
 	return &CupomController{
 		createUseCase: createUseCase,
+		guard:         guard,
 	}
 }
 
 func (c *CupomController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
 	var req dtos.CreateCupomRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	cupom, err := c.createUseCase.Create(req.TagUUID, req.Label, req.Description, req.Value, req.ValueType)
+	cupom, err := c.createUseCase.Create(websiteUUIDStr, req.TagUUID, req.Coin, req.Label, req.Description, req.Value, req.ValueType)
 	if err != nil {
+		if errors.Is(err, usecases.ErrProductTagNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusConflict)
 		return
 	}
 
+	tagUUID := ""
+	if cupom.TagUUID != nil {
+		tagUUID = cupom.TagUUID.String()
+	}
+
 	resp := dtos.CupomResponse{
 		UUID:        cupom.UUID.String(),
-		TagUUID:     cupom.TagUUID.String(),
+		WebsiteUUID: cupom.WebsiteUUID.String(),
+		TagUUID:     tagUUID,
+		Coin:        string(cupom.Coin),
 		Label:       cupom.Label,
 		Description: cupom.Description,
 		Value:       cupom.Value,
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+const rateDateLayout = "2006-01-02"
+
+type CurrencyController struct {
+	currencyUseCase *usecases.CurrencyUseCase
+	guard           *WebsiteGuard
+	admins          PlatformAdmins
+}
+
+func NewCurrencyController(currencyUseCase *usecases.CurrencyUseCase, guard *WebsiteGuard, admins PlatformAdmins) *CurrencyController {
+	return &CurrencyController{
+		currencyUseCase: currencyUseCase,
+		guard:           guard,
+		admins:          admins,
+	}
+}
+
+// SetRate records a rate every website converts with, so only platform
+// admins may set one.
+func (c *CurrencyController) SetRate(w http.ResponseWriter, r *http.Request) {
+	if !c.admins.allow(w, r) {
+		return
+	}
+
+	var req dtos.SetExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	rateDate := time.Now().UTC()
+	if req.RateDate != "" {
+		parsed, err := time.Parse(rateDateLayout, req.RateDate)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-006", "rate_date must be YYYY-MM-DD"))
+			return
+		}
+		rateDate = parsed
+	}
+
+	rate, err := c.currencyUseCase.SetRate(req.BaseCoin, req.QuoteCoin, req.Rate, rateDate)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, exchangeRateToResponse(rate))
+}
+
+func (c *CurrencyController) ListRates(w http.ResponseWriter, r *http.Request) {
+	rateDate := time.Now().UTC()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse(rateDateLayout, dateStr)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-006", "date must be YYYY-MM-DD"))
+			return
+		}
+		rateDate = parsed
+	}
+
+	query, err := listQueryFromRequest(r, "date")
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.currencyUseCase.ListRates(rateDate, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, exchangeRateToResponse))
+}
+
+func (c *CurrencyController) SyncRates(w http.ResponseWriter, r *http.Request) {
+	if !c.admins.allow(w, r) {
+		return
+	}
+
+	if err := c.currencyUseCase.SyncRates(time.Now().UTC()); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse("RAX-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "synced"})
+}
+
+func (c *CurrencyController) SetRounding(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.SetCurrencyRoundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	rounding, err := c.currencyUseCase.SetRounding(websiteUUIDStr, req.Coin, req.Mode, req.Increment, req.Ending)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, currencyRoundingToResponse(rounding))
+}
+
+func (c *CurrencyController) ListRoundings(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.currencyUseCase.ListRoundings(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, currencyRoundingToResponse))
+}
+
+func (c *CurrencyController) Convert(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	amount, err := strconv.Atoi(query.Get("amount"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-006", "amount must be an integer in cents"))
+		return
+	}
+
+	from := enums.CoinType(query.Get("from"))
+	to := enums.CoinType(query.Get("to"))
+
+	converted, err := c.currencyUseCase.Convert(websiteUUIDStr, amount, from, to)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.ConvertCurrencyResponse{
+		From:            string(from),
+		To:              string(to),
+		Amount:          amount,
+		ConvertedAmount: converted,
+	})
+}
+
+func exchangeRateToResponse(rate *domain.ExchangeRate) dtos.ExchangeRateResponse {
+	return dtos.ExchangeRateResponse{
+		UUID:      rate.UUID.String(),
+		BaseCoin:  string(rate.BaseCoin),
+		QuoteCoin: string(rate.QuoteCoin),
+		Rate:      rate.Rate.FloatString(10),
+		RateDate:  rate.RateDate.Format(rateDateLayout),
+		Source:    rate.Source,
+		CreatedAt: rate.CreatedAt.String(),
+	}
+}
+
+func currencyRoundingToResponse(rounding *domain.CurrencyRounding) dtos.CurrencyRoundingResponse {
+	return dtos.CurrencyRoundingResponse{
+		UUID:        rounding.UUID.String(),
+		WebsiteUUID: rounding.WebsiteUUID.String(),
+		Coin:        string(rounding.Coin),
+		Mode:        string(rounding.Mode),
+		Increment:   rounding.Increment,
+		Ending:      rounding.Ending,
+		CreatedAt:   rounding.CreatedAt.String(),
+	}
+}
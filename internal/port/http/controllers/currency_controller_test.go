@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/google/uuid"
+)
+
+// fakeWebsites serves the websites of a map; the guard needs nothing else.
+type fakeWebsites struct {
+	contracts.WebsiteContract
+	websites map[string]*domain.Website
+}
+
+func (f *fakeWebsites) FindWebsiteByUUID(uuid string) (*domain.Website, error) {
+	website, ok := f.websites[uuid]
+	if !ok {
+		return nil, errors.New("website not found")
+	}
+	return website, nil
+}
+
+func TestCurrencyControllerForbidden(t *testing.T) {
+	owner := uuid.New()
+	stranger := uuid.New()
+	admin := uuid.New()
+	website := &domain.Website{UUID: uuid.New(), OwnerUUID: owner, OwnerType: enums.UserOwner}
+
+	members := usecases.NewOrganizationMemberUseCase(nil, nil, nil, nil, &fakeWebsites{websites: map[string]*domain.Website{website.UUID.String(): website}}, nil, nil, "")
+	controller := NewCurrencyController(nil, NewWebsiteGuard(members, nil), NewPlatformAdmins([]string{admin.String()}))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		body    string
+		user    uuid.UUID
+		code    string
+	}{
+		{name: "set rate by a website owner", handler: controller.SetRate, method: http.MethodPost, body: `{"base_coin":"USD","quote_coin":"BRL","rate":"5"}`, user: owner, code: "R10-003"},
+		{name: "sync rates by a website owner", handler: controller.SyncRates, method: http.MethodPost, user: owner, code: "R10-003"},
+		{name: "set rounding by a non-owner", handler: controller.SetRounding, method: http.MethodPut, body: `{"coin":"BRL","mode":"up","increment":1}`, user: stranger, code: "R10-001"},
+		{name: "set rounding by a platform admin", handler: controller.SetRounding, method: http.MethodPut, body: `{"coin":"BRL","mode":"up","increment":1}`, user: admin, code: "R10-001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+			r.Header.Set("X-Website-UUID", website.UUID.String())
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserUUIDKey, tt.user.String()))
+			w := httptest.NewRecorder()
+
+			tt.handler(w, r)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+			if !strings.Contains(w.Body.String(), tt.code) {
+				t.Fatalf("body = %s, want %s", w.Body.String(), tt.code)
+			}
+		})
+	}
+}
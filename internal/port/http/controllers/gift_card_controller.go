@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type GiftCardController struct {
+	giftCardUseCase *usecases.GiftCardUseCase
+	guard           *WebsiteGuard
+}
+
+func NewGiftCardController(giftCardUseCase *usecases.GiftCardUseCase, guard *WebsiteGuard) *GiftCardController {
+	return &GiftCardController{
+		giftCardUseCase: giftCardUseCase,
+		guard:           guard,
+	}
+}
+
+// Create issues a card by hand. The response is the only place the full
+// code ever appears.
+func (c *GiftCardController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateGiftCardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	card, code, err := c.giftCardUseCase.IssueManual(websiteUUIDStr, req.Coin, req.Amount, req.ExpiresAt, req.RecipientEmail, req.Note)
+	if err != nil {
+		writeGiftCardError(w, err)
+		return
+	}
+
+	response := giftCardToResponse(card)
+	response.Code = code
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (c *GiftCardController) List(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.giftCardUseCase.List(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, giftCardToResponse))
+}
+
+func (c *GiftCardController) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	card, err := c.giftCardUseCase.Get(r.PathValue("uuid"), websiteUUIDStr)
+	if err != nil {
+		writeGiftCardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, giftCardToResponse(card))
+}
+
+func (c *GiftCardController) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.giftCardUseCase.Transactions(r.PathValue("uuid"), websiteUUIDStr, query)
+	if err != nil {
+		if errors.Is(err, usecases.ErrGiftCardNotFound) {
+			writeGiftCardError(w, err)
+			return
+		}
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, giftCardTransactionToResponse))
+}
+
+func (c *GiftCardController) Balance(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.GiftCardBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	card, err := c.giftCardUseCase.Balance(websiteUUIDStr, req.Code)
+	if err != nil {
+		writeGiftCardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, giftCardToResponse(card))
+}
+
+// MyStoreCredit lists the signed-in customer's store credit with the total
+// left per currency.
+func (c *GiftCardController) MyStoreCredit(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	credits, err := c.giftCardUseCase.ListStoreCredit(websiteUUIDStr, userUUID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	now := time.Now()
+	response := dtos.StoreCreditResponse{
+		Balances: make(map[string]int),
+		Credits:  make([]dtos.GiftCardResponse, 0, len(credits)),
+	}
+	for _, credit := range credits {
+		if credit.Balance > 0 && !credit.Expired(now) {
+			response.Balances[string(credit.Coin)] += credit.Balance
+		}
+		response.Credits = append(response.Credits, giftCardToResponse(credit))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Quote shows the checkout totals with the coupon and gift cards applied,
+// without spending anything.
+func (c *GiftCardController) Quote(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.CheckoutGiftCardsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	totals, err := c.giftCardUseCase.Quote(websiteUUIDStr, middleware.GetUserUUID(r), req.Coin, req.Subtotal, req.CupomUUID, req.Codes, req.UseStoreCredit)
+	if err != nil {
+		writeGiftCardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checkoutTotalsToResponse(totals))
+}
+
+func writeGiftCardError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrGiftCardNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R16-001", "gift card not found"))
+	case errors.Is(err, domain.ErrGiftCardExpired):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-002", "gift card expired"))
+	case errors.Is(err, domain.ErrGiftCardEmpty):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-003", "gift card has no balance"))
+	case errors.Is(err, domain.ErrGiftCardCoin):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-004", "gift card currency does not match"))
+	case errors.Is(err, domain.ErrCupomNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R16-009", "cupom not found"))
+	case errors.Is(err, domain.ErrCupomCoin):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-010", "cupom currency does not match"))
+	case errors.Is(err, domain.ErrGiftCardOwner):
+		writeJSON(w, http.StatusForbidden, errorResponse("R16-005", "store credit belongs to another customer"))
+	case errors.Is(err, domain.ErrGiftCardRedeemed):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-006", "gift card already used on this order"))
+	case errors.Is(err, domain.ErrGiftCardChanged):
+		writeJSON(w, http.StatusConflict, errorResponse("R16-007", "gift card balance changed"))
+	case errors.Is(err, usecases.ErrGiftCardCodeMissing):
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing gift card code"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func giftCardToResponse(card *domain.GiftCard) dtos.GiftCardResponse {
+	response := dtos.GiftCardResponse{
+		UUID:           card.UUID.String(),
+		Type:           string(card.Type),
+		Source:         string(card.Source),
+		CodeLast4:      card.CodeLast4,
+		Coin:           string(card.Coin),
+		InitialAmount:  card.InitialAmount,
+		Balance:        card.Balance,
+		RecipientEmail: card.RecipientEmail,
+		Note:           card.Note,
+		CreatedAt:      card.CreatedAt.String(),
+	}
+	if card.UserUUID != nil {
+		response.UserUUID = card.UserUUID.String()
+	}
+	if card.ProductUUID != nil {
+		response.ProductUUID = card.ProductUUID.String()
+	}
+	if card.OrderUUID != nil {
+		response.OrderUUID = card.OrderUUID.String()
+	}
+	if card.ExpiresAt != nil {
+		response.ExpiresAt = card.ExpiresAt.String()
+	}
+
+	return response
+}
+
+func giftCardTransactionToResponse(t *domain.GiftCardTransaction) dtos.GiftCardTransactionResponse {
+	orderUUID := ""
+	if t.OrderUUID != nil {
+		orderUUID = t.OrderUUID.String()
+	}
+
+	return dtos.GiftCardTransactionResponse{
+		UUID:         t.UUID.String(),
+		GiftCardUUID: t.GiftCardUUID.String(),
+		Type:         string(t.Type),
+		Amount:       t.Amount,
+		BalanceAfter: t.BalanceAfter,
+		OrderUUID:    orderUUID,
+		CreatedAt:    t.CreatedAt.String(),
+	}
+}
+
+func checkoutTotalsToResponse(totals *domain.CheckoutTotals) dtos.CheckoutTotalsResponse {
+	applications := make([]dtos.GiftCardApplicationResponse, 0, len(totals.GiftCards))
+	for _, a := range totals.GiftCards {
+		applications = append(applications, dtos.GiftCardApplicationResponse{
+			GiftCardUUID: a.Card.UUID.String(),
+			Type:         string(a.Card.Type),
+			CodeLast4:    a.Card.CodeLast4,
+			Amount:       a.Amount,
+			BalanceAfter: a.Card.Balance - a.Amount,
+		})
+	}
+
+	return dtos.CheckoutTotalsResponse{
+		Coin:      string(totals.Coin),
+		Subtotal:  totals.Subtotal,
+		Discount:  totals.Discount,
+		Total:     totals.Total,
+		GiftCards: applications,
+		AmountDue: totals.AmountDue,
+	}
+}
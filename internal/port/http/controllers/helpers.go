@@ -2,7 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -17,3 +20,28 @@ func errorResponse(code, message string) map[string]string {
 		"message": message,
 	}
 }
+
+func requireWebsiteUUID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	websiteUUIDStr := r.Header.Get("X-Website-UUID")
+	if websiteUUIDStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-003", "missing X-Website-UUID header"))
+		return "", false
+	}
+
+	if _, err := uuid.Parse(websiteUUIDStr); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-003", "invalid X-Website-UUID"))
+		return "", false
+	}
+
+	return websiteUUIDStr, true
+}
+
+// clientIP is the address the request came from. Forwarded headers are
+// not trusted, as clients could set them to dodge per-ip limits.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
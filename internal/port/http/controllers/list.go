@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+// listQueryFromRequest reads cursor, limit and sort; every other query
+// parameter except the reserved ones is handed to the repository as a filter,
+// which rejects the names it does not know.
+func listQueryFromRequest(r *http.Request, reserved ...string) (*domain.ListQuery, error) {
+	values := r.URL.Query()
+
+	limit := 0
+	if raw := values.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: limit must be a number", domain.ErrInvalidListQuery)
+		}
+		limit = parsed
+	}
+
+	filters := make(map[string]string)
+	for name := range values {
+		switch {
+		case name == "cursor", name == "limit", name == "sort", slices.Contains(reserved, name):
+			continue
+		}
+		filters[name] = values.Get(name)
+	}
+
+	return domain.NewListQuery(values.Get("cursor"), limit, values.Get("sort"), filters)
+}
+
+func writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrInvalidListQuery) {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+}
+
+func pageToResponse[T any, R any](page *domain.Page[T], convert func(T) R) dtos.PageResponse[R] {
+	resp := dtos.PageResponse[R]{
+		Data:  make([]R, 0, len(page.Items)),
+		Limit: page.Limit,
+	}
+	for _, item := range page.Items {
+		resp.Data = append(resp.Data, convert(item))
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+	return resp
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type LoyaltyController struct {
+	loyaltyUseCase *usecases.LoyaltyUseCase
+	guard          *WebsiteGuard
+}
+
+func NewLoyaltyController(loyaltyUseCase *usecases.LoyaltyUseCase, guard *WebsiteGuard) *LoyaltyController {
+	return &LoyaltyController{
+		loyaltyUseCase: loyaltyUseCase,
+		guard:          guard,
+	}
+}
+
+func (c *LoyaltyController) GetProgram(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	program, err := c.loyaltyUseCase.GetProgram(websiteUUIDStr)
+	if err != nil {
+		writeLoyaltyError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loyaltyProgramToResponse(program))
+}
+
+// SaveProgram replaces the website's rules, rates included. Changes apply
+// to points earned from now on.
+func (c *LoyaltyController) SaveProgram(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.SaveLoyaltyProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	rates := make([]*domain.LoyaltyRate, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		rates = append(rates, &domain.LoyaltyRate{
+			Coin:              enums.CoinType(rate.Coin),
+			EarnPointsPerUnit: rate.EarnPointsPerUnit,
+			PointValue:        rate.PointValue,
+		})
+	}
+
+	program, err := c.loyaltyUseCase.SaveProgram(websiteUUIDStr, req.Active, req.FirstOrderBonus, req.ReviewBonus, req.ExpiryDays, req.MinRedeemPoints, rates)
+	if err != nil {
+		writeLoyaltyError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loyaltyProgramToResponse(program))
+}
+
+func (c *LoyaltyController) Balance(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := c.loyaltyUseCase.Balance(websiteUUIDStr, userUUID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	response := dtos.LoyaltyBalanceResponse{
+		Points:         balance.Points,
+		ExpiringPoints: balance.ExpiringPoints,
+	}
+	if balance.ExpiringAt != nil {
+		response.ExpiringAt = balance.ExpiringAt.String()
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (c *LoyaltyController) History(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.loyaltyUseCase.History(websiteUUIDStr, userUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, loyaltyTransactionToResponse))
+}
+
+// Quote shows the discount the signed-in customer's points would give,
+// without spending them.
+func (c *LoyaltyController) Quote(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.RedeemLoyaltyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	quote, err := c.loyaltyUseCase.Quote(websiteUUIDStr, userUUID, req.Coin, req.Points, req.Subtotal)
+	if err != nil {
+		writeLoyaltyError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loyaltyQuoteToResponse(quote))
+}
+
+func writeLoyaltyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrLoyaltyProgramNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R17-001", "loyalty program not found"))
+	case errors.Is(err, domain.ErrLoyaltyInactive):
+		writeJSON(w, http.StatusConflict, errorResponse("R17-002", "loyalty program is not active"))
+	case errors.Is(err, domain.ErrLoyaltyCoin):
+		writeJSON(w, http.StatusConflict, errorResponse("R17-003", "loyalty program has no rate for this currency"))
+	case errors.Is(err, domain.ErrLoyaltyInsufficient):
+		writeJSON(w, http.StatusConflict, errorResponse("R17-004", "not enough loyalty points"))
+	case errors.Is(err, domain.ErrLoyaltyMinimum):
+		writeJSON(w, http.StatusConflict, errorResponse("R17-005", "below the minimum points to redeem"))
+	case errors.Is(err, domain.ErrLoyaltyApplied):
+		writeJSON(w, http.StatusConflict, errorResponse("R17-006", "loyalty points already applied"))
+	case errors.Is(err, usecases.ErrLoyaltyRedemptionNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R17-007", "loyalty redemption not found"))
+	case errors.Is(err, usecases.ErrLoyaltyEarningNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R17-008", "loyalty earning not found"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func loyaltyProgramToResponse(program *domain.LoyaltyProgram) dtos.LoyaltyProgramResponse {
+	rates := make([]dtos.LoyaltyRateResponse, 0, len(program.Rates))
+	for _, rate := range program.Rates {
+		rates = append(rates, dtos.LoyaltyRateResponse{
+			Coin:              string(rate.Coin),
+			EarnPointsPerUnit: rate.EarnPointsPerUnit,
+			PointValue:        rate.PointValue,
+		})
+	}
+
+	response := dtos.LoyaltyProgramResponse{
+		WebsiteUUID:     program.WebsiteUUID.String(),
+		Active:          program.Active,
+		FirstOrderBonus: program.FirstOrderBonus,
+		ReviewBonus:     program.ReviewBonus,
+		ExpiryDays:      program.ExpiryDays,
+		MinRedeemPoints: program.MinRedeemPoints,
+		Rates:           rates,
+		CreatedAt:       program.CreatedAt.String(),
+	}
+	if program.UpdatedAt != nil {
+		response.UpdatedAt = program.UpdatedAt.String()
+	}
+
+	return response
+}
+
+func loyaltyTransactionToResponse(t *domain.LoyaltyTransaction) dtos.LoyaltyTransactionResponse {
+	response := dtos.LoyaltyTransactionResponse{
+		UUID:      t.UUID.String(),
+		Type:      string(t.Type),
+		Points:    t.Points,
+		Remaining: t.Remaining,
+		Amount:    t.Amount,
+		CreatedAt: t.CreatedAt.String(),
+	}
+	if t.Coin != nil {
+		response.Coin = string(*t.Coin)
+	}
+	if t.OrderUUID != nil {
+		response.OrderUUID = t.OrderUUID.String()
+	}
+	if t.ReviewUUID != nil {
+		response.ReviewUUID = t.ReviewUUID.String()
+	}
+	if t.ExpiresAt != nil {
+		response.ExpiresAt = t.ExpiresAt.String()
+	}
+
+	return response
+}
+
+func loyaltyQuoteToResponse(quote *domain.LoyaltyQuote) dtos.LoyaltyQuoteResponse {
+	return dtos.LoyaltyQuoteResponse{
+		Coin:      string(quote.Coin),
+		Points:    quote.Points,
+		Discount:  quote.Discount,
+		Available: quote.Available,
+	}
+}
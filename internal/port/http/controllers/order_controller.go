@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type OrderController struct {
+	orderUseCase *usecases.OrderUseCase
+	guard        *WebsiteGuard
+}
+
+func NewOrderController(orderUseCase *usecases.OrderUseCase, guard *WebsiteGuard) *OrderController {
+	return &OrderController{
+		orderUseCase: orderUseCase,
+		guard:        guard,
+	}
+}
+
+func (c *OrderController) Checkout(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	order, err := c.orderUseCase.Checkout(websiteUUID, userUUID, req.CupomUUID, req.GiftCardCodes, req.UseStoreCredit, req.LoyaltyPoints, req.PaymentMethod)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, orderToResponse(order))
+}
+
+func (c *OrderController) ListMine(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.orderUseCase.ListByUser(websiteUUID, userUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, orderToResponse))
+}
+
+func (c *OrderController) GetMine(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	order, err := c.orderUseCase.GetForUser(uuidStr, userUUID)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orderToResponse(order))
+}
+
+// List returns every order of the website, for its staff.
+func (c *OrderController) List(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.orderUseCase.List(websiteUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, orderToResponse))
+}
+
+func (c *OrderController) Refund(w http.ResponseWriter, r *http.Request) {
+	order, ok := c.merchantOrder(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.RefundOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	order, err := c.orderUseCase.Refund(order, req.Amount, req.ToStoreCredit)
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orderToResponse(order))
+}
+
+// merchantOrder loads the order of the uuid path value and checks the
+// signed-in user's permission on its website.
+func (c *OrderController) merchantOrder(w http.ResponseWriter, r *http.Request, can func(*domain.WebsitePermissions) bool) (*domain.Order, bool) {
+	if middleware.GetUserUUID(r) == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return nil, false
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return nil, false
+	}
+
+	order, err := c.orderUseCase.Get(uuidStr)
+	if err != nil {
+		writeOrderError(w, err)
+		return nil, false
+	}
+
+	if !c.guard.allow(w, r, order.WebsiteUUID.String(), can) {
+		return nil, false
+	}
+
+	return order, true
+}
+
+func writeOrderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrOrderNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R22-008", "order not found"))
+	case errors.Is(err, domain.ErrOrderNotPaid):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-009", "order is not paid"))
+	case errors.Is(err, domain.ErrOrderRefundAmount):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-010", "refund exceeds what is left to refund"))
+	case errors.Is(err, usecases.ErrPaymentDeclined):
+		writeJSON(w, http.StatusPaymentRequired, errorResponse("R22-011", "payment declined"))
+	case errors.Is(err, usecases.ErrPaymentMethodMissing):
+		writeJSON(w, http.StatusBadRequest, errorResponse("R22-012", "payment method missing"))
+	case errors.Is(err, domain.ErrCupomExpired):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-013", "cupom expired"))
+	case errors.Is(err, domain.ErrCupomUsedUp):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-014", "cupom already used"))
+	case errors.Is(err, domain.ErrCartCheckingOut):
+		writeJSON(w, http.StatusConflict, errorResponse("R22-015", "cart is already being checked out"))
+	case errors.Is(err, usecases.ErrPaymentProviderMissing):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse("RAX-009", "feature unavailable"))
+	case errors.Is(err, domain.ErrCartEmpty), errors.Is(err, domain.ErrCartItemUnavailable):
+		writeCartError(w, err)
+	case errors.Is(err, domain.ErrLoyaltyInactive), errors.Is(err, domain.ErrLoyaltyCoin), errors.Is(err, domain.ErrLoyaltyInsufficient),
+		errors.Is(err, domain.ErrLoyaltyMinimum), errors.Is(err, domain.ErrLoyaltyApplied), errors.Is(err, usecases.ErrLoyaltyProgramNotFound):
+		writeLoyaltyError(w, err)
+	default:
+		writeGiftCardError(w, err)
+	}
+}
+
+func orderToResponse(order *domain.Order) dtos.OrderResponse {
+	response := dtos.OrderResponse{
+		UUID:             order.UUID.String(),
+		WebsiteUUID:      order.WebsiteUUID.String(),
+		UserUUID:         order.UserUUID.String(),
+		Email:            order.Email,
+		Status:           string(order.Status),
+		Coin:             string(order.Coin),
+		Subtotal:         order.Subtotal,
+		Discount:         order.Discount,
+		LoyaltyPoints:    order.LoyaltyPoints,
+		LoyaltyDiscount:  order.LoyaltyDiscount,
+		GiftCardAmount:   order.GiftCardAmount,
+		Total:            order.Total,
+		AmountPaid:       order.AmountPaid,
+		RefundedAmount:   order.RefundedAmount,
+		PaymentReference: order.PaymentReference,
+		Recovered:        order.Recovered,
+		CreatedAt:        order.CreatedAt.String(),
+	}
+	if order.CupomUUID != nil {
+		response.CupomUUID = order.CupomUUID.String()
+	}
+	if order.PaidAt != nil {
+		response.PaidAt = order.PaidAt.String()
+	}
+
+	for _, item := range order.Items {
+		itemResponse := dtos.OrderItemResponse{
+			UUID:        item.UUID.String(),
+			ProductUUID: item.ProductUUID.String(),
+			ProductType: string(item.ProductType),
+			Name:        item.Name,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+			Amount:      item.Amount,
+		}
+		if item.VariantUUID != nil {
+			itemResponse.VariantUUID = item.VariantUUID.String()
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
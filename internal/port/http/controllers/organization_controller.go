@@ -86,19 +86,19 @@ func (c *OrganizationController) GetAll(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	orgs, err := c.createUseCase.GetAll(websiteUUIDStr)
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.OrganizationResponse, 0, len(orgs))
-	for _, org := range orgs {
-		resp = append(resp, c.toResponse(org))
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *OrganizationController) toResponse(org *domain.OrganizationBR) dtos.OrganizationResponse {
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type OrganizationMemberController struct {
+	memberUseCase *usecases.OrganizationMemberUseCase
+}
+
+func NewOrganizationMemberController(memberUseCase *usecases.OrganizationMemberUseCase) *OrganizationMemberController {
+	return &OrganizationMemberController{
+		memberUseCase: memberUseCase,
+	}
+}
+
+func (c *OrganizationMemberController) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.memberUseCase.Members(userUUID, r.PathValue("uuid"), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			writeListError(w, err)
+			return
+		}
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, organizationMemberToResponse))
+}
+
+func (c *OrganizationMemberController) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.UpdateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	member, err := c.memberUseCase.UpdateMember(userUUID, r.PathValue("uuid"), r.PathValue("user_uuid"), req.Role, req.RbacUUID)
+	if err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, organizationMemberToResponse(member))
+}
+
+// RemoveMember removes a member; members remove themselves to leave.
+func (c *OrganizationMemberController) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	if err := c.memberUseCase.RemoveMember(userUUID, r.PathValue("uuid"), r.PathValue("user_uuid")); err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "removed"})
+}
+
+func (c *OrganizationMemberController) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.TransferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if err := c.memberUseCase.TransferOwnership(userUUID, r.PathValue("uuid"), req.UserUUID); err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "transferred"})
+}
+
+func (c *OrganizationMemberController) Invite(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	invitation, err := c.memberUseCase.Invite(userUUID, r.PathValue("uuid"), req.Email, req.Role, req.RbacUUID)
+	if err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, organizationInvitationToResponse(invitation))
+}
+
+func (c *OrganizationMemberController) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.memberUseCase.Invitations(userUUID, r.PathValue("uuid"), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			writeListError(w, err)
+			return
+		}
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, organizationInvitationToResponse))
+}
+
+func (c *OrganizationMemberController) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	if err := c.memberUseCase.RevokeInvitation(userUUID, r.PathValue("uuid"), r.PathValue("invitation_uuid")); err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+func (c *OrganizationMemberController) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	member, err := c.memberUseCase.Accept(userUUID, r.PathValue("token"))
+	if err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, organizationMemberToResponse(member))
+}
+
+func (c *OrganizationMemberController) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	if err := c.memberUseCase.Decline(userUUID, r.PathValue("token")); err != nil {
+		writeOrganizationMemberError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "declined"})
+}
+
+func writeOrganizationMemberError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrOrganizationNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R20-001", "organization not found"))
+	case errors.Is(err, usecases.ErrMemberNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R20-002", "organization member not found"))
+	case errors.Is(err, domain.ErrMemberExists):
+		writeJSON(w, http.StatusConflict, errorResponse("R20-003", "user is already a member"))
+	case errors.Is(err, usecases.ErrInvitationNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R20-004", "invitation not found"))
+	case errors.Is(err, domain.ErrInvitationExpired):
+		writeJSON(w, http.StatusGone, errorResponse("R20-005", "invitation expired"))
+	case errors.Is(err, domain.ErrInvitationAnswered):
+		writeJSON(w, http.StatusConflict, errorResponse("R20-006", "invitation already answered"))
+	case errors.Is(err, domain.ErrInvitationPending):
+		writeJSON(w, http.StatusConflict, errorResponse("R20-007", "invitation already pending for this email"))
+	case errors.Is(err, domain.ErrOwnerMember):
+		writeJSON(w, http.StatusConflict, errorResponse("R20-008", "the owner cannot be removed or change role"))
+	case errors.Is(err, usecases.ErrMemberRoleNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R20-009", "role not found"))
+	case errors.Is(err, usecases.ErrInvitationEmail):
+		writeJSON(w, http.StatusForbidden, errorResponse("R20-010", "invitation was sent to another email"))
+	case errors.Is(err, usecases.ErrMemberForbidden):
+		writeJSON(w, http.StatusForbidden, errorResponse("R10-001", "permission denied"))
+	case errors.Is(err, usecases.ErrOwnerRequired):
+		writeJSON(w, http.StatusForbidden, errorResponse("R10-004", "owner required"))
+	case errors.Is(err, usecases.ErrMemberWebsiteNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+	case errors.Is(err, usecases.ErrInvitationDelivery):
+		writeJSON(w, http.StatusBadGateway, errorResponse("R9-001", "email delivery failed"))
+	case errors.Is(err, domain.ErrTwoFactorRequired):
+		writeJSON(w, http.StatusForbidden, errorResponse("R21-006", "two-factor authentication required"))
+	case errors.Is(err, usecases.ErrMailerMissing):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse("RAX-009", "feature unavailable"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func organizationMemberToResponse(m *domain.OrganizationMember) dtos.OrganizationMemberResponse {
+	response := dtos.OrganizationMemberResponse{
+		UUID:             m.UUID.String(),
+		OrganizationUUID: m.OrganizationUUID.String(),
+		UserUUID:         m.UserUUID.String(),
+		Email:            m.Email,
+		Name:             m.Name,
+		Role:             string(m.Role),
+		CreatedAt:        m.CreatedAt.String(),
+	}
+
+	if m.RbacUUID != nil {
+		response.RbacUUID = m.RbacUUID.String()
+	}
+	if m.UpdatedAt != nil {
+		response.UpdatedAt = m.UpdatedAt.String()
+	}
+
+	return response
+}
+
+func organizationInvitationToResponse(i *domain.OrganizationInvitation) dtos.OrganizationInvitationResponse {
+	response := dtos.OrganizationInvitationResponse{
+		UUID:             i.UUID.String(),
+		OrganizationUUID: i.OrganizationUUID.String(),
+		Email:            i.Email,
+		Role:             string(i.Role),
+		InvitedBy:        i.InvitedBy.String(),
+		Status:           string(i.Status),
+		ExpiresAt:        i.ExpiresAt.String(),
+		CreatedAt:        i.CreatedAt.String(),
+	}
+
+	if i.RbacUUID != nil {
+		response.RbacUUID = i.RbacUUID.String()
+	}
+	if i.RespondedAt != nil {
+		response.RespondedAt = i.RespondedAt.String()
+	}
+
+	return response
+}
+
+func websitePermissionsToResponse(p *domain.WebsitePermissions) dtos.WebsitePermissionsResponse {
+	return dtos.WebsitePermissionsResponse{
+		WebsiteUUID:       p.WebsiteUUID.String(),
+		Role:              string(p.Role),
+		CanRead:           p.CanRead,
+		CanWrite:          p.CanWrite,
+		CanUpdate:         p.CanUpdate,
+		CanUpgrade:        p.CanUpgrade,
+		CanDelete:         p.CanDelete,
+		TwoFactorRequired: p.TwoFactorRequired,
+	}
+}
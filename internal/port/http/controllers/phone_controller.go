@@ -87,19 +87,19 @@ func (c *PhoneController) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	phones, err := c.createUseCase.GetAll(websiteUUIDStr)
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.PhoneResponse, 0, len(phones))
-	for _, phone := range phones {
-		resp = append(resp, c.toResponse(phone))
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *PhoneController) toResponse(phone *domain.Phone) dtos.PhoneResponse {
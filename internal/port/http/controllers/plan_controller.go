@@ -25,7 +25,7 @@ func (c *PlanController) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plan, err := c.createUseCase.Create(req.Name, req.Description, req.MaxWebsites, req.MaxRouters, req.MaxProducts, req.CostPerSaleRate, req.Coin, req.Price)
+	plan, err := c.createUseCase.Create(req.Name, req.Description, req.MaxWebsites, req.MaxRouters, req.MaxProducts, req.CostPerSaleRate, req.Coin, req.Price, req.TrialDays)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusConflict)
 		return
@@ -41,6 +41,7 @@ func (c *PlanController) Create(w http.ResponseWriter, r *http.Request) {
 		CostPerSaleRate: plan.CostPerSaleRate,
 		Coin:            string(plan.Coin),
 		Price:           plan.Price,
+		TrialDays:       plan.TrialDays,
 		CreatedAt:       plan.CreatedAt.String(),
 	}
 
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+// PlatformAdmins are the users who manage what every website shares, like
+// exchange rates. They are named by uuid in the configuration.
+type PlatformAdmins map[string]bool
+
+func NewPlatformAdmins(userUUIDs []string) PlatformAdmins {
+	admins := make(PlatformAdmins, len(userUUIDs))
+	for _, userUUID := range userUUIDs {
+		admins[userUUID] = true
+	}
+	return admins
+}
+
+// allow answers the request and returns false unless the signed-in user is
+// a platform admin.
+func (a PlatformAdmins) allow(w http.ResponseWriter, r *http.Request) bool {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return false
+	}
+
+	if !a[userUUID] {
+		writeJSON(w, http.StatusForbidden, errorResponse("R10-003", "administrator required"))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/ViitoJooj/verkoupe/pkg/pdf"
+	"github.com/ViitoJooj/verkoupe/pkg/spreadsheet"
+)
+
+type PlatformFeeController struct {
+	platformFeeUseCase *usecases.PlatformFeeUseCase
+	guard              *WebsiteGuard
+}
+
+func NewPlatformFeeController(platformFeeUseCase *usecases.PlatformFeeUseCase, guard *WebsiteGuard) *PlatformFeeController {
+	return &PlatformFeeController{
+		platformFeeUseCase: platformFeeUseCase,
+		guard:              guard,
+	}
+}
+
+// List shows the fee ledger of the owner in ?owner_uuid=, by default the
+// signed-in user.
+func (c *PlatformFeeController) List(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r, "owner_uuid")
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.platformFeeUseCase.List(ownerUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, platformFeeToResponse))
+}
+
+func (c *PlatformFeeController) Statement(w http.ResponseWriter, r *http.Request) {
+	statement, ok := c.statement(w, r)
+	if !ok {
+		return
+	}
+
+	response := dtos.MerchantStatementResponse{
+		OwnerUUID: statement.OwnerUUID.String(),
+		Month:     statement.Month.Format("2006-01"),
+		Totals:    make([]dtos.StatementTotalsResponse, 0, len(statement.Totals)),
+		Entries:   make([]dtos.PlatformFeeResponse, 0, len(statement.Entries)),
+	}
+	for _, t := range statement.Totals {
+		response.Totals = append(response.Totals, dtos.StatementTotalsResponse{
+			Coin:       string(t.Coin),
+			Sales:      t.Sales,
+			GrossSales: t.GrossSales,
+			Fees:       t.Fees,
+			Refunds:    t.Refunds,
+			FeeRefunds: t.FeeRefunds,
+			Net:        t.Net,
+		})
+	}
+	for _, e := range statement.Entries {
+		response.Entries = append(response.Entries, platformFeeToResponse(e))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Download serves the statement as ?format=csv (default) or pdf.
+func (c *PlatformFeeController) Download(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	contentType := spreadsheet.ContentType(spreadsheet.FormatCSV)
+	switch format {
+	case spreadsheet.FormatCSV:
+	case pdf.Format:
+		contentType = pdf.ContentType
+	default:
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "format must be csv or pdf"))
+		return
+	}
+
+	statement, ok := c.statement(w, r)
+	if !ok {
+		return
+	}
+
+	filename := "statement-" + statement.Month.Format("2006-01") + "." + format
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	if err := c.platformFeeUseCase.WriteStatement(statement, format, w); err != nil {
+		logger.Warn(err).Print()
+	}
+}
+
+func (c *PlatformFeeController) statement(w http.ResponseWriter, r *http.Request) (*domain.MerchantStatement, bool) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	statement, err := c.platformFeeUseCase.Statement(ownerUUID, r.PathValue("month"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-006", err.Error()))
+		return nil, false
+	}
+
+	return statement, true
+}
+
+func platformFeeToResponse(f *domain.PlatformFee) dtos.PlatformFeeResponse {
+	response := dtos.PlatformFeeResponse{
+		UUID:        f.UUID.String(),
+		OwnerUUID:   f.OwnerUUID.String(),
+		WebsiteUUID: f.WebsiteUUID.String(),
+		OrderUUID:   f.OrderUUID.String(),
+		Type:        string(f.Type),
+		Coin:        string(f.Coin),
+		Amount:      f.Amount,
+		Rate:        f.Rate,
+		Fee:         f.Fee,
+		CreatedAt:   f.CreatedAt.String(),
+	}
+	if f.PlanUUID != nil {
+		response.PlanUUID = f.PlanUUID.String()
+	}
+
+	return response
+}
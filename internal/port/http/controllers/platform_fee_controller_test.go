@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/google/uuid"
+)
+
+func TestPlatformFeeControllerForbidden(t *testing.T) {
+	user := uuid.New()
+	other := uuid.New()
+
+	members := usecases.NewOrganizationMemberUseCase(nil, noOrganizations{}, nil, nil, nil, nil, nil, "")
+	controller := NewPlatformFeeController(nil, NewWebsiteGuard(members, nil))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+	}{
+		{name: "list another owner", handler: controller.List, target: "/platform-fees?owner_uuid=" + other.String()},
+		{name: "statement of another owner", handler: controller.Statement, target: "/platform-fees/statements/2026-01?owner_uuid=" + other.String()},
+		{name: "download another owner", handler: controller.Download, target: "/platform-fees/statements/2026-01/download?owner_uuid=" + other.String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.SetPathValue("month", "2026-01")
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserUUIDKey, user.String()))
+			w := httptest.NewRecorder()
+
+			tt.handler(w, r)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+			}
+		})
+	}
+}
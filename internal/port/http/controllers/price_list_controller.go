@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type PriceListController struct {
+	createUseCase *usecases.CreatePriceListUseCase
+	guard         *WebsiteGuard
+}
+
+func NewPriceListController(createUseCase *usecases.CreatePriceListUseCase, guard *WebsiteGuard) *PriceListController {
+	return &PriceListController{
+		createUseCase: createUseCase,
+		guard:         guard,
+	}
+}
+
+func (c *PriceListController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	list, err := c.createUseCase.Create(websiteUUIDStr, req.Label, req.Coin, req.CustomerGroup, req.Priority, req.Active)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, priceListToResponse(list))
+}
+
+func (c *PriceListController) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	list, err := c.createUseCase.GetByUUID(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "price list not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, priceListToResponse(list))
+}
+
+func (c *PriceListController) GetAll(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, priceListToResponse))
+}
+
+func (c *PriceListController) Delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canDelete); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if err := c.createUseCase.Delete(uuidStr); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "price list not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// SetCustomerGroup puts a customer of the website in a customer group, so
+// checkout and prices use the price lists made for it.
+func (c *PriceListController) SetCustomerGroup(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+
+	var req dtos.SetCustomerGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	group, err := c.createUseCase.SetCustomerGroup(websiteUUIDStr, r.PathValue("user_uuid"), req.CustomerGroup)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.CustomerGroupResponse{
+		WebsiteUUID:   group.WebsiteUUID.String(),
+		UserUUID:      group.UserUUID.String(),
+		CustomerGroup: group.Group,
+		CreatedAt:     group.CreatedAt.String(),
+	})
+}
+
+func (c *PriceListController) RemoveCustomerGroup(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canDelete)
+	if !ok {
+		return
+	}
+
+	if err := c.createUseCase.RemoveCustomerGroup(websiteUUIDStr, r.PathValue("user_uuid")); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "customer group not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func priceListToResponse(l *domain.PriceList) dtos.PriceListResponse {
+	customerGroup := ""
+	if l.CustomerGroup != nil {
+		customerGroup = *l.CustomerGroup
+	}
+
+	return dtos.PriceListResponse{
+		UUID:          l.UUID.String(),
+		WebsiteUUID:   l.WebsiteUUID.String(),
+		Label:         l.Label,
+		Coin:          string(l.Coin),
+		CustomerGroup: customerGroup,
+		Priority:      l.Priority,
+		Active:        l.Active,
+		CreatedAt:     l.CreatedAt.String(),
+	}
+}
@@ -4,43 +4,114 @@ import (
 	"encoding/json"
 	"net/http"
 
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
 	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
 )
 
 type ProductController struct {
 	createUseCase *usecases.CreateProductUseCase
+	mediaUseCase  *usecases.CreateProductMediaUseCase
+	guard         *WebsiteGuard
 }
 
-func NewProductController(createUseCase *usecases.CreateProductUseCase) *ProductController {
+func NewProductController(createUseCase *usecases.CreateProductUseCase, mediaUseCase *usecases.CreateProductMediaUseCase, guard *WebsiteGuard) *ProductController {
 	return &ProductController{
 		createUseCase: createUseCase,
+		mediaUseCase:  mediaUseCase,
+		guard:         guard,
 	}
 }
 
 func (c *ProductController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
 	var req dtos.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	product, err := c.createUseCase.Create(req.Name, req.Description, req.ShortDescription, req.Height, req.Width, req.Thickness, req.Active)
+	product, err := c.createUseCase.Create(websiteUUIDStr, req.Name, req.Description, req.ShortDescription, req.Brand, req.GTIN, req.Type, req.Height, req.Width, req.Thickness, req.DownloadLimit, req.DownloadDays, req.Active)
+	if writeQuotaError(w, err, "R11-014", "product limit reached") {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusConflict)
 		return
 	}
 
-	resp := dtos.ProductResponse{
+	resp := productToResponse(product)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (c *ProductController) Get(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	product, err := c.createUseCase.FindByUUID(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-001", "product not found"))
+		return
+	}
+
+	medias, err := c.mediaUseCase.ListByProduct(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	resp := productToResponse(product)
+	resp.Media = productMediasToResponse(medias, c.mediaUseCase)
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (c *ProductController) List(w http.ResponseWriter, r *http.Request) {
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.List(query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, productToResponse))
+}
+
+func productToResponse(product *domain.Products) dtos.ProductResponse {
+	websiteUUID := ""
+	if product.WebsiteUUID != nil {
+		websiteUUID = product.WebsiteUUID.String()
+	}
+
+	return dtos.ProductResponse{
 		UUID:             product.UUID.String(),
+		WebsiteUUID:      websiteUUID,
 		Name:             product.Name,
 		Description:      product.Description,
 		ShortDescription: product.ShortDescription,
+		Brand:            product.Brand,
+		GTIN:             product.GTIN,
+		Type:             string(product.Type),
+		DownloadLimit:    product.DownloadLimit,
+		DownloadDays:     product.DownloadDays,
+		RatingCount:      product.RatingCount,
+		RatingAverage:    product.RatingAverage,
 		Active:           product.Active,
 		CreatedAt:        product.CreatedAt.String(),
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+type ProductDownloadController struct {
+	downloadUseCase *usecases.ProductDownloadUseCase
+}
+
+func NewProductDownloadController(downloadUseCase *usecases.ProductDownloadUseCase) *ProductDownloadController {
+	return &ProductDownloadController{
+		downloadUseCase: downloadUseCase,
+	}
+}
+
+// ListByOrder returns the grants of the shopper's order, each with a
+// freshly signed link. Grants are made when the order is paid.
+func (c *ProductDownloadController) ListByOrder(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	orderUUID := r.PathValue("uuid")
+	if orderUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	downloads, err := c.downloadUseCase.ListByOrder(orderUUID, userUUID)
+	if err != nil {
+		if errors.Is(err, usecases.ErrOrderNotFound) || errors.Is(err, domain.ErrOrderNotPaid) {
+			writeOrderError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	c.writeDownloads(w, http.StatusOK, downloads)
+}
+
+// Download serves the file behind a signed link. The link is the only
+// credential, so this route is registered without the auth middleware.
+func (c *ProductDownloadController) Download(w http.ResponseWriter, r *http.Request) {
+	download, body, err := c.downloadUseCase.Open(r.Context(), r.PathValue("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrDownloadLinkInvalid):
+			writeJSON(w, http.StatusNotFound, errorResponse("R12-008", "invalid download link"))
+		case errors.Is(err, usecases.ErrDownloadLinkExpired):
+			writeJSON(w, http.StatusGone, errorResponse("R12-009", "download link expired"))
+		case errors.Is(err, domain.ErrDownloadLimitReached):
+			writeJSON(w, http.StatusGone, errorResponse("R12-010", "download limit reached"))
+		case errors.Is(err, domain.ErrDownloadExpired):
+			writeJSON(w, http.StatusGone, errorResponse("R12-011", "download expired"))
+		default:
+			logger.Warn(err).Print()
+			writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		}
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", download.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(download.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, body); err != nil {
+		logger.Warn(err).Print()
+	}
+}
+
+func (c *ProductDownloadController) writeDownloads(w http.ResponseWriter, status int, downloads []*domain.ProductDownload) {
+	responses := make([]dtos.ProductDownloadResponse, 0, len(downloads))
+	for _, d := range downloads {
+		url, urlExpiresAt, err := c.downloadUseCase.Link(d)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+			return
+		}
+
+		resp := dtos.ProductDownloadResponse{
+			UUID:          d.UUID.String(),
+			OrderUUID:     d.OrderUUID.String(),
+			ProductUUID:   d.ProductUUID.String(),
+			FileUUID:      d.FileUUID.String(),
+			Filename:      d.Filename,
+			ContentType:   d.ContentType,
+			SizeBytes:     d.SizeBytes,
+			DownloadLimit: d.DownloadLimit,
+			DownloadCount: d.DownloadCount,
+			Remaining:     d.Remaining(),
+			ExpiresAt:     d.ExpiresAt.String(),
+			URL:           url,
+			CreatedAt:     d.CreatedAt.String(),
+		}
+		if d.LastDownloadedAt != nil {
+			resp.LastDownloadedAt = d.LastDownloadedAt.String()
+		}
+		if urlExpiresAt != nil {
+			resp.URLExpiresAt = urlExpiresAt.String()
+		}
+		responses = append(responses, resp)
+	}
+
+	writeJSON(w, status, responses)
+}
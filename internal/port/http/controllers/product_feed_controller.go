@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+type ProductFeedController struct {
+	feedUseCase *usecases.ProductFeedUseCase
+}
+
+func NewProductFeedController(feedUseCase *usecases.ProductFeedUseCase) *ProductFeedController {
+	return &ProductFeedController{
+		feedUseCase: feedUseCase,
+	}
+}
+
+func (c *ProductFeedController) Google(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDX-003", "missing website uuid"))
+		return
+	}
+
+	file, err := c.feedUseCase.Google(r.Context(), uuidStr)
+	if err != nil {
+		writeFeedError(w, err)
+		return
+	}
+
+	serveFeed(w, r, "google.xml", "application/xml; charset=utf-8", file)
+}
+
+func (c *ProductFeedController) Meta(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDX-003", "missing website uuid"))
+		return
+	}
+
+	file, err := c.feedUseCase.Meta(r.Context(), uuidStr)
+	if err != nil {
+		writeFeedError(w, err)
+		return
+	}
+
+	serveFeed(w, r, "meta.csv", "text/csv; charset=utf-8", file)
+}
+
+// serveFeed lets http.ServeContent answer conditional requests, so channels
+// polling the feed get a 304 until it is regenerated.
+func serveFeed(w http.ResponseWriter, r *http.Request, name string, contentType string, file *domain.ProductFeedFile) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=900")
+	w.Header().Set("ETag", `"`+strconv.FormatInt(file.GeneratedAt.Unix(), 36)+`"`)
+	http.ServeContent(w, r, name, file.GeneratedAt, bytes.NewReader(file.Data))
+}
+
+func writeFeedError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrFeedWebsiteURL):
+		writeJSON(w, http.StatusConflict, errorResponse("RDX-004", "website has no URL"))
+	case errors.Is(err, usecases.ErrFeedWebsiteNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+	case errors.Is(err, usecases.ErrWebsiteSuspended):
+		writeJSON(w, http.StatusForbidden, errorResponse("RDX-005", "website disabled"))
+	default:
+		logger.Warn(err).Print()
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+	}
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type ProductFileController struct {
+	fileUseCase *usecases.ProductFileUseCase
+	guard       *WebsiteGuard
+}
+
+func NewProductFileController(fileUseCase *usecases.ProductFileUseCase, guard *WebsiteGuard) *ProductFileController {
+	return &ProductFileController{
+		fileUseCase: fileUseCase,
+		guard:       guard,
+	}
+}
+
+// Upload accepts a multipart form with one "file" for a digital product.
+func (c *ProductFileController) Upload(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if !c.guard.product(w, r, productUUID, canWrite) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.fileUseCase.MaxBytes()+(1<<20))
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid multipart body"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("R8-001", "missing file"))
+		return
+	}
+	defer file.Close()
+
+	productFile, err := c.fileUseCase.Upload(r.Context(), productUUID, file, header.Size, header.Filename)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrMediaTooLarge):
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+		case errors.Is(err, usecases.ErrMediaUpload):
+			writeJSON(w, http.StatusBadGateway, errorResponse("R8-004", "upload failed"))
+		case errors.Is(err, usecases.ErrProductNotDigital):
+			writeJSON(w, http.StatusConflict, errorResponse("R11-009", "product is not digital"))
+		default:
+			writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, productFileToResponse(productFile))
+}
+
+func (c *ProductFileController) ListByProduct(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	files, err := c.fileUseCase.ListByProduct(productUUID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	responses := make([]dtos.ProductFileResponse, 0, len(files))
+	for _, f := range files {
+		responses = append(responses, productFileToResponse(f))
+	}
+
+	writeJSON(w, http.StatusOK, responses)
+}
+
+func (c *ProductFileController) Delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canDelete); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if err := c.fileUseCase.Delete(r.Context(), uuidStr); err != nil {
+		if errors.Is(err, domain.ErrProductFileInUse) {
+			writeJSON(w, http.StatusConflict, errorResponse("R11-011", "product file has active downloads"))
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-010", "product file not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func productFileToResponse(f *domain.ProductFile) dtos.ProductFileResponse {
+	return dtos.ProductFileResponse{
+		UUID:        f.UUID.String(),
+		ProductUUID: f.ProductUUID.String(),
+		Filename:    f.Filename,
+		ContentType: f.ContentType,
+		SizeBytes:   f.SizeBytes,
+		CreatedAt:   f.CreatedAt.String(),
+	}
+}
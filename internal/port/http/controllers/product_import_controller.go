@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+	"github.com/ViitoJooj/verkoupe/pkg/spreadsheet"
+)
+
+type ProductImportController struct {
+	importUseCase *usecases.ProductImportUseCase
+	guard         *WebsiteGuard
+}
+
+func NewProductImportController(importUseCase *usecases.ProductImportUseCase, guard *WebsiteGuard) *ProductImportController {
+	return &ProductImportController{
+		importUseCase: importUseCase,
+		guard:         guard,
+	}
+}
+
+// Create accepts a multipart form with a CSV or XLSX "file". The optional
+// "mapping" field is a JSON object from import field to header name, and
+// "dry_run" validates the file without writing anything. "source" set to
+// shopify reads a Shopify product export instead, priced in "coin".
+func (c *ProductImportController) Create(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canWrite)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.importUseCase.MaxBytes()+(1<<20))
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid multipart body"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("R8-001", "missing file"))
+		return
+	}
+	defer file.Close()
+
+	dryRun := false
+	if raw := r.FormValue("dry_run"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "dry_run must be true or false"))
+			return
+		}
+	}
+
+	var mapping map[string]string
+	if raw := r.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "mapping must be a JSON object of strings"))
+			return
+		}
+	}
+
+	job, err := c.importUseCase.Create(r.Context(), websiteUUID, header.Filename, file, header.Size, r.FormValue("source"), r.FormValue("coin"), dryRun, mapping)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrImportTooLarge):
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+		case errors.Is(err, usecases.ErrImportInvalidType):
+			writeJSON(w, http.StatusUnsupportedMediaType, errorResponse("R8-003", "file must be .csv or .xlsx"))
+		case errors.Is(err, usecases.ErrImportUpload):
+			writeJSON(w, http.StatusBadGateway, errorResponse("R8-004", "upload failed"))
+		default:
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", err.Error()))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, productImportToResponse(job))
+}
+
+func (c *ProductImportController) Get(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	job, err := c.importUseCase.Find(websiteUUID, uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-008", "product import not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, productImportToResponse(job))
+}
+
+func (c *ProductImportController) List(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.importUseCase.List(websiteUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, productImportToResponse))
+}
+
+func (c *ProductImportController) Errors(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.importUseCase.Errors(websiteUUID, uuidStr, query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidListQuery) {
+			writeListError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-008", "product import not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, productImportErrorToResponse))
+}
+
+// Export streams the website's catalog as ?format=csv (default) or xlsx.
+// Once the first bytes are out the status can no longer change, so failures
+// midway are only logged and the client gets a truncated file.
+func (c *ProductImportController) Export(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "format must be csv or xlsx"))
+		return
+	}
+
+	filename := "products-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	w.Header().Set("Content-Type", spreadsheet.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	if err := c.importUseCase.Export(r.Context(), websiteUUID, format, w); err != nil {
+		logger.Warn(err).Print()
+	}
+}
+
+func productImportToResponse(job *domain.ProductImportJob) dtos.ProductImportResponse {
+	startedAt := ""
+	if job.StartedAt != nil {
+		startedAt = job.StartedAt.String()
+	}
+
+	finishedAt := ""
+	if job.FinishedAt != nil {
+		finishedAt = job.FinishedAt.String()
+	}
+
+	mapping := job.Mapping
+	if mapping == nil {
+		mapping = map[string]string{}
+	}
+
+	return dtos.ProductImportResponse{
+		UUID:          job.UUID.String(),
+		Filename:      job.Filename,
+		Format:        job.Format,
+		Source:        string(job.Source),
+		Coin:          string(job.Coin),
+		Status:        string(job.Status),
+		DryRun:        job.DryRun,
+		Mapping:       mapping,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		CreatedCount:  job.CreatedCount,
+		FailedCount:   job.FailedCount,
+		WarningCount:  job.WarningCount,
+		Progress:      job.Progress(),
+		Error:         job.Error,
+		StartedAt:     startedAt,
+		FinishedAt:    finishedAt,
+		CreatedAt:     job.CreatedAt.String(),
+	}
+}
+
+func productImportErrorToResponse(e *domain.ProductImportError) dtos.ProductImportErrorResponse {
+	return dtos.ProductImportErrorResponse{
+		Row:     e.Row,
+		Level:   string(e.Level),
+		Field:   e.Field,
+		Message: e.Message,
+	}
+}
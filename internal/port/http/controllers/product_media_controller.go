@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+const maxMediaFilesPerRequest = 10
+
+type ProductMediaController struct {
+	createUseCase *usecases.CreateProductMediaUseCase
+	guard         *WebsiteGuard
+}
+
+func NewProductMediaController(createUseCase *usecases.CreateProductMediaUseCase, guard *WebsiteGuard) *ProductMediaController {
+	return &ProductMediaController{
+		createUseCase: createUseCase,
+		guard:         guard,
+	}
+}
+
+// Upload accepts a multipart form with one or more "file" parts. An optional
+// "alt" field is applied to every file of the request.
+func (c *ProductMediaController) Upload(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if !c.guard.product(w, r, productUUID, canWrite) {
+		return
+	}
+
+	maxBytes := c.createUseCase.MaxBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes*maxMediaFilesPerRequest+(1<<20))
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid multipart body"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse("R8-001", "missing file"))
+		return
+	}
+
+	if len(files) > maxMediaFilesPerRequest {
+		writeJSON(w, http.StatusBadRequest, errorResponse("R8-004", "too many files"))
+		return
+	}
+
+	alt := r.FormValue("alt")
+	responses := make([]dtos.ProductMediaResponse, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("R8-004", "upload failed"))
+			return
+		}
+
+		media, err := c.createUseCase.Upload(r.Context(), productUUID, file, header.Size, alt)
+		file.Close()
+		if err != nil {
+			switch {
+			case errors.Is(err, usecases.ErrMediaTooLarge):
+				writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", header.Filename+": file too large"))
+			case errors.Is(err, usecases.ErrMediaInvalidType):
+				writeJSON(w, http.StatusUnsupportedMediaType, errorResponse("R8-003", header.Filename+": invalid file type"))
+			case errors.Is(err, usecases.ErrMediaUpload):
+				writeJSON(w, http.StatusBadGateway, errorResponse("R8-004", header.Filename+": upload failed"))
+			default:
+				writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+			}
+			return
+		}
+
+		responses = append(responses, productMediaToResponse(media, c.createUseCase))
+	}
+
+	writeJSON(w, http.StatusCreated, responses)
+}
+
+func (c *ProductMediaController) ListByProduct(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	medias, err := c.createUseCase.ListByProduct(productUUID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, productMediasToResponse(medias, c.createUseCase))
+}
+
+func (c *ProductMediaController) Reorder(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if !c.guard.product(w, r, productUUID, canUpdate) {
+		return
+	}
+
+	var req dtos.ReorderProductMediaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	medias, err := c.createUseCase.Reorder(productUUID, req.MediaUUIDs)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, productMediasToResponse(medias, c.createUseCase))
+}
+
+func (c *ProductMediaController) Reprocess(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canUpdate); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	media, err := c.createUseCase.Reprocess(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("R8-006", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, productMediaToResponse(media, c.createUseCase))
+}
+
+func (c *ProductMediaController) Delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canDelete); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if err := c.createUseCase.Delete(r.Context(), uuidStr); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("R8-001", "product media not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func productMediasToResponse(medias []*domain.ProductMedia, mediaUseCase *usecases.CreateProductMediaUseCase) []dtos.ProductMediaResponse {
+	responses := make([]dtos.ProductMediaResponse, 0, len(medias))
+	for _, m := range medias {
+		responses = append(responses, productMediaToResponse(m, mediaUseCase))
+	}
+	return responses
+}
+
+// productMediaToResponse groups derivatives by format so storefronts can drop
+// srcset["jpeg"] straight into a srcset attribute.
+func productMediaToResponse(m *domain.ProductMedia, mediaUseCase *usecases.CreateProductMediaUseCase) dtos.ProductMediaResponse {
+	variants := make([]dtos.ProductMediaVariantResponse, 0, len(m.Derivatives))
+	srcset := make(map[string]string)
+	for _, d := range m.Derivatives {
+		url := mediaUseCase.URL(d.BlobKey)
+		variants = append(variants, dtos.ProductMediaVariantResponse{
+			URL:    url,
+			Format: d.Format,
+			Width:  d.Width,
+			Height: d.Height,
+		})
+
+		entry := url + " " + strconv.Itoa(d.Width) + "w"
+		if srcset[d.Format] != "" {
+			entry = srcset[d.Format] + ", " + entry
+		}
+		srcset[d.Format] = entry
+	}
+
+	return dtos.ProductMediaResponse{
+		UUID:        m.UUID.String(),
+		ProductUUID: m.ProductUUID.String(),
+		URL:         mediaUseCase.URL(m.BlobKey),
+		ContentType: m.ContentType,
+		SizeBytes:   m.SizeBytes,
+		Alt:         m.Alt,
+		Position:    m.Position,
+		Status:      string(m.Status),
+		Error:       m.Error,
+		Width:       m.Width,
+		Height:      m.Height,
+		Variants:    variants,
+		Srcset:      srcset,
+		CreatedAt:   m.CreatedAt.String(),
+	}
+}
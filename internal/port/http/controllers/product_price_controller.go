@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type ProductPriceController struct {
+	createUseCase  *usecases.CreateProductPriceUseCase
+	resolveUseCase *usecases.ResolveProductPriceUseCase
+	guard          *WebsiteGuard
+}
+
+func NewProductPriceController(createUseCase *usecases.CreateProductPriceUseCase, resolveUseCase *usecases.ResolveProductPriceUseCase, guard *WebsiteGuard) *ProductPriceController {
+	return &ProductPriceController{
+		createUseCase:  createUseCase,
+		resolveUseCase: resolveUseCase,
+		guard:          guard,
+	}
+}
+
+func (c *ProductPriceController) Create(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if !c.guard.product(w, r, productUUID, canWrite) {
+		return
+	}
+
+	var req dtos.CreateProductPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	price, err := c.createUseCase.Create(productUUID, req.VariantUUID, req.PriceListUUID, req.Coin, req.Amount, req.CompareAtAmount, req.SaleAmount, req.SaleStartsAt, req.SaleEndsAt)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("R11-005", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, productPriceToResponse(price))
+}
+
+func (c *ProductPriceController) ListByProduct(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.ListByProduct(productUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, productPriceToResponse))
+}
+
+func (c *ProductPriceController) Update(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canUpdate); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	var req dtos.UpdateProductPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	price, err := c.createUseCase.Update(uuidStr, req.Amount, req.CompareAtAmount, req.SaleAmount, req.SaleStartsAt, req.SaleEndsAt)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("R11-005", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, productPriceToResponse(price))
+}
+
+func (c *ProductPriceController) Delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canDelete); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if err := c.createUseCase.Delete(uuidStr); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "product price not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (c *ProductPriceController) Resolve(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	// Customer groups belong to a website, so without one only the prices
+	// open to everyone apply.
+	websiteUUIDStr := r.Header.Get("X-Website-UUID")
+	if websiteUUIDStr == "" {
+		resolved, err := c.resolveUseCase.Resolve(productUUID, query.Get("variant_uuid"), query.Get("coin"), "")
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, errorResponse("R11-005", err.Error()))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resolvedPriceToResponse(resolved))
+		return
+	}
+
+	customerGroup, err := c.resolveUseCase.CustomerGroup(websiteUUIDStr, middleware.GetUserUUID(r))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	display, err := c.resolveUseCase.ResolveForDisplay(websiteUUIDStr, productUUID, query.Get("variant_uuid"), query.Get("coin"), customerGroup)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-005", err.Error()))
+		return
+	}
+
+	resp := resolvedPriceToResponse(display.Resolved)
+	resp.DisplayCoin = string(display.DisplayCoin)
+	resp.DisplayAmount = &display.DisplayAmount
+	resp.DisplayCompareAtAmount = display.DisplayCompareAtAmount
+	resp.Converted = display.Converted
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func resolvedPriceToResponse(resolved *domain.ResolvedPrice) dtos.ResolvedPriceResponse {
+	resp := dtos.ResolvedPriceResponse{
+		PriceUUID:       resolved.PriceUUID.String(),
+		Coin:            string(resolved.Coin),
+		Amount:          resolved.Amount,
+		CompareAtAmount: resolved.CompareAtAmount,
+		OnSale:          resolved.OnSale,
+	}
+
+	if resolved.PriceListUUID != nil {
+		resp.PriceListUUID = resolved.PriceListUUID.String()
+	}
+
+	return resp
+}
+
+func productPriceToResponse(p *domain.ProductPrice) dtos.ProductPriceResponse {
+	resp := dtos.ProductPriceResponse{
+		UUID:            p.UUID.String(),
+		ProductUUID:     p.ProductUUID.String(),
+		Coin:            string(p.Coin),
+		Amount:          p.Amount,
+		CompareAtAmount: p.CompareAtAmount,
+		SaleAmount:      p.SaleAmount,
+		SaleStartsAt:    p.SaleStartsAt,
+		SaleEndsAt:      p.SaleEndsAt,
+		CreatedAt:       p.CreatedAt.String(),
+	}
+
+	if p.VariantUUID != nil {
+		resp.VariantUUID = p.VariantUUID.String()
+	}
+
+	if p.PriceListUUID != nil {
+		resp.PriceListUUID = p.PriceListUUID.String()
+	}
+
+	return resp
+}
@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+type ProductReviewController struct {
+	reviewUseCase *usecases.ProductReviewUseCase
+	guard         *WebsiteGuard
+}
+
+func NewProductReviewController(reviewUseCase *usecases.ProductReviewUseCase, guard *WebsiteGuard) *ProductReviewController {
+	return &ProductReviewController{
+		reviewUseCase: reviewUseCase,
+		guard:         guard,
+	}
+}
+
+// Submit accepts a multipart form with rating, title and body fields and up
+// to five "photo" parts.
+func (c *ProductReviewController) Submit(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	maxBytes := c.reviewUseCase.MaxPhotoBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes*domain.MaxReviewPhotos+(1<<20))
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid multipart body"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	rating, err := strconv.Atoi(r.FormValue("rating"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "rating must be a number"))
+		return
+	}
+
+	files := r.MultipartForm.File["photo"]
+	if len(files) > domain.MaxReviewPhotos {
+		writeJSON(w, http.StatusBadRequest, errorResponse("R8-004", "too many files"))
+		return
+	}
+
+	photos := make([]usecases.ReviewPhotoUpload, 0, len(files))
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("R8-004", "upload failed"))
+			return
+		}
+		defer file.Close()
+
+		photos = append(photos, usecases.ReviewPhotoUpload{File: file, Size: header.Size})
+	}
+
+	review, err := c.reviewUseCase.Submit(r.Context(), productUUID, userUUID, rating, r.FormValue("title"), r.FormValue("body"), photos)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrReviewRateLimited):
+			writeJSON(w, http.StatusTooManyRequests, errorResponse("R13-001", "rate limit exceeded"))
+		case errors.Is(err, domain.ErrReviewExists):
+			writeJSON(w, http.StatusConflict, errorResponse("R11-012", "product already reviewed"))
+		case errors.Is(err, usecases.ErrMediaTooLarge):
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse("R8-002", "file too large"))
+		case errors.Is(err, usecases.ErrMediaInvalidType):
+			writeJSON(w, http.StatusUnsupportedMediaType, errorResponse("R8-003", "invalid file type"))
+		case errors.Is(err, usecases.ErrMediaUpload):
+			writeJSON(w, http.StatusBadGateway, errorResponse("R8-004", "upload failed"))
+		default:
+			writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, c.toResponse(review))
+}
+
+// ListByProduct returns the approved reviews of a product for the storefront.
+func (c *ProductReviewController) ListByProduct(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.reviewUseCase.ListByProduct(productUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
+}
+
+func (c *ProductReviewController) Summary(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	summary, err := c.reviewUseCase.Summary(productUUID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-001", "product not found"))
+		return
+	}
+
+	distribution := make(map[string]int, 5)
+	for stars := 1; stars <= 5; stars++ {
+		distribution[strconv.Itoa(stars)] = summary.Distribution[stars]
+	}
+
+	writeJSON(w, http.StatusOK, dtos.ProductRatingSummaryResponse{
+		ProductUUID:  summary.ProductUUID.String(),
+		Count:        summary.Count,
+		Average:      summary.Average,
+		Distribution: distribution,
+	})
+}
+
+// ListForModeration is the merchant queue of the website of the request.
+// Filter with ?status=pending to see what still needs a decision.
+func (c *ProductReviewController) ListForModeration(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.reviewUseCase.ListForModeration(websiteUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
+}
+
+func (c *ProductReviewController) Approve(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+	userUUID := middleware.GetUserUUID(r)
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	review, err := c.reviewUseCase.Approve(r.Context(), websiteUUID, uuidStr, userUUID)
+	if err != nil {
+		c.writeModerationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(review))
+}
+
+func (c *ProductReviewController) Reject(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canUpdate)
+	if !ok {
+		return
+	}
+	userUUID := middleware.GetUserUUID(r)
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	var req dtos.RejectProductReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	review, err := c.reviewUseCase.Reject(r.Context(), websiteUUID, uuidStr, req.Reason, userUUID)
+	if err != nil {
+		c.writeModerationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(review))
+}
+
+// Photo serves a review photo to the moderators of the website, also before
+// the review is approved and the photo published under /media.
+func (c *ProductReviewController) Photo(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := c.guard.website(w, r, canRead)
+	if !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	photo, body, err := c.reviewUseCase.Photo(r.Context(), websiteUUID, uuidStr)
+	if err != nil {
+		if errors.Is(err, usecases.ErrReviewNotFound) {
+			writeJSON(w, http.StatusNotFound, errorResponse("R11-013", "product review not found"))
+			return
+		}
+		logger.Warn(err).Print()
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", photo.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(photo.SizeBytes, 10))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, body); err != nil {
+		logger.Warn(err).Print()
+	}
+}
+
+func (c *ProductReviewController) writeModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrReviewNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-013", "product review not found"))
+	case errors.Is(err, domain.ErrReviewOwnModeration):
+		writeJSON(w, http.StatusForbidden, errorResponse("R11-015", "own review cannot be moderated"))
+	case errors.Is(err, usecases.ErrReviewPublish):
+		writeJSON(w, http.StatusBadGateway, errorResponse("R8-004", "upload failed"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func (c *ProductReviewController) toResponse(review *domain.ProductReview) dtos.ProductReviewResponse {
+	photos := make([]dtos.ProductReviewPhotoResponse, 0, len(review.Photos))
+	for _, p := range review.Photos {
+		url := "/reviews/photos/" + p.UUID.String()
+		if p.Published() {
+			url = c.reviewUseCase.URL(p.BlobKey)
+		}
+		photos = append(photos, dtos.ProductReviewPhotoResponse{
+			UUID:        p.UUID.String(),
+			URL:         url,
+			ContentType: p.ContentType,
+			SizeBytes:   p.SizeBytes,
+			Position:    p.Position,
+		})
+	}
+
+	response := dtos.ProductReviewResponse{
+		UUID:            review.UUID.String(),
+		ProductUUID:     review.ProductUUID.String(),
+		UserUUID:        review.UserUUID.String(),
+		Rating:          review.Rating,
+		Title:           review.Title,
+		Body:            review.Body,
+		Verified:        review.Verified,
+		Status:          string(review.Status),
+		RejectionReason: review.RejectionReason,
+		Photos:          photos,
+		CreatedAt:       review.CreatedAt.String(),
+	}
+	if review.ModeratedBy != nil {
+		response.ModeratedBy = review.ModeratedBy.String()
+	}
+	if review.ModeratedAt != nil {
+		response.ModeratedAt = review.ModeratedAt.String()
+	}
+
+	return response
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type ProductSearchController struct {
+	searchUseCase *usecases.SearchProductsUseCase
+}
+
+func NewProductSearchController(searchUseCase *usecases.SearchProductsUseCase) *ProductSearchController {
+	return &ProductSearchController{
+		searchUseCase: searchUseCase,
+	}
+}
+
+// Search looks through the catalog of the X-Website-UUID website. It reads
+// q, tags (comma separated), category, coin, min_price, max_price,
+// in_stock, limit and offset from the query string.
+func (c *ProductSearchController) Search(w http.ResponseWriter, r *http.Request) {
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	minPrice, ok := optionalIntParam(w, params.Get("min_price"), "min_price")
+	if !ok {
+		return
+	}
+
+	maxPrice, ok := optionalIntParam(w, params.Get("max_price"), "max_price")
+	if !ok {
+		return
+	}
+
+	var inStock *bool
+	if raw := params.Get("in_stock"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "invalid in_stock"))
+			return
+		}
+		inStock = &parsed
+	}
+
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	offset, _ := strconv.Atoi(params.Get("offset"))
+
+	var tags []string
+	if raw := params.Get("tags"); raw != "" {
+		tags = strings.Split(raw, ",")
+	}
+
+	result, err := c.searchUseCase.Search(websiteUUID, params.Get("q"), tags, params.Get("category"), params.Get("coin"), minPrice, maxPrice, inStock, limit, offset)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, productSearchToResponse(result))
+}
+
+func optionalIntParam(w http.ResponseWriter, raw string, name string) (*int, bool) {
+	if raw == "" {
+		return nil, true
+	}
+
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "invalid "+name))
+		return nil, false
+	}
+
+	return &parsed, true
+}
+
+func productSearchToResponse(result *domain.ProductSearchResult) dtos.ProductSearchResponse {
+	hits := make([]dtos.ProductSearchHitResponse, 0, len(result.Hits))
+	for _, h := range result.Hits {
+		hits = append(hits, dtos.ProductSearchHitResponse{
+			Product:       productToResponse(h.Product),
+			Rank:          h.Rank,
+			NameHighlight: h.NameHighlight,
+			Snippet:       h.Snippet,
+			Price:         h.Price,
+			InStock:       h.InStock,
+		})
+	}
+
+	return dtos.ProductSearchResponse{
+		Query:  result.Query.Text,
+		Coin:   string(result.Query.Coin),
+		Total:  result.Total,
+		Limit:  result.Query.Limit,
+		Offset: result.Query.Offset,
+		Hits:   hits,
+		Facets: dtos.ProductSearchFacetsResponse{
+			Tags:       facetCountsToResponse(result.Facets.Tags),
+			Categories: facetCountsToResponse(result.Facets.Categories),
+			Price: dtos.PriceRangeFacetResponse{
+				Min: result.Facets.PriceMin,
+				Max: result.Facets.PriceMax,
+			},
+			Stock: dtos.StockFacetResponse{
+				InStock:    result.Facets.InStock,
+				OutOfStock: result.Facets.OutOfStock,
+			},
+		},
+	}
+}
+
+func facetCountsToResponse(facets []domain.FacetCount) []dtos.FacetCountResponse {
+	responses := make([]dtos.FacetCountResponse, 0, len(facets))
+	for _, f := range facets {
+		responses = append(responses, dtos.FacetCountResponse{
+			Value: f.Value,
+			Label: f.Label,
+			Count: f.Count,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type ProductVariantController struct {
+	createUseCase *usecases.CreateProductVariantUseCase
+	guard         *WebsiteGuard
+}
+
+func NewProductVariantController(createUseCase *usecases.CreateProductVariantUseCase, guard *WebsiteGuard) *ProductVariantController {
+	return &ProductVariantController{
+		createUseCase: createUseCase,
+		guard:         guard,
+	}
+}
+
+func (c *ProductVariantController) Create(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if !c.guard.product(w, r, productUUID, canWrite) {
+		return
+	}
+
+	var req dtos.CreateProductVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	variant, err := c.createUseCase.Create(productUUID, req.SKU, req.Label, req.GTIN, req.Active)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, productVariantToResponse(variant))
+}
+
+func (c *ProductVariantController) ListByProduct(w http.ResponseWriter, r *http.Request) {
+	productUUID := r.PathValue("uuid")
+	if productUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.ListByProduct(productUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, productVariantToResponse))
+}
+
+func (c *ProductVariantController) Delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.guard.website(w, r, canDelete); !ok {
+		return
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return
+	}
+
+	if err := c.createUseCase.Delete(uuidStr); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RAX-005", "product variant not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func productVariantToResponse(v *domain.ProductVariant) dtos.ProductVariantResponse {
+	return dtos.ProductVariantResponse{
+		UUID:        v.UUID.String(),
+		ProductUUID: v.ProductUUID.String(),
+		SKU:         v.SKU,
+		Label:       v.Label,
+		GTIN:        v.GTIN,
+		Active:      v.Active,
+		CreatedAt:   v.CreatedAt.String(),
+	}
+}
@@ -87,19 +87,19 @@ func (c *RbacController) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rbacs, err := c.createUseCase.GetAll(websiteUUIDStr)
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.RbacResponse, 0, len(rbacs))
-	for _, rbac := range rbacs {
-		resp = append(resp, c.toResponse(rbac))
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *RbacController) toResponse(rbac *domain.Rbac) dtos.RbacResponse {
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+)
+
+type SearchSuggestionController struct {
+	suggestionUseCase *usecases.SearchSuggestionUseCase
+}
+
+func NewSearchSuggestionController(suggestionUseCase *usecases.SearchSuggestionUseCase) *SearchSuggestionController {
+	return &SearchSuggestionController{
+		suggestionUseCase: suggestionUseCase,
+	}
+}
+
+// Suggest is public and answers for the storefront of the X-Website-UUID
+// header.
+func (c *SearchSuggestionController) Suggest(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	suggestions, err := c.suggestionUseCase.Suggest(r.URL.Query().Get("q"), websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	writeJSON(w, http.StatusOK, searchSuggestionsToResponse(suggestions))
+}
+
+// Popular is public like Suggest and answers for the website of the
+// X-Website-UUID header.
+func (c *SearchSuggestionController) Popular(w http.ResponseWriter, r *http.Request) {
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	queries, err := c.suggestionUseCase.Popular(websiteUUIDStr, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, searchQueriesToResponse(queries))
+}
+
+func searchSuggestionsToResponse(s *domain.SearchSuggestions) dtos.SearchSuggestionsResponse {
+	products := make([]dtos.ProductSuggestionResponse, 0, len(s.Products))
+	for _, p := range s.Products {
+		products = append(products, dtos.ProductSuggestionResponse{
+			UUID: p.UUID.String(),
+			Name: p.Name,
+		})
+	}
+
+	categories := make([]dtos.CategorySuggestionResponse, 0, len(s.Categories))
+	for _, c := range s.Categories {
+		categories = append(categories, dtos.CategorySuggestionResponse{
+			UUID: c.UUID.String(),
+			Name: c.Name,
+			Slug: c.Slug,
+		})
+	}
+
+	return dtos.SearchSuggestionsResponse{
+		Query:      s.Query,
+		Products:   products,
+		Categories: categories,
+		Queries:    searchQueriesToResponse(s.Queries),
+	}
+}
+
+func searchQueriesToResponse(queries []*domain.SearchQuery) []dtos.SearchQueryResponse {
+	responses := make([]dtos.SearchQueryResponse, 0, len(queries))
+	for _, q := range queries {
+		responses = append(responses, dtos.SearchQueryResponse{
+			Query:          q.Query,
+			Searches:       q.Searches,
+			LastResults:    q.LastResults,
+			LastSearchedAt: q.LastSearchedAt.String(),
+		})
+	}
+	return responses
+}
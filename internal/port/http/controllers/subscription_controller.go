@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type SubscriptionController struct {
+	subscriptionUseCase *usecases.SubscriptionUseCase
+	guard               *WebsiteGuard
+}
+
+func NewSubscriptionController(subscriptionUseCase *usecases.SubscriptionUseCase, guard *WebsiteGuard) *SubscriptionController {
+	return &SubscriptionController{
+		subscriptionUseCase: subscriptionUseCase,
+		guard:               guard,
+	}
+}
+
+// Subscribe puts the signed-in user on a plan or, with an Organization
+// owner, an organization the user owns or administers.
+func (c *SubscriptionController) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req dtos.SubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	ownerUUID := userUUID
+	if req.OwnerType == string(enums.OrganizationOwner) {
+		ownerUUID = req.OwnerUUID
+		if !c.guard.allowOwner(w, r, ownerUUID) {
+			return
+		}
+	}
+
+	subscription, err := c.subscriptionUseCase.Subscribe(ownerUUID, req.OwnerType, req.PlanUUID, req.Cycle, req.PaymentMethod)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionToResponse(subscription))
+}
+
+// Get shows the subscription of the owner in ?owner_uuid=, by default the
+// signed-in user.
+func (c *SubscriptionController) Get(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.subscriptionUseCase.GetByOwner(ownerUUID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-009", "subscription not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionToResponse(subscription))
+}
+
+// Usage shows what the owner in ?owner_uuid=, by default the signed-in
+// user, consumes against its plan's limits.
+func (c *SubscriptionController) Usage(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := c.subscriptionUseCase.Usage(ownerUUID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	response := dtos.PlanUsageResponse{OwnerUUID: usage.OwnerUUID.String()}
+	if usage.Plan != nil {
+		response.PlanUUID = usage.Plan.UUID.String()
+		response.PlanName = usage.Plan.Name
+		response.Websites = quotaToResponse(usage.Websites, usage.Plan.MaxWebsites)
+		response.Routes = quotaToResponse(usage.Routers, usage.Plan.MaxRouters)
+		response.Products = quotaToResponse(usage.Products, usage.Plan.MaxProducts)
+	} else {
+		response.Websites = dtos.QuotaResponse{Used: usage.Websites, Reached: true}
+		response.Routes = dtos.QuotaResponse{Used: usage.Routers, Reached: true}
+		response.Products = dtos.QuotaResponse{Used: usage.Products, Reached: true}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ChangePlan moves the owner in ?owner_uuid= to another plan, charging or
+// crediting the difference for the rest of the period.
+func (c *SubscriptionController) ChangePlan(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.ChangePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	subscription, err := c.subscriptionUseCase.ChangePlan(ownerUUID, req.PlanUUID, req.Cycle)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionToResponse(subscription))
+}
+
+func (c *SubscriptionController) UpdatePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdatePaymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	subscription, err := c.subscriptionUseCase.UpdatePaymentMethod(ownerUUID, req.PaymentMethod)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionToResponse(subscription))
+}
+
+func (c *SubscriptionController) Cancel(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.subscriptionUseCase.Cancel(ownerUUID)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionToResponse(subscription))
+}
+
+func (c *SubscriptionController) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r, "owner_uuid")
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.subscriptionUseCase.Invoices(ownerUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, subscriptionInvoiceToResponse))
+}
+
+// PayInvoice charges an open invoice again, usually after the payment
+// method was updated.
+func (c *SubscriptionController) PayInvoice(w http.ResponseWriter, r *http.Request) {
+	ownerUUID, ok := c.guard.owner(w, r)
+	if !ok {
+		return
+	}
+
+	invoice, err := c.subscriptionUseCase.PayInvoice(ownerUUID, r.PathValue("uuid"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptionInvoiceToResponse(invoice))
+}
+
+// writeQuotaError answers the errors a create counted against the plan can
+// fail with, code and message naming the limit. It reports whether err was
+// one of them.
+func writeQuotaError(w http.ResponseWriter, err error, code string, message string) bool {
+	switch {
+	case errors.Is(err, domain.ErrNoActivePlan):
+		writeJSON(w, http.StatusPaymentRequired, errorResponse("RDX-008", "no active plan"))
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		writeJSON(w, http.StatusForbidden, errorResponse(code, message))
+	default:
+		return false
+	}
+
+	return true
+}
+
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrSubscriptionNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-009", "subscription not found"))
+	case errors.Is(err, domain.ErrSubscriptionExists):
+		writeJSON(w, http.StatusConflict, errorResponse("R18-001", "subscription already exists"))
+	case errors.Is(err, usecases.ErrPaymentDeclined):
+		writeJSON(w, http.StatusPaymentRequired, errorResponse("R18-002", "payment declined"))
+	case errors.Is(err, usecases.ErrSubscriptionNotActive):
+		writeJSON(w, http.StatusConflict, errorResponse("R18-003", "subscription not active"))
+	case errors.Is(err, usecases.ErrInvoiceNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R18-004", "invoice not found"))
+	case errors.Is(err, domain.ErrInvoiceNotOpen):
+		writeJSON(w, http.StatusConflict, errorResponse("R18-005", "invoice already paid or void"))
+	case errors.Is(err, usecases.ErrPlanCoinMismatch):
+		writeJSON(w, http.StatusConflict, errorResponse("R18-006", "plan currency does not match"))
+	case errors.Is(err, usecases.ErrPaymentMethodMissing):
+		writeJSON(w, http.StatusBadRequest, errorResponse("R18-007", "payment method missing"))
+	case errors.Is(err, usecases.ErrPlanNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R18-008", "plan not found"))
+	case errors.Is(err, usecases.ErrPaymentProviderMissing):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse("RAX-009", "feature unavailable"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func quotaToResponse(used int, limit int) dtos.QuotaResponse {
+	quota := domain.Quota{Limit: limit}
+	return dtos.QuotaResponse{
+		Used:      used,
+		Limit:     limit,
+		Unlimited: limit == 0,
+		Reached:   !quota.Allows(used),
+	}
+}
+
+func subscriptionToResponse(s *domain.Subscription) dtos.SubscriptionResponse {
+	response := dtos.SubscriptionResponse{
+		UUID:              s.UUID.String(),
+		OwnerUUID:         s.OwnerUUID.String(),
+		OwnerType:         string(s.OwnerType),
+		PlanUUID:          s.PlanUUID.String(),
+		Status:            string(s.Status),
+		Cycle:             string(s.Cycle),
+		PaymentMethod:     s.PaymentMethod,
+		CancelAtPeriodEnd: s.CancelAtPeriodEnd,
+		Credit:            s.Credit,
+		CreatedAt:         s.CreatedAt.String(),
+	}
+	if s.TrialEndsAt != nil {
+		response.TrialEndsAt = s.TrialEndsAt.String()
+	}
+	if s.CurrentPeriodStart != nil {
+		response.CurrentPeriodStart = s.CurrentPeriodStart.String()
+	}
+	if s.CurrentPeriodEnd != nil {
+		response.CurrentPeriodEnd = s.CurrentPeriodEnd.String()
+	}
+	if s.PastDueSince != nil {
+		response.PastDueSince = s.PastDueSince.String()
+	}
+	if s.UpdatedAt != nil {
+		response.UpdatedAt = s.UpdatedAt.String()
+	}
+
+	return response
+}
+
+func subscriptionInvoiceToResponse(i *domain.SubscriptionInvoice) dtos.SubscriptionInvoiceResponse {
+	response := dtos.SubscriptionInvoiceResponse{
+		UUID:              i.UUID.String(),
+		SubscriptionUUID:  i.SubscriptionUUID.String(),
+		PlanUUID:          i.PlanUUID.String(),
+		Reason:            string(i.Reason),
+		Cycle:             string(i.Cycle),
+		Coin:              string(i.Coin),
+		Amount:            i.Amount,
+		CreditApplied:     i.CreditApplied,
+		Status:            string(i.Status),
+		Attempts:          i.Attempts,
+		PeriodStart:       i.PeriodStart.String(),
+		PeriodEnd:         i.PeriodEnd.String(),
+		ProviderReference: i.ProviderReference,
+		FailureReason:     i.FailureReason,
+		CreatedAt:         i.CreatedAt.String(),
+	}
+	if i.NextAttemptAt != nil {
+		response.NextAttemptAt = i.NextAttemptAt.String()
+	}
+	if i.PaidAt != nil {
+		response.PaidAt = i.PaidAt.String()
+	}
+
+	return response
+}
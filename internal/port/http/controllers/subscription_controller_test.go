@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/google/uuid"
+)
+
+// noOrganizations knows no organization, so every owner but the signed-in
+// user is someone else's.
+type noOrganizations struct {
+	contracts.OrganizationContract
+}
+
+func (noOrganizations) FindOrganizationByUUID(uuid string) (*domain.OrganizationBR, error) {
+	return nil, errors.New("organization not found")
+}
+
+func TestSubscriptionControllerForbidden(t *testing.T) {
+	user := uuid.New()
+	other := uuid.New()
+
+	members := usecases.NewOrganizationMemberUseCase(nil, noOrganizations{}, nil, nil, nil, nil, nil, "")
+	controller := NewSubscriptionController(nil, NewWebsiteGuard(members, nil))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		target  string
+		body    string
+	}{
+		{name: "get another owner", handler: controller.Get, method: http.MethodGet, target: "/subscriptions?owner_uuid=" + other.String()},
+		{name: "usage of another owner", handler: controller.Usage, method: http.MethodGet, target: "/subscriptions/usage?owner_uuid=" + other.String()},
+		{name: "cancel another owner", handler: controller.Cancel, method: http.MethodPost, target: "/subscriptions/cancel?owner_uuid=" + other.String()},
+		{name: "invoices of another owner", handler: controller.ListInvoices, method: http.MethodGet, target: "/subscriptions/invoices?owner_uuid=" + other.String()},
+		{name: "subscribe an organization the user is not in", handler: controller.Subscribe, method: http.MethodPost, target: "/subscriptions", body: `{"owner_uuid":"` + other.String() + `","owner_type":"Organization"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserUUIDKey, user.String()))
+			w := httptest.NewRecorder()
+
+			tt.handler(w, r)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+			}
+		})
+	}
+}
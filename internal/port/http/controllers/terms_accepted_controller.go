@@ -84,19 +84,19 @@ func (c *TermsAcceptedController) GetAll(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	termsAccepteds, err := c.createUseCase.GetAll(websiteUUIDStr)
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.TermsAcceptedResponse, 0, len(termsAccepteds))
-	for _, ta := range termsAccepteds {
-		resp = append(resp, c.toResponse(ta))
+	page, err := c.createUseCase.GetAll(websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *TermsAcceptedController) toResponse(ta *domain.TermsAcceptedBy) dtos.TermsAcceptedResponse {
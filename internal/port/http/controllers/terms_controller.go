@@ -72,19 +72,19 @@ func (c *TermsController) GetByUUID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *TermsController) GetAll(w http.ResponseWriter, r *http.Request) {
-	terms, err := c.createUseCase.GetAll()
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeListError(w, err)
 		return
 	}
 
-	resp := make([]dtos.TermsResponse, 0, len(terms))
-	for _, t := range terms {
-		resp = append(resp, c.toResponse(t))
+	page, err := c.createUseCase.GetAll(query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	writeJSON(w, http.StatusOK, pageToResponse(page, c.toResponse))
 }
 
 func (c *TermsController) toResponse(terms *domain.Terms) dtos.TermsResponse {
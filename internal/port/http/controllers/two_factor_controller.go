@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/ViitoJooj/verkoupe/pkg/totp"
+)
+
+type TwoFactorController struct {
+	twoFactorUseCase *usecases.TwoFactorUseCase
+}
+
+func NewTwoFactorController(twoFactorUseCase *usecases.TwoFactorUseCase) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorUseCase: twoFactorUseCase,
+	}
+}
+
+func (c *TwoFactorController) Status(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	enabledAt, left, err := c.twoFactorUseCase.Status(userUUID)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	resp := dtos.TwoFactorStatusResponse{
+		Enabled:           enabledAt != nil,
+		RecoveryCodesLeft: left,
+	}
+	if enabledAt != nil {
+		resp.EnabledAt = enabledAt.String()
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Enroll hands out a new secret to add to an authenticator app, as text,
+// otpauth URI and QR code. It is enabled by Enable.
+func (c *TwoFactorController) Enroll(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	enrollment, err := c.twoFactorUseCase.Enroll(userUUID)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.TwoFactorEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+		QRCode:     "data:" + totp.QRContentType + ";base64," + base64.StdEncoding.EncodeToString(enrollment.QR),
+	})
+}
+
+// Enable answers with the recovery codes; they are not shown again.
+func (c *TwoFactorController) Enable(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	codes, err := c.twoFactorUseCase.Enable(userUUID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (c *TwoFactorController) Disable(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if err := c.twoFactorUseCase.Disable(userUUID, req.Code, clientIP(r)); err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "disabled"})
+}
+
+func (c *TwoFactorController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	var req dtos.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	codes, err := c.twoFactorUseCase.RegenerateRecoveryCodes(userUUID, req.Code, clientIP(r))
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (c *TwoFactorController) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	devices, err := c.twoFactorUseCase.TrustedDevices(userUUID)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	responses := make([]dtos.TrustedDeviceResponse, 0, len(devices))
+	for _, device := range devices {
+		responses = append(responses, trustedDeviceToResponse(device))
+	}
+
+	writeJSON(w, http.StatusOK, responses)
+}
+
+func (c *TwoFactorController) RevokeDevice(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	if err := c.twoFactorUseCase.RevokeTrustedDevice(userUUID, r.PathValue("uuid")); err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+func writeTwoFactorError(w http.ResponseWriter, err error) {
+	var throttle *domain.LoginThrottleError
+	if errors.As(err, &throttle) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttle.RetryAfter.Seconds()))))
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled):
+		writeJSON(w, http.StatusConflict, errorResponse("R21-001", "two-factor authentication not enabled"))
+	case errors.Is(err, domain.ErrTwoFactorEnabled):
+		writeJSON(w, http.StatusConflict, errorResponse("R21-002", "two-factor authentication already enabled"))
+	case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+		writeJSON(w, http.StatusConflict, errorResponse("R21-003", "two-factor enrollment not started"))
+	case errors.Is(err, domain.ErrTwoFactorCode):
+		writeJSON(w, http.StatusUnauthorized, errorResponse("R21-004", "invalid two-factor code"))
+	case errors.Is(err, domain.ErrAccountLocked):
+		writeJSON(w, http.StatusLocked, errorResponse("RBX-016", "account locked"))
+	case errors.Is(err, domain.ErrTooManyLoginAttempts):
+		writeJSON(w, http.StatusTooManyRequests, errorResponse("RBX-017", "too many login attempts"))
+	case errors.Is(err, usecases.ErrTrustedDeviceNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R21-005", "trusted device not found"))
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+	}
+}
+
+func trustedDeviceToResponse(d *domain.TrustedDevice) dtos.TrustedDeviceResponse {
+	response := dtos.TrustedDeviceResponse{
+		UUID:      d.UUID.String(),
+		Name:      d.Name,
+		ExpiresAt: d.ExpiresAt.String(),
+		CreatedAt: d.CreatedAt.String(),
+	}
+
+	if d.LastUsedAt != nil {
+		response.LastUsedAt = d.LastUsedAt.String()
+	}
+
+	return response
+}
@@ -39,6 +39,9 @@ func (c *WebsiteComponentController) Create(w http.ResponseWriter, r *http.Reque
 	}
 
 	component, err := c.createUseCase.Create(req.WebsiteUUID, req.LogoURL, req.Tittle, req.Description, req.Path, req.Content, req.Visits, tenantWebsiteUUID)
+	if writeQuotaError(w, err, "RDX-007", "route limit reached") {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusConflict)
 		return
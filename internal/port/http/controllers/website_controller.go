@@ -13,11 +13,15 @@ import (
 
 type WebsiteController struct {
 	createUseCase *usecases.CreateWebsiteUseCase
+	memberUseCase *usecases.OrganizationMemberUseCase
+	guard         *WebsiteGuard
 }
 
-func NewWebsiteController(createUseCase *usecases.CreateWebsiteUseCase) *WebsiteController {
+func NewWebsiteController(createUseCase *usecases.CreateWebsiteUseCase, memberUseCase *usecases.OrganizationMemberUseCase, guard *WebsiteGuard) *WebsiteController {
 	return &WebsiteController{
 		createUseCase: createUseCase,
+		memberUseCase: memberUseCase,
+		guard:         guard,
 	}
 }
 
@@ -28,7 +32,10 @@ func (c *WebsiteController) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	website, err := c.createUseCase.Create(req.OwnerUUID, req.OwnerType, req.Label, req.URL, req.WriteIn, req.Description)
+	website, err := c.createUseCase.Create(req.OwnerUUID, req.OwnerType, req.Label, req.URL, req.WriteIn, req.Description, req.BaseCoin)
+	if writeQuotaError(w, err, "RDX-006", "website limit reached") {
+		return
+	}
 	if err != nil {
 		writeJSON(w, http.StatusConflict, errorResponse("RDX-002", err.Error()))
 		return
@@ -53,48 +60,62 @@ func (c *WebsiteController) GetByUUID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, websiteToResponse(website))
 }
 
-func (c *WebsiteController) ListByOwner(w http.ResponseWriter, r *http.Request) {
+// Permissions shows what the signed-in user may do on the website, either
+// as its owner or through an organization owning it.
+func (c *WebsiteController) Permissions(w http.ResponseWriter, r *http.Request) {
 	userUUID := middleware.GetUserUUID(r)
 	if userUUID == "" {
 		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
 		return
 	}
 
-	websites, err := c.createUseCase.ListByOwner(userUUID)
+	permissions, err := c.memberUseCase.WebsitePermissions(userUUID, r.PathValue("uuid"))
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		writeOrganizationMemberError(w, err)
 		return
 	}
 
-	if websites == nil {
-		websites = []*domain.Website{}
+	writeJSON(w, http.StatusOK, websitePermissionsToResponse(permissions))
+}
+
+// ListByOwner lists the websites of the signed-in user and of the
+// organizations they are a member of.
+func (c *WebsiteController) ListByOwner(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
 	}
 
-	responses := make([]dtos.WebsiteResponse, 0, len(websites))
-	for _, w := range websites {
-		responses = append(responses, websiteToResponse(w))
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.createUseCase.ListByOwner(userUUID, query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	writeJSON(w, http.StatusOK, responses)
+	writeJSON(w, http.StatusOK, pageToResponse(page, websiteToResponse))
 }
 
 func (c *WebsiteController) ListAll(w http.ResponseWriter, r *http.Request) {
-	websites, err := c.createUseCase.ListAll()
+	query, err := listQueryFromRequest(r)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		writeListError(w, err)
 		return
 	}
 
-	if websites == nil {
-		websites = []*domain.Website{}
-	}
-
-	responses := make([]dtos.WebsiteResponse, 0, len(websites))
-	for _, w := range websites {
-		responses = append(responses, websiteToResponse(w))
+	page, err := c.createUseCase.ListAll(query)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	writeJSON(w, http.StatusOK, responses)
+	writeJSON(w, http.StatusOK, pageToResponse(page, websiteToResponse))
 }
 
 func (c *WebsiteController) Update(w http.ResponseWriter, r *http.Request) {
@@ -109,8 +130,7 @@ func (c *WebsiteController) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := c.createUseCase.GetByUUID(uuidStr); err != nil {
-		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+	if !c.guard.allow(w, r, uuidStr, canUpdate) {
 		return
 	}
 
@@ -122,6 +142,72 @@ func (c *WebsiteController) Update(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "updated"})
 }
 
+// UpdateEmailVerification sets whether users must verify their email
+// before signing in to the website.
+func (c *WebsiteController) UpdateEmailVerification(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDX-003", "invalid uuid"))
+		return
+	}
+
+	var req dtos.UpdateEmailVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if !c.guard.allow(w, r, uuidStr, canUpdate) {
+		return
+	}
+
+	if err := c.createUseCase.SetEmailVerification(uuidStr, req.Required); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+		return
+	}
+
+	website, err := c.createUseCase.GetByUUID(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, websiteToResponse(website))
+}
+
+// UpdateTwoFactor sets whether roles that may write or delete on the
+// website need two-factor authentication to do so.
+func (c *WebsiteController) UpdateTwoFactor(w http.ResponseWriter, r *http.Request) {
+	uuidStr := r.PathValue("uuid")
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDX-003", "invalid uuid"))
+		return
+	}
+
+	var req dtos.UpdateTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	if !c.guard.allow(w, r, uuidStr, canUpdate) {
+		return
+	}
+
+	if err := c.createUseCase.SetTwoFactor(uuidStr, req.Required); err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+		return
+	}
+
+	website, err := c.createUseCase.GetByUUID(uuidStr)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, websiteToResponse(website))
+}
+
 func (c *WebsiteController) Delete(w http.ResponseWriter, r *http.Request) {
 	uuidStr := r.PathValue("uuid")
 	if uuidStr == "" {
@@ -129,6 +215,10 @@ func (c *WebsiteController) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !c.guard.allow(w, r, uuidStr, canDelete) {
+		return
+	}
+
 	if err := c.createUseCase.Delete(uuidStr); err != nil {
 		writeJSON(w, http.StatusNotFound, errorResponse("RDX-001", "website not found"))
 		return
@@ -138,14 +228,23 @@ func (c *WebsiteController) Delete(w http.ResponseWriter, r *http.Request) {
 }
 
 func websiteToResponse(w *domain.Website) dtos.WebsiteResponse {
+	suspendedAt := ""
+	if w.SuspendedAt != nil {
+		suspendedAt = w.SuspendedAt.String()
+	}
+
 	return dtos.WebsiteResponse{
-		UUID:        w.UUID.String(),
-		OwnerUUID:   w.OwnerUUID.String(),
-		OwnerType:   string(w.OwnerType),
-		Label:       w.Label,
-		URL:         w.URL,
-		WriteIn:     string(w.WriteIn),
-		Description: w.Description,
-		CreatedAt:   w.CreatedAt.String(),
+		UUID:                     w.UUID.String(),
+		OwnerUUID:                w.OwnerUUID.String(),
+		OwnerType:                string(w.OwnerType),
+		Label:                    w.Label,
+		URL:                      w.URL,
+		WriteIn:                  string(w.WriteIn),
+		Description:              w.Description,
+		BaseCoin:                 string(w.BaseCoin),
+		SuspendedAt:              suspendedAt,
+		RequireEmailVerification: w.RequireEmailVerification,
+		RequireTwoFactor:         w.RequireTwoFactor,
+		CreatedAt:                w.CreatedAt.String(),
 	}
 }
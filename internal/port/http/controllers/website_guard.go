@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+	"github.com/google/uuid"
+)
+
+// WebsiteGuard checks what the signed-in user may do on a website. Every
+// merchant endpoint goes through it, so a website requiring two-factor
+// authentication holds back writes everywhere alike.
+type WebsiteGuard struct {
+	memberUseCase  *usecases.OrganizationMemberUseCase
+	productUseCase *usecases.CreateProductUseCase
+}
+
+func NewWebsiteGuard(memberUseCase *usecases.OrganizationMemberUseCase, productUseCase *usecases.CreateProductUseCase) *WebsiteGuard {
+	return &WebsiteGuard{
+		memberUseCase:  memberUseCase,
+		productUseCase: productUseCase,
+	}
+}
+
+func canRead(p *domain.WebsitePermissions) bool   { return p.CanRead }
+func canWrite(p *domain.WebsitePermissions) bool  { return p.CanWrite }
+func canUpdate(p *domain.WebsitePermissions) bool { return p.CanUpdate }
+func canDelete(p *domain.WebsitePermissions) bool { return p.CanDelete }
+
+// allow answers the request and returns false unless the signed-in user
+// has the permission can checks on the website.
+func (g *WebsiteGuard) allow(w http.ResponseWriter, r *http.Request, websiteUUID string, can func(*domain.WebsitePermissions) bool) bool {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return false
+	}
+
+	permissions, err := g.memberUseCase.WebsitePermissions(userUUID, websiteUUID)
+	if err != nil {
+		writeOrganizationMemberError(w, err)
+		return false
+	}
+
+	if !can(permissions) {
+		if permissions.TwoFactorRequired {
+			writeJSON(w, http.StatusForbidden, errorResponse("R21-006", "two-factor authentication required"))
+			return false
+		}
+		writeJSON(w, http.StatusForbidden, errorResponse("R10-002", "insufficient permissions"))
+		return false
+	}
+
+	return true
+}
+
+// website checks the website named by the X-Website-UUID header.
+func (g *WebsiteGuard) website(w http.ResponseWriter, r *http.Request, can func(*domain.WebsitePermissions) bool) (string, bool) {
+	websiteUUID, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return "", false
+	}
+
+	return websiteUUID, g.allow(w, r, websiteUUID, can)
+}
+
+// owner checks the owner in ?owner_uuid=, by default the signed-in user,
+// for endpoints acting on everything an owner has, like its plan.
+func (g *WebsiteGuard) owner(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ownerUUID, ok := requireOwnerUUID(w, r)
+	if !ok {
+		return "", false
+	}
+
+	return ownerUUID, g.allowOwner(w, r, ownerUUID)
+}
+
+// allowOwner answers the request and returns false unless the signed-in
+// user may act for ownerUUID.
+func (g *WebsiteGuard) allowOwner(w http.ResponseWriter, r *http.Request, ownerUUID string) bool {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return false
+	}
+
+	if err := g.memberUseCase.ManageOwner(userUUID, ownerUUID); err != nil {
+		writeOrganizationMemberError(w, err)
+		return false
+	}
+
+	return true
+}
+
+// product checks the website of the header and that the product belongs to
+// it.
+func (g *WebsiteGuard) product(w http.ResponseWriter, r *http.Request, productUUID string, can func(*domain.WebsitePermissions) bool) bool {
+	websiteUUID, ok := g.website(w, r, can)
+	if !ok {
+		return false
+	}
+
+	product, err := g.productUseCase.FindByUUID(productUUID)
+	if err != nil || product.WebsiteUUID == nil || product.WebsiteUUID.String() != websiteUUID {
+		writeJSON(w, http.StatusNotFound, errorResponse("R11-001", "product not found"))
+		return false
+	}
+
+	return true
+}
+
+func requireOwnerUUID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ownerUUID := r.URL.Query().Get("owner_uuid")
+	if ownerUUID == "" {
+		ownerUUID = middleware.GetUserUUID(r)
+	}
+
+	if ownerUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return "", false
+	}
+
+	if _, err := uuid.Parse(ownerUUID); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "invalid owner uuid"))
+		return "", false
+	}
+
+	return ownerUUID, true
+}
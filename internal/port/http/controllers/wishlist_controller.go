@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/usecases"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/dtos"
+	"github.com/ViitoJooj/verkoupe/internal/port/http/middleware"
+)
+
+type WishlistController struct {
+	wishlistUseCase *usecases.WishlistUseCase
+}
+
+func NewWishlistController(wishlistUseCase *usecases.WishlistUseCase) *WishlistController {
+	return &WishlistController{
+		wishlistUseCase: wishlistUseCase,
+	}
+}
+
+func (c *WishlistController) Create(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	wishlist, err := c.wishlistUseCase.Create(userUUID, websiteUUIDStr, req.Name)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, c.toResponse(wishlist))
+}
+
+func (c *WishlistController) ListMine(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	wishlists, err := c.wishlistUseCase.ListByUser(userUUID, websiteUUIDStr)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse("RAX-001", "internal error"))
+		return
+	}
+
+	responses := make([]dtos.WishlistResponse, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		responses = append(responses, c.toResponse(wishlist))
+	}
+
+	writeJSON(w, http.StatusOK, responses)
+}
+
+func (c *WishlistController) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.wishlistUseCase.Get(uuidStr, userUUID)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(wishlist))
+}
+
+func (c *WishlistController) Update(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	wishlist, err := c.wishlistUseCase.Rename(uuidStr, userUUID, req.Name)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(wishlist))
+}
+
+func (c *WishlistController) Delete(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.wishlistUseCase.Delete(uuidStr, userUUID); err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (c *WishlistController) Share(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.wishlistUseCase.Share(uuidStr, userUUID)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(wishlist))
+}
+
+func (c *WishlistController) Unshare(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	wishlist, err := c.wishlistUseCase.Unshare(uuidStr, userUUID)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.toResponse(wishlist))
+}
+
+func (c *WishlistController) ListItems(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.wishlistUseCase.ListItems(uuidStr, userUUID, query)
+	if err != nil {
+		if errors.Is(err, usecases.ErrWishlistNotFound) {
+			c.writeError(w, err)
+			return
+		}
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, wishlistItemToResponse))
+}
+
+func (c *WishlistController) AddItem(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dtos.AddWishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RAX-004", "invalid request body"))
+		return
+	}
+
+	item, err := c.wishlistUseCase.AddItem(uuidStr, userUUID, req.ProductUUID, req.VariantUUID)
+	if err != nil {
+		c.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, wishlistItemToResponse(item))
+}
+
+func (c *WishlistController) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userUUID, uuidStr, ok := c.requireOwnerPath(w, r)
+	if !ok {
+		return
+	}
+
+	itemUUID := r.PathValue("item_uuid")
+	if itemUUID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing item_uuid"))
+		return
+	}
+
+	if err := c.wishlistUseCase.RemoveItem(uuidStr, userUUID, itemUUID); err != nil {
+		if errors.Is(err, usecases.ErrWishlistNotFound) {
+			c.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorResponse("R15-004", "wishlist item not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// ListAlerts returns the back-in-stock and on-sale alerts raised for the
+// shopper's wishlists on the website, newest first.
+func (c *WishlistController) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return
+	}
+
+	websiteUUIDStr, ok := requireWebsiteUUID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	page, err := c.wishlistUseCase.ListAlerts(userUUID, websiteUUIDStr, query)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pageToResponse(page, wishlistAlertToResponse))
+}
+
+// GetShared serves a wishlist through its share link. The token is the
+// only credential, so this route is registered without the auth middleware.
+func (c *WishlistController) GetShared(w http.ResponseWriter, r *http.Request) {
+	query, err := listQueryFromRequest(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	wishlist, page, err := c.wishlistUseCase.ListSharedItems(r.PathValue("token"), query)
+	if err != nil {
+		if errors.Is(err, usecases.ErrWishlistNotFound) {
+			c.writeError(w, err)
+			return
+		}
+		writeListError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.SharedWishlistResponse{
+		Name:  wishlist.Name,
+		Items: pageToResponse(page, wishlistItemToResponse),
+	})
+}
+
+func (c *WishlistController) requireOwnerPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	userUUID := middleware.GetUserUUID(r)
+	if userUUID == "" {
+		writeJSON(w, http.StatusUnauthorized, errorResponse("RBX-012", "unauthorized"))
+		return "", "", false
+	}
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse("RDI-001", "missing uuid"))
+		return "", "", false
+	}
+
+	return userUUID, uuidStr, true
+}
+
+func (c *WishlistController) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrWishlistNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse("R15-001", "wishlist not found"))
+	case errors.Is(err, domain.ErrWishlistExists):
+		writeJSON(w, http.StatusConflict, errorResponse("R15-002", "wishlist already exists"))
+	case errors.Is(err, domain.ErrWishlistItemExists):
+		writeJSON(w, http.StatusConflict, errorResponse("R15-003", "product already in wishlist"))
+	case errors.Is(err, usecases.ErrWishlistLimit):
+		writeJSON(w, http.StatusConflict, errorResponse("R15-005", "wishlist limit reached"))
+	default:
+		writeJSON(w, http.StatusConflict, errorResponse("RDI-002", err.Error()))
+	}
+}
+
+func (c *WishlistController) toResponse(wishlist *domain.Wishlist) dtos.WishlistResponse {
+	updatedAt := ""
+	if wishlist.UpdatedAt != nil {
+		updatedAt = wishlist.UpdatedAt.String()
+	}
+
+	return dtos.WishlistResponse{
+		UUID:        wishlist.UUID.String(),
+		WebsiteUUID: wishlist.WebsiteUUID.String(),
+		Name:        wishlist.Name,
+		Shared:      wishlist.Shared(),
+		ShareURL:    c.wishlistUseCase.ShareURL(wishlist),
+		ItemCount:   wishlist.ItemCount,
+		UpdatedAt:   updatedAt,
+		CreatedAt:   wishlist.CreatedAt.String(),
+	}
+}
+
+func wishlistItemToResponse(item *domain.WishlistItem) dtos.WishlistItemResponse {
+	variantUUID := ""
+	if item.VariantUUID != nil {
+		variantUUID = item.VariantUUID.String()
+	}
+
+	return dtos.WishlistItemResponse{
+		UUID:         item.UUID.String(),
+		WishlistUUID: item.WishlistUUID.String(),
+		ProductUUID:  item.ProductUUID.String(),
+		VariantUUID:  variantUUID,
+		InStock:      item.InStock,
+		OnSale:       item.OnSale,
+		CreatedAt:    item.CreatedAt.String(),
+	}
+}
+
+func wishlistAlertToResponse(alert *domain.WishlistAlert) dtos.WishlistAlertResponse {
+	variantUUID := ""
+	if alert.VariantUUID != nil {
+		variantUUID = alert.VariantUUID.String()
+	}
+
+	return dtos.WishlistAlertResponse{
+		UUID:         alert.UUID.String(),
+		Type:         string(alert.Type),
+		WishlistUUID: alert.WishlistUUID.String(),
+		ItemUUID:     alert.ItemUUID.String(),
+		ProductUUID:  alert.ProductUUID.String(),
+		VariantUUID:  variantUUID,
+		CreatedAt:    alert.CreatedAt.String(),
+	}
+}
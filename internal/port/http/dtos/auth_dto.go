@@ -15,8 +15,9 @@ type RegisterResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	DeviceToken string `json:"device_token"`
 }
 
 type LoginResponse struct {
@@ -27,4 +28,39 @@ type LoginResponse struct {
 	WebsiteUUID string `json:"website_uuid"`
 	Name        string `json:"name"`
 	Email       string `json:"email"`
+	DeviceToken string `json:"device_token,omitempty"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresAt         string `json:"expires_at"`
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RememberDevice bool   `json:"remember_device"`
+	DeviceName     string `json:"device_name"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `json:"token"`
 }
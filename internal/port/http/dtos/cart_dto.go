@@ -0,0 +1,67 @@
+package dtos
+
+type AddCartItemRequest struct {
+	ProductUUID string `json:"product_uuid"`
+	VariantUUID string `json:"variant_uuid"`
+	Quantity    int    `json:"quantity"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type CartResponse struct {
+	UUID        string             `json:"uuid"`
+	WebsiteUUID string             `json:"website_uuid"`
+	Status      string             `json:"status"`
+	Coin        string             `json:"coin,omitempty"`
+	Subtotal    int                `json:"subtotal"`
+	Available   bool               `json:"available"`
+	Items       []CartItemResponse `json:"items"`
+	UpdatedAt   string             `json:"updated_at"`
+	CreatedAt   string             `json:"created_at"`
+}
+
+type CartItemResponse struct {
+	UUID        string `json:"uuid"`
+	ProductUUID string `json:"product_uuid"`
+	VariantUUID string `json:"variant_uuid,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	UnitAmount  int    `json:"unit_amount"`
+	Amount      int    `json:"amount"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type SaveCartRecoverySettingsRequest struct {
+	Active         bool   `json:"active"`
+	IdleMinutes    int    `json:"idle_minutes"`
+	Reminders      int    `json:"reminders"`
+	IntervalHours  int    `json:"interval_hours"`
+	CupomValue     string `json:"cupom_value"`
+	CupomValueType string `json:"cupom_value_type"`
+	CupomValidDays int    `json:"cupom_valid_days"`
+}
+
+type CartRecoverySettingsResponse struct {
+	WebsiteUUID    string `json:"website_uuid"`
+	Active         bool   `json:"active"`
+	IdleMinutes    int    `json:"idle_minutes"`
+	Reminders      int    `json:"reminders"`
+	IntervalHours  int    `json:"interval_hours"`
+	CupomValue     string `json:"cupom_value,omitempty"`
+	CupomValueType string `json:"cupom_value_type,omitempty"`
+	CupomValidDays int    `json:"cupom_valid_days,omitempty"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type CartRecoveryTotalResponse struct {
+	Coin    string `json:"coin"`
+	Orders  int    `json:"orders"`
+	Revenue int    `json:"revenue"`
+}
+
+type CartRecoveryReportResponse struct {
+	Month  string                      `json:"month"`
+	Totals []CartRecoveryTotalResponse `json:"totals"`
+}
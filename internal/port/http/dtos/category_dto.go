@@ -0,0 +1,42 @@
+package dtos
+
+type CreateCategoryRequest struct {
+	ParentUUID     string `json:"parent_uuid"`
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	Position       int    `json:"position"`
+	SEOTitle       string `json:"seo_title"`
+	SEODescription string `json:"seo_description"`
+	Active         bool   `json:"active"`
+}
+
+type MoveCategoryRequest struct {
+	ParentUUID string `json:"parent_uuid"`
+	Position   int    `json:"position"`
+}
+
+type AssignProductCategoryRequest struct {
+	ProductUUID string `json:"product_uuid"`
+	Position    int    `json:"position"`
+}
+
+type CategoryResponse struct {
+	UUID           string             `json:"uuid"`
+	WebsiteUUID    string             `json:"website_uuid"`
+	ParentUUID     string             `json:"parent_uuid,omitempty"`
+	Depth          int                `json:"depth"`
+	Name           string             `json:"name"`
+	Slug           string             `json:"slug"`
+	Position       int                `json:"position"`
+	SEOTitle       string             `json:"seo_title"`
+	SEODescription string             `json:"seo_description"`
+	Active         bool               `json:"active"`
+	Children       []CategoryResponse `json:"children,omitempty"`
+	CreatedAt      string             `json:"created_at"`
+}
+
+type ProductCategoryResponse struct {
+	ProductUUID  string `json:"product_uuid"`
+	CategoryUUID string `json:"category_uuid"`
+	Position     int    `json:"position"`
+}
@@ -2,6 +2,7 @@ package dtos
 
 type CreateCupomRequest struct {
 	TagUUID     string `json:"tag_uuid"`
+	Coin        string `json:"coin"`
 	Label       string `json:"label"`
 	Description string `json:"description"`
 	Value       string `json:"value"`
@@ -10,7 +11,9 @@ type CreateCupomRequest struct {
 
 type CupomResponse struct {
 	UUID        string `json:"uuid"`
+	WebsiteUUID string `json:"website_uuid"`
 	TagUUID     string `json:"tag_uuid"`
+	Coin        string `json:"coin"`
 	Label       string `json:"label"`
 	Description string `json:"description"`
 	Value       string `json:"value"`
@@ -0,0 +1,42 @@
+package dtos
+
+type SetExchangeRateRequest struct {
+	BaseCoin  string `json:"base_coin"`
+	QuoteCoin string `json:"quote_coin"`
+	Rate      string `json:"rate"`
+	RateDate  string `json:"rate_date"`
+}
+
+type ExchangeRateResponse struct {
+	UUID      string `json:"uuid"`
+	BaseCoin  string `json:"base_coin"`
+	QuoteCoin string `json:"quote_coin"`
+	Rate      string `json:"rate"`
+	RateDate  string `json:"rate_date"`
+	Source    string `json:"source"`
+	CreatedAt string `json:"created_at"`
+}
+
+type SetCurrencyRoundingRequest struct {
+	Coin      string `json:"coin"`
+	Mode      string `json:"mode"`
+	Increment int    `json:"increment"`
+	Ending    int    `json:"ending"`
+}
+
+type CurrencyRoundingResponse struct {
+	UUID        string `json:"uuid"`
+	WebsiteUUID string `json:"website_uuid"`
+	Coin        string `json:"coin"`
+	Mode        string `json:"mode"`
+	Increment   int    `json:"increment"`
+	Ending      int    `json:"ending"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ConvertCurrencyResponse struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Amount          int    `json:"amount"`
+	ConvertedAmount int    `json:"converted_amount"`
+}
@@ -0,0 +1,73 @@
+package dtos
+
+import "time"
+
+type CreateGiftCardRequest struct {
+	Coin           string     `json:"coin"`
+	Amount         int        `json:"amount"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RecipientEmail string     `json:"recipient_email"`
+	Note           string     `json:"note"`
+}
+
+type GiftCardBalanceRequest struct {
+	Code string `json:"code"`
+}
+
+type CheckoutGiftCardsRequest struct {
+	Coin           string   `json:"coin"`
+	Subtotal       int      `json:"subtotal"`
+	CupomUUID      string   `json:"cupom_uuid"`
+	Codes          []string `json:"codes"`
+	UseStoreCredit bool     `json:"use_store_credit"`
+}
+
+type GiftCardResponse struct {
+	UUID           string `json:"uuid"`
+	Type           string `json:"type"`
+	Source         string `json:"source"`
+	Code           string `json:"code,omitempty"`
+	CodeLast4      string `json:"code_last4"`
+	Coin           string `json:"coin"`
+	InitialAmount  int    `json:"initial_amount"`
+	Balance        int    `json:"balance"`
+	UserUUID       string `json:"user_uuid,omitempty"`
+	ProductUUID    string `json:"product_uuid,omitempty"`
+	OrderUUID      string `json:"order_uuid,omitempty"`
+	RecipientEmail string `json:"recipient_email,omitempty"`
+	Note           string `json:"note,omitempty"`
+	ExpiresAt      string `json:"expires_at"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type GiftCardTransactionResponse struct {
+	UUID         string `json:"uuid"`
+	GiftCardUUID string `json:"gift_card_uuid"`
+	Type         string `json:"type"`
+	Amount       int    `json:"amount"`
+	BalanceAfter int    `json:"balance_after"`
+	OrderUUID    string `json:"order_uuid,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type StoreCreditResponse struct {
+	Balances map[string]int     `json:"balances"`
+	Credits  []GiftCardResponse `json:"credits"`
+}
+
+type GiftCardApplicationResponse struct {
+	GiftCardUUID string `json:"gift_card_uuid"`
+	Type         string `json:"type"`
+	CodeLast4    string `json:"code_last4"`
+	Amount       int    `json:"amount"`
+	BalanceAfter int    `json:"balance_after"`
+}
+
+type CheckoutTotalsResponse struct {
+	Coin      string                        `json:"coin"`
+	Subtotal  int                           `json:"subtotal"`
+	Discount  int                           `json:"discount"`
+	Total     int                           `json:"total"`
+	GiftCards []GiftCardApplicationResponse `json:"gift_cards"`
+	AmountDue int                           `json:"amount_due"`
+}
@@ -0,0 +1,66 @@
+package dtos
+
+type LoyaltyRateRequest struct {
+	Coin              string `json:"coin"`
+	EarnPointsPerUnit int    `json:"earn_points_per_unit"`
+	PointValue        int    `json:"point_value"`
+}
+
+type SaveLoyaltyProgramRequest struct {
+	Active          bool                 `json:"active"`
+	FirstOrderBonus int                  `json:"first_order_bonus"`
+	ReviewBonus     int                  `json:"review_bonus"`
+	ExpiryDays      int                  `json:"expiry_days"`
+	MinRedeemPoints int                  `json:"min_redeem_points"`
+	Rates           []LoyaltyRateRequest `json:"rates"`
+}
+
+type RedeemLoyaltyRequest struct {
+	Coin     string `json:"coin"`
+	Points   int    `json:"points"`
+	Subtotal int    `json:"subtotal"`
+}
+
+type LoyaltyRateResponse struct {
+	Coin              string `json:"coin"`
+	EarnPointsPerUnit int    `json:"earn_points_per_unit"`
+	PointValue        int    `json:"point_value"`
+}
+
+type LoyaltyProgramResponse struct {
+	WebsiteUUID     string                `json:"website_uuid"`
+	Active          bool                  `json:"active"`
+	FirstOrderBonus int                   `json:"first_order_bonus"`
+	ReviewBonus     int                   `json:"review_bonus"`
+	ExpiryDays      int                   `json:"expiry_days"`
+	MinRedeemPoints int                   `json:"min_redeem_points"`
+	Rates           []LoyaltyRateResponse `json:"rates"`
+	UpdatedAt       string                `json:"updated_at"`
+	CreatedAt       string                `json:"created_at"`
+}
+
+type LoyaltyTransactionResponse struct {
+	UUID       string `json:"uuid"`
+	Type       string `json:"type"`
+	Points     int    `json:"points"`
+	Remaining  int    `json:"remaining"`
+	Coin       string `json:"coin,omitempty"`
+	Amount     *int   `json:"amount,omitempty"`
+	OrderUUID  string `json:"order_uuid,omitempty"`
+	ReviewUUID string `json:"review_uuid,omitempty"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type LoyaltyBalanceResponse struct {
+	Points         int    `json:"points"`
+	ExpiringPoints int    `json:"expiring_points"`
+	ExpiringAt     string `json:"expiring_at"`
+}
+
+type LoyaltyQuoteResponse struct {
+	Coin      string `json:"coin"`
+	Points    int    `json:"points"`
+	Discount  int    `json:"discount"`
+	Available int    `json:"available"`
+}
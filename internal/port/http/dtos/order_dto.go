@@ -0,0 +1,48 @@
+package dtos
+
+type CheckoutRequest struct {
+	CupomUUID      string   `json:"cupom_uuid"`
+	GiftCardCodes  []string `json:"gift_card_codes"`
+	UseStoreCredit bool     `json:"use_store_credit"`
+	LoyaltyPoints  int      `json:"loyalty_points"`
+	PaymentMethod  string   `json:"payment_method"`
+}
+
+type RefundOrderRequest struct {
+	Amount        int  `json:"amount"`
+	ToStoreCredit bool `json:"to_store_credit"`
+}
+
+type OrderResponse struct {
+	UUID             string              `json:"uuid"`
+	WebsiteUUID      string              `json:"website_uuid"`
+	UserUUID         string              `json:"user_uuid"`
+	Email            string              `json:"email"`
+	Status           string              `json:"status"`
+	Coin             string              `json:"coin"`
+	Subtotal         int                 `json:"subtotal"`
+	Discount         int                 `json:"discount"`
+	LoyaltyPoints    int                 `json:"loyalty_points"`
+	LoyaltyDiscount  int                 `json:"loyalty_discount"`
+	GiftCardAmount   int                 `json:"gift_card_amount"`
+	Total            int                 `json:"total"`
+	AmountPaid       int                 `json:"amount_paid"`
+	RefundedAmount   int                 `json:"refunded_amount"`
+	CupomUUID        string              `json:"cupom_uuid,omitempty"`
+	PaymentReference string              `json:"payment_reference,omitempty"`
+	Recovered        bool                `json:"recovered"`
+	Items            []OrderItemResponse `json:"items,omitempty"`
+	PaidAt           string              `json:"paid_at,omitempty"`
+	CreatedAt        string              `json:"created_at"`
+}
+
+type OrderItemResponse struct {
+	UUID        string `json:"uuid"`
+	ProductUUID string `json:"product_uuid"`
+	VariantUUID string `json:"variant_uuid,omitempty"`
+	ProductType string `json:"product_type"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	UnitAmount  int    `json:"unit_amount"`
+	Amount      int    `json:"amount"`
+}
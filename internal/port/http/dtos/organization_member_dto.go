@@ -0,0 +1,52 @@
+package dtos
+
+type InviteMemberRequest struct {
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	RbacUUID string `json:"rbac_uuid"`
+}
+
+type UpdateMemberRequest struct {
+	Role     string `json:"role"`
+	RbacUUID string `json:"rbac_uuid"`
+}
+
+type TransferOwnershipRequest struct {
+	UserUUID string `json:"user_uuid"`
+}
+
+type OrganizationMemberResponse struct {
+	UUID             string `json:"uuid"`
+	OrganizationUUID string `json:"organization_uuid"`
+	UserUUID         string `json:"user_uuid"`
+	Email            string `json:"email"`
+	Name             string `json:"name"`
+	Role             string `json:"role"`
+	RbacUUID         string `json:"rbac_uuid,omitempty"`
+	UpdatedAt        string `json:"updated_at"`
+	CreatedAt        string `json:"created_at"`
+}
+
+type OrganizationInvitationResponse struct {
+	UUID             string `json:"uuid"`
+	OrganizationUUID string `json:"organization_uuid"`
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	RbacUUID         string `json:"rbac_uuid,omitempty"`
+	InvitedBy        string `json:"invited_by"`
+	Status           string `json:"status"`
+	ExpiresAt        string `json:"expires_at"`
+	RespondedAt      string `json:"responded_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+}
+
+type WebsitePermissionsResponse struct {
+	WebsiteUUID       string `json:"website_uuid"`
+	Role              string `json:"role"`
+	CanRead           bool   `json:"can_read"`
+	CanWrite          bool   `json:"can_write"`
+	CanUpdate         bool   `json:"can_update"`
+	CanUpgrade        bool   `json:"can_upgrade"`
+	CanDelete         bool   `json:"can_delete"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+}
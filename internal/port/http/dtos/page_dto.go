@@ -0,0 +1,9 @@
+package dtos
+
+// PageResponse wraps every list endpoint. NextCursor is null on the last page
+// and is passed back as ?cursor= to fetch the next one.
+type PageResponse[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+	Limit      int     `json:"limit"`
+}
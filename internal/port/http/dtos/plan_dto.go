@@ -9,6 +9,7 @@ type CreatePlanRequest struct {
 	CostPerSaleRate int    `json:"cost_per_sale_rate"`
 	Coin            string `json:"coin"`
 	Price           int    `json:"price"`
+	TrialDays       int    `json:"trial_days"`
 }
 
 type PlanResponse struct {
@@ -21,5 +22,6 @@ type PlanResponse struct {
 	CostPerSaleRate int    `json:"cost_per_sale_rate"`
 	Coin            string `json:"coin"`
 	Price           int    `json:"price"`
+	TrialDays       int    `json:"trial_days"`
 	CreatedAt       string `json:"created_at"`
 }
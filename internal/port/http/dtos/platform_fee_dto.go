@@ -0,0 +1,32 @@
+package dtos
+
+type PlatformFeeResponse struct {
+	UUID        string `json:"uuid"`
+	OwnerUUID   string `json:"owner_uuid"`
+	WebsiteUUID string `json:"website_uuid"`
+	OrderUUID   string `json:"order_uuid"`
+	PlanUUID    string `json:"plan_uuid,omitempty"`
+	Type        string `json:"type"`
+	Coin        string `json:"coin"`
+	Amount      int    `json:"amount"`
+	Rate        int    `json:"rate"`
+	Fee         int    `json:"fee"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type StatementTotalsResponse struct {
+	Coin       string `json:"coin"`
+	Sales      int    `json:"sales"`
+	GrossSales int    `json:"gross_sales"`
+	Fees       int    `json:"fees"`
+	Refunds    int    `json:"refunds"`
+	FeeRefunds int    `json:"fee_refunds"`
+	Net        int    `json:"net"`
+}
+
+type MerchantStatementResponse struct {
+	OwnerUUID string                    `json:"owner_uuid"`
+	Month     string                    `json:"month"`
+	Totals    []StatementTotalsResponse `json:"totals"`
+	Entries   []PlatformFeeResponse     `json:"entries"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+type ProductFileResponse struct {
+	UUID        string `json:"uuid"`
+	ProductUUID string `json:"product_uuid"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ProductDownloadResponse struct {
+	UUID             string `json:"uuid"`
+	OrderUUID        string `json:"order_uuid"`
+	ProductUUID      string `json:"product_uuid"`
+	FileUUID         string `json:"file_uuid"`
+	Filename         string `json:"filename"`
+	ContentType      string `json:"content_type"`
+	SizeBytes        int64  `json:"size_bytes"`
+	DownloadLimit    int    `json:"download_limit"`
+	DownloadCount    int    `json:"download_count"`
+	Remaining        int    `json:"remaining"`
+	ExpiresAt        string `json:"expires_at"`
+	LastDownloadedAt string `json:"last_downloaded_at"`
+	URL              string `json:"url"`
+	URLExpiresAt     string `json:"url_expires_at"`
+	CreatedAt        string `json:"created_at"`
+}
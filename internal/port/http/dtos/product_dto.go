@@ -4,17 +4,31 @@ type CreateProductRequest struct {
 	Name             string `json:"name"`
 	Description      string `json:"description"`
 	ShortDescription string `json:"short_description"`
+	Brand            string `json:"brand"`
+	GTIN             string `json:"gtin"`
+	Type             string `json:"type"`
 	Height           int    `json:"height"`
 	Width            int    `json:"width"`
 	Thickness        int    `json:"thickness"`
+	DownloadLimit    int    `json:"download_limit"`
+	DownloadDays     int    `json:"download_days"`
 	Active           bool   `json:"active"`
 }
 
 type ProductResponse struct {
-	UUID             string `json:"uuid"`
-	Name             string `json:"name"`
-	Description      string `json:"description"`
-	ShortDescription string `json:"short_description"`
-	Active           bool   `json:"active"`
-	CreatedAt        string `json:"created_at"`
+	UUID             string                 `json:"uuid"`
+	WebsiteUUID      string                 `json:"website_uuid,omitempty"`
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	ShortDescription string                 `json:"short_description"`
+	Brand            string                 `json:"brand"`
+	GTIN             string                 `json:"gtin"`
+	Type             string                 `json:"type"`
+	DownloadLimit    int                    `json:"download_limit,omitempty"`
+	DownloadDays     int                    `json:"download_days,omitempty"`
+	RatingCount      int                    `json:"rating_count"`
+	RatingAverage    float64                `json:"rating_average"`
+	Active           bool                   `json:"active"`
+	Media            []ProductMediaResponse `json:"media,omitempty"`
+	CreatedAt        string                 `json:"created_at"`
 }
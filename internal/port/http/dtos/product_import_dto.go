@@ -0,0 +1,29 @@
+package dtos
+
+type ProductImportResponse struct {
+	UUID          string            `json:"uuid"`
+	Filename      string            `json:"filename"`
+	Format        string            `json:"format"`
+	Source        string            `json:"source"`
+	Coin          string            `json:"coin"`
+	Status        string            `json:"status"`
+	DryRun        bool              `json:"dry_run"`
+	Mapping       map[string]string `json:"mapping"`
+	TotalRows     int               `json:"total_rows"`
+	ProcessedRows int               `json:"processed_rows"`
+	CreatedCount  int               `json:"created_count"`
+	FailedCount   int               `json:"failed_count"`
+	WarningCount  int               `json:"warning_count"`
+	Progress      float64           `json:"progress"`
+	Error         string            `json:"error,omitempty"`
+	StartedAt     string            `json:"started_at"`
+	FinishedAt    string            `json:"finished_at"`
+	CreatedAt     string            `json:"created_at"`
+}
+
+type ProductImportErrorResponse struct {
+	Row     int    `json:"row"`
+	Level   string `json:"level"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
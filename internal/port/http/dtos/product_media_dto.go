@@ -0,0 +1,29 @@
+package dtos
+
+type ReorderProductMediaRequest struct {
+	MediaUUIDs []string `json:"media_uuids"`
+}
+
+type ProductMediaVariantResponse struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type ProductMediaResponse struct {
+	UUID        string                        `json:"uuid"`
+	ProductUUID string                        `json:"product_uuid"`
+	URL         string                        `json:"url"`
+	ContentType string                        `json:"content_type"`
+	SizeBytes   int64                         `json:"size_bytes"`
+	Alt         string                        `json:"alt"`
+	Position    int                           `json:"position"`
+	Status      string                        `json:"status"`
+	Error       string                        `json:"error,omitempty"`
+	Width       *int                          `json:"width"`
+	Height      *int                          `json:"height"`
+	Variants    []ProductMediaVariantResponse `json:"variants"`
+	Srcset      map[string]string             `json:"srcset"`
+	CreatedAt   string                        `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package dtos
+
+import "time"
+
+type CreateProductVariantRequest struct {
+	SKU    string `json:"sku"`
+	Label  string `json:"label"`
+	GTIN   string `json:"gtin"`
+	Active bool   `json:"active"`
+}
+
+type ProductVariantResponse struct {
+	UUID        string `json:"uuid"`
+	ProductUUID string `json:"product_uuid"`
+	SKU         string `json:"sku"`
+	Label       string `json:"label"`
+	GTIN        string `json:"gtin"`
+	Active      bool   `json:"active"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type CreatePriceListRequest struct {
+	Label         string `json:"label"`
+	Coin          string `json:"coin"`
+	CustomerGroup string `json:"customer_group"`
+	Priority      int    `json:"priority"`
+	Active        bool   `json:"active"`
+}
+
+type PriceListResponse struct {
+	UUID          string `json:"uuid"`
+	WebsiteUUID   string `json:"website_uuid"`
+	Label         string `json:"label"`
+	Coin          string `json:"coin"`
+	CustomerGroup string `json:"customer_group"`
+	Priority      int    `json:"priority"`
+	Active        bool   `json:"active"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type SetCustomerGroupRequest struct {
+	CustomerGroup string `json:"customer_group"`
+}
+
+type CustomerGroupResponse struct {
+	WebsiteUUID   string `json:"website_uuid"`
+	UserUUID      string `json:"user_uuid"`
+	CustomerGroup string `json:"customer_group"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type CreateProductPriceRequest struct {
+	VariantUUID     string     `json:"variant_uuid"`
+	PriceListUUID   string     `json:"price_list_uuid"`
+	Coin            string     `json:"coin"`
+	Amount          int        `json:"amount"`
+	CompareAtAmount *int       `json:"compare_at_amount"`
+	SaleAmount      *int       `json:"sale_amount"`
+	SaleStartsAt    *time.Time `json:"sale_starts_at"`
+	SaleEndsAt      *time.Time `json:"sale_ends_at"`
+}
+
+type UpdateProductPriceRequest struct {
+	Amount          int        `json:"amount"`
+	CompareAtAmount *int       `json:"compare_at_amount"`
+	SaleAmount      *int       `json:"sale_amount"`
+	SaleStartsAt    *time.Time `json:"sale_starts_at"`
+	SaleEndsAt      *time.Time `json:"sale_ends_at"`
+}
+
+type ProductPriceResponse struct {
+	UUID            string     `json:"uuid"`
+	ProductUUID     string     `json:"product_uuid"`
+	VariantUUID     string     `json:"variant_uuid,omitempty"`
+	PriceListUUID   string     `json:"price_list_uuid,omitempty"`
+	Coin            string     `json:"coin"`
+	Amount          int        `json:"amount"`
+	CompareAtAmount *int       `json:"compare_at_amount"`
+	SaleAmount      *int       `json:"sale_amount"`
+	SaleStartsAt    *time.Time `json:"sale_starts_at"`
+	SaleEndsAt      *time.Time `json:"sale_ends_at"`
+	CreatedAt       string     `json:"created_at"`
+}
+
+type ResolvedPriceResponse struct {
+	PriceUUID              string `json:"price_uuid"`
+	PriceListUUID          string `json:"price_list_uuid,omitempty"`
+	Coin                   string `json:"coin"`
+	Amount                 int    `json:"amount"`
+	CompareAtAmount        *int   `json:"compare_at_amount"`
+	OnSale                 bool   `json:"on_sale"`
+	DisplayCoin            string `json:"display_coin,omitempty"`
+	DisplayAmount          *int   `json:"display_amount,omitempty"`
+	DisplayCompareAtAmount *int   `json:"display_compare_at_amount,omitempty"`
+	Converted              bool   `json:"converted"`
+}
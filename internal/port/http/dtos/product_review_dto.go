@@ -0,0 +1,36 @@
+package dtos
+
+type RejectProductReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ProductReviewPhotoResponse struct {
+	UUID        string `json:"uuid"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	Position    int    `json:"position"`
+}
+
+type ProductReviewResponse struct {
+	UUID            string                       `json:"uuid"`
+	ProductUUID     string                       `json:"product_uuid"`
+	UserUUID        string                       `json:"user_uuid"`
+	Rating          int                          `json:"rating"`
+	Title           string                       `json:"title"`
+	Body            string                       `json:"body"`
+	Verified        bool                         `json:"verified"`
+	Status          string                       `json:"status"`
+	RejectionReason string                       `json:"rejection_reason,omitempty"`
+	ModeratedBy     string                       `json:"moderated_by,omitempty"`
+	ModeratedAt     string                       `json:"moderated_at,omitempty"`
+	Photos          []ProductReviewPhotoResponse `json:"photos"`
+	CreatedAt       string                       `json:"created_at"`
+}
+
+type ProductRatingSummaryResponse struct {
+	ProductUUID  string         `json:"product_uuid"`
+	Count        int            `json:"count"`
+	Average      float64        `json:"average"`
+	Distribution map[string]int `json:"distribution"`
+}
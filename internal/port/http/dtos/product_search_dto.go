@@ -0,0 +1,43 @@
+package dtos
+
+type ProductSearchHitResponse struct {
+	Product       ProductResponse `json:"product"`
+	Rank          float64         `json:"rank"`
+	NameHighlight string          `json:"name_highlight"`
+	Snippet       string          `json:"snippet"`
+	Price         *int            `json:"price"`
+	InStock       bool            `json:"in_stock"`
+}
+
+type FacetCountResponse struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+type PriceRangeFacetResponse struct {
+	Min *int `json:"min"`
+	Max *int `json:"max"`
+}
+
+type StockFacetResponse struct {
+	InStock    int `json:"in_stock"`
+	OutOfStock int `json:"out_of_stock"`
+}
+
+type ProductSearchFacetsResponse struct {
+	Tags       []FacetCountResponse    `json:"tags"`
+	Categories []FacetCountResponse    `json:"categories"`
+	Price      PriceRangeFacetResponse `json:"price"`
+	Stock      StockFacetResponse      `json:"stock"`
+}
+
+type ProductSearchResponse struct {
+	Query  string                      `json:"query"`
+	Coin   string                      `json:"coin"`
+	Total  int                         `json:"total"`
+	Limit  int                         `json:"limit"`
+	Offset int                         `json:"offset"`
+	Hits   []ProductSearchHitResponse  `json:"hits"`
+	Facets ProductSearchFacetsResponse `json:"facets"`
+}
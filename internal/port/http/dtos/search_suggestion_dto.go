@@ -0,0 +1,26 @@
+package dtos
+
+type ProductSuggestionResponse struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type CategorySuggestionResponse struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type SearchQueryResponse struct {
+	Query          string `json:"query"`
+	Searches       int    `json:"searches"`
+	LastResults    int    `json:"last_results"`
+	LastSearchedAt string `json:"last_searched_at"`
+}
+
+type SearchSuggestionsResponse struct {
+	Query      string                       `json:"query"`
+	Products   []ProductSuggestionResponse  `json:"products"`
+	Categories []CategorySuggestionResponse `json:"categories"`
+	Queries    []SearchQueryResponse        `json:"queries"`
+}
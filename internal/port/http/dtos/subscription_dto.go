@@ -0,0 +1,74 @@
+package dtos
+
+type SubscribeRequest struct {
+	OwnerUUID     string `json:"owner_uuid"`
+	OwnerType     string `json:"owner_type"`
+	PlanUUID      string `json:"plan_uuid"`
+	Cycle         string `json:"cycle"`
+	PaymentMethod string `json:"payment_method"`
+}
+
+// ChangePlanRequest keeps the current cycle when Cycle is empty.
+type ChangePlanRequest struct {
+	PlanUUID string `json:"plan_uuid"`
+	Cycle    string `json:"cycle"`
+}
+
+type UpdatePaymentMethodRequest struct {
+	PaymentMethod string `json:"payment_method"`
+}
+
+type SubscriptionResponse struct {
+	UUID               string `json:"uuid"`
+	OwnerUUID          string `json:"owner_uuid"`
+	OwnerType          string `json:"owner_type"`
+	PlanUUID           string `json:"plan_uuid"`
+	Status             string `json:"status"`
+	Cycle              string `json:"cycle"`
+	PaymentMethod      string `json:"payment_method,omitempty"`
+	TrialEndsAt        string `json:"trial_ends_at,omitempty"`
+	CurrentPeriodStart string `json:"current_period_start,omitempty"`
+	CurrentPeriodEnd   string `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd  bool   `json:"cancel_at_period_end"`
+	PastDueSince       string `json:"past_due_since,omitempty"`
+	Credit             int    `json:"credit"`
+	UpdatedAt          string `json:"updated_at"`
+	CreatedAt          string `json:"created_at"`
+}
+
+type SubscriptionInvoiceResponse struct {
+	UUID              string `json:"uuid"`
+	SubscriptionUUID  string `json:"subscription_uuid"`
+	PlanUUID          string `json:"plan_uuid"`
+	Reason            string `json:"reason"`
+	Cycle             string `json:"cycle"`
+	Coin              string `json:"coin"`
+	Amount            int    `json:"amount"`
+	CreditApplied     int    `json:"credit_applied"`
+	Status            string `json:"status"`
+	Attempts          int    `json:"attempts"`
+	NextAttemptAt     string `json:"next_attempt_at,omitempty"`
+	PeriodStart       string `json:"period_start"`
+	PeriodEnd         string `json:"period_end"`
+	ProviderReference string `json:"provider_reference,omitempty"`
+	FailureReason     string `json:"failure_reason,omitempty"`
+	PaidAt            string `json:"paid_at,omitempty"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// QuotaResponse is one plan limit; Limit is zero when Unlimited.
+type QuotaResponse struct {
+	Used      int  `json:"used"`
+	Limit     int  `json:"limit"`
+	Unlimited bool `json:"unlimited"`
+	Reached   bool `json:"reached"`
+}
+
+type PlanUsageResponse struct {
+	OwnerUUID string        `json:"owner_uuid"`
+	PlanUUID  string        `json:"plan_uuid,omitempty"`
+	PlanName  string        `json:"plan_name,omitempty"`
+	Websites  QuotaResponse `json:"websites"`
+	Routes    QuotaResponse `json:"routes"`
+	Products  QuotaResponse `json:"products"`
+}
@@ -0,0 +1,31 @@
+package dtos
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool   `json:"enabled"`
+	EnabledAt         string `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int    `json:"recovery_codes_left"`
+}
+
+// TwoFactorEnrollmentResponse carries the QR code as a data: URI, ready to
+// use as the source of an image.
+type TwoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TrustedDeviceResponse struct {
+	UUID       string `json:"uuid"`
+	Name       string `json:"name"`
+	ExpiresAt  string `json:"expires_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
@@ -7,15 +7,28 @@ type CreateWebsiteRequest struct {
 	URL         string `json:"url"`
 	WriteIn     string `json:"write_in"`
 	Description string `json:"description"`
+	BaseCoin    string `json:"base_coin"`
+}
+
+type UpdateEmailVerificationRequest struct {
+	Required bool `json:"required"`
+}
+
+type UpdateTwoFactorRequest struct {
+	Required bool `json:"required"`
 }
 
 type WebsiteResponse struct {
-	UUID        string `json:"uuid"`
-	OwnerUUID   string `json:"owner_uuid"`
-	OwnerType   string `json:"owner_type"`
-	Label       string `json:"label"`
-	URL         string `json:"url"`
-	WriteIn     string `json:"write_in"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"created_at"`
+	UUID                     string `json:"uuid"`
+	OwnerUUID                string `json:"owner_uuid"`
+	OwnerType                string `json:"owner_type"`
+	Label                    string `json:"label"`
+	URL                      string `json:"url"`
+	WriteIn                  string `json:"write_in"`
+	Description              string `json:"description"`
+	BaseCoin                 string `json:"base_coin"`
+	SuspendedAt              string `json:"suspended_at,omitempty"`
+	RequireEmailVerification bool   `json:"require_email_verification"`
+	RequireTwoFactor         bool   `json:"require_two_factor"`
+	CreatedAt                string `json:"created_at"`
 }
@@ -0,0 +1,50 @@
+package dtos
+
+type CreateWishlistRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateWishlistRequest struct {
+	Name string `json:"name"`
+}
+
+type AddWishlistItemRequest struct {
+	ProductUUID string `json:"product_uuid"`
+	VariantUUID string `json:"variant_uuid"`
+}
+
+type WishlistResponse struct {
+	UUID        string `json:"uuid"`
+	WebsiteUUID string `json:"website_uuid"`
+	Name        string `json:"name"`
+	Shared      bool   `json:"shared"`
+	ShareURL    string `json:"share_url,omitempty"`
+	ItemCount   int    `json:"item_count"`
+	UpdatedAt   string `json:"updated_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type WishlistItemResponse struct {
+	UUID         string `json:"uuid"`
+	WishlistUUID string `json:"wishlist_uuid"`
+	ProductUUID  string `json:"product_uuid"`
+	VariantUUID  string `json:"variant_uuid,omitempty"`
+	InStock      bool   `json:"in_stock"`
+	OnSale       bool   `json:"on_sale"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type SharedWishlistResponse struct {
+	Name  string                             `json:"name"`
+	Items PageResponse[WishlistItemResponse] `json:"items"`
+}
+
+type WishlistAlertResponse struct {
+	UUID         string `json:"uuid"`
+	Type         string `json:"type"`
+	WishlistUUID string `json:"wishlist_uuid"`
+	ItemUUID     string `json:"item_uuid"`
+	ProductUUID  string `json:"product_uuid"`
+	VariantUUID  string `json:"variant_uuid,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
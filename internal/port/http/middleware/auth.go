@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ViitoJooj/verkoupe/pkg/token"
 )
@@ -14,7 +15,13 @@ const UserUUIDKey contextKey = "user_uuid"
 const WebsiteUUIDKey contextKey = "website_uuid"
 const RoleKey contextKey = "role"
 
-func AuthMiddleware(pasetoSecret string) func(http.Handler) http.Handler {
+// SessionCheck tells whether an access token of userUUID issued at
+// issuedAt is still valid, e.g. not issued before a password reset.
+type SessionCheck func(userUUID string, issuedAt time.Time) bool
+
+// AuthMiddleware accepts requests with a valid access token. sessionValid
+// may be nil to accept every unexpired token.
+func AuthMiddleware(pasetoSecret string, sessionValid SessionCheck) func(http.Handler) http.Handler {
 	secret := []byte(pasetoSecret)
 	if len(secret) < 32 {
 		padded := make([]byte, 32)
@@ -52,6 +59,11 @@ func AuthMiddleware(pasetoSecret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			if sessionValid != nil && !sessionValid(claims.UserUUID, claims.IssuedAt) {
+				writeUnauthorized(w, "RBX-018", "invalid session")
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), UserUUIDKey, claims.UserUUID)
 			ctx = context.WithValue(ctx, WebsiteUUIDKey, claims.WebSiteUUID)
 			ctx = context.WithValue(ctx, RoleKey, claims.Role)
@@ -62,9 +74,17 @@ func AuthMiddleware(pasetoSecret string) func(http.Handler) http.Handler {
 
 func isPublicRoute(path string, method string) bool {
 	publicRoutes := map[string]bool{
-		"POST /auth/register": true,
-		"POST /auth/login":    true,
-		"GET /health":         true,
+		"POST /auth/register":            true,
+		"POST /auth/login":               true,
+		"POST /auth/2fa/verify":          true,
+		"POST /auth/verify-email":        true,
+		"POST /auth/verify-email/resend": true,
+		"POST /auth/password/forgot":     true,
+		"POST /auth/password/reset":      true,
+		"POST /auth/unlock":              true,
+		"GET /health":                    true,
+		"GET /products/search":           true,
+		"GET /search/suggestions":        true,
 	}
 
 	return publicRoutes[method+" "+path]
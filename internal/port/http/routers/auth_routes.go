@@ -9,4 +9,10 @@ import (
 func RegisterAuthRoutes(mux *http.ServeMux, controller *controllers.AuthController, middlewares ...func(http.Handler) http.Handler) {
 	mux.Handle("POST /auth/register", wrapHandler(controller.Register, middlewares...))
 	mux.Handle("POST /auth/login", wrapHandler(controller.Login, middlewares...))
+	mux.Handle("POST /auth/2fa/verify", wrapHandler(controller.VerifyTwoFactor, middlewares...))
+	mux.Handle("POST /auth/verify-email", wrapHandler(controller.VerifyEmail, middlewares...))
+	mux.Handle("POST /auth/verify-email/resend", wrapHandler(controller.ResendVerification, middlewares...))
+	mux.Handle("POST /auth/password/forgot", wrapHandler(controller.ForgotPassword, middlewares...))
+	mux.Handle("POST /auth/password/reset", wrapHandler(controller.ResetPassword, middlewares...))
+	mux.Handle("POST /auth/unlock", wrapHandler(controller.UnlockAccount, middlewares...))
 }
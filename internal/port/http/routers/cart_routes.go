@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterCartRoutes(mux *http.ServeMux, controller *controllers.CartController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /cart", wrapHandler(controller.Get, middlewares...))
+	mux.Handle("POST /cart/items", wrapHandler(controller.AddItem, middlewares...))
+	mux.Handle("PUT /cart/items/{uuid}", wrapHandler(controller.UpdateItem, middlewares...))
+	mux.Handle("DELETE /cart/items/{uuid}", wrapHandler(controller.RemoveItem, middlewares...))
+	mux.Handle("GET /carts/recovery", wrapHandler(controller.GetRecoverySettings, middlewares...))
+	mux.Handle("PUT /carts/recovery", wrapHandler(controller.SaveRecoverySettings, middlewares...))
+	mux.Handle("GET /carts/recovery/report", wrapHandler(controller.RecoveryReport, middlewares...))
+}
+
+// RegisterCartRestoreRoutes serves the links of recovery reminders, which
+// are opened from an email without a session.
+func RegisterCartRestoreRoutes(mux *http.ServeMux, controller *controllers.CartController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /carts/restore/{token}", wrapHandler(controller.Restore, middlewares...))
+}
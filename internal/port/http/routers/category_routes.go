@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterCategoryRoutes(mux *http.ServeMux, controller *controllers.CategoryController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /categories", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /categories", wrapHandler(controller.GetTree, middlewares...))
+	mux.Handle("GET /categories/{uuid}", wrapHandler(controller.GetByUUID, middlewares...))
+	mux.Handle("PUT /categories/{uuid}/move", wrapHandler(controller.Move, middlewares...))
+	mux.Handle("DELETE /categories/{uuid}", wrapHandler(controller.Delete, middlewares...))
+	mux.Handle("POST /categories/{uuid}/products", wrapHandler(controller.AssignProduct, middlewares...))
+	mux.Handle("GET /categories/{uuid}/products", wrapHandler(controller.ListProducts, middlewares...))
+	mux.Handle("DELETE /categories/{uuid}/products/{product_uuid}", wrapHandler(controller.RemoveProduct, middlewares...))
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterCurrencyRoutes(mux *http.ServeMux, controller *controllers.CurrencyController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /exchange-rates", wrapHandler(controller.SetRate, middlewares...))
+	mux.Handle("GET /exchange-rates", wrapHandler(controller.ListRates, middlewares...))
+	mux.Handle("POST /exchange-rates/sync", wrapHandler(controller.SyncRates, middlewares...))
+	mux.Handle("PUT /currencies/roundings", wrapHandler(controller.SetRounding, middlewares...))
+	mux.Handle("GET /currencies/roundings", wrapHandler(controller.ListRoundings, middlewares...))
+	mux.Handle("GET /currencies/convert", wrapHandler(controller.Convert, middlewares...))
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterGiftCardRoutes(mux *http.ServeMux, controller *controllers.GiftCardController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /gift-cards", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /gift-cards", wrapHandler(controller.List, middlewares...))
+	mux.Handle("POST /gift-cards/balance", wrapHandler(controller.Balance, middlewares...))
+	mux.Handle("GET /gift-cards/{uuid}", wrapHandler(controller.GetByUUID, middlewares...))
+	mux.Handle("GET /gift-cards/{uuid}/transactions", wrapHandler(controller.ListTransactions, middlewares...))
+	mux.Handle("GET /store-credit", wrapHandler(controller.MyStoreCredit, middlewares...))
+	mux.Handle("POST /checkout/gift-cards/quote", wrapHandler(controller.Quote, middlewares...))
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterLoyaltyRoutes(mux *http.ServeMux, controller *controllers.LoyaltyController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /loyalty/program", wrapHandler(controller.GetProgram, middlewares...))
+	mux.Handle("PUT /loyalty/program", wrapHandler(controller.SaveProgram, middlewares...))
+	mux.Handle("GET /loyalty/balance", wrapHandler(controller.Balance, middlewares...))
+	mux.Handle("GET /loyalty/history", wrapHandler(controller.History, middlewares...))
+	mux.Handle("POST /checkout/loyalty/quote", wrapHandler(controller.Quote, middlewares...))
+}
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RegisterMediaFileRoutes serves blobs stored by the local driver. Product
+// media is public, so callers should not pass the auth middleware here; only
+// keys under products/ are exposed, other blobs such as import uploads and
+// photos of reviews not yet approved stay private.
+func RegisterMediaFileRoutes(mux *http.ServeMux, dir string, middlewares ...func(http.Handler) http.Handler) {
+	fileServer := http.StripPrefix("/media/", http.FileServer(http.Dir(dir)))
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/media/products/") || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	}
+
+	mux.Handle("GET /media/", wrapHandler(handler, middlewares...))
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterOrderRoutes(mux *http.ServeMux, controller *controllers.OrderController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /cart/checkout", wrapHandler(controller.Checkout, middlewares...))
+	mux.Handle("GET /orders", wrapHandler(controller.ListMine, middlewares...))
+	mux.Handle("GET /orders/{uuid}", wrapHandler(controller.GetMine, middlewares...))
+	mux.Handle("GET /store/orders", wrapHandler(controller.List, middlewares...))
+	mux.Handle("POST /orders/{uuid}/refund", wrapHandler(controller.Refund, middlewares...))
+}
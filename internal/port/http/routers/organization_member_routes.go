@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterOrganizationMemberRoutes(mux *http.ServeMux, controller *controllers.OrganizationMemberController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /organizations/{uuid}/members", wrapHandler(controller.ListMembers, middlewares...))
+	mux.Handle("PUT /organizations/{uuid}/members/{user_uuid}", wrapHandler(controller.UpdateMember, middlewares...))
+	mux.Handle("DELETE /organizations/{uuid}/members/{user_uuid}", wrapHandler(controller.RemoveMember, middlewares...))
+	mux.Handle("POST /organizations/{uuid}/owner", wrapHandler(controller.TransferOwnership, middlewares...))
+	mux.Handle("POST /organizations/{uuid}/invitations", wrapHandler(controller.Invite, middlewares...))
+	mux.Handle("GET /organizations/{uuid}/invitations", wrapHandler(controller.ListInvitations, middlewares...))
+	mux.Handle("DELETE /organizations/{uuid}/invitations/{invitation_uuid}", wrapHandler(controller.RevokeInvitation, middlewares...))
+	mux.Handle("POST /invitations/{token}/accept", wrapHandler(controller.AcceptInvitation, middlewares...))
+	mux.Handle("POST /invitations/{token}/decline", wrapHandler(controller.DeclineInvitation, middlewares...))
+}
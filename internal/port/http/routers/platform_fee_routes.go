@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterPlatformFeeRoutes(mux *http.ServeMux, controller *controllers.PlatformFeeController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /platform-fees", wrapHandler(controller.List, middlewares...))
+	mux.Handle("GET /statements/{month}", wrapHandler(controller.Statement, middlewares...))
+	mux.Handle("GET /statements/{month}/download", wrapHandler(controller.Download, middlewares...))
+}
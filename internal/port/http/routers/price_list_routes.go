@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterPriceListRoutes(mux *http.ServeMux, controller *controllers.PriceListController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /prices-lists", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /prices-lists", wrapHandler(controller.GetAll, middlewares...))
+	mux.Handle("GET /prices-lists/{uuid}", wrapHandler(controller.GetByUUID, middlewares...))
+	mux.Handle("DELETE /prices-lists/{uuid}", wrapHandler(controller.Delete, middlewares...))
+	mux.Handle("PUT /customers-groups/{user_uuid}", wrapHandler(controller.SetCustomerGroup, middlewares...))
+	mux.Handle("DELETE /customers-groups/{user_uuid}", wrapHandler(controller.RemoveCustomerGroup, middlewares...))
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductDownloadRoutes(mux *http.ServeMux, controller *controllers.ProductDownloadController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /orders/{uuid}/downloads", wrapHandler(controller.ListByOrder, middlewares...))
+}
+
+// RegisterDownloadFileRoutes serves signed download links. The signature is
+// the credential, so callers should not pass the auth middleware here.
+func RegisterDownloadFileRoutes(mux *http.ServeMux, controller *controllers.ProductDownloadController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /downloads/{token}", wrapHandler(controller.Download, middlewares...))
+}
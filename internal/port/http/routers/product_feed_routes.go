@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+// RegisterProductFeedRoutes exposes the shopping channel feeds. Merchant
+// Center and Meta fetch them without credentials, so callers should not pass
+// the auth middleware here.
+func RegisterProductFeedRoutes(mux *http.ServeMux, controller *controllers.ProductFeedController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /feeds/{uuid}/google.xml", wrapHandler(controller.Google, middlewares...))
+	mux.Handle("GET /feeds/{uuid}/meta.csv", wrapHandler(controller.Meta, middlewares...))
+}
@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductFileRoutes(mux *http.ServeMux, controller *controllers.ProductFileController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /products/{uuid}/files", wrapHandler(controller.Upload, middlewares...))
+	mux.Handle("GET /products/{uuid}/files", wrapHandler(controller.ListByProduct, middlewares...))
+	mux.Handle("DELETE /products/files/{uuid}", wrapHandler(controller.Delete, middlewares...))
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductImportRoutes(mux *http.ServeMux, controller *controllers.ProductImportController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /imports/products", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /imports/products", wrapHandler(controller.List, middlewares...))
+	mux.Handle("GET /imports/products/{uuid}", wrapHandler(controller.Get, middlewares...))
+	mux.Handle("GET /imports/products/{uuid}/errors", wrapHandler(controller.Errors, middlewares...))
+	mux.Handle("GET /exports/products", wrapHandler(controller.Export, middlewares...))
+}
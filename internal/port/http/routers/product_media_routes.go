@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductMediaRoutes(mux *http.ServeMux, controller *controllers.ProductMediaController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /products/{uuid}/media", wrapHandler(controller.Upload, middlewares...))
+	mux.Handle("GET /products/{uuid}/media", wrapHandler(controller.ListByProduct, middlewares...))
+	mux.Handle("PUT /products/{uuid}/media/order", wrapHandler(controller.Reorder, middlewares...))
+	mux.Handle("POST /products/media/{uuid}/reprocess", wrapHandler(controller.Reprocess, middlewares...))
+	mux.Handle("DELETE /products/media/{uuid}", wrapHandler(controller.Delete, middlewares...))
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductPriceRoutes(mux *http.ServeMux, controller *controllers.ProductPriceController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /products/{uuid}/prices", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /products/{uuid}/prices", wrapHandler(controller.ListByProduct, middlewares...))
+	mux.Handle("GET /products/{uuid}/price", wrapHandler(controller.Resolve, middlewares...))
+	mux.Handle("PUT /products/prices/{uuid}", wrapHandler(controller.Update, middlewares...))
+	mux.Handle("DELETE /products/prices/{uuid}", wrapHandler(controller.Delete, middlewares...))
+}
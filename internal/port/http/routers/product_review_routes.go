@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductReviewRoutes(mux *http.ServeMux, controller *controllers.ProductReviewController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /products/{uuid}/reviews", wrapHandler(controller.Submit, middlewares...))
+	mux.Handle("GET /reviews", wrapHandler(controller.ListForModeration, middlewares...))
+	mux.Handle("POST /reviews/{uuid}/approve", wrapHandler(controller.Approve, middlewares...))
+	mux.Handle("POST /reviews/{uuid}/reject", wrapHandler(controller.Reject, middlewares...))
+	mux.Handle("GET /reviews/photos/{uuid}", wrapHandler(controller.Photo, middlewares...))
+}
+
+// RegisterPublicProductReviewRoutes serves approved reviews and ratings to
+// storefronts, which browse without a session.
+func RegisterPublicProductReviewRoutes(mux *http.ServeMux, controller *controllers.ProductReviewController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /products/{uuid}/reviews", wrapHandler(controller.ListByProduct, middlewares...))
+	mux.Handle("GET /products/{uuid}/rating", wrapHandler(controller.Summary, middlewares...))
+}
@@ -8,4 +8,6 @@ import (
 
 func RegisterProductRoutes(mux *http.ServeMux, controller *controllers.ProductController, middlewares ...func(http.Handler) http.Handler) {
 	mux.Handle("POST /products", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /products", wrapHandler(controller.List, middlewares...))
+	mux.Handle("GET /products/{uuid}", wrapHandler(controller.Get, middlewares...))
 }
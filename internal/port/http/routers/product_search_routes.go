@@ -0,0 +1,11 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductSearchRoutes(mux *http.ServeMux, controller *controllers.ProductSearchController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /products/search", wrapHandler(controller.Search, middlewares...))
+}
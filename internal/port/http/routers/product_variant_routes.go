@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterProductVariantRoutes(mux *http.ServeMux, controller *controllers.ProductVariantController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /products/{uuid}/variants", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /products/{uuid}/variants", wrapHandler(controller.ListByProduct, middlewares...))
+	mux.Handle("DELETE /products/variants/{uuid}", wrapHandler(controller.Delete, middlewares...))
+}
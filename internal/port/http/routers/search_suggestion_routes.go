@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterSearchSuggestionRoutes(mux *http.ServeMux, controller *controllers.SearchSuggestionController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /search/suggestions", wrapHandler(controller.Suggest, middlewares...))
+	mux.Handle("GET /search/queries/popular", wrapHandler(controller.Popular, middlewares...))
+}
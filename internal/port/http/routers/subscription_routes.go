@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterSubscriptionRoutes(mux *http.ServeMux, controller *controllers.SubscriptionController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /subscriptions", wrapHandler(controller.Subscribe, middlewares...))
+	mux.Handle("GET /subscriptions", wrapHandler(controller.Get, middlewares...))
+	mux.Handle("GET /subscriptions/usage", wrapHandler(controller.Usage, middlewares...))
+	mux.Handle("PUT /subscriptions/plan", wrapHandler(controller.ChangePlan, middlewares...))
+	mux.Handle("PUT /subscriptions/payment-method", wrapHandler(controller.UpdatePaymentMethod, middlewares...))
+	mux.Handle("POST /subscriptions/cancel", wrapHandler(controller.Cancel, middlewares...))
+	mux.Handle("GET /subscriptions/invoices", wrapHandler(controller.ListInvoices, middlewares...))
+	mux.Handle("POST /subscriptions/invoices/{uuid}/pay", wrapHandler(controller.PayInvoice, middlewares...))
+}
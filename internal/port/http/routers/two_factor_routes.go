@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterTwoFactorRoutes(mux *http.ServeMux, controller *controllers.TwoFactorController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /auth/2fa", wrapHandler(controller.Status, middlewares...))
+	mux.Handle("POST /auth/2fa/enroll", wrapHandler(controller.Enroll, middlewares...))
+	mux.Handle("POST /auth/2fa/enable", wrapHandler(controller.Enable, middlewares...))
+	mux.Handle("POST /auth/2fa/disable", wrapHandler(controller.Disable, middlewares...))
+	mux.Handle("POST /auth/2fa/recovery-codes", wrapHandler(controller.RegenerateRecoveryCodes, middlewares...))
+	mux.Handle("GET /auth/2fa/devices", wrapHandler(controller.ListDevices, middlewares...))
+	mux.Handle("DELETE /auth/2fa/devices/{uuid}", wrapHandler(controller.RevokeDevice, middlewares...))
+}
@@ -11,6 +11,9 @@ func RegisterWebsiteRoutes(mux *http.ServeMux, controller *controllers.WebsiteCo
 	mux.Handle("GET /websites/{uuid}", wrapHandler(controller.GetByUUID, middlewares...))
 	mux.Handle("GET /websites", wrapHandler(controller.ListAll, middlewares...))
 	mux.Handle("GET /websites/owner", wrapHandler(controller.ListByOwner, middlewares...))
+	mux.Handle("GET /websites/{uuid}/permissions", wrapHandler(controller.Permissions, middlewares...))
 	mux.Handle("PUT /websites/{uuid}", wrapHandler(controller.Update, middlewares...))
+	mux.Handle("PUT /websites/{uuid}/email-verification", wrapHandler(controller.UpdateEmailVerification, middlewares...))
+	mux.Handle("PUT /websites/{uuid}/two-factor", wrapHandler(controller.UpdateTwoFactor, middlewares...))
 	mux.Handle("DELETE /websites/{uuid}", wrapHandler(controller.Delete, middlewares...))
 }
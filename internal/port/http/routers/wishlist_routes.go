@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/ViitoJooj/verkoupe/internal/port/http/controllers"
+)
+
+func RegisterWishlistRoutes(mux *http.ServeMux, controller *controllers.WishlistController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("POST /wishlists", wrapHandler(controller.Create, middlewares...))
+	mux.Handle("GET /wishlists", wrapHandler(controller.ListMine, middlewares...))
+	mux.Handle("GET /wishlists/alerts", wrapHandler(controller.ListAlerts, middlewares...))
+	mux.Handle("GET /wishlists/{uuid}", wrapHandler(controller.GetByUUID, middlewares...))
+	mux.Handle("PUT /wishlists/{uuid}", wrapHandler(controller.Update, middlewares...))
+	mux.Handle("DELETE /wishlists/{uuid}", wrapHandler(controller.Delete, middlewares...))
+	mux.Handle("POST /wishlists/{uuid}/share", wrapHandler(controller.Share, middlewares...))
+	mux.Handle("DELETE /wishlists/{uuid}/share", wrapHandler(controller.Unshare, middlewares...))
+	mux.Handle("GET /wishlists/{uuid}/items", wrapHandler(controller.ListItems, middlewares...))
+	mux.Handle("POST /wishlists/{uuid}/items", wrapHandler(controller.AddItem, middlewares...))
+	mux.Handle("DELETE /wishlists/{uuid}/items/{item_uuid}", wrapHandler(controller.RemoveItem, middlewares...))
+}
+
+// RegisterSharedWishlistRoutes serves wishlists through their share links,
+// which are meant to be opened without a session.
+func RegisterSharedWishlistRoutes(mux *http.ServeMux, controller *controllers.WishlistController, middlewares ...func(http.Handler) http.Handler) {
+	mux.Handle("GET /shared/wishlists/{token}", wrapHandler(controller.GetShared, middlewares...))
+}
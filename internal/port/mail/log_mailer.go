@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+var _ contracts.MailerContract = (*LogMailer)(nil)
+
+// LogMailer delivers nothing: it prints every email to the server log, so
+// links in invitations and similar mails can be followed in development.
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{
+		from: from,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, email domain.Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("mail from %s to %s: %s\n%s", m.from, email.To, email.Subject, email.Body)
+	return nil
+}
@@ -0,0 +1,101 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/google/uuid"
+)
+
+var _ contracts.PaymentProviderContract = (*FakeProvider)(nil)
+
+// Payment methods the fake provider treats specially. Any other method is
+// charged successfully.
+const (
+	FakeDeclinedMethod = "fake_declined"
+	FakeErrorMethod    = "fake_error"
+)
+
+// FakeProvider charges nothing. It lets billing run end to end without a
+// gateway: charges to FakeDeclinedMethod are declined, charges to
+// FakeErrorMethod fail as if the provider were down. Results are kept by
+// idempotency key so a retried charge answers like the first one.
+type FakeProvider struct {
+	mu      sync.Mutex
+	charges map[string]*domain.PaymentResult
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		charges: make(map[string]*domain.PaymentResult),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Charge(ctx context.Context, charge domain.PaymentCharge) (*domain.PaymentResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if charge.PaymentMethod == FakeErrorMethod {
+		return nil, errors.New("payment provider unavailable")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.charges[charge.IdempotencyKey]; ok && charge.IdempotencyKey != "" {
+		return result, nil
+	}
+
+	result := &domain.PaymentResult{
+		Reference: "fake_" + uuid.NewString(),
+		Paid:      true,
+	}
+	if charge.PaymentMethod == FakeDeclinedMethod {
+		result.Paid = false
+		result.FailureReason = "card declined"
+	}
+
+	if charge.IdempotencyKey != "" {
+		p.charges[charge.IdempotencyKey] = result
+	}
+
+	return result, nil
+}
+
+// Refund sends back any amount of a charge the fake provider made.
+func (p *FakeProvider) Refund(ctx context.Context, refund domain.PaymentRefund) (*domain.PaymentResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.charges[refund.IdempotencyKey]; ok && refund.IdempotencyKey != "" {
+		return result, nil
+	}
+
+	result := &domain.PaymentResult{
+		Reference: "fake_refund_" + uuid.NewString(),
+		Paid:      true,
+	}
+	if !strings.HasPrefix(refund.Reference, "fake_") {
+		result.Paid = false
+		result.FailureReason = "unknown charge"
+	}
+
+	if refund.IdempotencyKey != "" {
+		p.charges[refund.IdempotencyKey] = result
+	}
+
+	return result, nil
+}
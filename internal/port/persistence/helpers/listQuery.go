@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+type ColumnKind int
+
+const (
+	TextColumn ColumnKind = iota
+	UUIDColumn
+	BoolColumn
+	IntColumn
+	TimeColumn
+)
+
+// parse turns a query string value into the Go type bound for the column, so
+// bad input is rejected before it reaches the database.
+func (k ColumnKind) parse(value string) (interface{}, error) {
+	switch k {
+	case UUIDColumn:
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return id.String(), nil
+	case BoolColumn:
+		return strconv.ParseBool(value)
+	case IntColumn:
+		return strconv.ParseInt(value, 10, 64)
+	case TimeColumn:
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, value)
+	default:
+		return value, nil
+	}
+}
+
+type FilterMatch int
+
+const (
+	MatchEqual FilterMatch = iota
+	MatchContains
+	MatchMin
+	MatchMax
+)
+
+type FilterColumn struct {
+	Column string
+	Kind   ColumnKind
+	Match  FilterMatch
+}
+
+// SortColumn must point at a NOT NULL column: the keyset comparison skips
+// NULLs, so rows with a NULL sort value would never be reached.
+type SortColumn[T any] struct {
+	Column string
+	Kind   ColumnKind
+	Value  func(T) string
+}
+
+// ListSpec whitelists what a list endpoint may sort and filter on. Query is
+// the SELECT ... FROM part; conditions are appended as a WHERE clause.
+// DefaultSort applies when the client asks for none, "-" meaning descending;
+// when empty the rows come in primary key order.
+type ListSpec[T any] struct {
+	Query       string
+	Key         string
+	KeyOf       func(T) uuid.UUID
+	Sorts       map[string]SortColumn[T]
+	Filters     map[string]FilterColumn
+	DefaultSort string
+	Scan        func(*sql.Rows) ([]T, error)
+}
+
+// TimeValue formats a timestamp for a cursor without losing precision.
+func TimeValue(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// QueryPage runs spec with the filters, sort and cursor from q on top of the
+// caller's own conditions, whose placeholders must match args. It reads one
+// row past the limit to know whether another page exists.
+func QueryPage[T any](ctx context.Context, db *sql.DB, spec ListSpec[T], q *domain.ListQuery, conditions []string, args ...interface{}) (*domain.Page[T], error) {
+	if q == nil {
+		q, _ = domain.NewListQuery("", 0, "", nil)
+	}
+
+	sortName, desc := q.Sort, q.Desc
+	if sortName == "" && spec.DefaultSort != "" {
+		sortName = strings.TrimPrefix(spec.DefaultSort, "-")
+		desc = sortName != spec.DefaultSort
+	}
+
+	var sortColumn *SortColumn[T]
+	if sortName != "" && sortName != "uuid" {
+		column, ok := spec.Sorts[sortName]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidListQuery, sortName)
+		}
+		sortColumn = &column
+	}
+
+	names := make([]string, 0, len(q.Filters))
+	for name := range q.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		filter, ok := spec.Filters[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot filter by %q", domain.ErrInvalidListQuery, name)
+		}
+
+		value, err := filter.Kind.parse(q.Filters[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid value for %q", domain.ErrInvalidListQuery, name)
+		}
+
+		switch filter.Match {
+		case MatchContains:
+			args = append(args, "%"+likeEscaper.Replace(fmt.Sprint(value))+"%")
+			conditions = append(conditions, fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, filter.Column, len(args)))
+		case MatchMin:
+			args = append(args, value)
+			conditions = append(conditions, fmt.Sprintf("%s >= $%d", filter.Column, len(args)))
+		case MatchMax:
+			args = append(args, value)
+			conditions = append(conditions, fmt.Sprintf("%s <= $%d", filter.Column, len(args)))
+		default:
+			args = append(args, value)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", filter.Column, len(args)))
+		}
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if q.After != nil {
+		if sortColumn == nil {
+			args = append(args, q.After.UUID)
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d", spec.Key, comparison, len(args)))
+		} else {
+			value, err := sortColumn.Kind.parse(q.After.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidListQuery)
+			}
+			args = append(args, value, q.After.UUID)
+			conditions = append(conditions, fmt.Sprintf("(%s, %s) %s ($%d, $%d)", sortColumn.Column, spec.Key, comparison, len(args)-1, len(args)))
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString(spec.Query)
+	if len(conditions) > 0 {
+		query.WriteString("\n\tWHERE ")
+		query.WriteString(strings.Join(conditions, "\n\tAND "))
+	}
+	query.WriteString("\n\tORDER BY ")
+	if sortColumn != nil {
+		query.WriteString(sortColumn.Column + " " + direction + ", ")
+	}
+	query.WriteString(spec.Key + " " + direction)
+	args = append(args, q.Limit+1)
+	fmt.Fprintf(&query, "\n\tLIMIT $%d", len(args))
+
+	rows, err := db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items, err := spec.Scan(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.Page[T]{Items: items, Limit: q.Limit}
+	if len(items) > q.Limit {
+		page.Items = items[:q.Limit]
+		last := page.Items[q.Limit-1]
+
+		cursor := domain.Cursor{Sort: q.SortKey(), UUID: spec.KeyOf(last)}
+		if sortColumn != nil {
+			cursor.Value = sortColumn.Value(last)
+		}
+		page.NextCursor = cursor.Encode()
+	}
+
+	return page, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
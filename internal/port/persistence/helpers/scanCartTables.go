@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func ScanCarts(rows *sql.Rows) ([]*domain.Cart, error) {
+	var carts []*domain.Cart
+
+	for rows.Next() {
+		c, err := scanCart(rows)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return carts, nil
+}
+
+func ScanCart(row *sql.Row) (*domain.Cart, error) {
+	c, err := scanCart(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("cart not found")
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func scanCart(row rowScanner) (*domain.Cart, error) {
+	c := &domain.Cart{}
+	var recoveryCupomUUID, orderUUID uuid.NullUUID
+
+	err := row.Scan(
+		&c.UUID,
+		&c.WebsiteUUID,
+		&c.UserUUID,
+		&c.Email,
+		&c.Status,
+		&c.RemindersSent,
+		&c.LastReminderAt,
+		&c.RestoredAt,
+		&recoveryCupomUUID,
+		&orderUUID,
+		&c.ActivityAt,
+		&c.UpdatedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if recoveryCupomUUID.Valid {
+		c.RecoveryCupomUUID = &recoveryCupomUUID.UUID
+	}
+	if orderUUID.Valid {
+		c.OrderUUID = &orderUUID.UUID
+	}
+
+	return c, nil
+}
+
+func ScanCartItems(rows *sql.Rows) ([]*domain.CartItem, error) {
+	var items []*domain.CartItem
+
+	for rows.Next() {
+		item := &domain.CartItem{}
+		var variantUUID uuid.NullUUID
+
+		err := rows.Scan(
+			&item.UUID,
+			&item.CartUUID,
+			&item.ProductUUID,
+			&variantUUID,
+			&item.Quantity,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if variantUUID.Valid {
+			item.VariantUUID = &variantUUID.UUID
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func ScanCartRecoverySettings(row *sql.Row) (*domain.CartRecoverySettings, error) {
+	s := &domain.CartRecoverySettings{}
+	var cupomValue, cupomValueType sql.NullString
+
+	err := row.Scan(
+		&s.WebsiteUUID,
+		&s.Active,
+		&s.IdleMinutes,
+		&s.Reminders,
+		&s.IntervalHours,
+		&cupomValue,
+		&cupomValueType,
+		&s.CupomValidDays,
+		&s.UpdatedAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("cart recovery settings not found")
+		}
+		return nil, err
+	}
+
+	s.CupomValue = cupomValue.String
+	s.CupomValueType = enums.CupomValueType(cupomValueType.String)
+	return s, nil
+}
+
+func ScanCartRecoveryTotals(rows *sql.Rows) ([]*domain.CartRecoveryTotal, error) {
+	var totals []*domain.CartRecoveryTotal
+
+	for rows.Next() {
+		t := &domain.CartRecoveryTotal{}
+		if err := rows.Scan(&t.Coin, &t.Orders, &t.Revenue); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanCategories(rows *sql.Rows) ([]*domain.Category, error) {
+	var categories []*domain.Category
+
+	for rows.Next() {
+		c := &domain.Category{}
+		var seoTitle, seoDescription sql.NullString
+		err := rows.Scan(
+			&c.UUID,
+			&c.WebsiteUUID,
+			&c.ParentUUID,
+			&c.Path,
+			&c.Depth,
+			&c.Name,
+			&c.Slug,
+			&c.Position,
+			&seoTitle,
+			&seoDescription,
+			&c.Active,
+			&c.UpdatedAt,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		c.SEOTitle = seoTitle.String
+		c.SEODescription = seoDescription.String
+		categories = append(categories, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func ScanCategory(row *sql.Row) (*domain.Category, error) {
+	c := &domain.Category{}
+	var seoTitle, seoDescription sql.NullString
+
+	err := row.Scan(
+		&c.UUID,
+		&c.WebsiteUUID,
+		&c.ParentUUID,
+		&c.Path,
+		&c.Depth,
+		&c.Name,
+		&c.Slug,
+		&c.Position,
+		&seoTitle,
+		&seoDescription,
+		&c.Active,
+		&c.UpdatedAt,
+		&c.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	c.SEOTitle = seoTitle.String
+	c.SEODescription = seoDescription.String
+	return c, nil
+}
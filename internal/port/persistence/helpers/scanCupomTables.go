@@ -5,21 +5,14 @@ import (
 	"errors"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
 )
 
 func ScanCupoms(rows *sql.Rows) ([]*domain.Cupons, error) {
 	var cupoms []*domain.Cupons
 
 	for rows.Next() {
-		c := &domain.Cupons{}
-		err := rows.Scan(
-			&c.UUID,
-			&c.TagUUID,
-			&c.Label,
-			&c.Description,
-			&c.Value,
-			&c.ValueType,
-		)
+		c, err := scanCupom(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -34,23 +27,49 @@ func ScanCupoms(rows *sql.Rows) ([]*domain.Cupons, error) {
 }
 
 func ScanCupom(row *sql.Row) (*domain.Cupons, error) {
+	c, err := scanCupom(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCupomNotFound
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func scanCupom(row rowScanner) (*domain.Cupons, error) {
 	c := &domain.Cupons{}
+	var websiteUUID, tagUUID uuid.NullUUID
+	var description sql.NullString
+	var usageLimit sql.NullInt64
 
 	err := row.Scan(
 		&c.UUID,
-		&c.TagUUID,
+		&websiteUUID,
+		&tagUUID,
+		&c.Coin,
 		&c.Label,
-		&c.Description,
+		&description,
 		&c.Value,
 		&c.ValueType,
+		&usageLimit,
+		&c.UsedCount,
+		&c.ExpiresAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("cupom not found")
-		}
 		return nil, err
 	}
 
+	c.WebsiteUUID = websiteUUID.UUID
+	c.Description = description.String
+	if tagUUID.Valid {
+		c.TagUUID = &tagUUID.UUID
+	}
+	if usageLimit.Valid {
+		limit := int(usageLimit.Int64)
+		c.UsageLimit = &limit
+	}
+
 	return c, nil
 }
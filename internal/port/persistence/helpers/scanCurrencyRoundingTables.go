@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanCurrencyRoundings(rows *sql.Rows) ([]*domain.CurrencyRounding, error) {
+	var roundings []*domain.CurrencyRounding
+
+	for rows.Next() {
+		c := &domain.CurrencyRounding{}
+		err := rows.Scan(
+			&c.UUID,
+			&c.WebsiteUUID,
+			&c.Coin,
+			&c.Mode,
+			&c.Increment,
+			&c.Ending,
+			&c.UpdatedAt,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		roundings = append(roundings, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roundings, nil
+}
+
+func ScanCurrencyRounding(row *sql.Row) (*domain.CurrencyRounding, error) {
+	c := &domain.CurrencyRounding{}
+
+	err := row.Scan(
+		&c.UUID,
+		&c.WebsiteUUID,
+		&c.Coin,
+		&c.Mode,
+		&c.Increment,
+		&c.Ending,
+		&c.UpdatedAt,
+		&c.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("currency rounding not found")
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+	"math/big"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func ScanExchangeRates(rows *sql.Rows) ([]*domain.ExchangeRate, error) {
+	var rates []*domain.ExchangeRate
+
+	for rows.Next() {
+		e, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func ScanExchangeRate(row *sql.Row) (*domain.ExchangeRate, error) {
+	e, err := scanExchangeRate(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("exchange rate not found")
+		}
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func scanExchangeRate(row rowScanner) (*domain.ExchangeRate, error) {
+	e := &domain.ExchangeRate{}
+	var rate string
+
+	err := row.Scan(
+		&e.UUID,
+		&e.BaseCoin,
+		&e.QuoteCoin,
+		&rate,
+		&e.RateDate,
+		&e.Source,
+		&e.UpdatedAt,
+		&e.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return nil, errors.New("invalid exchange rate")
+	}
+	e.Rate = parsed
+
+	return e, nil
+}
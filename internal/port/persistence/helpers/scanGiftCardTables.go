@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanGiftCards(rows *sql.Rows) ([]*domain.GiftCard, error) {
+	var cards []*domain.GiftCard
+
+	for rows.Next() {
+		g, err := scanGiftCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+func ScanGiftCard(row *sql.Row) (*domain.GiftCard, error) {
+	g, err := scanGiftCard(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func scanGiftCard(row rowScanner) (*domain.GiftCard, error) {
+	g := &domain.GiftCard{}
+	var userUUID, productUUID, orderUUID uuid.NullUUID
+	var recipientEmail, note sql.NullString
+
+	err := row.Scan(
+		&g.UUID,
+		&g.WebsiteUUID,
+		&g.Type,
+		&g.Source,
+		&g.CodeHash,
+		&g.CodeLast4,
+		&g.Coin,
+		&g.InitialAmount,
+		&g.Balance,
+		&userUUID,
+		&productUUID,
+		&orderUUID,
+		&recipientEmail,
+		&note,
+		&g.ExpiresAt,
+		&g.UpdatedAt,
+		&g.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if userUUID.Valid {
+		g.UserUUID = &userUUID.UUID
+	}
+	if productUUID.Valid {
+		g.ProductUUID = &productUUID.UUID
+	}
+	if orderUUID.Valid {
+		g.OrderUUID = &orderUUID.UUID
+	}
+	g.RecipientEmail = recipientEmail.String
+	g.Note = note.String
+
+	return g, nil
+}
+
+func ScanGiftCardTransactions(rows *sql.Rows) ([]*domain.GiftCardTransaction, error) {
+	var transactions []*domain.GiftCardTransaction
+
+	for rows.Next() {
+		t := &domain.GiftCardTransaction{}
+		var orderUUID uuid.NullUUID
+
+		err := rows.Scan(
+			&t.UUID,
+			&t.GiftCardUUID,
+			&t.Type,
+			&t.Amount,
+			&t.BalanceAfter,
+			&orderUUID,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if orderUUID.Valid {
+			t.OrderUUID = &orderUUID.UUID
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/google/uuid"
+)
+
+func ScanLoyaltyRates(rows *sql.Rows) ([]*domain.LoyaltyRate, error) {
+	var rates []*domain.LoyaltyRate
+
+	for rows.Next() {
+		rate := &domain.LoyaltyRate{}
+		if err := rows.Scan(&rate.Coin, &rate.EarnPointsPerUnit, &rate.PointValue); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func ScanLoyaltyTransactions(rows *sql.Rows) ([]*domain.LoyaltyTransaction, error) {
+	var transactions []*domain.LoyaltyTransaction
+
+	for rows.Next() {
+		t, err := scanLoyaltyTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func ScanLoyaltyTransaction(row *sql.Row) (*domain.LoyaltyTransaction, error) {
+	t, err := scanLoyaltyTransaction(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("loyalty transaction not found")
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func scanLoyaltyTransaction(row rowScanner) (*domain.LoyaltyTransaction, error) {
+	t := &domain.LoyaltyTransaction{}
+	var coin sql.NullString
+	var amount sql.NullInt64
+	var orderUUID, reviewUUID uuid.NullUUID
+
+	err := row.Scan(
+		&t.UUID,
+		&t.WebsiteUUID,
+		&t.UserUUID,
+		&t.Type,
+		&t.Points,
+		&t.Remaining,
+		&coin,
+		&amount,
+		&orderUUID,
+		&reviewUUID,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if coin.Valid {
+		c := enums.CoinType(coin.String)
+		t.Coin = &c
+	}
+	if amount.Valid {
+		a := int(amount.Int64)
+		t.Amount = &a
+	}
+	if orderUUID.Valid {
+		t.OrderUUID = &orderUUID.UUID
+	}
+	if reviewUUID.Valid {
+		t.ReviewUUID = &reviewUUID.UUID
+	}
+
+	return t, nil
+}
@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanOrders(rows *sql.Rows) ([]*domain.Order, error) {
+	var orders []*domain.Order
+
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func ScanOrder(row *sql.Row) (*domain.Order, error) {
+	o, err := scanOrder(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	o := &domain.Order{}
+	var cartUUID, cupomUUID uuid.NullUUID
+	var paymentMethod, paymentReference, failureReason sql.NullString
+
+	err := row.Scan(
+		&o.UUID,
+		&o.WebsiteUUID,
+		&o.UserUUID,
+		&cartUUID,
+		&o.Email,
+		&o.Status,
+		&o.Coin,
+		&o.Subtotal,
+		&o.Discount,
+		&o.LoyaltyPoints,
+		&o.LoyaltyDiscount,
+		&o.GiftCardAmount,
+		&o.Total,
+		&o.AmountPaid,
+		&o.RefundedAmount,
+		&o.ProviderRefunded,
+		&cupomUUID,
+		&paymentMethod,
+		&paymentReference,
+		&failureReason,
+		&o.Recovered,
+		&o.PaidAt,
+		&o.UpdatedAt,
+		&o.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if cartUUID.Valid {
+		o.CartUUID = &cartUUID.UUID
+	}
+	if cupomUUID.Valid {
+		o.CupomUUID = &cupomUUID.UUID
+	}
+	o.PaymentMethod = paymentMethod.String
+	o.PaymentReference = paymentReference.String
+	o.FailureReason = failureReason.String
+
+	return o, nil
+}
+
+func ScanOrderItems(rows *sql.Rows) ([]*domain.OrderItem, error) {
+	var items []*domain.OrderItem
+
+	for rows.Next() {
+		item := &domain.OrderItem{}
+		var variantUUID uuid.NullUUID
+
+		err := rows.Scan(
+			&item.UUID,
+			&item.OrderUUID,
+			&item.ProductUUID,
+			&variantUUID,
+			&item.ProductType,
+			&item.Name,
+			&item.Quantity,
+			&item.UnitAmount,
+			&item.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if variantUUID.Valid {
+			item.VariantUUID = &variantUUID.UUID
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func ScanOrderEvents(rows *sql.Rows) ([]*domain.OrderEvent, error) {
+	var events []*domain.OrderEvent
+
+	for rows.Next() {
+		e := &domain.OrderEvent{}
+		var lastError sql.NullString
+
+		err := rows.Scan(
+			&e.UUID,
+			&e.OrderUUID,
+			&e.Hook,
+			&e.Type,
+			&e.Amount,
+			&e.RefundedTo,
+			&e.Status,
+			&e.Attempts,
+			&lastError,
+			&e.RunAt,
+			&e.UpdatedAt,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		e.LastError = lastError.String
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,123 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanOrganizationMembers(rows *sql.Rows) ([]*domain.OrganizationMember, error) {
+	var members []*domain.OrganizationMember
+
+	for rows.Next() {
+		m, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func ScanOrganizationMember(row *sql.Row) (*domain.OrganizationMember, error) {
+	m, err := scanOrganizationMember(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organization member not found")
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func scanOrganizationMember(row rowScanner) (*domain.OrganizationMember, error) {
+	m := &domain.OrganizationMember{}
+	var rbacUUID uuid.NullUUID
+
+	err := row.Scan(
+		&m.UUID,
+		&m.OrganizationUUID,
+		&m.UserUUID,
+		&m.Role,
+		&rbacUUID,
+		&m.Email,
+		&m.Name,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if rbacUUID.Valid {
+		m.RbacUUID = &rbacUUID.UUID
+	}
+
+	return m, nil
+}
+
+func ScanOrganizationInvitations(rows *sql.Rows) ([]*domain.OrganizationInvitation, error) {
+	var invitations []*domain.OrganizationInvitation
+
+	for rows.Next() {
+		i, err := scanOrganizationInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func ScanOrganizationInvitation(row *sql.Row) (*domain.OrganizationInvitation, error) {
+	i, err := scanOrganizationInvitation(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("organization invitation not found")
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func scanOrganizationInvitation(row rowScanner) (*domain.OrganizationInvitation, error) {
+	i := &domain.OrganizationInvitation{}
+	var rbacUUID uuid.NullUUID
+
+	err := row.Scan(
+		&i.UUID,
+		&i.OrganizationUUID,
+		&i.Email,
+		&i.Role,
+		&rbacUUID,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if rbacUUID.Valid {
+		i.RbacUUID = &rbacUUID.UUID
+	}
+
+	return i, nil
+}
@@ -22,6 +22,7 @@ func ScanPlans(rows *sql.Rows) ([]*domain.VerkoupePlan, error) {
 			&plan.CostPerSaleRate,
 			&plan.Coin,
 			&plan.Price,
+			&plan.TrialDays,
 			&plan.UpdatedAt,
 			&plan.CreatedAt,
 		)
@@ -51,6 +52,7 @@ func ScanPlan(row *sql.Row) (*domain.VerkoupePlan, error) {
 		&plan.CostPerSaleRate,
 		&plan.Coin,
 		&plan.Price,
+		&plan.TrialDays,
 		&plan.UpdatedAt,
 		&plan.CreatedAt,
 	)
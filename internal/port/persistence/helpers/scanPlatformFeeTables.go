@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanPlatformFees(rows *sql.Rows) ([]*domain.PlatformFee, error) {
+	var fees []*domain.PlatformFee
+
+	for rows.Next() {
+		f, err := scanPlatformFee(rows)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fees, nil
+}
+
+func ScanPlatformFee(row *sql.Row) (*domain.PlatformFee, error) {
+	f, err := scanPlatformFee(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("platform fee not found")
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func scanPlatformFee(row rowScanner) (*domain.PlatformFee, error) {
+	f := &domain.PlatformFee{}
+	var planUUID uuid.NullUUID
+
+	err := row.Scan(
+		&f.UUID,
+		&f.OwnerUUID,
+		&f.WebsiteUUID,
+		&f.OrderUUID,
+		&planUUID,
+		&f.Type,
+		&f.Coin,
+		&f.Amount,
+		&f.Rate,
+		&f.Fee,
+		&f.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if planUUID.Valid {
+		f.PlanUUID = &planUUID.UUID
+	}
+
+	return f, nil
+}
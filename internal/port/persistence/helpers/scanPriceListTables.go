@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanPriceLists(rows *sql.Rows) ([]*domain.PriceList, error) {
+	var lists []*domain.PriceList
+
+	for rows.Next() {
+		l := &domain.PriceList{}
+		err := rows.Scan(
+			&l.UUID,
+			&l.WebsiteUUID,
+			&l.Label,
+			&l.Coin,
+			&l.CustomerGroup,
+			&l.Priority,
+			&l.Active,
+			&l.UpdatedAt,
+			&l.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func ScanPriceList(row *sql.Row) (*domain.PriceList, error) {
+	l := &domain.PriceList{}
+
+	err := row.Scan(
+		&l.UUID,
+		&l.WebsiteUUID,
+		&l.Label,
+		&l.Coin,
+		&l.CustomerGroup,
+		&l.Priority,
+		&l.Active,
+		&l.UpdatedAt,
+		&l.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("price list not found")
+		}
+		return nil, err
+	}
+
+	return l, nil
+}
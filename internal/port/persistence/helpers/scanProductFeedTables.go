@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanFeedProducts(rows *sql.Rows) ([]*domain.FeedProduct, error) {
+	var products []*domain.FeedProduct
+
+	for rows.Next() {
+		p := &domain.FeedProduct{}
+		var description, shortDescription, brand, gtin, sku, variantLabel, variantGTIN, category sql.NullString
+		var variantUUID uuid.NullUUID
+		var images []byte
+
+		err := rows.Scan(
+			&p.ProductUUID,
+			&p.Name,
+			&description,
+			&shortDescription,
+			&brand,
+			&gtin,
+			&variantUUID,
+			&sku,
+			&variantLabel,
+			&variantGTIN,
+			&p.Digital,
+			&p.Stock,
+			&images,
+			&category,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Description = description.String
+		p.ShortDescription = shortDescription.String
+		p.Brand = brand.String
+		p.GTIN = gtin.String
+		p.Category = category.String
+
+		if variantUUID.Valid {
+			p.VariantUUID = &variantUUID.UUID
+			p.SKU = sku.String
+			p.VariantLabel = variantLabel.String
+			if variantGTIN.String != "" {
+				p.GTIN = variantGTIN.String
+			}
+		}
+
+		if err := json.Unmarshal(images, &p.ImageKeys); err != nil {
+			return nil, err
+		}
+
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
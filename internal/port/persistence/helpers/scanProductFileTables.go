@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductFiles(rows *sql.Rows) ([]*domain.ProductFile, error) {
+	var files []*domain.ProductFile
+
+	for rows.Next() {
+		f := &domain.ProductFile{}
+		err := rows.Scan(
+			&f.UUID,
+			&f.ProductUUID,
+			&f.BlobKey,
+			&f.Filename,
+			&f.ContentType,
+			&f.SizeBytes,
+			&f.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func ScanProductFile(row *sql.Row) (*domain.ProductFile, error) {
+	f := &domain.ProductFile{}
+
+	err := row.Scan(
+		&f.UUID,
+		&f.ProductUUID,
+		&f.BlobKey,
+		&f.Filename,
+		&f.ContentType,
+		&f.SizeBytes,
+		&f.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product file not found")
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func ScanProductDownloads(rows *sql.Rows) ([]*domain.ProductDownload, error) {
+	var downloads []*domain.ProductDownload
+
+	for rows.Next() {
+		d := &domain.ProductDownload{}
+		err := rows.Scan(
+			&d.UUID,
+			&d.OrderUUID,
+			&d.ProductUUID,
+			&d.FileUUID,
+			&d.Filename,
+			&d.ContentType,
+			&d.SizeBytes,
+			&d.BlobKey,
+			&d.DownloadLimit,
+			&d.DownloadCount,
+			&d.ExpiresAt,
+			&d.LastDownloadedAt,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		downloads = append(downloads, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return downloads, nil
+}
+
+func ScanProductDownload(row *sql.Row) (*domain.ProductDownload, error) {
+	d := &domain.ProductDownload{}
+
+	err := row.Scan(
+		&d.UUID,
+		&d.OrderUUID,
+		&d.ProductUUID,
+		&d.FileUUID,
+		&d.Filename,
+		&d.ContentType,
+		&d.SizeBytes,
+		&d.BlobKey,
+		&d.DownloadLimit,
+		&d.DownloadCount,
+		&d.ExpiresAt,
+		&d.LastDownloadedAt,
+		&d.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product download not found")
+		}
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanProductImportJobs(rows *sql.Rows) ([]*domain.ProductImportJob, error) {
+	var jobs []*domain.ProductImportJob
+
+	for rows.Next() {
+		job, err := scanProductImportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func ScanProductImportJob(row *sql.Row) (*domain.ProductImportJob, error) {
+	job, err := scanProductImportJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product import not found")
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func scanProductImportJob(row rowScanner) (*domain.ProductImportJob, error) {
+	job := &domain.ProductImportJob{}
+	var websiteUUID uuid.NullUUID
+	var mapping []byte
+	var reason sql.NullString
+
+	err := row.Scan(
+		&job.UUID,
+		&websiteUUID,
+		&job.Filename,
+		&job.Format,
+		&job.Source,
+		&job.Coin,
+		&job.Status,
+		&job.DryRun,
+		&mapping,
+		&job.BlobKey,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.CreatedCount,
+		&job.FailedCount,
+		&job.WarningCount,
+		&reason,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.UpdatedAt,
+		&job.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(mapping, &job.Mapping); err != nil {
+		return nil, err
+	}
+	job.WebsiteUUID = websiteUUID.UUID
+	job.Error = reason.String
+
+	return job, nil
+}
+
+func ScanProductImportErrors(rows *sql.Rows) ([]*domain.ProductImportError, error) {
+	var problems []*domain.ProductImportError
+
+	for rows.Next() {
+		p := &domain.ProductImportError{}
+		var field sql.NullString
+		err := rows.Scan(
+			&p.UUID,
+			&p.ImportUUID,
+			&p.Row,
+			&p.Level,
+			&field,
+			&p.Message,
+			&p.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		p.Field = field.String
+		problems = append(problems, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+func ScanProductExportRow(rows *sql.Rows) (*domain.ProductExportRow, error) {
+	r := &domain.ProductExportRow{}
+	var description, shortDescription, brand, gtin, sku, variantGTIN, variantLabel, coin sql.NullString
+	var height, width, thickness, price, compareAt sql.NullInt64
+	var variantActive sql.NullBool
+	var tags []byte
+
+	err := rows.Scan(
+		&r.Handle,
+		&r.Name,
+		&description,
+		&shortDescription,
+		&brand,
+		&gtin,
+		&height,
+		&width,
+		&thickness,
+		&r.Active,
+		&tags,
+		&sku,
+		&variantGTIN,
+		&variantLabel,
+		&variantActive,
+		&coin,
+		&price,
+		&compareAt,
+		&r.Stock,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(tags, &r.Tags); err != nil {
+		return nil, err
+	}
+
+	r.Description = description.String
+	r.ShortDescription = shortDescription.String
+	r.Brand = brand.String
+	r.GTIN = gtin.String
+	r.Height = int(height.Int64)
+	r.Width = int(width.Int64)
+	r.Thickness = int(thickness.Int64)
+	r.SKU = sku.String
+	r.VariantGTIN = variantGTIN.String
+	r.VariantLabel = variantLabel.String
+	if variantActive.Valid {
+		r.VariantActive = &variantActive.Bool
+	}
+	r.Coin = coin.String
+	r.Price = nullIntPtr(price)
+	r.CompareAtPrice = nullIntPtr(compareAt)
+
+	return r, nil
+}
@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductMedias(rows *sql.Rows) ([]*domain.ProductMedia, error) {
+	var medias []*domain.ProductMedia
+
+	for rows.Next() {
+		m := &domain.ProductMedia{}
+		var alt, reason sql.NullString
+		var width, height sql.NullInt64
+		err := rows.Scan(
+			&m.UUID,
+			&m.ProductUUID,
+			&m.BlobKey,
+			&m.ContentType,
+			&m.SizeBytes,
+			&alt,
+			&m.Position,
+			&m.Status,
+			&reason,
+			&width,
+			&height,
+			&m.UpdatedAt,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		m.Alt = alt.String
+		m.Error = reason.String
+		m.Width = nullIntPtr(width)
+		m.Height = nullIntPtr(height)
+		medias = append(medias, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return medias, nil
+}
+
+func ScanProductMedia(row *sql.Row) (*domain.ProductMedia, error) {
+	m := &domain.ProductMedia{}
+	var alt, reason sql.NullString
+	var width, height sql.NullInt64
+
+	err := row.Scan(
+		&m.UUID,
+		&m.ProductUUID,
+		&m.BlobKey,
+		&m.ContentType,
+		&m.SizeBytes,
+		&alt,
+		&m.Position,
+		&m.Status,
+		&reason,
+		&width,
+		&height,
+		&m.UpdatedAt,
+		&m.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product media not found")
+		}
+		return nil, err
+	}
+
+	m.Alt = alt.String
+	m.Error = reason.String
+	m.Width = nullIntPtr(width)
+	m.Height = nullIntPtr(height)
+	return m, nil
+}
+
+func ScanProductMediaDerivatives(rows *sql.Rows) ([]*domain.ProductMediaDerivative, error) {
+	var derivatives []*domain.ProductMediaDerivative
+
+	for rows.Next() {
+		d := &domain.ProductMediaDerivative{}
+		err := rows.Scan(
+			&d.UUID,
+			&d.MediaUUID,
+			&d.BlobKey,
+			&d.Format,
+			&d.Width,
+			&d.Height,
+			&d.SizeBytes,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		derivatives = append(derivatives, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return derivatives, nil
+}
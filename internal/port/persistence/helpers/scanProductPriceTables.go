@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductPrices(rows *sql.Rows) ([]*domain.ProductPrice, error) {
+	var prices []*domain.ProductPrice
+
+	for rows.Next() {
+		p := &domain.ProductPrice{}
+		var compareAt, sale sql.NullInt64
+		err := rows.Scan(
+			&p.UUID,
+			&p.ProductUUID,
+			&p.VariantUUID,
+			&p.PriceListUUID,
+			&p.Coin,
+			&p.Amount,
+			&compareAt,
+			&sale,
+			&p.SaleStartsAt,
+			&p.SaleEndsAt,
+			&p.UpdatedAt,
+			&p.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		p.CompareAtAmount = nullIntPtr(compareAt)
+		p.SaleAmount = nullIntPtr(sale)
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func ScanProductPrice(row *sql.Row) (*domain.ProductPrice, error) {
+	p := &domain.ProductPrice{}
+	var compareAt, sale sql.NullInt64
+
+	err := row.Scan(
+		&p.UUID,
+		&p.ProductUUID,
+		&p.VariantUUID,
+		&p.PriceListUUID,
+		&p.Coin,
+		&p.Amount,
+		&compareAt,
+		&sale,
+		&p.SaleStartsAt,
+		&p.SaleEndsAt,
+		&p.UpdatedAt,
+		&p.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product price not found")
+		}
+		return nil, err
+	}
+
+	p.CompareAtAmount = nullIntPtr(compareAt)
+	p.SaleAmount = nullIntPtr(sale)
+	return p, nil
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
@@ -0,0 +1,103 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanProductReviews(rows *sql.Rows) ([]*domain.ProductReview, error) {
+	var reviews []*domain.ProductReview
+
+	for rows.Next() {
+		r, err := scanProductReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func ScanProductReview(row *sql.Row) (*domain.ProductReview, error) {
+	r, err := scanProductReview(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product review not found")
+		}
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func scanProductReview(row rowScanner) (*domain.ProductReview, error) {
+	r := &domain.ProductReview{}
+	var shippedUUID, moderatedBy uuid.NullUUID
+	var title, body, reason sql.NullString
+
+	err := row.Scan(
+		&r.UUID,
+		&r.ProductUUID,
+		&r.UserUUID,
+		&shippedUUID,
+		&r.Rating,
+		&title,
+		&body,
+		&r.Verified,
+		&r.Status,
+		&reason,
+		&moderatedBy,
+		&r.ModeratedAt,
+		&r.UpdatedAt,
+		&r.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if shippedUUID.Valid {
+		r.ShippedUUID = &shippedUUID.UUID
+	}
+	if moderatedBy.Valid {
+		r.ModeratedBy = &moderatedBy.UUID
+	}
+	r.Title = title.String
+	r.Body = body.String
+	r.RejectionReason = reason.String
+	return r, nil
+}
+
+func ScanProductReviewPhotos(rows *sql.Rows) ([]*domain.ProductReviewPhoto, error) {
+	var photos []*domain.ProductReviewPhoto
+
+	for rows.Next() {
+		p := &domain.ProductReviewPhoto{}
+		err := rows.Scan(
+			&p.UUID,
+			&p.ReviewUUID,
+			&p.BlobKey,
+			&p.ContentType,
+			&p.SizeBytes,
+			&p.Position,
+			&p.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
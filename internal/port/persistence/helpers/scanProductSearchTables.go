@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"database/sql"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductSearchHits(rows *sql.Rows) ([]*domain.ProductSearchHit, error) {
+	var hits []*domain.ProductSearchHit
+
+	for rows.Next() {
+		p := &domain.Products{}
+		h := &domain.ProductSearchHit{Product: p}
+		var price sql.NullInt64
+		err := rows.Scan(
+			&p.UUID,
+			&p.Name,
+			&p.Description,
+			&p.ShortDescription,
+			&p.Active,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+			&h.Rank,
+			&price,
+			&h.InStock,
+			&h.NameHighlight,
+			&h.Snippet,
+		)
+		if err != nil {
+			return nil, err
+		}
+		h.Price = nullIntPtr(price)
+		hits = append(hits, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+func ScanFacetCounts(rows *sql.Rows) ([]domain.FacetCount, error) {
+	facets := []domain.FacetCount{}
+
+	for rows.Next() {
+		var f domain.FacetCount
+		if err := rows.Scan(&f.Value, &f.Label, &f.Count); err != nil {
+			return nil, err
+		}
+		facets = append(facets, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
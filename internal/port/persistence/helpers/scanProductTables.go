@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
 )
 
 func ScanProducts(rows *sql.Rows) ([]*domain.Products, error) {
@@ -12,11 +13,21 @@ func ScanProducts(rows *sql.Rows) ([]*domain.Products, error) {
 
 	for rows.Next() {
 		p := &domain.Products{}
+		var websiteUUID uuid.NullUUID
+		var brand, gtin sql.NullString
 		err := rows.Scan(
 			&p.UUID,
+			&websiteUUID,
 			&p.Name,
 			&p.Description,
 			&p.ShortDescription,
+			&brand,
+			&gtin,
+			&p.Type,
+			&p.DownloadLimit,
+			&p.DownloadDays,
+			&p.RatingCount,
+			&p.RatingAverage,
 			&p.Active,
 			&p.CreatedAt,
 			&p.UpdatedAt,
@@ -24,6 +35,49 @@ func ScanProducts(rows *sql.Rows) ([]*domain.Products, error) {
 		if err != nil {
 			return nil, err
 		}
+		if websiteUUID.Valid {
+			p.WebsiteUUID = &websiteUUID.UUID
+		}
+		p.Brand = brand.String
+		p.GTIN = gtin.String
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func ScanCategoryProducts(rows *sql.Rows) ([]*domain.CategoryProduct, error) {
+	var products []*domain.CategoryProduct
+
+	for rows.Next() {
+		p := &domain.CategoryProduct{Products: &domain.Products{}}
+		var brand, gtin sql.NullString
+		err := rows.Scan(
+			&p.UUID,
+			&p.Name,
+			&p.Description,
+			&p.ShortDescription,
+			&brand,
+			&gtin,
+			&p.Type,
+			&p.DownloadLimit,
+			&p.DownloadDays,
+			&p.RatingCount,
+			&p.RatingAverage,
+			&p.Active,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+			&p.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		p.Brand = brand.String
+		p.GTIN = gtin.String
 		products = append(products, p)
 	}
 
@@ -36,12 +90,22 @@ func ScanProducts(rows *sql.Rows) ([]*domain.Products, error) {
 
 func ScanProduct(row *sql.Row) (*domain.Products, error) {
 	p := &domain.Products{}
+	var websiteUUID uuid.NullUUID
+	var brand, gtin sql.NullString
 
 	err := row.Scan(
 		&p.UUID,
+		&websiteUUID,
 		&p.Name,
 		&p.Description,
 		&p.ShortDescription,
+		&brand,
+		&gtin,
+		&p.Type,
+		&p.DownloadLimit,
+		&p.DownloadDays,
+		&p.RatingCount,
+		&p.RatingAverage,
 		&p.Active,
 		&p.CreatedAt,
 		&p.UpdatedAt,
@@ -54,5 +118,10 @@ func ScanProduct(row *sql.Row) (*domain.Products, error) {
 		return nil, err
 	}
 
+	if websiteUUID.Valid {
+		p.WebsiteUUID = &websiteUUID.UUID
+	}
+	p.Brand = brand.String
+	p.GTIN = gtin.String
 	return p, nil
 }
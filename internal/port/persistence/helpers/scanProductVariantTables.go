@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductVariants(rows *sql.Rows) ([]*domain.ProductVariant, error) {
+	var variants []*domain.ProductVariant
+
+	for rows.Next() {
+		v := &domain.ProductVariant{}
+		var sku, gtin sql.NullString
+		err := rows.Scan(
+			&v.UUID,
+			&v.ProductUUID,
+			&sku,
+			&v.Label,
+			&gtin,
+			&v.Active,
+			&v.UpdatedAt,
+			&v.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		v.SKU = sku.String
+		v.GTIN = gtin.String
+		variants = append(variants, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func ScanProductVariant(row *sql.Row) (*domain.ProductVariant, error) {
+	v := &domain.ProductVariant{}
+	var sku, gtin sql.NullString
+
+	err := row.Scan(
+		&v.UUID,
+		&v.ProductUUID,
+		&sku,
+		&v.Label,
+		&gtin,
+		&v.Active,
+		&v.UpdatedAt,
+		&v.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("product variant not found")
+		}
+		return nil, err
+	}
+
+	v.SKU = sku.String
+	v.GTIN = gtin.String
+	return v, nil
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"database/sql"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanProductSuggestions(rows *sql.Rows) ([]*domain.ProductSuggestion, error) {
+	var suggestions []*domain.ProductSuggestion
+
+	for rows.Next() {
+		s := &domain.ProductSuggestion{}
+		if err := rows.Scan(&s.UUID, &s.Name); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func ScanCategorySuggestions(rows *sql.Rows) ([]*domain.CategorySuggestion, error) {
+	var suggestions []*domain.CategorySuggestion
+
+	for rows.Next() {
+		s := &domain.CategorySuggestion{}
+		if err := rows.Scan(&s.UUID, &s.Name, &s.Slug); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func ScanSearchQueries(rows *sql.Rows) ([]*domain.SearchQuery, error) {
+	var queries []*domain.SearchQuery
+
+	for rows.Next() {
+		q := &domain.SearchQuery{}
+		if err := rows.Scan(&q.Query, &q.Searches, &q.LastResults, &q.LastSearchedAt); err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanSubscriptions(rows *sql.Rows) ([]*domain.Subscription, error) {
+	var subscriptions []*domain.Subscription
+
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func ScanSubscription(row *sql.Row) (*domain.Subscription, error) {
+	s, err := scanSubscription(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func scanSubscription(row rowScanner) (*domain.Subscription, error) {
+	s := &domain.Subscription{}
+	var paymentMethod sql.NullString
+
+	err := row.Scan(
+		&s.UUID,
+		&s.OwnerUUID,
+		&s.OwnerType,
+		&s.PlanUUID,
+		&s.Status,
+		&s.Cycle,
+		&paymentMethod,
+		&s.TrialEndsAt,
+		&s.CurrentPeriodStart,
+		&s.CurrentPeriodEnd,
+		&s.CancelAtPeriodEnd,
+		&s.PastDueSince,
+		&s.Credit,
+		&s.UpdatedAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.PaymentMethod = paymentMethod.String
+
+	return s, nil
+}
+
+func ScanSubscriptionInvoices(rows *sql.Rows) ([]*domain.SubscriptionInvoice, error) {
+	var invoices []*domain.SubscriptionInvoice
+
+	for rows.Next() {
+		i, err := scanSubscriptionInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func ScanSubscriptionInvoice(row *sql.Row) (*domain.SubscriptionInvoice, error) {
+	i, err := scanSubscriptionInvoice(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func scanSubscriptionInvoice(row rowScanner) (*domain.SubscriptionInvoice, error) {
+	i := &domain.SubscriptionInvoice{}
+	var providerReference, failureReason sql.NullString
+
+	err := row.Scan(
+		&i.UUID,
+		&i.SubscriptionUUID,
+		&i.OwnerUUID,
+		&i.PlanUUID,
+		&i.Reason,
+		&i.Cycle,
+		&i.Coin,
+		&i.Amount,
+		&i.CreditApplied,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&providerReference,
+		&failureReason,
+		&i.PaidAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	i.ProviderReference = providerReference.String
+	i.FailureReason = failureReason.String
+
+	return i, nil
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanTwoFactor(row *sql.Row) (*domain.TwoFactor, error) {
+	t := &domain.TwoFactor{}
+
+	err := row.Scan(
+		&t.UserUUID,
+		&t.Secret,
+		&t.EnabledAt,
+		&t.LastStep,
+		&t.UpdatedAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func ScanTrustedDevices(rows *sql.Rows) ([]*domain.TrustedDevice, error) {
+	var devices []*domain.TrustedDevice
+
+	for rows.Next() {
+		d := &domain.TrustedDevice{}
+		err := rows.Scan(
+			&d.UUID,
+			&d.UserUUID,
+			&d.Name,
+			&d.TokenHash,
+			&d.ExpiresAt,
+			&d.LastUsedAt,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func ScanTrustedDevice(row *sql.Row) (*domain.TrustedDevice, error) {
+	d := &domain.TrustedDevice{}
+
+	err := row.Scan(
+		&d.UUID,
+		&d.UserUUID,
+		&d.Name,
+		&d.TokenHash,
+		&d.ExpiresAt,
+		&d.LastUsedAt,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("trusted device not found")
+		}
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -21,6 +21,11 @@ func ScanUsers(rows *sql.Rows) ([]*domain.User, error) {
 			&user.Role,
 			&user.Password,
 			&user.CPF,
+			&user.EmailVerifiedAt,
+			&user.SessionsRevokedAt,
+			&user.FailedLogins,
+			&user.LastFailedLoginAt,
+			&user.LockedUntil,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -49,6 +54,11 @@ func ScanUser(row *sql.Row) (*domain.User, error) {
 		&user.Role,
 		&user.Password,
 		&user.CPF,
+		&user.EmailVerifiedAt,
+		&user.SessionsRevokedAt,
+		&user.FailedLogins,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
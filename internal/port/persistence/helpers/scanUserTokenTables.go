@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+)
+
+func ScanUserToken(row *sql.Row) (*domain.UserToken, error) {
+	t := &domain.UserToken{}
+
+	err := row.Scan(
+		&t.UUID,
+		&t.UserUUID,
+		&t.Purpose,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("user token not found")
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
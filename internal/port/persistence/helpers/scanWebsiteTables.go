@@ -20,6 +20,10 @@ func ScanWebsites(rows *sql.Rows) ([]*domain.Website, error) {
 			&w.URL,
 			&w.WriteIn,
 			&w.Description,
+			&w.BaseCoin,
+			&w.SuspendedAt,
+			&w.RequireEmailVerification,
+			&w.RequireTwoFactor,
 			&w.UpdatedAt,
 			&w.CreatedAt,
 		)
@@ -47,6 +51,10 @@ func ScanWebsite(row *sql.Row) (*domain.Website, error) {
 		&w.URL,
 		&w.WriteIn,
 		&w.Description,
+		&w.BaseCoin,
+		&w.SuspendedAt,
+		&w.RequireEmailVerification,
+		&w.RequireTwoFactor,
 		&w.UpdatedAt,
 		&w.CreatedAt,
 	)
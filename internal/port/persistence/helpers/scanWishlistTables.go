@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+)
+
+func ScanWishlists(rows *sql.Rows) ([]*domain.Wishlist, error) {
+	var wishlists []*domain.Wishlist
+
+	for rows.Next() {
+		w, err := scanWishlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		wishlists = append(wishlists, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+func ScanWishlist(row *sql.Row) (*domain.Wishlist, error) {
+	w, err := scanWishlist(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("wishlist not found")
+		}
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func scanWishlist(row rowScanner) (*domain.Wishlist, error) {
+	w := &domain.Wishlist{}
+	var shareToken sql.NullString
+
+	err := row.Scan(
+		&w.UUID,
+		&w.UserUUID,
+		&w.WebsiteUUID,
+		&w.Name,
+		&shareToken,
+		&w.ItemCount,
+		&w.UpdatedAt,
+		&w.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w.ShareToken = shareToken.String
+	return w, nil
+}
+
+func ScanWishlistItems(rows *sql.Rows) ([]*domain.WishlistItem, error) {
+	var items []*domain.WishlistItem
+
+	for rows.Next() {
+		item := &domain.WishlistItem{}
+		var variantUUID uuid.NullUUID
+
+		err := rows.Scan(
+			&item.UUID,
+			&item.WishlistUUID,
+			&item.ProductUUID,
+			&variantUUID,
+			&item.InStock,
+			&item.OnSale,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if variantUUID.Valid {
+			item.VariantUUID = &variantUUID.UUID
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func ScanWishlistAlerts(rows *sql.Rows) ([]*domain.WishlistAlert, error) {
+	var alerts []*domain.WishlistAlert
+
+	for rows.Next() {
+		alert := &domain.WishlistAlert{}
+		var variantUUID uuid.NullUUID
+
+		err := rows.Scan(
+			&alert.UUID,
+			&alert.Type,
+			&alert.WishlistUUID,
+			&alert.ItemUUID,
+			&alert.UserUUID,
+			&alert.WebsiteUUID,
+			&alert.ProductUUID,
+			&variantUUID,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if variantUUID.Valid {
+			alert.VariantUUID = &variantUUID.UUID
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
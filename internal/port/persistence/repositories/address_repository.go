@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.AddressContract = (*AddressRepository)(nil)
@@ -92,54 +93,49 @@ func (r *AddressRepository) FindDefaultAddressByOwner(ownerUUID string, websiteU
 	return helpers.ScanAddress(row)
 }
 
-func (r *AddressRepository) GetAddressesFromOwner(ownerUUID string, websiteUUID string) ([]*domain.AddressBR, error) {
+var addressListSpec = helpers.ListSpec[*domain.AddressBR]{
+	Query: `SELECT uuid, website_uuid, owner_uuid, owner_type, label, address_line1, address_line2, neighborhood, city, state, state_code, postal_code, reference_point, delivery_notes, is_default, created_at, updated_at
+	FROM addresses`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.AddressBR) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.AddressBR]{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.AddressBR) string { return v.Label }},
+		"city":       {Column: "city", Kind: helpers.TextColumn, Value: func(v *domain.AddressBR) string { return v.City }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.AddressBR) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":          {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"city":           {Column: "city", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"state_code":     {Column: "state_code", Kind: helpers.TextColumn},
+		"postal_code":    {Column: "postal_code", Kind: helpers.TextColumn},
+		"owner_uuid":     {Column: "owner_uuid", Kind: helpers.UUIDColumn},
+		"owner_type":     {Column: "owner_type", Kind: helpers.TextColumn},
+		"is_default":     {Column: "is_default", Kind: helpers.BoolColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanAddresses,
+}
+
+func (r *AddressRepository) GetAddressesFromOwner(ownerUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, address_line1, address_line2, neighborhood, city, state, state_code, postal_code, reference_point, delivery_notes, is_default, created_at, updated_at
-	FROM addresses
-	WHERE owner_uuid = $1 AND website_uuid = $2`
-
-	rows, err := r.db.QueryContext(ctx, query, ownerUUID, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanAddresses(rows)
+	return helpers.QueryPage(ctx, r.db, addressListSpec, query, []string{"owner_uuid = $1 AND website_uuid = $2"}, ownerUUID, websiteUUID)
 }
 
-func (r *AddressRepository) GetAddressesFromWebsite(websiteUUID string) ([]*domain.AddressBR, error) {
+func (r *AddressRepository) GetAddressesFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, address_line1, address_line2, neighborhood, city, state, state_code, postal_code, reference_point, delivery_notes, is_default, created_at, updated_at
-	FROM addresses
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanAddresses(rows)
+	return helpers.QueryPage(ctx, r.db, addressListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *AddressRepository) GetAddresses() ([]*domain.AddressBR, error) {
+func (r *AddressRepository) GetAddresses(query *domain.ListQuery) (*domain.Page[*domain.AddressBR], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, address_line1, address_line2, neighborhood, city, state, state_code, postal_code, reference_point, delivery_notes, is_default, created_at, updated_at
-	FROM addresses`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanAddresses(rows)
+	return helpers.QueryPage(ctx, r.db, addressListSpec, query, nil)
 }
 
 func (r *AddressRepository) UpdateAddressByUUID(uuid string) error {
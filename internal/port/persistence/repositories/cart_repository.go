@@ -0,0 +1,375 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.CartContract = (*CartRepository)(nil)
+
+const cartColumns = `c.uuid, c.website_uuid, c.user_uuid, c.email, c.status, c.reminders_sent,
+	c.last_reminder_at, c.restored_at, c.recovery_cupom_uuid, c.order_uuid,
+	c.activity_at, c.updated_at, c.created_at`
+
+const cartItemColumns = `uuid, cart_uuid, product_uuid, variant_uuid, quantity, created_at`
+
+const cartRecoverySettingsColumns = `website_uuid, active, idle_minutes, reminders, interval_hours,
+	cupom_value, cupom_value_type, cupom_valid_days, updated_at, created_at`
+
+type CartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *CartRepository {
+	return &CartRepository{
+		db: db,
+	}
+}
+
+// CreateCart returns the shopper's open cart on the website, creating it
+// when there is none. Two requests racing to create it get the same cart.
+func (r *CartRepository) CreateCart(cart *domain.Cart) (*domain.Cart, error) {
+	if cart == nil {
+		return nil, errors.New("invalid cart")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO carts AS c (website_uuid, user_uuid, email, status)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (website_uuid, user_uuid) WHERE status = 'open'
+	DO UPDATE SET email = EXCLUDED.email
+	RETURNING ` + cartColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		query,
+		cart.WebsiteUUID,
+		cart.UserUUID,
+		cart.Email,
+		cart.Status,
+	)
+
+	created, err := helpers.ScanCart(row)
+	if err != nil {
+		return nil, errors.New("could not create cart")
+	}
+
+	return created, r.loadItems(ctx, created)
+}
+
+func (r *CartRepository) FindCartByUUID(uuid string) (*domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + cartColumns + `
+	FROM carts c
+	WHERE c.uuid = $1`
+
+	cart, err := helpers.ScanCart(r.db.QueryRowContext(ctx, query, uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	return cart, r.loadItems(ctx, cart)
+}
+
+func (r *CartRepository) FindOpenCart(websiteUUID string, userUUID string) (*domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + cartColumns + `
+	FROM carts c
+	WHERE c.website_uuid = $1 AND c.user_uuid = $2 AND c.status = 'open'`
+
+	cart, err := helpers.ScanCart(r.db.QueryRowContext(ctx, query, websiteUUID, userUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	return cart, r.loadItems(ctx, cart)
+}
+
+func (r *CartRepository) loadItems(ctx context.Context, cart *domain.Cart) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+cartItemColumns+` FROM carts_items WHERE cart_uuid = $1 ORDER BY created_at, uuid`, cart.UUID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	items, err := helpers.ScanCartItems(rows)
+	if err != nil {
+		return err
+	}
+
+	cart.Items = items
+	return nil
+}
+
+// SaveCartItem adds the item to its cart. Adding a product already in the
+// cart adds to its quantity instead, up to domain.MaxCartItemQuantity.
+func (r *CartRepository) SaveCartItem(item *domain.CartItem) (*domain.CartItem, error) {
+	if item == nil {
+		return nil, errors.New("invalid cart item")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO carts_items (cart_uuid, product_uuid, variant_uuid, quantity)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (cart_uuid, product_uuid, COALESCE(variant_uuid, '00000000-0000-0000-0000-000000000000'))
+	DO UPDATE SET quantity = LEAST(carts_items.quantity + EXCLUDED.quantity, $5)
+	RETURNING uuid, quantity, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		item.CartUUID,
+		item.ProductUUID,
+		item.VariantUUID,
+		item.Quantity,
+		domain.MaxCartItemQuantity,
+	).Scan(
+		&item.UUID,
+		&item.Quantity,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.New("could not add cart item")
+	}
+
+	if err := touchCart(ctx, tx, item.CartUUID.String()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *CartRepository) UpdateCartItemQuantity(cartUUID string, itemUUID string, quantity int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE carts_items SET quantity = $3 WHERE cart_uuid = $1 AND uuid = $2`, cartUUID, itemUUID, quantity)
+	if err != nil {
+		return err
+	}
+
+	if err := requireAffected(result, "cart item not found"); err != nil {
+		return err
+	}
+
+	if err := touchCart(ctx, tx, cartUUID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CartRepository) RemoveCartItem(cartUUID string, itemUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM carts_items WHERE cart_uuid = $1 AND uuid = $2`, cartUUID, itemUUID)
+	if err != nil {
+		return err
+	}
+
+	if err := requireAffected(result, "cart item not found"); err != nil {
+		return err
+	}
+
+	if err := touchCart(ctx, tx, cartUUID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// touchCart records activity on the cart, which holds back its recovery
+// reminders.
+func touchCart(ctx context.Context, tx *sql.Tx, cartUUID string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE carts SET activity_at = NOW(), updated_at = NOW() WHERE uuid = $1`, cartUUID)
+	return err
+}
+
+func requireAffected(result sql.Result, notFound string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(notFound)
+	}
+
+	return nil
+}
+
+func (r *CartRepository) MarkCartRestored(cartUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE carts
+		SET restored_at = COALESCE(restored_at, NOW()), activity_at = NOW(), updated_at = NOW()
+		WHERE uuid = $1 AND status = 'open'`,
+		cartUUID,
+	)
+	return err
+}
+
+// GetCartsDueReminder returns open carts with items whose website recovers
+// carts, idle long enough and, after a first reminder, waiting long enough
+// since the last one. The longest idle come first.
+func (r *CartRepository) GetCartsDueReminder(ctx context.Context, limit int) ([]*domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + cartColumns + `
+	FROM carts c
+	JOIN carts_recovery_settings s ON s.website_uuid = c.website_uuid AND s.active
+	WHERE c.status = 'open'
+	AND c.email <> ''
+	AND c.reminders_sent < s.reminders
+	AND c.activity_at <= NOW() - make_interval(mins => s.idle_minutes)
+	AND (c.last_reminder_at IS NULL OR c.last_reminder_at <= NOW() - make_interval(hours => s.interval_hours))
+	AND EXISTS (SELECT 1 FROM carts_items i WHERE i.cart_uuid = c.uuid)
+	ORDER BY c.activity_at, c.uuid
+	LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanCarts(rows)
+}
+
+// ClaimCartReminder counts reminder number sent+1 as sent, unless another
+// run got to the cart first, the cart was ordered or the coupon of the
+// reminder could not be linked. cupomUUID may be nil.
+func (r *CartRepository) ClaimCartReminder(cartUUID uuid.UUID, sent int, cupomUUID *uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE carts
+		SET reminders_sent = reminders_sent + 1, last_reminder_at = NOW(),
+			recovery_cupom_uuid = COALESCE($3, recovery_cupom_uuid), updated_at = NOW()
+		WHERE uuid = $1 AND reminders_sent = $2 AND status = 'open'`,
+		cartUUID,
+		sent,
+		cupomUUID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *CartRepository) FindCartRecoverySettings(websiteUUID string) (*domain.CartRecoverySettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + cartRecoverySettingsColumns + `
+	FROM carts_recovery_settings
+	WHERE website_uuid = $1`
+
+	return helpers.ScanCartRecoverySettings(r.db.QueryRowContext(ctx, query, websiteUUID))
+}
+
+func (r *CartRepository) SaveCartRecoverySettings(settings *domain.CartRecoverySettings) (*domain.CartRecoverySettings, error) {
+	if settings == nil {
+		return nil, errors.New("invalid cart recovery settings")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO carts_recovery_settings (website_uuid, active, idle_minutes, reminders, interval_hours, cupom_value, cupom_value_type, cupom_valid_days)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8)
+	ON CONFLICT (website_uuid) DO UPDATE SET
+		active = EXCLUDED.active,
+		idle_minutes = EXCLUDED.idle_minutes,
+		reminders = EXCLUDED.reminders,
+		interval_hours = EXCLUDED.interval_hours,
+		cupom_value = EXCLUDED.cupom_value,
+		cupom_value_type = EXCLUDED.cupom_value_type,
+		cupom_valid_days = EXCLUDED.cupom_valid_days,
+		updated_at = NOW()
+	RETURNING ` + cartRecoverySettingsColumns
+
+	return helpers.ScanCartRecoverySettings(r.db.QueryRowContext(
+		ctx,
+		query,
+		settings.WebsiteUUID,
+		settings.Active,
+		settings.IdleMinutes,
+		settings.Reminders,
+		settings.IntervalHours,
+		settings.CupomValue,
+		string(settings.CupomValueType),
+		settings.CupomValidDays,
+	))
+}
+
+// GetCartRecoveryTotals sums the recovered orders of the website paid in
+// [start, end), per currency, net of refunds.
+func (r *CartRepository) GetCartRecoveryTotals(websiteUUID string, start time.Time, end time.Time) ([]*domain.CartRecoveryTotal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT coin, COUNT(*), COALESCE(SUM(total - refunded_amount), 0)
+	FROM orders
+	WHERE website_uuid = $1 AND recovered AND paid_at >= $2 AND paid_at < $3
+	GROUP BY coin
+	ORDER BY coin`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanCartRecoveryTotals(rows)
+}
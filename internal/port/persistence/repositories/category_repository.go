@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.CategoryContract = (*CategoryRepository)(nil)
+
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+func (r *CategoryRepository) CreateCategory(category *domain.Category) (*domain.Category, error) {
+	if category == nil {
+		return nil, errors.New("invalid category")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	parentPath := ""
+	depth := 0
+	if category.ParentUUID != nil {
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT path, depth FROM categories WHERE uuid = $1 AND website_uuid = $2 FOR UPDATE`,
+			category.ParentUUID,
+			category.WebsiteUUID,
+		).Scan(&parentPath, &depth)
+		if err != nil {
+			return nil, errors.New("parent category not found")
+		}
+		depth++
+	}
+
+	query := `INSERT INTO categories (website_uuid, parent_uuid, depth, name, slug, position, seo_title, seo_description, active)
+	VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+	RETURNING uuid, created_at, updated_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		category.WebsiteUUID,
+		category.ParentUUID,
+		depth,
+		category.Name,
+		category.Slug,
+		category.Position,
+		category.SEOTitle,
+		category.SEODescription,
+		category.Active,
+	).Scan(
+		&category.UUID,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.New("could not create category")
+	}
+
+	category.Path = parentPath + category.UUID.String() + "/"
+	category.Depth = depth
+
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET path = $2 WHERE uuid = $1`, category.UUID, category.Path); err != nil {
+		return nil, errors.New("could not create category")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (r *CategoryRepository) FindCategoryByUUID(uuid string) (*domain.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, website_uuid, parent_uuid, path, depth, name, slug, position, seo_title, seo_description, active, updated_at, created_at
+	FROM categories
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanCategory(row)
+}
+
+func (r *CategoryRepository) FindCategoryBySlugAndWebsite(slug string, websiteUUID string) (*domain.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, website_uuid, parent_uuid, path, depth, name, slug, position, seo_title, seo_description, active, updated_at, created_at
+	FROM categories
+	WHERE slug = $1 AND website_uuid = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, slug, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories, err := helpers.ScanCategories(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	return categories[0], nil
+}
+
+func (r *CategoryRepository) GetCategoriesFromWebsite(websiteUUID string) ([]*domain.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, website_uuid, parent_uuid, path, depth, name, slug, position, seo_title, seo_description, active, updated_at, created_at
+	FROM categories
+	WHERE website_uuid = $1
+	ORDER BY depth, position, name`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanCategories(rows)
+}
+
+func (r *CategoryRepository) CountCategoryChildren(uuid string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM categories WHERE parent_uuid = $1`, uuid).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MoveCategory re-parents category and its whole subtree under parent (or to
+// the root when parent is nil). Paths are re-read under lock so a concurrent
+// move cannot sneak a cycle in between the use case check and the update.
+func (r *CategoryRepository) MoveCategory(category *domain.Category, parent *domain.Category, position int) error {
+	if category == nil {
+		return errors.New("invalid category")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "categories:"+category.WebsiteUUID.String()); err != nil {
+		return err
+	}
+
+	var oldPath string
+	var oldDepth int
+	err = tx.QueryRowContext(ctx, `SELECT path, depth FROM categories WHERE uuid = $1`, category.UUID).Scan(&oldPath, &oldDepth)
+	if err != nil {
+		return errors.New("category not found")
+	}
+
+	newPath := category.UUID.String() + "/"
+	newDepth := 0
+	var parentUUID interface{}
+	if parent != nil {
+		var parentPath string
+		var parentDepth int
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT path, depth FROM categories WHERE uuid = $1 AND website_uuid = $2`,
+			parent.UUID,
+			category.WebsiteUUID,
+		).Scan(&parentPath, &parentDepth)
+		if err != nil {
+			return errors.New("parent category not found")
+		}
+
+		if strings.HasPrefix(parentPath, oldPath) {
+			return errors.New("category cannot be moved into itself or its descendants")
+		}
+
+		newPath = parentPath + newPath
+		newDepth = parentDepth + 1
+		parentUUID = parent.UUID
+	}
+
+	query := `UPDATE categories
+	SET path = $3 || SUBSTRING(path FROM LENGTH($2) + 1), depth = depth + $4, updated_at = NOW()
+	WHERE website_uuid = $1 AND path LIKE $2 || '%'`
+
+	if _, err := tx.ExecContext(ctx, query, category.WebsiteUUID, oldPath, newPath, newDepth-oldDepth); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET parent_uuid = $2, position = $3 WHERE uuid = $1`, category.UUID, parentUUID, position); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CategoryRepository) DeleteCategoryByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM products_categories WHERE category_uuid = $1`, uuid); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE uuid = $1`, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("category not found")
+	}
+
+	return tx.Commit()
+}
+
+func (r *CategoryRepository) AssignProductToCategory(productCategory *domain.ProductCategory) error {
+	if productCategory == nil {
+		return errors.New("invalid product category")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO products_categories (product_uuid, category_uuid, position)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (product_uuid, category_uuid) DO UPDATE SET position = EXCLUDED.position
+	RETURNING created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		productCategory.ProductUUID,
+		productCategory.CategoryUUID,
+		productCategory.Position,
+	).Scan(&productCategory.CreatedAt)
+
+	if err != nil {
+		return errors.New("could not assign product to category")
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) RemoveProductFromCategory(productUUID string, categoryUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM products_categories WHERE product_uuid = $1 AND category_uuid = $2`
+
+	result, err := r.db.ExecContext(ctx, query, productUUID, categoryUUID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product not in category")
+	}
+
+	return nil
+}
+
+var categoryProductListSpec = helpers.ListSpec[*domain.CategoryProduct]{
+	Key:   "p.uuid",
+	KeyOf: func(v *domain.CategoryProduct) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.CategoryProduct]{
+		"position":   {Column: "matched.rank", Kind: helpers.IntColumn, Value: func(v *domain.CategoryProduct) string { return strconv.FormatInt(v.Rank, 10) }},
+		"name":       {Column: "p.name", Kind: helpers.TextColumn, Value: func(v *domain.CategoryProduct) string { return v.Name }},
+		"created_at": {Column: "p.created_at", Kind: helpers.TimeColumn, Value: func(v *domain.CategoryProduct) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":   {Column: "p.name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"active": {Column: "p.active", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "position",
+	Scan:        helpers.ScanCategoryProducts,
+}
+
+func (r *CategoryRepository) GetProductsFromCategory(category *domain.Category, withDescendants bool, query *domain.ListQuery) (*domain.Page[*domain.CategoryProduct], error) {
+	if category == nil {
+		return nil, errors.New("invalid category")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := "c.uuid = $1"
+	args := []interface{}{category.UUID}
+	if withDescendants {
+		filter = "c.website_uuid = $1 AND c.path LIKE $2 || '%'"
+		args = []interface{}{category.WebsiteUUID, category.Path}
+	}
+
+	// Depth and position are packed into one bigint so the pair can serve as
+	// a single keyset column; any int32 position keeps its order.
+	spec := categoryProductListSpec
+	spec.Query = fmt.Sprintf(`SELECT p.uuid, p.name, p.description, p.short_description, p.brand, p.gtin, p.type, p.download_limit, p.download_days, p.rating_count, p.rating_average, p.active, p.created_at, p.updated_at, matched.rank
+	FROM products p
+	INNER JOIN (
+		SELECT pc.product_uuid, MIN(c.depth)::BIGINT * 4294967296 + MIN(pc.position) AS rank
+		FROM products_categories pc
+		INNER JOIN categories c ON c.uuid = pc.category_uuid
+		WHERE %s
+		GROUP BY pc.product_uuid
+	) matched ON matched.product_uuid = p.uuid`, filter)
+
+	return helpers.QueryPage(ctx, r.db, spec, query, nil, args...)
+}
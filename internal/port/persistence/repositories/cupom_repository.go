@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.CupomContract = (*CupomRepository)(nil)
@@ -33,18 +34,23 @@ func (r *CupomRepository) CreateCupom(cupom *domain.Cupons) (*domain.Cupons, err
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO cupons (tag_uuid, label, description, value, value_type)
-	VALUES ($1, $2, $3, $4, $5)`
+	query := `INSERT INTO cupons (website_uuid, tag_uuid, coin, label, description, value, value_type, usage_limit, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING uuid`
 
-	_, err := r.db.ExecContext(
+	err := r.db.QueryRowContext(
 		ctx,
 		query,
+		cupom.WebsiteUUID,
 		cupom.TagUUID,
+		cupom.Coin,
 		cupom.Label,
 		cupom.Description,
 		cupom.Value,
 		cupom.ValueType,
-	)
+		cupom.UsageLimit,
+		cupom.ExpiresAt,
+	).Scan(&cupom.UUID)
 
 	if err != nil {
 		return nil, errors.New("could not create cupom")
@@ -57,7 +63,7 @@ func (r *CupomRepository) FindCupomByUUID(uuid string) (*domain.Cupons, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, tag_uuid, label, description, value, value_type
+	query := `SELECT uuid, website_uuid, tag_uuid, coin, label, description, value, value_type, usage_limit, used_count, expires_at
 	FROM cupons
 	WHERE uuid = $1`
 
@@ -69,7 +75,7 @@ func (r *CupomRepository) FindCupomByLabel(label string) (*domain.Cupons, error)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, tag_uuid, label, description, value, value_type
+	query := `SELECT uuid, website_uuid, tag_uuid, coin, label, description, value, value_type, usage_limit, used_count, expires_at
 	FROM cupons
 	WHERE label = $1`
 
@@ -77,37 +83,73 @@ func (r *CupomRepository) FindCupomByLabel(label string) (*domain.Cupons, error)
 	return helpers.ScanCupom(row)
 }
 
-func (r *CupomRepository) GetCuponsFromTag(tagUUID string) ([]*domain.Cupons, error) {
+var cupomListSpec = helpers.ListSpec[*domain.Cupons]{
+	Query: `SELECT uuid, website_uuid, tag_uuid, coin, label, description, value, value_type, usage_limit, used_count, expires_at
+	FROM cupons`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Cupons) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Cupons]{
+		"label": {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.Cupons) string { return v.Label }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"tag_uuid":   {Column: "tag_uuid", Kind: helpers.UUIDColumn},
+		"coin":       {Column: "coin", Kind: helpers.TextColumn},
+		"value_type": {Column: "value_type", Kind: helpers.TextColumn},
+	},
+	Scan: helpers.ScanCupoms,
+}
+
+func (r *CupomRepository) GetCuponsFromTag(tagUUID string, query *domain.ListQuery) (*domain.Page[*domain.Cupons], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, tag_uuid, label, description, value, value_type
-	FROM cupons
-	WHERE tag_uuid = $1`
+	return helpers.QueryPage(ctx, r.db, cupomListSpec, query, []string{"tag_uuid = $1"}, tagUUID)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, tagUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (r *CupomRepository) GetCupons(query *domain.ListQuery) (*domain.Page[*domain.Cupons], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	return helpers.ScanCupoms(rows)
+	return helpers.QueryPage(ctx, r.db, cupomListSpec, query, nil)
 }
 
-func (r *CupomRepository) GetCupons() ([]*domain.Cupons, error) {
+// UseCupom counts one more order using the coupon, failing with
+// domain.ErrCupomUsedUp once its usage limit is reached.
+func (r *CupomRepository) UseCupom(uuid string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, tag_uuid, label, description, value, value_type
-	FROM cupons`
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE cupons
+		SET used_count = used_count + 1
+		WHERE uuid = $1 AND (usage_limit IS NULL OR used_count < usage_limit)`,
+		uuid,
+	)
+	if err != nil {
+		return err
+	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCupomUsedUp
 	}
-	defer rows.Close()
 
-	return helpers.ScanCupoms(rows)
+	return nil
+}
+
+// ReleaseCupom gives back a use taken by an order that was not paid.
+func (r *CupomRepository) ReleaseCupom(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE cupons SET used_count = used_count - 1 WHERE uuid = $1 AND used_count > 0`, uuid)
+	return err
 }
 
 func (r *CupomRepository) UpdateCupomByUUID(uuid string) error {
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.CurrencyRoundingContract = (*CurrencyRoundingRepository)(nil)
+
+type CurrencyRoundingRepository struct {
+	db *sql.DB
+}
+
+func NewCurrencyRoundingRepository(db *sql.DB) *CurrencyRoundingRepository {
+	return &CurrencyRoundingRepository{
+		db: db,
+	}
+}
+
+func (r *CurrencyRoundingRepository) UpsertCurrencyRounding(rounding *domain.CurrencyRounding) (*domain.CurrencyRounding, error) {
+	if rounding == nil {
+		return nil, errors.New("invalid currency rounding")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO currencies_roundings (website_uuid, coin, mode, increment, ending)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (website_uuid, coin)
+	DO UPDATE SET mode = EXCLUDED.mode, increment = EXCLUDED.increment, ending = EXCLUDED.ending, updated_at = NOW()
+	RETURNING uuid, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		rounding.WebsiteUUID,
+		rounding.Coin,
+		rounding.Mode,
+		rounding.Increment,
+		rounding.Ending,
+	).Scan(
+		&rounding.UUID,
+		&rounding.CreatedAt,
+		&rounding.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not save currency rounding")
+	}
+
+	return rounding, nil
+}
+
+func (r *CurrencyRoundingRepository) FindCurrencyRounding(websiteUUID string, coin string) (*domain.CurrencyRounding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, website_uuid, coin, mode, increment, ending, updated_at, created_at
+	FROM currencies_roundings
+	WHERE website_uuid = $1 AND coin = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID, coin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roundings, err := helpers.ScanCurrencyRoundings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roundings) == 0 {
+		return nil, nil
+	}
+
+	return roundings[0], nil
+}
+
+var currencyRoundingListSpec = helpers.ListSpec[*domain.CurrencyRounding]{
+	Query: `SELECT uuid, website_uuid, coin, mode, increment, ending, updated_at, created_at
+	FROM currencies_roundings`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.CurrencyRounding) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.CurrencyRounding]{
+		"coin":       {Column: "coin", Kind: helpers.TextColumn, Value: func(v *domain.CurrencyRounding) string { return string(v.Coin) }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.CurrencyRounding) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"coin": {Column: "coin", Kind: helpers.TextColumn},
+		"mode": {Column: "mode", Kind: helpers.TextColumn},
+	},
+	DefaultSort: "coin",
+	Scan:        helpers.ScanCurrencyRoundings,
+}
+
+func (r *CurrencyRoundingRepository) GetCurrencyRoundingsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.CurrencyRounding], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, currencyRoundingListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
+}
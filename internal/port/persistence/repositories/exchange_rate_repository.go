@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.ExchangeRateContract = (*ExchangeRateRepository)(nil)
+
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db: db,
+	}
+}
+
+func (r *ExchangeRateRepository) UpsertExchangeRate(rate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	if rate == nil {
+		return nil, errors.New("invalid exchange rate")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO exchanges_rates (base_coin, quote_coin, rate, rate_date, source)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (base_coin, quote_coin, rate_date)
+	DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source, updated_at = NOW()
+	RETURNING uuid, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		rate.BaseCoin,
+		rate.QuoteCoin,
+		rate.Rate.FloatString(10),
+		rate.RateDate,
+		rate.Source,
+	).Scan(
+		&rate.UUID,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not save exchange rate")
+	}
+
+	return rate, nil
+}
+
+func (r *ExchangeRateRepository) FindLatestExchangeRate(baseCoin string, quoteCoin string, at time.Time) (*domain.ExchangeRate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, base_coin, quote_coin, rate, rate_date, source, updated_at, created_at
+	FROM exchanges_rates
+	WHERE base_coin = $1 AND quote_coin = $2 AND rate_date <= $3
+	ORDER BY rate_date DESC
+	LIMIT 1`
+
+	row := r.db.QueryRowContext(ctx, query, baseCoin, quoteCoin, at)
+	return helpers.ScanExchangeRate(row)
+}
+
+var exchangeRateListSpec = helpers.ListSpec[*domain.ExchangeRate]{
+	Query: `SELECT uuid, base_coin, quote_coin, rate, rate_date, source, updated_at, created_at
+	FROM exchanges_rates`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ExchangeRate) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ExchangeRate]{
+		"base_coin":  {Column: "base_coin", Kind: helpers.TextColumn, Value: func(v *domain.ExchangeRate) string { return string(v.BaseCoin) }},
+		"quote_coin": {Column: "quote_coin", Kind: helpers.TextColumn, Value: func(v *domain.ExchangeRate) string { return string(v.QuoteCoin) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"base_coin":  {Column: "base_coin", Kind: helpers.TextColumn},
+		"quote_coin": {Column: "quote_coin", Kind: helpers.TextColumn},
+		"source":     {Column: "source", Kind: helpers.TextColumn},
+	},
+	DefaultSort: "quote_coin",
+	Scan:        helpers.ScanExchangeRates,
+}
+
+func (r *ExchangeRateRepository) GetExchangeRatesByDate(rateDate time.Time, query *domain.ListQuery) (*domain.Page[*domain.ExchangeRate], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, exchangeRateListSpec, query, []string{"rate_date = $1"}, rateDate)
+}
@@ -0,0 +1,423 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.GiftCardContract = (*GiftCardRepository)(nil)
+
+const giftCardColumns = `uuid, website_uuid, type, source, code_hash, code_last4, coin, initial_amount, balance, user_uuid, product_uuid, order_uuid, recipient_email, note, expires_at, updated_at, created_at`
+
+const giftCardTransactionColumns = `uuid, gift_card_uuid, type, amount, balance_after, order_uuid, created_at`
+
+type GiftCardRepository struct {
+	db *sql.DB
+}
+
+func NewGiftCardRepository(db *sql.DB) *GiftCardRepository {
+	return &GiftCardRepository{
+		db: db,
+	}
+}
+
+// CreateGiftCard stores the card together with the issue entry that opens
+// its ledger.
+func (r *GiftCardRepository) CreateGiftCard(card *domain.GiftCard) (*domain.GiftCard, error) {
+	if card == nil {
+		return nil, errors.New("invalid gift card")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO gift_cards (website_uuid, type, source, code_hash, code_last4, coin, initial_amount, balance, user_uuid, product_uuid, order_uuid, recipient_email, note, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''), $14)
+	RETURNING uuid, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		card.WebsiteUUID,
+		card.Type,
+		card.Source,
+		card.CodeHash,
+		card.CodeLast4,
+		card.Coin,
+		card.InitialAmount,
+		card.Balance,
+		card.UserUUID,
+		card.ProductUUID,
+		card.OrderUUID,
+		card.RecipientEmail,
+		card.Note,
+		card.ExpiresAt,
+	).Scan(
+		&card.UUID,
+		&card.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrGiftCardCodeExists
+		}
+		return nil, errors.New("could not create gift card")
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO gift_cards_transactions (gift_card_uuid, type, amount, balance_after, order_uuid)
+		VALUES ($1, $2, $3, $3, $4)`,
+		card.UUID,
+		enums.GiftCardIssue,
+		card.Balance,
+		card.OrderUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+func (r *GiftCardRepository) FindGiftCardByUUID(uuid string) (*domain.GiftCard, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + giftCardColumns + `
+	FROM gift_cards
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanGiftCard(row)
+}
+
+func (r *GiftCardRepository) FindGiftCardByCode(websiteUUID string, codeHash string) (*domain.GiftCard, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + giftCardColumns + `
+	FROM gift_cards
+	WHERE website_uuid = $1 AND code_hash = $2`
+
+	row := r.db.QueryRowContext(ctx, query, websiteUUID, codeHash)
+	return helpers.ScanGiftCard(row)
+}
+
+var giftCardListSpec = helpers.ListSpec[*domain.GiftCard]{
+	Query: `SELECT ` + giftCardColumns + `
+	FROM gift_cards`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.GiftCard) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.GiftCard]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.GiftCard) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"type":       {Column: "type", Kind: helpers.TextColumn},
+		"source":     {Column: "source", Kind: helpers.TextColumn},
+		"coin":       {Column: "coin", Kind: helpers.TextColumn},
+		"user_uuid":  {Column: "user_uuid", Kind: helpers.UUIDColumn},
+		"order_uuid": {Column: "order_uuid", Kind: helpers.UUIDColumn},
+		"code_last4": {Column: "code_last4", Kind: helpers.TextColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanGiftCards,
+}
+
+func (r *GiftCardRepository) GetGiftCards(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCard], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, giftCardListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
+}
+
+func (r *GiftCardRepository) GetGiftCardsByUser(websiteUUID string, userUUID string) ([]*domain.GiftCard, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + giftCardColumns + `
+	FROM gift_cards
+	WHERE website_uuid = $1 AND user_uuid = $2
+	ORDER BY created_at, uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanGiftCards(rows)
+}
+
+// CountOrderGiftCards counts the cards an order already issued for a gift
+// card product.
+func (r *GiftCardRepository) CountOrderGiftCards(orderUUID string, productUUID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM gift_cards WHERE order_uuid = $1 AND product_uuid = $2 AND source = $3`,
+		orderUUID,
+		productUUID,
+		enums.GiftCardOrder,
+	).Scan(&count)
+	return count, err
+}
+
+var giftCardTransactionListSpec = helpers.ListSpec[*domain.GiftCardTransaction]{
+	Query: `SELECT ` + giftCardTransactionColumns + `
+	FROM gift_cards_transactions`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.GiftCardTransaction) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.GiftCardTransaction]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.GiftCardTransaction) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"type":       {Column: "type", Kind: helpers.TextColumn},
+		"order_uuid": {Column: "order_uuid", Kind: helpers.UUIDColumn},
+	},
+	DefaultSort: "created_at",
+	Scan:        helpers.ScanGiftCardTransactions,
+}
+
+func (r *GiftCardRepository) GetGiftCardTransactions(giftCardUUID string, query *domain.ListQuery) (*domain.Page[*domain.GiftCardTransaction], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, giftCardTransactionListSpec, query, []string{"gift_card_uuid = $1"}, giftCardUUID)
+}
+
+// RedeemGiftCards takes every application off its card or none of them. A
+// card whose balance dropped below the amount since the totals were worked
+// out fails the whole redemption with ErrGiftCardChanged, so the shopper
+// can be shown fresh totals.
+func (r *GiftCardRepository) RedeemGiftCards(orderUUID uuid.UUID, applications []*domain.GiftCardApplication) ([]*domain.GiftCardTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transactions := make([]*domain.GiftCardTransaction, 0, len(applications))
+	for _, application := range applications {
+		var balance int
+		err := tx.QueryRowContext(
+			ctx,
+			`UPDATE gift_cards
+			SET balance = balance - $2, updated_at = NOW()
+			WHERE uuid = $1 AND balance >= $2 AND (expires_at IS NULL OR expires_at > NOW())
+			RETURNING balance`,
+			application.Card.UUID,
+			application.Amount,
+		).Scan(&balance)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrGiftCardChanged
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t := &domain.GiftCardTransaction{
+			GiftCardUUID: application.Card.UUID,
+			Type:         enums.GiftCardRedeem,
+			Amount:       -application.Amount,
+			BalanceAfter: balance,
+			OrderUUID:    &orderUUID,
+		}
+		err = tx.QueryRowContext(
+			ctx,
+			`INSERT INTO gift_cards_transactions (gift_card_uuid, type, amount, balance_after, order_uuid)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING uuid, created_at`,
+			t.GiftCardUUID,
+			t.Type,
+			t.Amount,
+			t.BalanceAfter,
+			t.OrderUUID,
+		).Scan(
+			&t.UUID,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return nil, domain.ErrGiftCardRedeemed
+			}
+			return nil, err
+		}
+
+		transactions = append(transactions, t)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ReleaseGiftCards gives back what an order took from gift cards, e.g. when
+// the rest of the payment failed. The redemptions are locked first, so a
+// concurrent release waits and then finds them released; releasing twice
+// is a no-op.
+func (r *GiftCardRepository) ReleaseGiftCards(orderUUID string) ([]*domain.GiftCardTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT gift_card_uuid, -amount
+		FROM gift_cards_transactions
+		WHERE order_uuid = $1 AND type = $2
+		ORDER BY gift_card_uuid
+		FOR UPDATE`,
+		orderUUID,
+		enums.GiftCardRedeem,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	type redemption struct {
+		cardUUID uuid.UUID
+		amount   int
+	}
+	var redemptions []redemption
+	for rows.Next() {
+		var rd redemption
+		if err := rows.Scan(&rd.cardUUID, &rd.amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		redemptions = append(redemptions, rd)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orderUUIDParsed, err := uuid.Parse(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []*domain.GiftCardTransaction
+	for _, rd := range redemptions {
+		var released bool
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT EXISTS (SELECT 1 FROM gift_cards_transactions WHERE gift_card_uuid = $1 AND order_uuid = $2 AND type = $3)`,
+			rd.cardUUID,
+			orderUUID,
+			enums.GiftCardRelease,
+		).Scan(&released)
+		if err != nil {
+			return nil, err
+		}
+		if released {
+			continue
+		}
+
+		t := &domain.GiftCardTransaction{
+			GiftCardUUID: rd.cardUUID,
+			Type:         enums.GiftCardRelease,
+			Amount:       rd.amount,
+			OrderUUID:    &orderUUIDParsed,
+		}
+		err = tx.QueryRowContext(
+			ctx,
+			`UPDATE gift_cards SET balance = balance + $2, updated_at = NOW() WHERE uuid = $1 RETURNING balance`,
+			rd.cardUUID,
+			rd.amount,
+		).Scan(&t.BalanceAfter)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.QueryRowContext(
+			ctx,
+			`INSERT INTO gift_cards_transactions (gift_card_uuid, type, amount, balance_after, order_uuid)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING uuid, created_at`,
+			t.GiftCardUUID,
+			t.Type,
+			t.Amount,
+			t.BalanceAfter,
+			t.OrderUUID,
+		).Scan(
+			&t.UUID,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, t)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ExpireGiftCards zeroes the balance of expired cards, recording what was
+// lost in their ledgers, and returns how many cards it touched.
+func (r *GiftCardRepository) ExpireGiftCards(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	query := `WITH due AS (
+		SELECT uuid, balance
+		FROM gift_cards
+		WHERE expires_at <= NOW() AND balance > 0
+		FOR UPDATE SKIP LOCKED
+	),
+	zeroed AS (
+		UPDATE gift_cards g
+		SET balance = 0, updated_at = NOW()
+		FROM due d
+		WHERE g.uuid = d.uuid
+		RETURNING g.uuid, d.balance
+	)
+	INSERT INTO gift_cards_transactions (gift_card_uuid, type, amount, balance_after)
+	SELECT uuid, $1, -balance, 0 FROM zeroed`
+
+	result, err := r.db.ExecContext(ctx, query, enums.GiftCardExpire)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
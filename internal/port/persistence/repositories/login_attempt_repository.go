@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+var _ contracts.LoginAttemptContract = (*LoginAttemptRepository)(nil)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+func (r *LoginAttemptRepository) RecordIPLoginFailure(ip string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO login_failures (ip) VALUES ($1)`, ip)
+	return err
+}
+
+// CountIPLoginFailures returns how many sign ins failed from ip since a
+// moment, and when the last of them happened.
+func (r *LoginAttemptRepository) CountIPLoginFailures(ip string, since time.Time) (int, *time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*), MAX(created_at) FROM login_failures WHERE ip = $1 AND created_at > $2`
+
+	var count int
+	var last *time.Time
+	if err := r.db.QueryRowContext(ctx, query, ip, since).Scan(&count, &last); err != nil {
+		return 0, nil, err
+	}
+
+	return count, last, nil
+}
+
+// RecordUserLoginFailure counts a failed sign in of the user and locks the
+// account for lockFor once lockAfter of them failed in a row. A lock that
+// already ran out starts the count over. It returns the failures so far and
+// when the lock ends, if locked.
+func (r *LoginAttemptRepository) RecordUserLoginFailure(userUUID string, lockAfter int, lockFor time.Duration) (int, *time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE users
+	SET failed_logins = CASE WHEN locked_until <= NOW() THEN 1 ELSE failed_logins + 1 END,
+		last_failed_login_at = NOW(),
+		locked_until = CASE
+			WHEN locked_until <= NOW() THEN NULL
+			WHEN failed_logins + 1 >= $2 THEN NOW() + make_interval(secs => $3)
+			ELSE locked_until
+		END
+	WHERE uuid = $1
+	RETURNING failed_logins, locked_until`
+
+	var failures int
+	var lockedUntil *time.Time
+	if err := r.db.QueryRowContext(ctx, query, userUUID, lockAfter, lockFor.Seconds()).Scan(&failures, &lockedUntil); err != nil {
+		return 0, nil, err
+	}
+
+	return failures, lockedUntil, nil
+}
+
+func (r *LoginAttemptRepository) ResetUserLoginFailures(userUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE users SET failed_logins = 0, last_failed_login_at = NULL, locked_until = NULL WHERE uuid = $1`
+
+	_, err := r.db.ExecContext(ctx, query, userUUID)
+	return err
+}
+
+func (r *LoginAttemptRepository) PruneLoginFailures(before time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM login_failures WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
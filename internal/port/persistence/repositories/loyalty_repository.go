@@ -0,0 +1,488 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.LoyaltyContract = (*LoyaltyRepository)(nil)
+
+const loyaltyTransactionColumns = `uuid, website_uuid, user_uuid, type, points, remaining, coin, amount, order_uuid, review_uuid, expires_at, created_at`
+
+// loyaltyLots are the entries a customer can still spend from.
+const loyaltyLots = `FROM loyalty_transactions
+	WHERE website_uuid = $1 AND user_uuid = $2 AND remaining > 0
+	AND (expires_at IS NULL OR expires_at > NOW())`
+
+// Points about to expire are reported this far ahead.
+const loyaltyExpiringWindow = "30 days"
+
+type LoyaltyRepository struct {
+	db *sql.DB
+}
+
+func NewLoyaltyRepository(db *sql.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		db: db,
+	}
+}
+
+func (r *LoyaltyRepository) FindLoyaltyProgram(websiteUUID string) (*domain.LoyaltyProgram, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	program := &domain.LoyaltyProgram{}
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT website_uuid, active, first_order_bonus, review_bonus, expiry_days, min_redeem_points, updated_at, created_at
+		FROM loyalty_programs
+		WHERE website_uuid = $1`,
+		websiteUUID,
+	).Scan(
+		&program.WebsiteUUID,
+		&program.Active,
+		&program.FirstOrderBonus,
+		&program.ReviewBonus,
+		&program.ExpiryDays,
+		&program.MinRedeemPoints,
+		&program.UpdatedAt,
+		&program.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("loyalty program not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT coin, earn_points_per_unit, point_value FROM loyalty_rates WHERE website_uuid = $1 ORDER BY coin`, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	program.Rates, err = helpers.ScanLoyaltyRates(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// SaveLoyaltyProgram creates or replaces the website's program, rates
+// included.
+func (r *LoyaltyRepository) SaveLoyaltyProgram(program *domain.LoyaltyProgram) (*domain.LoyaltyProgram, error) {
+	if program == nil {
+		return nil, errors.New("invalid loyalty program")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO loyalty_programs (website_uuid, active, first_order_bonus, review_bonus, expiry_days, min_redeem_points)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (website_uuid) DO UPDATE
+	SET active = EXCLUDED.active,
+		first_order_bonus = EXCLUDED.first_order_bonus,
+		review_bonus = EXCLUDED.review_bonus,
+		expiry_days = EXCLUDED.expiry_days,
+		min_redeem_points = EXCLUDED.min_redeem_points,
+		updated_at = NOW()
+	RETURNING updated_at, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		program.WebsiteUUID,
+		program.Active,
+		program.FirstOrderBonus,
+		program.ReviewBonus,
+		program.ExpiryDays,
+		program.MinRedeemPoints,
+	).Scan(
+		&program.UpdatedAt,
+		&program.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.New("could not save loyalty program")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM loyalty_rates WHERE website_uuid = $1`, program.WebsiteUUID); err != nil {
+		return nil, err
+	}
+
+	for _, rate := range program.Rates {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO loyalty_rates (website_uuid, coin, earn_points_per_unit, point_value) VALUES ($1, $2, $3, $4)`,
+			program.WebsiteUUID,
+			rate.Coin,
+			rate.EarnPointsPerUnit,
+			rate.PointValue,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// AddLoyaltyPoints records an entry that adds points as a new lot. Entries
+// tied to an order or review only go in once; a second try returns
+// ErrLoyaltyApplied.
+func (r *LoyaltyRepository) AddLoyaltyPoints(transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error) {
+	if transaction == nil || transaction.Points <= 0 {
+		return nil, errors.New("invalid loyalty transaction")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transaction.Remaining = transaction.Points
+	if err := insertLoyaltyTransaction(ctx, r.db, transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// SpendLoyaltyPoints takes -transaction.Points from the customer's lots,
+// the ones expiring first before the others, and records the entry.
+func (r *LoyaltyRepository) SpendLoyaltyPoints(transaction *domain.LoyaltyTransaction) (*domain.LoyaltyTransaction, error) {
+	if transaction == nil || transaction.Points >= 0 {
+		return nil, errors.New("invalid loyalty transaction")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := spendLoyaltyLots(ctx, tx, transaction.WebsiteUUID, transaction.UserUUID, -transaction.Points); err != nil {
+		return nil, err
+	}
+
+	transaction.Remaining = 0
+	if err := insertLoyaltyTransaction(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// ReverseLoyaltyEarning takes back the share of an order's earned points
+// matching a refund of refundAmount that brought what the order refunded
+// to refundedTo. The earning is locked so concurrent refunds of the same
+// order add up correctly. Points the customer already spent are not
+// clawed back; nil means there was nothing left to take.
+func (r *LoyaltyRepository) ReverseLoyaltyEarning(orderUUID string, refundedTo int, refundAmount int) (*domain.LoyaltyTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	earning, err := helpers.ScanLoyaltyTransaction(tx.QueryRowContext(
+		ctx,
+		`SELECT `+loyaltyTransactionColumns+`
+		FROM loyalty_transactions
+		WHERE order_uuid = $1 AND type = $2
+		FOR UPDATE`,
+		orderUUID,
+		enums.LoyaltyEarn,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var reversed int
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(-points), 0) FROM loyalty_transactions WHERE order_uuid = $1 AND type = $2`,
+		orderUUID,
+		enums.LoyaltyReverse,
+	).Scan(&reversed)
+	if err != nil {
+		return nil, err
+	}
+
+	orderAmount := 0
+	if earning.Amount != nil {
+		orderAmount = *earning.Amount
+	}
+
+	var available int
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(remaining), 0) `+loyaltyLots, earning.WebsiteUUID, earning.UserUUID).Scan(&available)
+	if err != nil {
+		return nil, err
+	}
+
+	points := min(domain.LoyaltyReversal(earning.Points, orderAmount, reversed, refundedTo), available)
+	if points <= 0 {
+		return nil, nil
+	}
+
+	if err := spendLoyaltyLots(ctx, tx, earning.WebsiteUUID, earning.UserUUID, points); err != nil {
+		return nil, err
+	}
+
+	transaction := &domain.LoyaltyTransaction{
+		WebsiteUUID: earning.WebsiteUUID,
+		UserUUID:    earning.UserUUID,
+		Type:        enums.LoyaltyReverse,
+		Points:      -points,
+		Coin:        earning.Coin,
+		Amount:      &refundAmount,
+		OrderUUID:   earning.OrderUUID,
+	}
+	if err := insertLoyaltyTransaction(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (r *LoyaltyRepository) FindLoyaltyTransactionByOrder(orderUUID string, transactionType enums.LoyaltyTransactionType) (*domain.LoyaltyTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + loyaltyTransactionColumns + `
+	FROM loyalty_transactions
+	WHERE order_uuid = $1 AND type = $2
+	ORDER BY created_at
+	LIMIT 1`
+
+	row := r.db.QueryRowContext(ctx, query, orderUUID, transactionType)
+	return helpers.ScanLoyaltyTransaction(row)
+}
+
+func (r *LoyaltyRepository) CountLoyaltyEarnings(websiteUUID string, userUUID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM loyalty_transactions WHERE website_uuid = $1 AND user_uuid = $2 AND type = $3`,
+		websiteUUID,
+		userUUID,
+		enums.LoyaltyEarn,
+	).Scan(&count)
+	return count, err
+}
+
+// GetLoyaltyBalance sums the points left to spend. ExpiringPoints are the
+// ones expiring within loyaltyExpiringWindow, the first of them at
+// ExpiringAt.
+func (r *LoyaltyRepository) GetLoyaltyBalance(websiteUUID string, userUUID string) (*domain.LoyaltyBalance, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	balance := &domain.LoyaltyBalance{}
+	query := `SELECT
+		COALESCE(SUM(remaining), 0),
+		COALESCE(SUM(remaining) FILTER (WHERE expires_at <= NOW() + INTERVAL '` + loyaltyExpiringWindow + `'), 0),
+		MIN(expires_at)
+	` + loyaltyLots
+
+	err := r.db.QueryRowContext(ctx, query, websiteUUID, userUUID).Scan(
+		&balance.Points,
+		&balance.ExpiringPoints,
+		&balance.ExpiringAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	balance.WebsiteUUID, err = uuid.Parse(websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	balance.UserUUID, err = uuid.Parse(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+var loyaltyTransactionListSpec = helpers.ListSpec[*domain.LoyaltyTransaction]{
+	Query: `SELECT ` + loyaltyTransactionColumns + `
+	FROM loyalty_transactions`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.LoyaltyTransaction) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.LoyaltyTransaction]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.LoyaltyTransaction) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"type":       {Column: "type", Kind: helpers.TextColumn},
+		"order_uuid": {Column: "order_uuid", Kind: helpers.UUIDColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanLoyaltyTransactions,
+}
+
+func (r *LoyaltyRepository) GetLoyaltyTransactions(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.LoyaltyTransaction], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, loyaltyTransactionListSpec, query, []string{"website_uuid = $1 AND user_uuid = $2"}, websiteUUID, userUUID)
+}
+
+// ExpireLoyaltyPoints closes lots past their expiry, recording what was
+// lost as an expire entry per lot, and returns how many lots it closed.
+func (r *LoyaltyRepository) ExpireLoyaltyPoints(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	query := `WITH due AS (
+		SELECT uuid, website_uuid, user_uuid, remaining
+		FROM loyalty_transactions
+		WHERE remaining > 0 AND expires_at <= NOW()
+		FOR UPDATE SKIP LOCKED
+	),
+	closed AS (
+		UPDATE loyalty_transactions t
+		SET remaining = 0
+		FROM due d
+		WHERE t.uuid = d.uuid
+		RETURNING d.website_uuid, d.user_uuid, d.remaining
+	)
+	INSERT INTO loyalty_transactions (website_uuid, user_uuid, type, points)
+	SELECT website_uuid, user_uuid, $1, -remaining FROM closed`
+
+	result, err := r.db.ExecContext(ctx, query, enums.LoyaltyExpire)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// loyaltyExecutor is satisfied by both *sql.DB and *sql.Tx.
+type loyaltyExecutor interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertLoyaltyTransaction(ctx context.Context, db loyaltyExecutor, t *domain.LoyaltyTransaction) error {
+	query := `INSERT INTO loyalty_transactions (website_uuid, user_uuid, type, points, remaining, coin, amount, order_uuid, review_uuid, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING uuid, created_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		t.WebsiteUUID,
+		t.UserUUID,
+		t.Type,
+		t.Points,
+		t.Remaining,
+		t.Coin,
+		t.Amount,
+		t.OrderUUID,
+		t.ReviewUUID,
+		t.ExpiresAt,
+	).Scan(
+		&t.UUID,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return domain.ErrLoyaltyApplied
+		}
+		return err
+	}
+
+	return nil
+}
+
+// spendLoyaltyLots locks the customer's lots, soonest to expire first, and
+// takes points from them in that order. Concurrent spends queue on the
+// locks and see each other's result.
+func spendLoyaltyLots(ctx context.Context, tx *sql.Tx, websiteUUID uuid.UUID, userUUID uuid.UUID, points int) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT uuid, remaining `+loyaltyLots+`
+		ORDER BY expires_at NULLS LAST, created_at, uuid
+		FOR UPDATE`,
+		websiteUUID,
+		userUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	type lot struct {
+		uuid      uuid.UUID
+		remaining int
+	}
+	var lots []lot
+	available := 0
+	for rows.Next() {
+		var l lot
+		if err := rows.Scan(&l.uuid, &l.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		lots = append(lots, l)
+		available += l.remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if available < points {
+		return domain.ErrLoyaltyInsufficient
+	}
+
+	for _, l := range lots {
+		if points == 0 {
+			break
+		}
+
+		take := min(l.remaining, points)
+		if _, err := tx.ExecContext(ctx, `UPDATE loyalty_transactions SET remaining = remaining - $2 WHERE uuid = $1`, l.uuid, take); err != nil {
+			return err
+		}
+		points -= take
+	}
+
+	return nil
+}
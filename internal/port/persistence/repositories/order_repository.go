@@ -0,0 +1,430 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.OrderContract = (*OrderRepository)(nil)
+
+const orderColumns = `uuid, website_uuid, user_uuid, cart_uuid, email, status, coin,
+	subtotal, discount, loyalty_points, loyalty_discount, gift_card_amount, total, amount_paid,
+	refunded_amount, provider_refunded, cupom_uuid, payment_method, payment_reference,
+	failure_reason, recovered, paid_at, updated_at, created_at`
+
+type OrderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+	}
+}
+
+func (r *OrderRepository) CreateOrder(order *domain.Order) (*domain.Order, error) {
+	if order == nil {
+		return nil, errors.New("invalid order")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO orders (website_uuid, user_uuid, cart_uuid, email, status, coin, subtotal, total, amount_paid, payment_method, recovered)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11)
+	RETURNING uuid, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		order.WebsiteUUID,
+		order.UserUUID,
+		order.CartUUID,
+		order.Email,
+		order.Status,
+		order.Coin,
+		order.Subtotal,
+		order.Total,
+		order.AmountPaid,
+		order.PaymentMethod,
+		order.Recovered,
+	).Scan(
+		&order.UUID,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrCartCheckingOut
+		}
+		return nil, errors.New("could not create order")
+	}
+
+	for _, item := range order.Items {
+		item.OrderUUID = order.UUID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO orders_items (order_uuid, product_uuid, variant_uuid, product_type, name, quantity, unit_amount, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING uuid`,
+			item.OrderUUID,
+			item.ProductUUID,
+			item.VariantUUID,
+			item.ProductType,
+			item.Name,
+			item.Quantity,
+			item.UnitAmount,
+			item.Amount,
+		).Scan(&item.UUID)
+		if err != nil {
+			return nil, errors.New("could not create order item")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) FindOrderByUUID(uuid string) (*domain.Order, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	order, err := helpers.ScanOrder(r.db.QueryRowContext(ctx, `SELECT `+orderColumns+` FROM orders WHERE uuid = $1`, uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT uuid, order_uuid, product_uuid, variant_uuid, product_type, name, quantity, unit_amount, amount
+		FROM orders_items
+		WHERE order_uuid = $1
+		ORDER BY uuid`,
+		uuid,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	order.Items, err = helpers.ScanOrderItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+var orderListSpec = helpers.ListSpec[*domain.Order]{
+	Query: `SELECT ` + orderColumns + `
+	FROM orders`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Order) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Order]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.Order) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"status":         {Column: "status", Kind: helpers.TextColumn},
+		"coin":           {Column: "coin", Kind: helpers.TextColumn},
+		"user_uuid":      {Column: "user_uuid", Kind: helpers.UUIDColumn},
+		"recovered":      {Column: "recovered", Kind: helpers.BoolColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanOrders,
+}
+
+func (r *OrderRepository) GetOrdersByUser(websiteUUID string, userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, orderListSpec, query, []string{"website_uuid = $1", "user_uuid = $2"}, websiteUUID, userUUID)
+}
+
+func (r *OrderRepository) GetOrders(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Order], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, orderListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
+}
+
+// UpdateOrderTotals saves the discounts and gift card share of a pending
+// order.
+func (r *OrderRepository) UpdateOrderTotals(order *domain.Order) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE orders
+		SET discount = $2, loyalty_points = $3, loyalty_discount = $4, gift_card_amount = $5,
+			total = $6, amount_paid = $7, cupom_uuid = $8, updated_at = NOW()
+		WHERE uuid = $1 AND status = 'pending'`,
+		order.UUID,
+		order.Discount,
+		order.LoyaltyPoints,
+		order.LoyaltyDiscount,
+		order.GiftCardAmount,
+		order.Total,
+		order.AmountPaid,
+		order.CupomUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(result, "order not found")
+}
+
+// MarkOrderPaid settles a pending order, closes the cart it came from and
+// queues the paid event of every hook in one go.
+func (r *OrderRepository) MarkOrderPaid(order *domain.Order, hooks []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE orders
+		SET status = 'paid', payment_reference = NULLIF($2, ''), paid_at = NOW(), updated_at = NOW()
+		WHERE uuid = $1 AND status = 'pending'
+		RETURNING status, paid_at, updated_at`,
+		order.UUID,
+		order.PaymentReference,
+	).Scan(
+		&order.Status,
+		&order.PaidAt,
+		&order.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("order not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if order.CartUUID != nil {
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE carts SET status = 'ordered', order_uuid = $2, updated_at = NOW() WHERE uuid = $1 AND status = 'open'`,
+			order.CartUUID,
+			order.UUID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertOrderEvents(ctx, tx, order, enums.OrderEventPaid, 0, hooks, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrderRepository) MarkOrderFailed(order *domain.Order) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		`UPDATE orders
+		SET status = 'failed', payment_reference = NULLIF($2, ''), failure_reason = LEFT($3, 500), updated_at = NOW()
+		WHERE uuid = $1 AND status = 'pending'
+		RETURNING status, updated_at`,
+		order.UUID,
+		order.PaymentReference,
+		order.FailureReason,
+	).Scan(
+		&order.Status,
+		&order.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("order not found")
+	}
+	return err
+}
+
+// RefundOrder adds amount to what the order refunded, providerAmount of it
+// sent back to the payment method, and queues the refund event of every
+// hook to run at runAt. The check and the update are one statement, so
+// concurrent refunds can never give back more than the order cost;
+// domain.ErrOrderRefundAmount means they would have. Negative amounts undo
+// a refund the provider failed to make, and drop its events.
+func (r *OrderRepository) RefundOrder(order *domain.Order, amount int, providerAmount int, hooks []string, runAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE orders
+		SET refunded_amount = refunded_amount + $2,
+			provider_refunded = provider_refunded + $3,
+			status = CASE WHEN refunded_amount + $2 >= total THEN 'refunded' ELSE 'paid' END,
+			updated_at = NOW()
+		WHERE uuid = $1
+		AND status IN ('paid', 'refunded')
+		AND refunded_amount + $2 BETWEEN 0 AND total
+		AND provider_refunded + $3 BETWEEN 0 AND amount_paid
+		RETURNING status, refunded_amount, provider_refunded, updated_at`,
+		order.UUID,
+		amount,
+		providerAmount,
+	).Scan(
+		&order.Status,
+		&order.RefundedAmount,
+		&order.ProviderRefunded,
+		&order.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrOrderRefundAmount
+	}
+	if err != nil {
+		return err
+	}
+
+	if amount < 0 {
+		_, err = tx.ExecContext(
+			ctx,
+			`DELETE FROM orders_events WHERE order_uuid = $1 AND type = $2 AND refunded_to = $3 AND status = $4`,
+			order.UUID,
+			enums.OrderEventRefund,
+			order.RefundedAmount-amount,
+			enums.OrderEventPending,
+		)
+	} else {
+		err = insertOrderEvents(ctx, tx, order, enums.OrderEventRefund, amount, hooks, runAt)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertOrderEvents(ctx context.Context, tx *sql.Tx, order *domain.Order, eventType enums.OrderEventType, amount int, hooks []string, runAt time.Time) error {
+	refundedTo := 0
+	if eventType == enums.OrderEventRefund {
+		refundedTo = order.RefundedAmount
+	}
+
+	for _, hook := range hooks {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO orders_events (order_uuid, hook, type, amount, refunded_to, run_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (order_uuid, type, refunded_to, hook) DO NOTHING`,
+			order.UUID,
+			hook,
+			eventType,
+			amount,
+			refundedTo,
+			runAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReleaseOrderEvents runs the events of a refund now that the provider made
+// it.
+func (r *OrderRepository) ReleaseOrderEvents(orderUUID string, refundedTo int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE orders_events SET run_at = NOW(), updated_at = NOW()
+		WHERE order_uuid = $1 AND type = $2 AND refunded_to = $3 AND status = $4`,
+		orderUUID,
+		enums.OrderEventRefund,
+		refundedTo,
+		enums.OrderEventPending,
+	)
+	return err
+}
+
+const orderEventColumns = `uuid, order_uuid, hook, type, amount, refunded_to, status, attempts, last_error, run_at, updated_at, created_at`
+
+// ClaimOrderEvents marks up to limit due events as running, of orderUUID
+// or of any order when it is empty. Events left running for 10 minutes are
+// taken to have died with their process and are claimed again.
+func (r *OrderRepository) ClaimOrderEvents(orderUUID string, limit int) ([]*domain.OrderEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE orders_events
+	SET status = 'running', attempts = attempts + 1, updated_at = NOW()
+	WHERE uuid IN (
+		SELECT uuid FROM orders_events
+		WHERE ((status = 'pending' AND run_at <= NOW())
+		OR (status = 'running' AND updated_at < NOW() - INTERVAL '10 minutes'))
+		AND ($2 = '' OR order_uuid::TEXT = $2)
+		ORDER BY run_at, uuid
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + orderEventColumns
+
+	rows, err := r.db.QueryContext(ctx, query, limit, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanOrderEvents(rows)
+}
+
+func (r *OrderRepository) UpdateOrderEvent(event *domain.OrderEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE orders_events
+		SET status = $2, last_error = LEFT(NULLIF($3, ''), 500), run_at = $4, updated_at = NOW()
+		WHERE uuid = $1`,
+		event.UUID,
+		event.Status,
+		event.LastError,
+		event.RunAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(result, "order event not found")
+}
@@ -0,0 +1,379 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.OrganizationMemberContract = (*OrganizationMemberRepository)(nil)
+
+// Members are always read with the email and name of their user.
+const organizationMemberColumns = `m.uuid, m.organization_uuid, m.user_uuid, m.role, m.rbac_uuid, COALESCE(u.email, ''), COALESCE(u.name, ''), m.created_at, m.updated_at`
+
+const organizationInvitationColumns = `uuid, organization_uuid, email, role, rbac_uuid, token_hash, invited_by, status, expires_at, responded_at, created_at`
+
+type OrganizationMemberRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationMemberRepository(db *sql.DB) *OrganizationMemberRepository {
+	return &OrganizationMemberRepository{
+		db: db,
+	}
+}
+
+type memberExecutor interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertOrganizationMember(ctx context.Context, db memberExecutor, member *domain.OrganizationMember) (*domain.OrganizationMember, error) {
+	query := `WITH m AS (
+		INSERT INTO organizations_members (organization_uuid, user_uuid, role, rbac_uuid)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+	)
+	SELECT ` + organizationMemberColumns + `
+	FROM m
+	LEFT JOIN users u ON u.uuid = m.user_uuid`
+
+	created, err := helpers.ScanOrganizationMember(db.QueryRowContext(
+		ctx,
+		query,
+		member.OrganizationUUID,
+		member.UserUUID,
+		member.Role,
+		member.RbacUUID,
+	))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrMemberExists
+		}
+		return nil, errors.New("could not add organization member")
+	}
+
+	return created, nil
+}
+
+func (r *OrganizationMemberRepository) AddOrganizationMember(member *domain.OrganizationMember) (*domain.OrganizationMember, error) {
+	if member == nil {
+		return nil, errors.New("invalid organization member")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return insertOrganizationMember(ctx, r.db, member)
+}
+
+func (r *OrganizationMemberRepository) FindOrganizationMember(organizationUUID string, userUUID string) (*domain.OrganizationMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + organizationMemberColumns + `
+	FROM organizations_members m
+	LEFT JOIN users u ON u.uuid = m.user_uuid
+	WHERE m.organization_uuid = $1 AND m.user_uuid = $2`
+
+	row := r.db.QueryRowContext(ctx, query, organizationUUID, userUUID)
+	return helpers.ScanOrganizationMember(row)
+}
+
+var organizationMemberListSpec = helpers.ListSpec[*domain.OrganizationMember]{
+	Query: `SELECT ` + organizationMemberColumns + `
+	FROM organizations_members m
+	LEFT JOIN users u ON u.uuid = m.user_uuid`,
+	Key:   "m.uuid",
+	KeyOf: func(v *domain.OrganizationMember) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.OrganizationMember]{
+		"created_at": {Column: "m.created_at", Kind: helpers.TimeColumn, Value: func(v *domain.OrganizationMember) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"role":           {Column: "m.role", Kind: helpers.TextColumn},
+		"email":          {Column: "u.email", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"name":           {Column: "u.name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"created_after":  {Column: "m.created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "m.created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanOrganizationMembers,
+}
+
+func (r *OrganizationMemberRepository) GetOrganizationMembers(organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationMember], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, organizationMemberListSpec, query, []string{"m.organization_uuid = $1"}, organizationUUID)
+}
+
+func (r *OrganizationMemberRepository) UpdateOrganizationMember(member *domain.OrganizationMember) error {
+	if member == nil {
+		return errors.New("invalid organization member")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE organizations_members
+	SET role = $3, rbac_uuid = $4, updated_at = NOW()
+	WHERE organization_uuid = $1 AND user_uuid = $2 AND role <> 'owner'`
+
+	result, err := r.db.ExecContext(ctx, query, member.OrganizationUUID, member.UserUUID, member.Role, member.RbacUUID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("organization member not found")
+	}
+
+	return nil
+}
+
+// RemoveOrganizationMember never removes the owner; ownership has to be
+// transferred first.
+func (r *OrganizationMemberRepository) RemoveOrganizationMember(organizationUUID string, userUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM organizations_members
+	WHERE organization_uuid = $1 AND user_uuid = $2 AND role <> 'owner'`
+
+	result, err := r.db.ExecContext(ctx, query, organizationUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("organization member not found")
+	}
+
+	return nil
+}
+
+// TransferOrganizationOwnership makes toUserUUID, already a member, the
+// owner of the organization. The previous owner stays on as an admin.
+func (r *OrganizationMemberRepository) TransferOrganizationOwnership(organizationUUID string, fromUserUUID string, toUserUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			`UPDATE organizations_members SET role = 'admin', rbac_uuid = NULL, updated_at = NOW()
+			WHERE organization_uuid = $1 AND user_uuid = $2 AND role = 'owner'`,
+			[]interface{}{organizationUUID, fromUserUUID},
+		},
+		{
+			`UPDATE organizations_members SET role = 'owner', rbac_uuid = NULL, updated_at = NOW()
+			WHERE organization_uuid = $1 AND user_uuid = $2`,
+			[]interface{}{organizationUUID, toUserUUID},
+		},
+		{
+			`UPDATE organizations SET owner_uuid = $2, updated_at = NOW() WHERE uuid = $1`,
+			[]interface{}{organizationUUID, toUserUUID},
+		},
+	}
+
+	for _, step := range steps {
+		result, err := tx.ExecContext(ctx, step.query, step.args...)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return errors.New("organization member not found")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrganizationMemberRepository) CreateOrganizationInvitation(invitation *domain.OrganizationInvitation) (*domain.OrganizationInvitation, error) {
+	if invitation == nil {
+		return nil, errors.New("invalid organization invitation")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO organizations_invitations (organization_uuid, email, role, rbac_uuid, token_hash, invited_by, status, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING ` + organizationInvitationColumns
+
+	created, err := helpers.ScanOrganizationInvitation(r.db.QueryRowContext(
+		ctx,
+		query,
+		invitation.OrganizationUUID,
+		invitation.Email,
+		invitation.Role,
+		invitation.RbacUUID,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.Status,
+		invitation.ExpiresAt,
+	))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrInvitationPending
+		}
+		return nil, errors.New("could not create organization invitation")
+	}
+
+	return created, nil
+}
+
+func (r *OrganizationMemberRepository) FindOrganizationInvitationByUUID(uuid string) (*domain.OrganizationInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + organizationInvitationColumns + `
+	FROM organizations_invitations
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanOrganizationInvitation(row)
+}
+
+func (r *OrganizationMemberRepository) FindOrganizationInvitationByToken(tokenHash string) (*domain.OrganizationInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + organizationInvitationColumns + `
+	FROM organizations_invitations
+	WHERE token_hash = $1`
+
+	row := r.db.QueryRowContext(ctx, query, tokenHash)
+	return helpers.ScanOrganizationInvitation(row)
+}
+
+var organizationInvitationListSpec = helpers.ListSpec[*domain.OrganizationInvitation]{
+	Query: `SELECT ` + organizationInvitationColumns + `
+	FROM organizations_invitations`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.OrganizationInvitation) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.OrganizationInvitation]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.OrganizationInvitation) string { return helpers.TimeValue(v.CreatedAt) }},
+		"expires_at": {Column: "expires_at", Kind: helpers.TimeColumn, Value: func(v *domain.OrganizationInvitation) string { return helpers.TimeValue(v.ExpiresAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"status":         {Column: "status", Kind: helpers.TextColumn},
+		"email":          {Column: "email", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanOrganizationInvitations,
+}
+
+func (r *OrganizationMemberRepository) GetOrganizationInvitations(organizationUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationInvitation], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, organizationInvitationListSpec, query, []string{"organization_uuid = $1"}, organizationUUID)
+}
+
+// AcceptOrganizationInvitation closes a pending, unexpired invitation and
+// adds its member in one transaction, so an invitation is used only once.
+func (r *OrganizationMemberRepository) AcceptOrganizationInvitation(invitation *domain.OrganizationInvitation, member *domain.OrganizationMember) (*domain.OrganizationMember, error) {
+	if invitation == nil || member == nil {
+		return nil, errors.New("invalid organization invitation")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE organizations_invitations SET status = $2, responded_at = NOW()
+		WHERE uuid = $1 AND status = $3 AND expires_at > NOW()`,
+		invitation.UUID,
+		enums.InvitationAccepted,
+		enums.InvitationPending,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, domain.ErrInvitationAnswered
+	}
+
+	created, err := insertOrganizationMember(ctx, tx, member)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// AnswerOrganizationInvitation declines or revokes a pending invitation.
+func (r *OrganizationMemberRepository) AnswerOrganizationInvitation(uuid string, status enums.InvitationStatusType) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE organizations_invitations SET status = $2, responded_at = NOW()
+	WHERE uuid = $1 AND status = $3`
+
+	result, err := r.db.ExecContext(ctx, query, uuid, status, enums.InvitationPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvitationAnswered
+	}
+
+	return nil
+}
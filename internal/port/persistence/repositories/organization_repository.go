@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.OrganizationContract = (*OrganizationRepository)(nil)
@@ -25,6 +27,7 @@ func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
 	}
 }
 
+// CreateOrganization also makes the owner its first member.
 func (r *OrganizationRepository) CreateOrganization(org *domain.OrganizationBR) (*domain.OrganizationBR, error) {
 	if org == nil {
 		return nil, errors.New("invalid organization")
@@ -33,11 +36,17 @@ func (r *OrganizationRepository) CreateOrganization(org *domain.OrganizationBR)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO organizations (website_uuid, owner_uuid, image_url, name, trade_name, cnpj)
 	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING uuid, created_at, updated_at`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		org.WebSiteUUID,
@@ -56,6 +65,19 @@ func (r *OrganizationRepository) CreateOrganization(org *domain.OrganizationBR)
 		return nil, errors.New("could not create organization")
 	}
 
+	_, err = insertOrganizationMember(ctx, tx, &domain.OrganizationMember{
+		OrganizationUUID: org.UUID,
+		UserUUID:         org.OwnerUUID,
+		Role:             enums.MemberOwner,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return org, nil
 }
 
@@ -83,37 +105,37 @@ func (r *OrganizationRepository) FindOrganizationByCNPJAndWebsite(cnpj string, w
 	return helpers.ScanOrganization(row)
 }
 
-func (r *OrganizationRepository) GetOrganizationsFromWebsite(websiteUUID string) ([]*domain.OrganizationBR, error) {
+var organizationListSpec = helpers.ListSpec[*domain.OrganizationBR]{
+	Query: `SELECT uuid, website_uuid, owner_uuid, image_url, name, trade_name, cnpj, created_at, updated_at
+	FROM organizations`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.OrganizationBR) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.OrganizationBR]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.OrganizationBR) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":           {Column: "name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"trade_name":     {Column: "trade_name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"cnpj":           {Column: "cnpj", Kind: helpers.TextColumn},
+		"owner_uuid":     {Column: "owner_uuid", Kind: helpers.UUIDColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanOrganizations,
+}
+
+func (r *OrganizationRepository) GetOrganizationsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.OrganizationBR], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, image_url, name, trade_name, cnpj, created_at, updated_at
-	FROM organizations
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanOrganizations(rows)
+	return helpers.QueryPage(ctx, r.db, organizationListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *OrganizationRepository) GetOrganizations() ([]*domain.OrganizationBR, error) {
+func (r *OrganizationRepository) GetOrganizations(query *domain.ListQuery) (*domain.Page[*domain.OrganizationBR], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, image_url, name, trade_name, cnpj, created_at, updated_at
-	FROM organizations`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanOrganizations(rows)
+	return helpers.QueryPage(ctx, r.db, organizationListSpec, query, nil)
 }
 
 func (r *OrganizationRepository) UpdateOrganizationByUUID(uuid string) error {
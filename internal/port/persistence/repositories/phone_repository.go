@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.PhoneContract = (*PhoneRepository)(nil)
@@ -84,54 +85,45 @@ func (r *PhoneRepository) FindDefaultPhoneByOwner(ownerUUID string, websiteUUID
 	return helpers.ScanPhone(row)
 }
 
-func (r *PhoneRepository) GetPhonesFromOwner(ownerUUID string, websiteUUID string) ([]*domain.Phone, error) {
+var phoneListSpec = helpers.ListSpec[*domain.Phone]{
+	Query: `SELECT uuid, website_uuid, owner_uuid, owner_type, label, number, is_default, created_at, updated_at
+	FROM phones`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Phone) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Phone]{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.Phone) string { return v.Label }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.Phone) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":          {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"owner_uuid":     {Column: "owner_uuid", Kind: helpers.UUIDColumn},
+		"owner_type":     {Column: "owner_type", Kind: helpers.TextColumn},
+		"is_default":     {Column: "is_default", Kind: helpers.BoolColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanPhones,
+}
+
+func (r *PhoneRepository) GetPhonesFromOwner(ownerUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Phone], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, number, is_default, created_at, updated_at
-	FROM phones
-	WHERE owner_uuid = $1 AND website_uuid = $2`
-
-	rows, err := r.db.QueryContext(ctx, query, ownerUUID, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanPhones(rows)
+	return helpers.QueryPage(ctx, r.db, phoneListSpec, query, []string{"owner_uuid = $1 AND website_uuid = $2"}, ownerUUID, websiteUUID)
 }
 
-func (r *PhoneRepository) GetPhonesFromWebsite(websiteUUID string) ([]*domain.Phone, error) {
+func (r *PhoneRepository) GetPhonesFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Phone], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, number, is_default, created_at, updated_at
-	FROM phones
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanPhones(rows)
+	return helpers.QueryPage(ctx, r.db, phoneListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *PhoneRepository) GetPhones() ([]*domain.Phone, error) {
+func (r *PhoneRepository) GetPhones(query *domain.ListQuery) (*domain.Page[*domain.Phone], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, label, number, is_default, created_at, updated_at
-	FROM phones`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanPhones(rows)
+	return helpers.QueryPage(ctx, r.db, phoneListSpec, query, nil)
 }
 
 func (r *PhoneRepository) UpdatePhoneByUUID(uuid string) error {
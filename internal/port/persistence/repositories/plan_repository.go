@@ -5,12 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.PlanContract = (*PlanRepository)(nil)
@@ -33,8 +35,8 @@ func (r *PlanRepository) CreatePlan(plan *domain.VerkoupePlan) (*domain.Verkoupe
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO plans (name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	query := `INSERT INTO plans (name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, trial_days)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	RETURNING uuid, created_at, updated_at`
 
 	err := r.db.QueryRowContext(
@@ -48,6 +50,7 @@ func (r *PlanRepository) CreatePlan(plan *domain.VerkoupePlan) (*domain.Verkoupe
 		plan.CostPerSaleRate,
 		plan.Coin,
 		plan.Price,
+		plan.TrialDays,
 	).Scan(
 		&plan.UUID,
 		&plan.CreatedAt,
@@ -65,7 +68,7 @@ func (r *PlanRepository) FindPlanByUUID(uuid string) (*domain.VerkoupePlan, erro
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, updated_at, created_at
+	query := `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, trial_days, updated_at, created_at
 	FROM plans
 	WHERE uuid = $1`
 
@@ -77,7 +80,7 @@ func (r *PlanRepository) FindPlanByName(name string) (*domain.VerkoupePlan, erro
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, updated_at, created_at
+	query := `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, trial_days, updated_at, created_at
 	FROM plans
 	WHERE name = $1`
 
@@ -85,20 +88,30 @@ func (r *PlanRepository) FindPlanByName(name string) (*domain.VerkoupePlan, erro
 	return helpers.ScanPlan(row)
 }
 
-func (r *PlanRepository) GetPlans() ([]*domain.VerkoupePlan, error) {
+var planListSpec = helpers.ListSpec[*domain.VerkoupePlan]{
+	Query: `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, trial_days, updated_at, created_at
+	FROM plans`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.VerkoupePlan) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.VerkoupePlan]{
+		"name":       {Column: "name", Kind: helpers.TextColumn, Value: func(v *domain.VerkoupePlan) string { return v.Name }},
+		"price":      {Column: "price", Kind: helpers.IntColumn, Value: func(v *domain.VerkoupePlan) string { return strconv.Itoa(v.Price) }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.VerkoupePlan) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":      {Column: "name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"coin":      {Column: "coin", Kind: helpers.TextColumn},
+		"min_price": {Column: "price", Kind: helpers.IntColumn, Match: helpers.MatchMin},
+		"max_price": {Column: "price", Kind: helpers.IntColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanPlans,
+}
+
+func (r *PlanRepository) GetPlans(query *domain.ListQuery) (*domain.Page[*domain.VerkoupePlan], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, max_websites, max_routers, max_products, cost_per_sale_rate, coin, price, updated_at, created_at
-	FROM plans`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanPlans(rows)
+	return helpers.QueryPage(ctx, r.db, planListSpec, query, nil)
 }
 
 func (r *PlanRepository) UpdatePlanByUUID(uuid string) error {
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.PlatformFeeContract = (*PlatformFeeRepository)(nil)
+
+const platformFeeColumns = `uuid, owner_uuid, website_uuid, order_uuid, plan_uuid, type, coin, amount, rate, fee, created_at`
+
+type PlatformFeeRepository struct {
+	db *sql.DB
+}
+
+func NewPlatformFeeRepository(db *sql.DB) *PlatformFeeRepository {
+	return &PlatformFeeRepository{
+		db: db,
+	}
+}
+
+// RecordPlatformFee stores the fee of a sale once per order; recording the
+// same order again returns the entry already there.
+func (r *PlatformFeeRepository) RecordPlatformFee(fee *domain.PlatformFee) (*domain.PlatformFee, error) {
+	if fee == nil {
+		return nil, errors.New("invalid platform fee")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO platform_fees (owner_uuid, website_uuid, order_uuid, plan_uuid, type, coin, amount, rate, fee)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (order_uuid) WHERE type = 'fee' DO NOTHING
+	RETURNING ` + platformFeeColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		query,
+		fee.OwnerUUID,
+		fee.WebsiteUUID,
+		fee.OrderUUID,
+		fee.PlanUUID,
+		fee.Type,
+		fee.Coin,
+		fee.Amount,
+		fee.Rate,
+		fee.Fee,
+	)
+
+	recorded, err := helpers.ScanPlatformFee(row)
+	if err == nil {
+		return recorded, nil
+	}
+
+	existing, findErr := r.FindPlatformFeeByOrder(fee.OrderUUID.String())
+	if findErr != nil {
+		return nil, errors.New("could not record platform fee")
+	}
+
+	return existing, nil
+}
+
+// RefundPlatformFee gives back the share of an order's fee not yet given
+// back now that refundedTo of it was refunded in total. When nothing is
+// left to give back it returns nil without error.
+func (r *PlatformFeeRepository) RefundPlatformFee(orderUUID string, refundedTo int) (*domain.PlatformFee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sale, err := helpers.ScanPlatformFee(tx.QueryRowContext(
+		ctx,
+		`SELECT `+platformFeeColumns+`
+		FROM platform_fees
+		WHERE order_uuid = $1 AND type = $2
+		FOR UPDATE`,
+		orderUUID,
+		enums.PlatformFeeCharge,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var refunded int
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(amount), 0) FROM platform_fees WHERE order_uuid = $1 AND type = $2`,
+		orderUUID,
+		enums.PlatformFeeRefund,
+	).Scan(&refunded)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, fee := domain.PlatformFeeRefund(sale, refunded, refundedTo)
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	query := `INSERT INTO platform_fees (owner_uuid, website_uuid, order_uuid, plan_uuid, type, coin, amount, rate, fee)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING ` + platformFeeColumns
+
+	refund, err := helpers.ScanPlatformFee(tx.QueryRowContext(
+		ctx,
+		query,
+		sale.OwnerUUID,
+		sale.WebsiteUUID,
+		sale.OrderUUID,
+		sale.PlanUUID,
+		enums.PlatformFeeRefund,
+		sale.Coin,
+		amount,
+		sale.Rate,
+		fee,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (r *PlatformFeeRepository) FindPlatformFeeByOrder(orderUUID string) (*domain.PlatformFee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + platformFeeColumns + `
+	FROM platform_fees
+	WHERE order_uuid = $1 AND type = $2`
+
+	row := r.db.QueryRowContext(ctx, query, orderUUID, enums.PlatformFeeCharge)
+	return helpers.ScanPlatformFee(row)
+}
+
+var platformFeeListSpec = helpers.ListSpec[*domain.PlatformFee]{
+	Query: `SELECT ` + platformFeeColumns + `
+	FROM platform_fees`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.PlatformFee) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.PlatformFee]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.PlatformFee) string { return helpers.TimeValue(v.CreatedAt) }},
+		"amount":     {Column: "amount", Kind: helpers.IntColumn, Value: func(v *domain.PlatformFee) string { return strconv.Itoa(v.Amount) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"type":           {Column: "type", Kind: helpers.TextColumn},
+		"coin":           {Column: "coin", Kind: helpers.TextColumn},
+		"website_uuid":   {Column: "website_uuid", Kind: helpers.UUIDColumn},
+		"order_uuid":     {Column: "order_uuid", Kind: helpers.UUIDColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanPlatformFees,
+}
+
+func (r *PlatformFeeRepository) GetPlatformFees(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.PlatformFee], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, platformFeeListSpec, query, []string{"owner_uuid = $1"}, ownerUUID)
+}
+
+// GetPlatformFeesBetween lists the owner's entries created in [from, to).
+func (r *PlatformFeeRepository) GetPlatformFeesBetween(ownerUUID string, from time.Time, to time.Time) ([]*domain.PlatformFee, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + platformFeeColumns + `
+	FROM platform_fees
+	WHERE owner_uuid = $1 AND created_at >= $2 AND created_at < $3
+	ORDER BY created_at, uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanPlatformFees(rows)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.PreparingShippingProductContract = (*PreparingShippingProductRepository)(nil)
@@ -74,20 +75,23 @@ func (r *PreparingShippingProductRepository) FindPreparingShippingProductByProdu
 	return helpers.ScanPreparingShippingProduct(row)
 }
 
-func (r *PreparingShippingProductRepository) GetPreparingShippingProducts() ([]*domain.PreparingShippingProducts, error) {
+var preparingShippingProductListSpec = helpers.ListSpec[*domain.PreparingShippingProducts]{
+	Query: `SELECT uuid, product_uuid, address_uuid
+	FROM preparing_shipping_products`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.PreparingShippingProducts) uuid.UUID { return v.UUID },
+	Filters: map[string]helpers.FilterColumn{
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+		"address_uuid": {Column: "address_uuid", Kind: helpers.UUIDColumn},
+	},
+	Scan: helpers.ScanPreparingShippingProducts,
+}
+
+func (r *PreparingShippingProductRepository) GetPreparingShippingProducts(query *domain.ListQuery) (*domain.Page[*domain.PreparingShippingProducts], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, product_uuid, address_uuid
-	FROM preparing_shipping_products`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanPreparingShippingProducts(rows)
+	return helpers.QueryPage(ctx, r.db, preparingShippingProductListSpec, query, nil)
 }
 
 func (r *PreparingShippingProductRepository) UpdatePreparingShippingProductByUUID(uuid string) error {
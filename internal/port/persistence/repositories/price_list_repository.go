@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.PriceListContract = (*PriceListRepository)(nil)
+
+type PriceListRepository struct {
+	db *sql.DB
+}
+
+func NewPriceListRepository(db *sql.DB) *PriceListRepository {
+	return &PriceListRepository{
+		db: db,
+	}
+}
+
+func (r *PriceListRepository) CreatePriceList(list *domain.PriceList) (*domain.PriceList, error) {
+	if list == nil {
+		return nil, errors.New("invalid price list")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO prices_lists (website_uuid, label, coin, customer_group, priority, active)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING uuid, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		list.WebsiteUUID,
+		list.Label,
+		list.Coin,
+		list.CustomerGroup,
+		list.Priority,
+		list.Active,
+	).Scan(
+		&list.UUID,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not create price list")
+	}
+
+	return list, nil
+}
+
+func (r *PriceListRepository) FindPriceListByUUID(uuid string) (*domain.PriceList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, website_uuid, label, coin, customer_group, priority, active, updated_at, created_at
+	FROM prices_lists
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanPriceList(row)
+}
+
+var priceListListSpec = helpers.ListSpec[*domain.PriceList]{
+	Query: `SELECT uuid, website_uuid, label, coin, customer_group, priority, active, updated_at, created_at
+	FROM prices_lists`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.PriceList) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.PriceList]{
+		"priority":   {Column: "priority", Kind: helpers.IntColumn, Value: func(v *domain.PriceList) string { return strconv.Itoa(v.Priority) }},
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.PriceList) string { return v.Label }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.PriceList) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":          {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"coin":           {Column: "coin", Kind: helpers.TextColumn},
+		"customer_group": {Column: "customer_group", Kind: helpers.TextColumn},
+		"active":         {Column: "active", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "-priority",
+	Scan:        helpers.ScanPriceLists,
+}
+
+func (r *PriceListRepository) GetPriceListsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.PriceList], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, priceListListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
+}
+
+func (r *PriceListRepository) DeletePriceListByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM products_prices WHERE price_list_uuid = $1`, uuid); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM prices_lists WHERE uuid = $1`, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("price list not found")
+	}
+
+	return tx.Commit()
+}
+
+// SaveCustomerGroup puts the customer in group, moving them out of the one
+// they were in.
+func (r *PriceListRepository) SaveCustomerGroup(group *domain.CustomerGroup) (*domain.CustomerGroup, error) {
+	if group == nil {
+		return nil, errors.New("invalid customer group")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO customers_groups (website_uuid, user_uuid, customer_group)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (website_uuid, user_uuid) DO UPDATE
+	SET customer_group = EXCLUDED.customer_group,
+		updated_at = NOW()
+	RETURNING updated_at, created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		group.WebsiteUUID,
+		group.UserUUID,
+		group.Group,
+	).Scan(
+		&group.UpdatedAt,
+		&group.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.New("could not save customer group")
+	}
+
+	return group, nil
+}
+
+// FindCustomerGroup returns the group the customer is in on the website,
+// empty when they are in none.
+func (r *PriceListRepository) FindCustomerGroup(websiteUUID string, userUUID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var group string
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT customer_group FROM customers_groups WHERE website_uuid = $1 AND user_uuid = $2`,
+		websiteUUID,
+		userUUID,
+	).Scan(&group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	return group, err
+}
+
+func (r *PriceListRepository) DeleteCustomerGroup(websiteUUID string, userUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM customers_groups WHERE website_uuid = $1 AND user_uuid = $2`, websiteUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(result, "customer group not found")
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.ProductDownloadContract = (*ProductDownloadRepository)(nil)
+
+const productDownloadColumns = `d.uuid, d.order_uuid, d.product_uuid, d.file_uuid, f.filename, f.content_type, f.size_bytes, f.blob_key,
+	d.download_limit, d.download_count, d.expires_at, d.last_downloaded_at, d.created_at`
+
+type ProductDownloadRepository struct {
+	db *sql.DB
+}
+
+func NewProductDownloadRepository(db *sql.DB) *ProductDownloadRepository {
+	return &ProductDownloadRepository{
+		db: db,
+	}
+}
+
+// CreateProductDownloads is idempotent per order and file, so granting an
+// order twice, e.g. on a repeated payment notification, keeps the first
+// grant and its download count.
+func (r *ProductDownloadRepository) CreateProductDownloads(downloads []*domain.ProductDownload) error {
+	if len(downloads) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO products_downloads (order_uuid, product_uuid, file_uuid, download_limit, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (order_uuid, file_uuid) DO NOTHING`
+
+	for _, d := range downloads {
+		if _, err := tx.ExecContext(ctx, query, d.OrderUUID, d.ProductUUID, d.FileUUID, d.DownloadLimit, d.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProductDownloadRepository) FindProductDownloadByUUID(uuid string) (*domain.ProductDownload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + productDownloadColumns + `
+	FROM products_downloads d
+	JOIN products_files f ON f.uuid = d.file_uuid
+	WHERE d.uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductDownload(row)
+}
+
+func (r *ProductDownloadRepository) GetProductDownloadsFromOrder(orderUUID string) ([]*domain.ProductDownload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + productDownloadColumns + `
+	FROM products_downloads d
+	JOIN products_files f ON f.uuid = d.file_uuid
+	WHERE d.order_uuid = $1
+	ORDER BY d.created_at, f.created_at, d.uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductDownloads(rows)
+}
+
+// ConsumeProductDownload counts one download, checking the limit and the
+// expiry in the same statement so concurrent requests cannot overdraw it.
+func (r *ProductDownloadRepository) ConsumeProductDownload(uuid string) (*domain.ProductDownload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `WITH d AS (
+		UPDATE products_downloads
+		SET download_count = download_count + 1, last_downloaded_at = NOW()
+		WHERE uuid = $1 AND download_count < download_limit AND expires_at > NOW()
+		RETURNING *
+	)
+	SELECT ` + productDownloadColumns + `
+	FROM d
+	JOIN products_files f ON f.uuid = d.file_uuid`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	download, err := helpers.ScanProductDownload(row)
+	if err == nil {
+		return download, nil
+	}
+
+	current, findErr := r.FindProductDownloadByUUID(uuid)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if checkErr := current.Check(time.Now()); checkErr != nil {
+		return nil, checkErr
+	}
+	return nil, err
+}
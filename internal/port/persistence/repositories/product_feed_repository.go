@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.ProductFeedContract = (*ProductFeedRepository)(nil)
+
+type ProductFeedRepository struct {
+	db *sql.DB
+}
+
+func NewProductFeedRepository(db *sql.DB) *ProductFeedRepository {
+	return &ProductFeedRepository{
+		db: db,
+	}
+}
+
+// GetFeedProducts lists every active product of the website, one row per active variant,
+// leaving out products whose variants are all inactive and gift cards, which
+// the channels do not accept, with its stock, gallery images in display order
+// and the deepest active category it has on the website.
+func (r *ProductFeedRepository) GetFeedProducts(ctx context.Context, websiteUUID string) ([]*domain.FeedProduct, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	query := `SELECT p.uuid, p.name, p.description, p.short_description, p.brand, p.gtin,
+		v.uuid, v.sku, v.label, v.gtin, p.type = 'digital',
+		(SELECT COUNT(*) FROM storage_products s WHERE s.product_uuid = p.uuid),
+		COALESCE((
+			SELECT json_agg(m.blob_key ORDER BY m.position, m.created_at)
+			FROM products_medias m
+			WHERE m.product_uuid = p.uuid AND m.content_type LIKE 'image/%'
+		), '[]'),
+		(
+			SELECT c.name
+			FROM products_categories pc
+			JOIN categories c ON c.uuid = pc.category_uuid
+			WHERE pc.product_uuid = p.uuid AND c.website_uuid = $1 AND c.active
+			ORDER BY c.depth DESC, pc.position, c.name
+			LIMIT 1
+		)
+	FROM products p
+	LEFT JOIN products_variants v ON v.product_uuid = p.uuid AND v.active
+	WHERE p.website_uuid = $1 AND p.active AND p.type <> 'gift_card'
+	AND (v.uuid IS NOT NULL OR NOT EXISTS (SELECT 1 FROM products_variants x WHERE x.product_uuid = p.uuid))
+	ORDER BY p.created_at, p.uuid, v.created_at, v.uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanFeedProducts(rows)
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.ProductFileContract = (*ProductFileRepository)(nil)
+
+type ProductFileRepository struct {
+	db *sql.DB
+}
+
+func NewProductFileRepository(db *sql.DB) *ProductFileRepository {
+	return &ProductFileRepository{
+		db: db,
+	}
+}
+
+func (r *ProductFileRepository) CreateProductFile(file *domain.ProductFile) (*domain.ProductFile, error) {
+	if file == nil {
+		return nil, errors.New("invalid product file")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO products_files (product_uuid, blob_key, filename, content_type, size_bytes)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING uuid, created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		file.ProductUUID,
+		file.BlobKey,
+		file.Filename,
+		file.ContentType,
+		file.SizeBytes,
+	).Scan(
+		&file.UUID,
+		&file.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not create product file")
+	}
+
+	return file, nil
+}
+
+func (r *ProductFileRepository) FindProductFileByUUID(uuid string) (*domain.ProductFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, blob_key, filename, content_type, size_bytes, created_at
+	FROM products_files
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductFile(row)
+}
+
+func (r *ProductFileRepository) GetProductFilesFromProduct(productUUID string) ([]*domain.ProductFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, blob_key, filename, content_type, size_bytes, created_at
+	FROM products_files
+	WHERE product_uuid = $1
+	ORDER BY created_at, uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductFiles(rows)
+}
+
+// DeleteProductFileByUUID refuses to delete a file buyers can still
+// download; it can go once every grant on it is used up or expired.
+func (r *ProductFileRepository) DeleteProductFileByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM products_files f
+	WHERE f.uuid = $1
+	AND NOT EXISTS (
+		SELECT 1 FROM products_downloads d
+		WHERE d.file_uuid = f.uuid AND d.expires_at > NOW() AND d.download_count < d.download_limit
+	)`
+
+	result, err := r.db.ExecContext(ctx, query, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.FindProductFileByUUID(uuid); err != nil {
+			return err
+		}
+		return domain.ErrProductFileInUse
+	}
+
+	return nil
+}
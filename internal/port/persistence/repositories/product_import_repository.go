@@ -0,0 +1,403 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.ProductImportContract = (*ProductImportRepository)(nil)
+
+var ErrProductImportConflict = errors.New("product already exists")
+
+type ProductImportRepository struct {
+	db *sql.DB
+}
+
+func NewProductImportRepository(db *sql.DB) *ProductImportRepository {
+	return &ProductImportRepository{
+		db: db,
+	}
+}
+
+const productImportColumns = `uuid, website_uuid, filename, format, source, coin, status, dry_run, mapping, blob_key, total_rows, processed_rows, created_count, failed_count, warning_count, error, started_at, finished_at, updated_at, created_at`
+
+func (r *ProductImportRepository) CreateProductImportJob(job *domain.ProductImportJob) (*domain.ProductImportJob, error) {
+	if job == nil {
+		return nil, errors.New("invalid product import")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mapping, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO products_imports (uuid, website_uuid, filename, format, source, coin, status, dry_run, mapping, blob_key)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING created_at`
+
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		job.UUID,
+		job.WebsiteUUID,
+		job.Filename,
+		job.Format,
+		job.Source,
+		job.Coin,
+		job.Status,
+		job.DryRun,
+		mapping,
+		job.BlobKey,
+	).Scan(&job.CreatedAt)
+
+	if err != nil {
+		return nil, errors.New("could not create product import")
+	}
+
+	return job, nil
+}
+
+func (r *ProductImportRepository) FindProductImportJobByUUID(uuid string) (*domain.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + productImportColumns + `
+	FROM products_imports
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductImportJob(row)
+}
+
+var productImportListSpec = helpers.ListSpec[*domain.ProductImportJob]{
+	Query: `SELECT ` + productImportColumns + `
+	FROM products_imports`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductImportJob) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ProductImportJob]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ProductImportJob) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"status":  {Column: "status", Kind: helpers.TextColumn},
+		"format":  {Column: "format", Kind: helpers.TextColumn},
+		"source":  {Column: "source", Kind: helpers.TextColumn},
+		"dry_run": {Column: "dry_run", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanProductImportJobs,
+}
+
+func (r *ProductImportRepository) GetProductImportJobs(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportJob], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, productImportListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
+}
+
+var productImportErrorListSpec = helpers.ListSpec[*domain.ProductImportError]{
+	Query: `SELECT uuid, import_uuid, row_number, level, field, message, created_at
+	FROM products_imports_errors`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductImportError) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ProductImportError]{
+		"row": {Column: "row_number", Kind: helpers.IntColumn, Value: func(v *domain.ProductImportError) string { return strconv.Itoa(v.Row) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"level": {Column: "level", Kind: helpers.TextColumn},
+		"field": {Column: "field", Kind: helpers.TextColumn},
+	},
+	DefaultSort: "row",
+	Scan:        helpers.ScanProductImportErrors,
+}
+
+func (r *ProductImportRepository) GetProductImportErrors(importUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductImportError], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, productImportErrorListSpec, query, []string{"import_uuid = $1"}, importUUID)
+}
+
+// ClaimPendingProductImportJobs marks up to limit pending imports as running.
+// A run whose progress stopped moving for a while (a crashed worker) is
+// claimed again from scratch, so its previous errors are dropped.
+func (r *ProductImportRepository) ClaimPendingProductImportJobs(limit int) ([]*domain.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE products_imports
+	SET status = 'running', total_rows = 0, processed_rows = 0, created_count = 0, failed_count = 0, warning_count = 0,
+		error = NULL, started_at = NOW(), finished_at = NULL, updated_at = NOW()
+	WHERE uuid IN (
+		SELECT uuid FROM products_imports
+		WHERE status = 'pending'
+		OR (status = 'running' AND updated_at < NOW() - INTERVAL '10 minutes')
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + productImportColumns
+
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := helpers.ScanProductImportJobs(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM products_imports_errors WHERE import_uuid = $1`, job.UUID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// UpdateProductImportProgress saves the counters of a running import along
+// with the row errors found since the last update.
+func (r *ProductImportRepository) UpdateProductImportProgress(job *domain.ProductImportJob, problems []*domain.ProductImportError) error {
+	if job == nil {
+		return errors.New("invalid product import")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(problems) > 0 {
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("products_imports_errors", "import_uuid", "row_number", "level", "field", "message"))
+		if err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			if _, err := stmt.ExecContext(ctx, job.UUID, p.Row, p.Level, sql.NullString{String: truncate(p.Field, 50), Valid: p.Field != ""}, truncate(p.Message, 500)); err != nil {
+				stmt.Close()
+				return err
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			stmt.Close()
+			return err
+		}
+		if err := stmt.Close(); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE products_imports
+	SET total_rows = $2, processed_rows = $3, created_count = $4, failed_count = $5, warning_count = $6, updated_at = NOW()
+	WHERE uuid = $1`
+
+	if _, err := tx.ExecContext(ctx, query, job.UUID, job.TotalRows, job.ProcessedRows, job.CreatedCount, job.FailedCount, job.WarningCount); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProductImportRepository) CompleteProductImportJob(job *domain.ProductImportJob) error {
+	if job == nil {
+		return errors.New("invalid product import")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE products_imports
+	SET status = $2, error = NULLIF($3, ''), total_rows = $4, processed_rows = $5, created_count = $6, failed_count = $7,
+		warning_count = $8, finished_at = NOW(), updated_at = NOW()
+	WHERE uuid = $1`
+
+	_, err := r.db.ExecContext(ctx, query, job.UUID, job.Status, truncate(job.Error, 500), job.TotalRows, job.ProcessedRows, job.CreatedCount, job.FailedCount, job.WarningCount)
+	return err
+}
+
+// ImportProduct writes a product with its variants, tags, prices and stock
+// in one transaction. Names and SKUs already in the website's catalog are
+// rejected under an advisory lock, so two imports cannot both create the
+// same one, and the product counts against quota like any other.
+func (r *ProductImportRepository) ImportProduct(item *domain.ProductImportItem, quota domain.Quota) error {
+	if item == nil || item.Product == nil || item.Product.WebsiteUUID == nil {
+		return errors.New("invalid product import item")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('products_imports'))`); err != nil {
+		return err
+	}
+
+	if err := claimQuota(ctx, tx, quota, productUsageQuery); err != nil {
+		return err
+	}
+
+	p := item.Product
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE website_uuid = $1 AND LOWER(name) = LOWER($2))`, p.WebsiteUUID, p.Name).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return ErrProductImportConflict
+	}
+
+	skus := make([]string, 0, len(item.Variants))
+	for _, v := range item.Variants {
+		if v.SKU != "" {
+			skus = append(skus, v.SKU)
+		}
+	}
+	if len(skus) > 0 {
+		var taken sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT v.sku FROM products_variants v
+		JOIN products p ON p.uuid = v.product_uuid
+		WHERE p.website_uuid = $1 AND v.sku = ANY($2)
+		LIMIT 1`, p.WebsiteUUID, pq.Array(skus)).Scan(&taken)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if taken.Valid {
+			return fmt.Errorf("SKU %q already exists", taken.String)
+		}
+	}
+
+	height, width, thickness := p.Dimensions()
+	err = tx.QueryRowContext(ctx, `INSERT INTO products (uuid, website_uuid, name, description, short_description, brand, gtin, height, width, thickness, active)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, 0), NULLIF($9, 0), NULLIF($10, 0), $11)
+	RETURNING created_at`,
+		p.UUID, p.WebsiteUUID, p.Name, p.Description, p.ShortDescription, p.Brand, p.GTIN, height, width, thickness, p.Active,
+	).Scan(&p.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range item.Variants {
+		_, err := tx.ExecContext(ctx, `INSERT INTO products_variants (uuid, product_uuid, sku, label, gtin, active)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6)`,
+			v.UUID, v.ProductUUID, v.SKU, v.Label, v.GTIN, v.Active,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range item.Tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO products_tags (product_uuid, label) VALUES ($1, $2)`, t.ProductUUID, t.Label); err != nil {
+			return err
+		}
+	}
+
+	for _, price := range item.Prices {
+		_, err := tx.ExecContext(ctx, `INSERT INTO products_prices (product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+			price.ProductUUID, price.VariantUUID, price.PriceListUUID, price.Coin, price.Amount, price.CompareAtAmount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if item.Stock > 0 {
+		_, err := tx.ExecContext(ctx, `INSERT INTO storage_products (product_uuid) SELECT $1 FROM generate_series(1, $2)`, p.UUID, item.Stock)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StreamProductExport walks the website's catalog, one row per variant,
+// calling fn as rows arrive so the caller can write them out without
+// buffering. Each row carries the variant price, or the product base price
+// when the variant has none; price lists are left out.
+func (r *ProductImportRepository) StreamProductExport(ctx context.Context, websiteUUID string, fn func(row *domain.ProductExportRow) error) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	query := `SELECT p.uuid::TEXT, p.name, p.description, p.short_description, p.brand, p.gtin, p.height, p.width, p.thickness, p.active,
+		COALESCE((SELECT json_agg(t.label ORDER BY t.created_at) FROM products_tags t WHERE t.product_uuid = p.uuid), '[]'),
+		v.sku, v.gtin, v.label, v.active,
+		price.coin, price.amount, price.compare_at_amount,
+		(SELECT COUNT(*) FROM storage_products s WHERE s.product_uuid = p.uuid)
+	FROM products p
+	LEFT JOIN products_variants v ON v.product_uuid = p.uuid
+	LEFT JOIN LATERAL (
+		SELECT pp.coin, pp.amount, pp.compare_at_amount
+		FROM products_prices pp
+		WHERE pp.product_uuid = p.uuid
+		AND pp.price_list_uuid IS NULL
+		AND (pp.variant_uuid = v.uuid OR pp.variant_uuid IS NULL)
+		ORDER BY pp.variant_uuid IS NULL, pp.created_at
+		LIMIT 1
+	) price ON TRUE
+	WHERE p.website_uuid = $1
+	ORDER BY p.uuid, v.created_at NULLS FIRST, v.uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := helpers.ScanProductExportRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
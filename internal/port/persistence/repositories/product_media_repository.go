@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.ProductMediaContract = (*ProductMediaRepository)(nil)
+
+type ProductMediaRepository struct {
+	db *sql.DB
+}
+
+func NewProductMediaRepository(db *sql.DB) *ProductMediaRepository {
+	return &ProductMediaRepository{
+		db: db,
+	}
+}
+
+func (r *ProductMediaRepository) CreateProductMedia(media *domain.ProductMedia) (*domain.ProductMedia, error) {
+	if media == nil {
+		return nil, errors.New("invalid product media")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "products_medias:"+media.ProductUUID.String()); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO products_medias (product_uuid, blob_key, content_type, size_bytes, alt, position)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), (SELECT COALESCE(MAX(position) + 1, 0) FROM products_medias WHERE product_uuid = $1))
+	RETURNING uuid, position, created_at, updated_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		media.ProductUUID,
+		media.BlobKey,
+		media.ContentType,
+		media.SizeBytes,
+		media.Alt,
+	).Scan(
+		&media.UUID,
+		&media.Position,
+		&media.CreatedAt,
+		&media.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.New("could not create product media")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *ProductMediaRepository) FindProductMediaByUUID(uuid string) (*domain.ProductMedia, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, blob_key, content_type, size_bytes, alt, position, processing_status, processing_error, width, height, updated_at, created_at
+	FROM products_medias
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductMedia(row)
+}
+
+func (r *ProductMediaRepository) GetProductMediasFromProduct(productUUID string) ([]*domain.ProductMedia, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, blob_key, content_type, size_bytes, alt, position, processing_status, processing_error, width, height, updated_at, created_at
+	FROM products_medias
+	WHERE product_uuid = $1
+	ORDER BY position, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductMedias(rows)
+}
+
+func (r *ProductMediaRepository) GetProductMediaDerivativesFromProduct(productUUID string) ([]*domain.ProductMediaDerivative, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT d.uuid, d.media_uuid, d.blob_key, d.format, d.width, d.height, d.size_bytes, d.created_at
+	FROM products_medias_derivatives d
+	JOIN products_medias m ON m.uuid = d.media_uuid
+	WHERE m.product_uuid = $1
+	ORDER BY d.format, d.width`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductMediaDerivatives(rows)
+}
+
+func (r *ProductMediaRepository) GetProductMediaDerivativesFromMedia(mediaUUID string) ([]*domain.ProductMediaDerivative, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, media_uuid, blob_key, format, width, height, size_bytes, created_at
+	FROM products_medias_derivatives
+	WHERE media_uuid = $1
+	ORDER BY format, width`
+
+	rows, err := r.db.QueryContext(ctx, query, mediaUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductMediaDerivatives(rows)
+}
+
+// ReorderProductMedias assigns positions following the order of mediaUUIDs.
+// The list must contain every media of the product exactly once.
+func (r *ProductMediaRepository) ReorderProductMedias(productUUID string, mediaUUIDs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "products_medias:"+productUUID); err != nil {
+		return err
+	}
+
+	var total int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM products_medias WHERE product_uuid = $1`, productUUID).Scan(&total); err != nil {
+		return err
+	}
+
+	if total != len(mediaUUIDs) {
+		return errors.New("media list must contain every media of the product")
+	}
+
+	for position, mediaUUID := range mediaUUIDs {
+		result, err := tx.ExecContext(
+			ctx,
+			`UPDATE products_medias SET position = $1, updated_at = NOW() WHERE uuid = $2 AND product_uuid = $3`,
+			position,
+			mediaUUID,
+			productUUID,
+		)
+		if err != nil {
+			return errors.New("could not reorder product medias")
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return errors.New("product media not found")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProductMediaRepository) DeleteProductMediaByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM products_medias_derivatives WHERE media_uuid = $1`, uuid); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM products_medias WHERE uuid = $1`, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product media not found")
+	}
+
+	return tx.Commit()
+}
+
+// ClaimPendingProductMedias moves up to limit pending medias to processing.
+// Rows stuck in processing for a while (a crashed worker) are claimed again,
+// and SKIP LOCKED lets several instances share the queue.
+func (r *ProductMediaRepository) ClaimPendingProductMedias(limit int) ([]*domain.ProductMedia, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE products_medias SET processing_status = 'processing', processing_error = NULL, updated_at = NOW()
+	WHERE uuid IN (
+		SELECT uuid FROM products_medias
+		WHERE processing_status = 'pending'
+		OR (processing_status = 'processing' AND updated_at < NOW() - INTERVAL '10 minutes')
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING uuid, product_uuid, blob_key, content_type, size_bytes, alt, position, processing_status, processing_error, width, height, updated_at, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductMedias(rows)
+}
+
+// CompleteProductMediaProcessing replaces the derivatives of a media and
+// marks it ready.
+func (r *ProductMediaRepository) CompleteProductMediaProcessing(media *domain.ProductMedia, derivatives []*domain.ProductMediaDerivative) error {
+	if media == nil {
+		return errors.New("invalid product media")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM products_medias_derivatives WHERE media_uuid = $1`, media.UUID); err != nil {
+		return err
+	}
+
+	for _, d := range derivatives {
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO products_medias_derivatives (media_uuid, blob_key, format, width, height, size_bytes)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING uuid, created_at`,
+			media.UUID,
+			d.BlobKey,
+			d.Format,
+			d.Width,
+			d.Height,
+			d.SizeBytes,
+		).Scan(&d.UUID, &d.CreatedAt)
+		if err != nil {
+			return errors.New("could not create product media derivative")
+		}
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE products_medias
+		SET processing_status = 'ready', processing_error = NULL, size_bytes = $2, width = $3, height = $4, processed_at = NOW(), updated_at = NOW()
+		WHERE uuid = $1`,
+		media.UUID,
+		media.SizeBytes,
+		media.Width,
+		media.Height,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product media not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	media.Status = enums.MediaReady
+	media.Derivatives = derivatives
+	return nil
+}
+
+func (r *ProductMediaRepository) UpdateProductMediaStatus(uuid string, status enums.MediaStatusType, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE products_medias SET processing_status = $2, processing_error = NULLIF($3, ''), processed_at = NOW(), updated_at = NOW() WHERE uuid = $1`,
+		uuid,
+		status,
+		reason,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product media not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.ProductPriceContract = (*ProductPriceRepository)(nil)
+
+type ProductPriceRepository struct {
+	db *sql.DB
+}
+
+func NewProductPriceRepository(db *sql.DB) *ProductPriceRepository {
+	return &ProductPriceRepository{
+		db: db,
+	}
+}
+
+func (r *ProductPriceRepository) CreateProductPrice(price *domain.ProductPrice) (*domain.ProductPrice, error) {
+	if price == nil {
+		return nil, errors.New("invalid product price")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO products_prices (product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount, sale_amount, sale_starts_at, sale_ends_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING uuid, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		price.ProductUUID,
+		price.VariantUUID,
+		price.PriceListUUID,
+		price.Coin,
+		price.Amount,
+		price.CompareAtAmount,
+		price.SaleAmount,
+		price.SaleStartsAt,
+		price.SaleEndsAt,
+	).Scan(
+		&price.UUID,
+		&price.CreatedAt,
+		&price.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not create product price")
+	}
+
+	return price, nil
+}
+
+func (r *ProductPriceRepository) FindProductPriceByUUID(uuid string) (*domain.ProductPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount, sale_amount, sale_starts_at, sale_ends_at, updated_at, created_at
+	FROM products_prices
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductPrice(row)
+}
+
+func (r *ProductPriceRepository) GetProductPricesFromProduct(productUUID string) ([]*domain.ProductPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount, sale_amount, sale_starts_at, sale_ends_at, updated_at, created_at
+	FROM products_prices
+	WHERE product_uuid = $1
+	ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductPrices(rows)
+}
+
+// GetProductPricesFromProducts loads the prices of several products in one
+// query, for pricing a whole catalog.
+func (r *ProductPriceRepository) GetProductPricesFromProducts(productUUIDs []string) ([]*domain.ProductPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount, sale_amount, sale_starts_at, sale_ends_at, updated_at, created_at
+	FROM products_prices
+	WHERE product_uuid = ANY($1::UUID[])
+	ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productUUIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductPrices(rows)
+}
+
+var productPriceListSpec = helpers.ListSpec[*domain.ProductPrice]{
+	Query: `SELECT uuid, product_uuid, variant_uuid, price_list_uuid, coin, amount, compare_at_amount, sale_amount, sale_starts_at, sale_ends_at, updated_at, created_at
+	FROM products_prices`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductPrice) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ProductPrice]{
+		"amount":     {Column: "amount", Kind: helpers.IntColumn, Value: func(v *domain.ProductPrice) string { return strconv.Itoa(v.Amount) }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ProductPrice) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"coin":            {Column: "coin", Kind: helpers.TextColumn},
+		"variant_uuid":    {Column: "variant_uuid", Kind: helpers.UUIDColumn},
+		"price_list_uuid": {Column: "price_list_uuid", Kind: helpers.UUIDColumn},
+	},
+	DefaultSort: "created_at",
+	Scan:        helpers.ScanProductPrices,
+}
+
+// ListProductPricesFromProduct is the paged variant for the API; price
+// resolution still reads every row through GetProductPricesFromProduct.
+func (r *ProductPriceRepository) ListProductPricesFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductPrice], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, productPriceListSpec, query, []string{"product_uuid = $1"}, productUUID)
+}
+
+func (r *ProductPriceRepository) GetPriceListsFromProduct(productUUID string) ([]*domain.PriceList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT DISTINCT l.uuid, l.website_uuid, l.label, l.coin, l.customer_group, l.priority, l.active, l.updated_at, l.created_at
+	FROM prices_lists l
+	INNER JOIN products_prices p ON p.price_list_uuid = l.uuid
+	WHERE p.product_uuid = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanPriceLists(rows)
+}
+
+func (r *ProductPriceRepository) GetPriceListsFromProducts(productUUIDs []string) ([]*domain.PriceList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT DISTINCT l.uuid, l.website_uuid, l.label, l.coin, l.customer_group, l.priority, l.active, l.updated_at, l.created_at
+	FROM prices_lists l
+	INNER JOIN products_prices p ON p.price_list_uuid = l.uuid
+	WHERE p.product_uuid = ANY($1::UUID[])`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productUUIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanPriceLists(rows)
+}
+
+func (r *ProductPriceRepository) UpdateProductPrice(price *domain.ProductPrice) error {
+	if price == nil {
+		return errors.New("invalid product price")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE products_prices
+	SET amount = $2, compare_at_amount = $3, sale_amount = $4, sale_starts_at = $5, sale_ends_at = $6, updated_at = NOW()
+	WHERE uuid = $1`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		price.UUID,
+		price.Amount,
+		price.CompareAtAmount,
+		price.SaleAmount,
+		price.SaleStartsAt,
+		price.SaleEndsAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product price not found")
+	}
+
+	return nil
+}
+
+func (r *ProductPriceRepository) DeleteProductPriceByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM products_prices WHERE uuid = $1`
+
+	result, err := r.db.ExecContext(ctx, query, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product price not found")
+	}
+
+	return nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 var _ contracts.ProductContract = (*ProductRepository)(nil)
@@ -25,7 +27,9 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	}
 }
 
-func (r *ProductRepository) CreateProduct(product *domain.Products) (*domain.Products, error) {
+// CreateProduct fails with domain.ErrQuotaExceeded when the owner of the
+// product's website already has as many products as quota allows.
+func (r *ProductRepository) CreateProduct(product *domain.Products, quota domain.Quota) (*domain.Products, error) {
 	if product == nil {
 		return nil, errors.New("invalid product")
 	}
@@ -33,16 +37,32 @@ func (r *ProductRepository) CreateProduct(product *domain.Products) (*domain.Pro
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO products (name, description, short_description, active)
-	VALUES ($1, $2, $3, $4)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := claimQuota(ctx, tx, quota, productUsageQuery); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO products (website_uuid, name, description, short_description, brand, gtin, type, download_limit, download_days, active)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10)
 	RETURNING uuid, created_at, updated_at`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
+		product.WebsiteUUID,
 		product.Name,
 		product.Description,
 		product.ShortDescription,
+		product.Brand,
+		product.GTIN,
+		product.Type,
+		product.DownloadLimit,
+		product.DownloadDays,
 		product.Active,
 	).Scan(
 		&product.UUID,
@@ -54,6 +74,10 @@ func (r *ProductRepository) CreateProduct(product *domain.Products) (*domain.Pro
 		return nil, errors.New("could not create product")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return product, nil
 }
 
@@ -61,7 +85,7 @@ func (r *ProductRepository) FindProductByUUID(uuid string) (*domain.Products, er
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, short_description, active, created_at, updated_at
+	query := `SELECT uuid, website_uuid, name, description, short_description, brand, gtin, type, download_limit, download_days, rating_count, rating_average, active, created_at, updated_at
 	FROM products
 	WHERE uuid = $1`
 
@@ -73,7 +97,7 @@ func (r *ProductRepository) FindProductByName(name string) (*domain.Products, er
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, short_description, active, created_at, updated_at
+	query := `SELECT uuid, website_uuid, name, description, short_description, brand, gtin, type, download_limit, download_days, rating_count, rating_average, active, created_at, updated_at
 	FROM products
 	WHERE name = $1`
 
@@ -81,14 +105,19 @@ func (r *ProductRepository) FindProductByName(name string) (*domain.Products, er
 	return helpers.ScanProduct(row)
 }
 
-func (r *ProductRepository) GetProducts() ([]*domain.Products, error) {
+func (r *ProductRepository) GetProductsByUUIDS(uuids []string) ([]*domain.Products, error) {
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, short_description, active, created_at, updated_at
-	FROM products`
+	query := `SELECT uuid, website_uuid, name, description, short_description, brand, gtin, type, download_limit, download_days, rating_count, rating_average, active, created_at, updated_at
+	FROM products
+	WHERE uuid = ANY($1::UUID[])`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuids))
 	if err != nil {
 		return nil, err
 	}
@@ -97,21 +126,37 @@ func (r *ProductRepository) GetProducts() ([]*domain.Products, error) {
 	return helpers.ScanProducts(rows)
 }
 
-func (r *ProductRepository) GetActiveProducts() ([]*domain.Products, error) {
+var productListSpec = helpers.ListSpec[*domain.Products]{
+	Query: `SELECT uuid, website_uuid, name, description, short_description, brand, gtin, type, download_limit, download_days, rating_count, rating_average, active, created_at, updated_at
+	FROM products`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Products) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Products]{
+		"name":       {Column: "name", Kind: helpers.TextColumn, Value: func(v *domain.Products) string { return v.Name }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.Products) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":           {Column: "name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"active":         {Column: "active", Kind: helpers.BoolColumn},
+		"type":           {Column: "type", Kind: helpers.TextColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanProducts,
+}
+
+func (r *ProductRepository) GetProducts(query *domain.ListQuery) (*domain.Page[*domain.Products], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, short_description, active, created_at, updated_at
-	FROM products
-	WHERE active = true`
+	return helpers.QueryPage(ctx, r.db, productListSpec, query, nil)
+}
 
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (r *ProductRepository) GetActiveProducts(query *domain.ListQuery) (*domain.Page[*domain.Products], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	return helpers.ScanProducts(rows)
+	return helpers.QueryPage(ctx, r.db, productListSpec, query, []string{"active = true"})
 }
 
 func (r *ProductRepository) UpdateProductByUUID(uuid string) error {
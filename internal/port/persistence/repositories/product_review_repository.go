@@ -0,0 +1,361 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.ProductReviewContract = (*ProductReviewRepository)(nil)
+
+const productReviewColumns = `uuid, product_uuid, user_uuid, shipped_uuid, rating, title, body, verified, status, rejection_reason, moderated_by, moderated_at, updated_at, created_at`
+
+type ProductReviewRepository struct {
+	db *sql.DB
+}
+
+func NewProductReviewRepository(db *sql.DB) *ProductReviewRepository {
+	return &ProductReviewRepository{
+		db: db,
+	}
+}
+
+func (r *ProductReviewRepository) CreateProductReview(review *domain.ProductReview) (*domain.ProductReview, error) {
+	if review == nil {
+		return nil, errors.New("invalid product review")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO products_reviews (product_uuid, user_uuid, shipped_uuid, rating, title, body, verified, status)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8)
+	RETURNING uuid, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		review.ProductUUID,
+		review.UserUUID,
+		review.ShippedUUID,
+		review.Rating,
+		review.Title,
+		review.Body,
+		review.Verified,
+		review.Status,
+	).Scan(
+		&review.UUID,
+		&review.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrReviewExists
+		}
+		return nil, errors.New("could not create product review")
+	}
+
+	for _, photo := range review.Photos {
+		photo.ReviewUUID = review.UUID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO products_reviews_photos (review_uuid, blob_key, content_type, size_bytes, position)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING uuid, created_at`,
+			photo.ReviewUUID,
+			photo.BlobKey,
+			photo.ContentType,
+			photo.SizeBytes,
+			photo.Position,
+		).Scan(
+			&photo.UUID,
+			&photo.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *ProductReviewRepository) FindProductReviewByUUID(uuid string) (*domain.ProductReview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + productReviewColumns + `
+	FROM products_reviews
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductReview(row)
+}
+
+// FindDeliveredProductShipment looks for a delivery of the product to one
+// of the user's addresses. It returns nil when there is none.
+func (r *ProductReviewRepository) FindDeliveredProductShipment(productUUID string, userUUID string) (*uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ps.uuid
+	FROM products_shipped ps
+	JOIN addresses a ON a.uuid = ps.address_uuid
+	WHERE ps.product_uuid = $1
+	AND a.owner_uuid = $2
+	AND a.owner_type = 'User'
+	AND LOWER(ps.status) = $3
+	ORDER BY ps.uuid
+	LIMIT 1`
+
+	var shipped uuid.UUID
+	err := r.db.QueryRowContext(ctx, query, productUUID, userUUID, domain.ShipmentDelivered).Scan(&shipped)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &shipped, nil
+}
+
+func (r *ProductReviewRepository) CountProductReviewsByUserSince(userUUID string, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products_reviews WHERE user_uuid = $1 AND created_at >= $2`, userUUID, since).Scan(&count)
+	return count, err
+}
+
+var productReviewSorts = map[string]helpers.SortColumn[*domain.ProductReview]{
+	"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ProductReview) string { return helpers.TimeValue(v.CreatedAt) }},
+	"rating":     {Column: "rating", Kind: helpers.IntColumn, Value: func(v *domain.ProductReview) string { return strconv.Itoa(v.Rating) }},
+}
+
+var approvedProductReviewListSpec = helpers.ListSpec[*domain.ProductReview]{
+	Query: `SELECT ` + productReviewColumns + `
+	FROM products_reviews`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductReview) uuid.UUID { return v.UUID },
+	Sorts: productReviewSorts,
+	Filters: map[string]helpers.FilterColumn{
+		"rating":   {Column: "rating", Kind: helpers.IntColumn},
+		"verified": {Column: "verified", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanProductReviews,
+}
+
+func (r *ProductReviewRepository) GetApprovedProductReviews(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, approvedProductReviewListSpec, query, []string{"product_uuid = $1 AND status = $2"}, productUUID, enums.ReviewApproved)
+}
+
+var productReviewListSpec = helpers.ListSpec[*domain.ProductReview]{
+	Query: `SELECT ` + productReviewColumns + `
+	FROM products_reviews`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductReview) uuid.UUID { return v.UUID },
+	Sorts: productReviewSorts,
+	Filters: map[string]helpers.FilterColumn{
+		"status":       {Column: "status", Kind: helpers.TextColumn},
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+		"user_uuid":    {Column: "user_uuid", Kind: helpers.UUIDColumn},
+		"rating":       {Column: "rating", Kind: helpers.IntColumn},
+		"verified":     {Column: "verified", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "created_at",
+	Scan:        helpers.ScanProductReviews,
+}
+
+// GetProductReviews backs the moderation queue of a website; oldest first
+// so reviews are handled in the order they came in.
+func (r *ProductReviewRepository) GetProductReviews(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductReview], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, productReviewListSpec, query, []string{"product_uuid IN (SELECT uuid FROM products WHERE website_uuid = $1)"}, websiteUUID)
+}
+
+func (r *ProductReviewRepository) GetProductReviewPhotos(reviewUUIDs []uuid.UUID) ([]*domain.ProductReviewPhoto, error) {
+	if len(reviewUUIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids := make([]string, len(reviewUUIDs))
+	for i, id := range reviewUUIDs {
+		ids[i] = id.String()
+	}
+
+	query := `SELECT uuid, review_uuid, blob_key, content_type, size_bytes, position, created_at
+	FROM products_reviews_photos
+	WHERE review_uuid = ANY($1::UUID[])
+	ORDER BY review_uuid, position`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductReviewPhotos(rows)
+}
+
+func (r *ProductReviewRepository) FindProductReviewPhotoByUUID(uuid string) (*domain.ProductReviewPhoto, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, review_uuid, blob_key, content_type, size_bytes, position, created_at
+	FROM products_reviews_photos
+	WHERE uuid = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	photos, err := helpers.ScanProductReviewPhotos(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, errors.New("product review photo not found")
+	}
+
+	return photos[0], nil
+}
+
+// ModerateProductReview stores the decision with the keys of its photos,
+// which move once published, and refreshes the rating cached on the
+// product. The product row is locked first so concurrent decisions on the
+// same product recompute one after the other.
+func (r *ProductReviewRepository) ModerateProductReview(review *domain.ProductReview) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM products WHERE uuid = $1 FOR UPDATE`, review.ProductUUID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE products_reviews
+		SET status = $2, rejection_reason = NULLIF($3, ''), moderated_by = $4, moderated_at = $5, updated_at = NOW()
+		WHERE uuid = $1`,
+		review.UUID,
+		review.Status,
+		review.RejectionReason,
+		review.ModeratedBy,
+		review.ModeratedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product review not found")
+	}
+
+	for _, photo := range review.Photos {
+		if _, err := tx.ExecContext(ctx, `UPDATE products_reviews_photos SET blob_key = $2 WHERE uuid = $1`, photo.UUID, photo.BlobKey); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE products p
+		SET rating_count = s.count, rating_average = s.average
+		FROM (
+			SELECT COUNT(*) AS count, COALESCE(ROUND(AVG(rating), 2), 0) AS average
+			FROM products_reviews
+			WHERE product_uuid = $1 AND status = $2
+		) s
+		WHERE p.uuid = $1`,
+		review.ProductUUID,
+		enums.ReviewApproved,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ProductReviewRepository) GetProductRatingSummary(productUUID string) (*domain.ProductRatingSummary, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	summary := &domain.ProductRatingSummary{}
+	err := r.db.QueryRowContext(ctx, `SELECT uuid, rating_count, rating_average FROM products WHERE uuid = $1`, productUUID).Scan(
+		&summary.ProductUUID,
+		&summary.Count,
+		&summary.Average,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT rating, COUNT(*)
+	FROM products_reviews
+	WHERE product_uuid = $1 AND status = $2
+	GROUP BY rating`
+
+	rows, err := r.db.QueryContext(ctx, query, productUUID, enums.ReviewApproved)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return nil, err
+		}
+		if rating >= 1 && rating <= 5 {
+			summary.Distribution[rating] = count
+		}
+	}
+
+	return summary, rows.Err()
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/lib/pq"
+)
+
+var _ contracts.ProductSearchContract = (*ProductSearchRepository)(nil)
+
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter=" … "`
+
+type ProductSearchRepository struct {
+	db *sql.DB
+}
+
+func NewProductSearchRepository(db *sql.DB) *ProductSearchRepository {
+	return &ProductSearchRepository{
+		db: db,
+	}
+}
+
+// SearchProducts matches the query against the weighted search_vector
+// (portuguese stemming, accents folded) and falls back to trigram word
+// similarity on the name so typos like "camizeta" still find "Camiseta".
+// Facets are computed over the whole matched set, not only the page.
+func (r *ProductSearchRepository) SearchProducts(query *domain.ProductSearchQuery) (*domain.ProductSearchResult, error) {
+	if query == nil {
+		return nil, errors.New("invalid search query")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cte, args := buildProductSearchCTE(query)
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	nameHighlight := "html_escape(p.name)"
+	snippet := "html_escape(LEFT(COALESCE(p.short_description, ''), 200))"
+	if query.Text != "" {
+		nameHighlight = "ts_headline('portuguese_unaccent', html_escape(p.name), q.tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')"
+		snippet = fmt.Sprintf("ts_headline('portuguese_unaccent', html_escape(COALESCE(p.short_description, '') || ' ' || COALESCE(p.description, '')), q.tsq, '%s')", searchHeadlineOptions)
+	}
+
+	hitsArgs := append(append([]interface{}{}, args...), query.Limit, query.Offset)
+	hitsQuery := fmt.Sprintf(`%s
+	SELECT p.uuid, p.name, COALESCE(p.description, ''), COALESCE(p.short_description, ''), p.active, p.created_at, p.updated_at,
+		m.rank, m.price, m.in_stock, %s, %s
+	FROM matched m
+	INNER JOIN products p ON p.uuid = m.uuid
+	CROSS JOIN q
+	ORDER BY m.rank DESC, p.uuid DESC
+	LIMIT $%d OFFSET $%d`, cte, nameHighlight, snippet, len(args)+1, len(args)+2)
+
+	rows, err := tx.QueryContext(ctx, hitsQuery, hitsArgs...)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := helpers.ScanProductSearchHits(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ProductSearchResult{Hits: hits}
+
+	var priceMin, priceMax sql.NullInt64
+	err = tx.QueryRowContext(ctx, cte+`
+	SELECT COUNT(*), COUNT(*) FILTER (WHERE in_stock), MIN(price), MAX(price)
+	FROM matched`, args...).Scan(&result.Total, &result.Facets.InStock, &priceMin, &priceMax)
+	if err != nil {
+		return nil, err
+	}
+	result.Facets.OutOfStock = result.Total - result.Facets.InStock
+	if priceMin.Valid {
+		minPrice, maxPrice := int(priceMin.Int64), int(priceMax.Int64)
+		result.Facets.PriceMin = &minPrice
+		result.Facets.PriceMax = &maxPrice
+	}
+
+	rows, err = tx.QueryContext(ctx, cte+`
+	SELECT LOWER(t.label), MIN(t.label), COUNT(DISTINCT t.product_uuid)
+	FROM products_tags t
+	INNER JOIN matched m ON m.uuid = t.product_uuid
+	GROUP BY LOWER(t.label)
+	ORDER BY 3 DESC, 1
+	LIMIT 20`, args...)
+	if err != nil {
+		return nil, err
+	}
+	result.Facets.Tags, err = helpers.ScanFacetCounts(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, cte+`
+	SELECT c.uuid::TEXT, c.name, COUNT(DISTINCT pc.product_uuid)
+	FROM products_categories pc
+	INNER JOIN matched m ON m.uuid = pc.product_uuid
+	INNER JOIN categories c ON c.uuid = pc.category_uuid
+	GROUP BY c.uuid, c.name
+	ORDER BY 3 DESC, 2
+	LIMIT 20`, args...)
+	if err != nil {
+		return nil, err
+	}
+	result.Facets.Categories, err = helpers.ScanFacetCounts(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// buildProductSearchCTE returns the shared "q" and "matched" CTEs, limited
+// to the products of the query website. The price used for filters and
+// facets is the base product price in the query coin, with an active sale
+// applied.
+func buildProductSearchCTE(query *domain.ProductSearchQuery) (string, []interface{}) {
+	args := []interface{}{query.Text, query.Coin, query.WebsiteUUID}
+	param := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	rank := "0::FLOAT8"
+	conditions := []string{"p.website_uuid = $3", "p.active"}
+
+	if query.Text != "" {
+		rank = "ts_rank_cd(p.search_vector, q.tsq, 32) + word_similarity(q.plain, immutable_unaccent(LOWER(p.name)))"
+		conditions = append(conditions, "(p.search_vector @@ q.tsq OR q.plain <% immutable_unaccent(LOWER(p.name)))")
+	}
+
+	if len(query.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM products_tags t WHERE t.product_uuid = p.uuid AND LOWER(t.label) = ANY(%s))",
+			param(pq.Array(query.Tags)),
+		))
+	}
+
+	if query.CategoryUUID != nil {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM products_categories pc
+			INNER JOIN categories c ON c.uuid = pc.category_uuid
+			INNER JOIN categories root ON root.uuid = %s
+			WHERE pc.product_uuid = p.uuid AND c.website_uuid = root.website_uuid AND c.path LIKE root.path || '%%'
+		)`, param(*query.CategoryUUID)))
+	}
+
+	if query.MinPrice != nil {
+		conditions = append(conditions, "price.amount >= "+param(*query.MinPrice))
+	}
+
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "price.amount <= "+param(*query.MaxPrice))
+	}
+
+	inStock := "EXISTS (SELECT 1 FROM storage_products s WHERE s.product_uuid = p.uuid)"
+	if query.InStock != nil {
+		if *query.InStock {
+			conditions = append(conditions, inStock)
+		} else {
+			conditions = append(conditions, "NOT "+inStock)
+		}
+	}
+
+	cte := fmt.Sprintf(`WITH q AS (
+		SELECT websearch_to_tsquery('portuguese_unaccent', $1) AS tsq, immutable_unaccent(LOWER($1)) AS plain
+	), matched AS (
+		SELECT p.uuid, %s AS rank, price.amount AS price, %s AS in_stock
+		FROM products p
+		CROSS JOIN q
+		LEFT JOIN LATERAL (
+			SELECT CASE
+				WHEN pp.sale_amount IS NOT NULL AND pp.sale_starts_at <= NOW() AND (pp.sale_ends_at IS NULL OR pp.sale_ends_at > NOW())
+				THEN pp.sale_amount
+				ELSE pp.amount
+			END AS amount
+			FROM products_prices pp
+			WHERE pp.product_uuid = p.uuid AND pp.variant_uuid IS NULL AND pp.price_list_uuid IS NULL AND pp.coin = $2
+			LIMIT 1
+		) price ON TRUE
+		WHERE %s
+	)`, rank, inStock, strings.Join(conditions, " AND "))
+
+	return cte, args
+}
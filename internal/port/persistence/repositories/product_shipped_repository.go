@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.ProductShippedContract = (*ProductShippedRepository)(nil)
@@ -100,20 +101,24 @@ func (r *ProductShippedRepository) FindProductShippedByStatus(status string) ([]
 	return helpers.ScanProductsShipped(rows)
 }
 
-func (r *ProductShippedRepository) GetProductsShipped() ([]*domain.ProductShipped, error) {
+var productShippedListSpec = helpers.ListSpec[*domain.ProductShipped]{
+	Query: `SELECT uuid, product_uuid, address_uuid, status
+	FROM products_shipped`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductShipped) uuid.UUID { return v.UUID },
+	Filters: map[string]helpers.FilterColumn{
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+		"address_uuid": {Column: "address_uuid", Kind: helpers.UUIDColumn},
+		"status":       {Column: "status", Kind: helpers.TextColumn},
+	},
+	Scan: helpers.ScanProductsShipped,
+}
+
+func (r *ProductShippedRepository) GetProductsShipped(query *domain.ListQuery) (*domain.Page[*domain.ProductShipped], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, product_uuid, address_uuid, status
-	FROM products_shipped`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanProductsShipped(rows)
+	return helpers.QueryPage(ctx, r.db, productShippedListSpec, query, nil)
 }
 
 func (r *ProductShippedRepository) UpdateProductShippedByUUID(uuid string) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.ProductTagContract = (*ProductTagRepository)(nil)
@@ -84,37 +85,34 @@ func (r *ProductTagRepository) FindProductTagsByLabel(label string) ([]*domain.P
 	return helpers.ScanProductTags(rows)
 }
 
-func (r *ProductTagRepository) GetProductTagsFromProduct(productUUID string) ([]*domain.ProductsTags, error) {
+var productTagListSpec = helpers.ListSpec[*domain.ProductsTags]{
+	Query: `SELECT uuid, product_uuid, label, created_at, updated_at
+	FROM products_tags`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductsTags) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ProductsTags]{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.ProductsTags) string { return v.Label }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ProductsTags) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":        {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+	},
+	Scan: helpers.ScanProductTags,
+}
+
+func (r *ProductTagRepository) GetProductTagsFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductsTags], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, product_uuid, label, created_at, updated_at
-	FROM products_tags
-	WHERE product_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, productUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanProductTags(rows)
+	return helpers.QueryPage(ctx, r.db, productTagListSpec, query, []string{"product_uuid = $1"}, productUUID)
 }
 
-func (r *ProductTagRepository) GetProductTags() ([]*domain.ProductsTags, error) {
+func (r *ProductTagRepository) GetProductTags(query *domain.ListQuery) (*domain.Page[*domain.ProductsTags], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, product_uuid, label, created_at, updated_at
-	FROM products_tags`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanProductTags(rows)
+	return helpers.QueryPage(ctx, r.db, productTagListSpec, query, nil)
 }
 
 func (r *ProductTagRepository) UpdateProductTagByUUID(uuid string) error {
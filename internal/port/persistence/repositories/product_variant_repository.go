@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.ProductVariantContract = (*ProductVariantRepository)(nil)
+
+type ProductVariantRepository struct {
+	db *sql.DB
+}
+
+func NewProductVariantRepository(db *sql.DB) *ProductVariantRepository {
+	return &ProductVariantRepository{
+		db: db,
+	}
+}
+
+func (r *ProductVariantRepository) CreateProductVariant(variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	if variant == nil {
+		return nil, errors.New("invalid product variant")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO products_variants (product_uuid, sku, label, gtin, active)
+	VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), $5)
+	RETURNING uuid, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		variant.ProductUUID,
+		variant.SKU,
+		variant.Label,
+		variant.GTIN,
+		variant.Active,
+	).Scan(
+		&variant.UUID,
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, errors.New("could not create product variant")
+	}
+
+	return variant, nil
+}
+
+func (r *ProductVariantRepository) FindProductVariantByUUID(uuid string) (*domain.ProductVariant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT uuid, product_uuid, sku, label, gtin, active, updated_at, created_at
+	FROM products_variants
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanProductVariant(row)
+}
+
+var productVariantListSpec = helpers.ListSpec[*domain.ProductVariant]{
+	Query: `SELECT uuid, product_uuid, sku, label, gtin, active, updated_at, created_at
+	FROM products_variants`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ProductVariant) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ProductVariant]{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(v *domain.ProductVariant) string { return v.Label }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ProductVariant) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"sku":    {Column: "sku", Kind: helpers.TextColumn},
+		"label":  {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"active": {Column: "active", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "created_at",
+	Scan:        helpers.ScanProductVariants,
+}
+
+func (r *ProductVariantRepository) GetProductVariantsFromProduct(productUUID string, query *domain.ListQuery) (*domain.Page[*domain.ProductVariant], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, productVariantListSpec, query, []string{"product_uuid = $1"}, productUUID)
+}
+
+func (r *ProductVariantRepository) DeleteProductVariantByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM products_prices WHERE variant_uuid = $1`, uuid); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM products_variants WHERE uuid = $1`, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("product variant not found")
+	}
+
+	return tx.Commit()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.RbacContract = (*RbacRepository)(nil)
@@ -84,37 +85,39 @@ func (r *RbacRepository) FindRbacByLabelAndWebsite(label string, websiteUUID str
 	return helpers.ScanRbac(row)
 }
 
-func (r *RbacRepository) GetRbacFromWebsite(websiteUUID string) ([]*domain.Rbac, error) {
+var rbacListSpec = helpers.ListSpec[*domain.Rbac]{
+	Query: `SELECT uuid, website_uuid, label, can_read, can_write, can_update, can_upgrade, can_delete, created_at, updated_at
+	FROM rbac`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Rbac) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Rbac]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.Rbac) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":          {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"can_read":       {Column: "can_read", Kind: helpers.BoolColumn},
+		"can_write":      {Column: "can_write", Kind: helpers.BoolColumn},
+		"can_update":     {Column: "can_update", Kind: helpers.BoolColumn},
+		"can_upgrade":    {Column: "can_upgrade", Kind: helpers.BoolColumn},
+		"can_delete":     {Column: "can_delete", Kind: helpers.BoolColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanRbacSlice,
+}
+
+func (r *RbacRepository) GetRbacFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.Rbac], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, label, can_read, can_write, can_update, can_upgrade, can_delete, created_at, updated_at
-	FROM rbac
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanRbacSlice(rows)
+	return helpers.QueryPage(ctx, r.db, rbacListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *RbacRepository) GetRbac() ([]*domain.Rbac, error) {
+func (r *RbacRepository) GetRbac(query *domain.ListQuery) (*domain.Page[*domain.Rbac], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, label, can_read, can_write, can_update, can_upgrade, can_delete, created_at, updated_at
-	FROM rbac`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanRbacSlice(rows)
+	return helpers.QueryPage(ctx, r.db, rbacListSpec, query, nil)
 }
 
 func (r *RbacRepository) UpdateRbacByUUID(uuid string) error {
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.SearchSuggestionContract = (*SearchSuggestionRepository)(nil)
+
+// Suggestions run on every keystroke, so they get a much tighter deadline
+// than regular queries.
+const suggestionTimeout = 500 * time.Millisecond
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type SearchSuggestionRepository struct {
+	db *sql.DB
+}
+
+func NewSearchSuggestionRepository(db *sql.DB) *SearchSuggestionRepository {
+	return &SearchSuggestionRepository{
+		db: db,
+	}
+}
+
+// SuggestProducts prefers names starting with the typed text and then falls
+// back to trigram word similarity for typos.
+func (r *SearchSuggestionRepository) SuggestProducts(prefix string, websiteUUID string, limit int) ([]*domain.ProductSuggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), suggestionTimeout)
+	defer cancel()
+
+	query := `WITH q AS (SELECT immutable_unaccent($1) AS plain)
+	SELECT p.uuid, p.name
+	FROM products p
+	CROSS JOIN q
+	WHERE p.active
+	AND p.website_uuid = $3
+	AND (immutable_unaccent(LOWER(p.name)) LIKE q.plain || '%' OR q.plain <% immutable_unaccent(LOWER(p.name)))
+	ORDER BY immutable_unaccent(LOWER(p.name)) LIKE q.plain || '%' DESC,
+		word_similarity(q.plain, immutable_unaccent(LOWER(p.name))) DESC,
+		p.name
+	LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, likeEscaper.Replace(prefix), limit, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanProductSuggestions(rows)
+}
+
+func (r *SearchSuggestionRepository) SuggestCategories(prefix string, websiteUUID string, limit int) ([]*domain.CategorySuggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), suggestionTimeout)
+	defer cancel()
+
+	query := `WITH q AS (SELECT immutable_unaccent($1) AS plain)
+	SELECT c.uuid, c.name, c.slug
+	FROM categories c
+	CROSS JOIN q
+	WHERE c.active
+	AND c.website_uuid = $3
+	AND (immutable_unaccent(LOWER(c.name)) LIKE q.plain || '%' OR q.plain <% immutable_unaccent(LOWER(c.name)))
+	ORDER BY immutable_unaccent(LOWER(c.name)) LIKE q.plain || '%' DESC,
+		word_similarity(q.plain, immutable_unaccent(LOWER(c.name))) DESC,
+		c.depth,
+		c.name
+	LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, likeEscaper.Replace(prefix), limit, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanCategorySuggestions(rows)
+}
+
+// SuggestSearchQueries only offers past searches on the website that
+// returned something, ranked by how often they were made.
+func (r *SearchSuggestionRepository) SuggestSearchQueries(prefix string, websiteUUID string, limit int) ([]*domain.SearchQuery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), suggestionTimeout)
+	defer cancel()
+
+	query := `SELECT query, searches, last_results, last_searched_at
+	FROM search_queries
+	WHERE website_uuid = $3 AND query LIKE $1 || '%' AND last_results > 0
+	ORDER BY searches DESC, query
+	LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, likeEscaper.Replace(prefix), limit, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanSearchQueries(rows)
+}
+
+func (r *SearchSuggestionRepository) GetPopularSearchQueries(websiteUUID string, limit int) ([]*domain.SearchQuery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT query, searches, last_results, last_searched_at
+	FROM search_queries
+	WHERE website_uuid = $2
+	ORDER BY searches DESC, query
+	LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanSearchQueries(rows)
+}
+
+func (r *SearchSuggestionRepository) RecordSearchQueries(queries []*domain.SearchQuery) error {
+	if len(queries) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO search_queries (website_uuid, query, searches, last_results, last_searched_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (website_uuid, query) DO UPDATE SET
+		searches = search_queries.searches + EXCLUDED.searches,
+		last_results = EXCLUDED.last_results,
+		last_searched_at = GREATEST(search_queries.last_searched_at, EXCLUDED.last_searched_at)`
+
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, query, q.WebsiteUUID, q.Query, q.Searches, q.LastResults, q.LastSearchedAt); err != nil {
+			return errors.New("could not record search queries")
+		}
+	}
+
+	return tx.Commit()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.StorageProductContract = (*StorageProductRepository)(nil)
@@ -73,20 +74,22 @@ func (r *StorageProductRepository) FindStorageProductByProductUUID(productUUID s
 	return helpers.ScanStorageProduct(row)
 }
 
-func (r *StorageProductRepository) GetStorageProducts() ([]*domain.StorageProducts, error) {
+var storageProductListSpec = helpers.ListSpec[*domain.StorageProducts]{
+	Query: `SELECT uuid, product_uuid
+	FROM storage_products`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.StorageProducts) uuid.UUID { return v.UUID },
+	Filters: map[string]helpers.FilterColumn{
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+	},
+	Scan: helpers.ScanStorageProducts,
+}
+
+func (r *StorageProductRepository) GetStorageProducts(query *domain.ListQuery) (*domain.Page[*domain.StorageProducts], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, product_uuid
-	FROM storage_products`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanStorageProducts(rows)
+	return helpers.QueryPage(ctx, r.db, storageProductListSpec, query, nil)
 }
 
 func (r *StorageProductRepository) UpdateStorageProductByUUID(uuid string) error {
@@ -0,0 +1,543 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+)
+
+var _ contracts.SubscriptionContract = (*SubscriptionRepository)(nil)
+
+// What an owner uses of each plan limit. Routes are the distinct component
+// paths of the owner's websites.
+const (
+	websiteUsageQuery = `SELECT COUNT(*) FROM websites WHERE owner_uuid = $1`
+	routeUsageQuery   = `SELECT COUNT(*) FROM (
+		SELECT DISTINCT c.website_uuid, c.path
+		FROM websites_components c
+		JOIN websites w ON w.uuid = c.website_uuid
+		WHERE w.owner_uuid = $1
+	) routes`
+	productUsageQuery = `SELECT COUNT(*) FROM products p JOIN websites w ON w.uuid = p.website_uuid WHERE w.owner_uuid = $1`
+)
+
+const subscriptionColumns = `uuid, owner_uuid, owner_type, plan_uuid, status, cycle, payment_method, trial_ends_at, current_period_start, current_period_end, cancel_at_period_end, past_due_since, credit, updated_at, created_at`
+
+const subscriptionInvoiceColumns = `uuid, subscription_uuid, owner_uuid, plan_uuid, reason, cycle, coin, amount, credit_applied, status, attempts, next_attempt_at, period_start, period_end, provider_reference, failure_reason, paid_at, updated_at, created_at`
+
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+// CreateSubscription puts the owner on the subscription's plan. An owner
+// whose last subscription was canceled or never got paid starts over on
+// the same row, keeping its credit and the end of any trial it already
+// had; open invoices of the old attempt are voided.
+func (r *SubscriptionRepository) CreateSubscription(subscription *domain.Subscription) (*domain.Subscription, error) {
+	if subscription == nil {
+		return nil, errors.New("invalid subscription")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO subscriptions (owner_uuid, owner_type, plan_uuid, status, cycle, payment_method, trial_ends_at, current_period_start, current_period_end)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (owner_uuid) DO UPDATE
+	SET owner_type = EXCLUDED.owner_type,
+		plan_uuid = EXCLUDED.plan_uuid,
+		status = EXCLUDED.status,
+		cycle = EXCLUDED.cycle,
+		payment_method = EXCLUDED.payment_method,
+		trial_ends_at = COALESCE(EXCLUDED.trial_ends_at, subscriptions.trial_ends_at),
+		current_period_start = EXCLUDED.current_period_start,
+		current_period_end = EXCLUDED.current_period_end,
+		cancel_at_period_end = FALSE,
+		past_due_since = NULL,
+		updated_at = NOW()
+	WHERE subscriptions.status IN ($10, $11)
+	RETURNING uuid, trial_ends_at, credit, updated_at, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		subscription.OwnerUUID,
+		subscription.OwnerType,
+		subscription.PlanUUID,
+		subscription.Status,
+		subscription.Cycle,
+		subscription.PaymentMethod,
+		subscription.TrialEndsAt,
+		subscription.CurrentPeriodStart,
+		subscription.CurrentPeriodEnd,
+		enums.SubscriptionCanceled,
+		enums.SubscriptionIncomplete,
+	).Scan(
+		&subscription.UUID,
+		&subscription.TrialEndsAt,
+		&subscription.Credit,
+		&subscription.UpdatedAt,
+		&subscription.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSubscriptionExists
+	}
+	if err != nil {
+		return nil, errors.New("could not save subscription")
+	}
+
+	if err := voidOpenInvoices(ctx, tx, subscription.UUID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *SubscriptionRepository) UpdateSubscription(subscription *domain.Subscription) (*domain.Subscription, error) {
+	if subscription == nil {
+		return nil, errors.New("invalid subscription")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := updateSubscription(ctx, r.db, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// CancelSubscription ends the subscription now and voids its open
+// invoices, so nothing is retried after it.
+func (r *SubscriptionRepository) CancelSubscription(subscription *domain.Subscription) (*domain.Subscription, error) {
+	if subscription == nil {
+		return nil, errors.New("invalid subscription")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	subscription.Status = enums.SubscriptionCanceled
+	subscription.CancelAtPeriodEnd = false
+	subscription.PastDueSince = nil
+	if err := updateSubscription(ctx, tx, subscription); err != nil {
+		return nil, err
+	}
+
+	if err := voidOpenInvoices(ctx, tx, subscription.UUID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *SubscriptionRepository) FindSubscriptionByOwner(ownerUUID string) (*domain.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE owner_uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, ownerUUID)
+	return helpers.ScanSubscription(row)
+}
+
+// FindActivePlanByOwner finds the plan the owner can use right now: during
+// its trial, while paid up and through the grace period of a failed
+// renewal.
+func (r *SubscriptionRepository) FindActivePlanByOwner(ownerUUID string) (*domain.VerkoupePlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT p.uuid, p.name, p.description, p.max_websites, p.max_routers, p.max_products, p.cost_per_sale_rate, p.coin, p.price, p.trial_days, p.updated_at, p.created_at
+	FROM subscriptions s
+	JOIN plans p ON p.uuid = s.plan_uuid
+	WHERE s.owner_uuid = $1 AND s.status IN ($2, $3, $4)`
+
+	row := r.db.QueryRowContext(ctx, query, ownerUUID, enums.SubscriptionTrialing, enums.SubscriptionActive, enums.SubscriptionPastDue)
+	return helpers.ScanPlan(row)
+}
+
+func (r *SubscriptionRepository) GetPlanUsage(ownerUUID string) (*domain.PlanUsage, error) {
+	ownerUUIDParsed, err := uuid.Parse(ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	usage := &domain.PlanUsage{OwnerUUID: ownerUUIDParsed}
+	query := `SELECT (` + websiteUsageQuery + `), (` + routeUsageQuery + `), (` + productUsageQuery + `)`
+
+	err = r.db.QueryRowContext(ctx, query, ownerUUID).Scan(
+		&usage.Websites,
+		&usage.Routers,
+		&usage.Products,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// GetDueSubscriptions finds trials and paid periods that ended by now.
+// Subscriptions already past due are retried through their invoice.
+func (r *SubscriptionRepository) GetDueSubscriptions(now time.Time) ([]*domain.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE status IN ($1, $2) AND current_period_end <= $3
+	ORDER BY current_period_end, uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, enums.SubscriptionTrialing, enums.SubscriptionActive, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanSubscriptions(rows)
+}
+
+// SuspendOverdueSubscriptions suspends subscriptions past due since
+// before cutoff together with their owners' websites, and reports how many
+// subscriptions it suspended.
+func (r *SubscriptionRepository) SuspendOverdueSubscriptions(cutoff time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `WITH suspended AS (
+		UPDATE subscriptions
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND past_due_since <= $3
+		RETURNING owner_uuid
+	), suspended_websites AS (
+		UPDATE websites
+		SET suspended_at = NOW()
+		WHERE owner_uuid IN (SELECT owner_uuid FROM suspended) AND suspended_at IS NULL
+		RETURNING uuid
+	)
+	SELECT COUNT(*) FROM suspended`
+
+	var suspended int
+	err := r.db.QueryRowContext(ctx, query, enums.SubscriptionSuspended, enums.SubscriptionPastDue, cutoff).Scan(&suspended)
+	if err != nil {
+		return 0, err
+	}
+
+	return suspended, nil
+}
+
+// CreateInvoice stores a new open invoice. A renewal is created once per
+// period: creating it again returns the one already there.
+func (r *SubscriptionRepository) CreateInvoice(invoice *domain.SubscriptionInvoice) (*domain.SubscriptionInvoice, error) {
+	if invoice == nil {
+		return nil, errors.New("invalid invoice")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO subscriptions_invoices (subscription_uuid, owner_uuid, plan_uuid, reason, cycle, coin, amount, credit_applied, period_start, period_end)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (subscription_uuid, period_start) WHERE reason = 'renewal' DO NOTHING
+	RETURNING ` + subscriptionInvoiceColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		query,
+		invoice.SubscriptionUUID,
+		invoice.OwnerUUID,
+		invoice.PlanUUID,
+		invoice.Reason,
+		invoice.Cycle,
+		invoice.Coin,
+		invoice.Amount,
+		invoice.CreditApplied,
+		invoice.PeriodStart,
+		invoice.PeriodEnd,
+	)
+
+	created, err := helpers.ScanSubscriptionInvoice(row)
+	if err == nil {
+		return created, nil
+	}
+	if invoice.Reason != enums.InvoiceRenewal {
+		return nil, errors.New("could not create invoice")
+	}
+
+	query = `SELECT ` + subscriptionInvoiceColumns + `
+	FROM subscriptions_invoices
+	WHERE subscription_uuid = $1 AND period_start = $2 AND reason = $3`
+
+	row = r.db.QueryRowContext(ctx, query, invoice.SubscriptionUUID, invoice.PeriodStart, enums.InvoiceRenewal)
+	existing, findErr := helpers.ScanSubscriptionInvoice(row)
+	if findErr != nil {
+		return nil, errors.New("could not create invoice")
+	}
+
+	return existing, nil
+}
+
+func (r *SubscriptionRepository) FindInvoiceByUUID(invoiceUUID string) (*domain.SubscriptionInvoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + subscriptionInvoiceColumns + `
+	FROM subscriptions_invoices
+	WHERE uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, invoiceUUID)
+	return helpers.ScanSubscriptionInvoice(row)
+}
+
+var subscriptionInvoiceListSpec = helpers.ListSpec[*domain.SubscriptionInvoice]{
+	Query: `SELECT ` + subscriptionInvoiceColumns + `
+	FROM subscriptions_invoices`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.SubscriptionInvoice) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.SubscriptionInvoice]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.SubscriptionInvoice) string { return helpers.TimeValue(v.CreatedAt) }},
+		"amount":     {Column: "amount", Kind: helpers.IntColumn, Value: func(v *domain.SubscriptionInvoice) string { return strconv.Itoa(v.Amount) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"status": {Column: "status", Kind: helpers.TextColumn},
+		"reason": {Column: "reason", Kind: helpers.TextColumn},
+		"coin":   {Column: "coin", Kind: helpers.TextColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanSubscriptionInvoices,
+}
+
+func (r *SubscriptionRepository) GetInvoicesByOwner(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.SubscriptionInvoice], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, subscriptionInvoiceListSpec, query, []string{"owner_uuid = $1"}, ownerUUID)
+}
+
+// GetDueInvoices finds failed renewals whose next retry is due by now.
+func (r *SubscriptionRepository) GetDueInvoices(now time.Time) ([]*domain.SubscriptionInvoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + subscriptionInvoiceColumns + `
+	FROM subscriptions_invoices
+	WHERE status = $1 AND next_attempt_at <= $2
+	ORDER BY next_attempt_at, uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, enums.InvoiceOpen, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanSubscriptionInvoices(rows)
+}
+
+// SettleInvoice marks an open invoice paid and saves what paying it did to
+// the subscription. Paying lifts the suspension of the owner's websites.
+// It fails with domain.ErrInvoiceNotOpen when the invoice was settled
+// meanwhile.
+func (r *SubscriptionRepository) SettleInvoice(invoice *domain.SubscriptionInvoice, subscription *domain.Subscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE subscriptions_invoices
+	SET status = $2, attempts = $3, provider_reference = $4, failure_reason = NULL, next_attempt_at = NULL, paid_at = NOW(), updated_at = NOW()
+	WHERE uuid = $1 AND status = $5
+	RETURNING paid_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, invoice.UUID, enums.InvoicePaid, invoice.Attempts, invoice.ProviderReference, enums.InvoiceOpen).Scan(
+		&invoice.PaidAt,
+		&invoice.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrInvoiceNotOpen
+	}
+	if err != nil {
+		return err
+	}
+	invoice.Status = enums.InvoicePaid
+	invoice.NextAttemptAt = nil
+	invoice.FailureReason = ""
+
+	if err := updateSubscription(ctx, tx, subscription); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE websites SET suspended_at = NULL WHERE owner_uuid = $1 AND suspended_at IS NOT NULL`, subscription.OwnerUUID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FailInvoice records a failed attempt at an open invoice, which stays
+// open for a retry or is voided, and saves what the failure did to the
+// subscription.
+func (r *SubscriptionRepository) FailInvoice(invoice *domain.SubscriptionInvoice, subscription *domain.Subscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE subscriptions_invoices
+	SET status = $2, attempts = $3, next_attempt_at = $4, provider_reference = $5, failure_reason = $6, updated_at = NOW()
+	WHERE uuid = $1 AND status = $7
+	RETURNING updated_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		invoice.UUID,
+		invoice.Status,
+		invoice.Attempts,
+		invoice.NextAttemptAt,
+		invoice.ProviderReference,
+		invoice.FailureReason,
+		enums.InvoiceOpen,
+	).Scan(&invoice.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrInvoiceNotOpen
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := updateSubscription(ctx, tx, subscription); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// subscriptionExecutor is satisfied by both *sql.DB and *sql.Tx.
+type subscriptionExecutor interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func updateSubscription(ctx context.Context, db subscriptionExecutor, subscription *domain.Subscription) error {
+	query := `UPDATE subscriptions
+	SET plan_uuid = $2, status = $3, cycle = $4, payment_method = $5, current_period_start = $6, current_period_end = $7, cancel_at_period_end = $8, past_due_since = $9, credit = $10, updated_at = NOW()
+	WHERE uuid = $1
+	RETURNING updated_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		subscription.UUID,
+		subscription.PlanUUID,
+		subscription.Status,
+		subscription.Cycle,
+		subscription.PaymentMethod,
+		subscription.CurrentPeriodStart,
+		subscription.CurrentPeriodEnd,
+		subscription.CancelAtPeriodEnd,
+		subscription.PastDueSince,
+		subscription.Credit,
+	).Scan(&subscription.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("subscription not found")
+	}
+
+	return err
+}
+
+func voidOpenInvoices(ctx context.Context, db subscriptionExecutor, subscriptionUUID uuid.UUID) error {
+	_, err := db.ExecContext(
+		ctx,
+		`UPDATE subscriptions_invoices SET status = $2, next_attempt_at = NULL, updated_at = NOW() WHERE subscription_uuid = $1 AND status = $3`,
+		subscriptionUUID,
+		enums.InvoiceVoid,
+		enums.InvoiceOpen,
+	)
+	return err
+}
+
+// lockQuota serializes creates counted against the owner's plan until tx
+// ends. Without it two concurrent creates could both count the same usage
+// and both fit under the limit.
+func lockQuota(ctx context.Context, tx *sql.Tx, ownerUUID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, ownerUUID.String())
+	return err
+}
+
+// checkQuota fails with domain.ErrQuotaExceeded when usageQuery already
+// counts as much as the quota allows. Call it after lockQuota, so the count
+// sees every create committed before the lock was granted.
+func checkQuota(ctx context.Context, tx *sql.Tx, quota domain.Quota, usageQuery string) error {
+	var used int
+	if err := tx.QueryRowContext(ctx, usageQuery, quota.OwnerUUID).Scan(&used); err != nil {
+		return err
+	}
+
+	if !quota.Allows(used) {
+		return domain.ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+func claimQuota(ctx context.Context, tx *sql.Tx, quota domain.Quota, usageQuery string) error {
+	if quota.Limit == 0 {
+		return nil
+	}
+
+	if err := lockQuota(ctx, tx, quota.OwnerUUID); err != nil {
+		return err
+	}
+
+	return checkQuota(ctx, tx, quota, usageQuery)
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// openTestDB connects to the database in DATABASE_URL, skipping the test
+// when it is unset.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	if !strings.Contains(dsn, "sslmode") {
+		if strings.Contains(dsn, "?") {
+			dsn += "&sslmode=disable"
+		} else {
+			dsn += "?sslmode=disable"
+		}
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// TestClaimQuotaParallel races creates against one owner's quota: however
+// many run at once, exactly the limit may commit.
+func TestClaimQuotaParallel(t *testing.T) {
+	db := openTestDB(t)
+
+	table := "quota_race_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := db.Exec(`CREATE TABLE ` + table + ` (owner_uuid UUID NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Exec(`DROP TABLE IF EXISTS ` + table) })
+
+	usageQuery := `SELECT COUNT(*) FROM ` + table + ` WHERE owner_uuid = $1`
+
+	tests := []struct {
+		name    string
+		limit   int
+		creates int
+		want    int
+	}{
+		{name: "limit one", limit: 1, creates: 10, want: 1},
+		{name: "limit three", limit: 3, creates: 20, want: 3},
+		{name: "under the limit", limit: 10, creates: 5, want: 5},
+		{name: "unlimited", limit: 0, creates: 8, want: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota := domain.Quota{OwnerUUID: uuid.New(), Limit: tt.limit}
+
+			var wg sync.WaitGroup
+			results := make(chan error, tt.creates)
+			start := make(chan struct{})
+			for range tt.creates {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					results <- createWithQuota(db, quota, table, usageQuery)
+				}()
+			}
+			close(start)
+			wg.Wait()
+			close(results)
+
+			created, exceeded := 0, 0
+			for err := range results {
+				switch {
+				case err == nil:
+					created++
+				case errors.Is(err, domain.ErrQuotaExceeded):
+					exceeded++
+				default:
+					t.Fatalf("create: %v", err)
+				}
+			}
+
+			if created != tt.want || exceeded != tt.creates-tt.want {
+				t.Fatalf("created %d, exceeded %d; want %d and %d", created, exceeded, tt.want, tt.creates-tt.want)
+			}
+
+			var stored int
+			if err := db.QueryRow(usageQuery, quota.OwnerUUID).Scan(&stored); err != nil {
+				t.Fatal(err)
+			}
+			if stored != tt.want {
+				t.Fatalf("stored %d rows, want %d", stored, tt.want)
+			}
+		})
+	}
+}
+
+// createWithQuota inserts one row the way the repositories create a
+// resource counted against a plan.
+func createWithQuota(db *sql.DB, quota domain.Quota, table string, usageQuery string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := claimQuota(ctx, tx, quota, usageQuery); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (owner_uuid) VALUES ($1)`, table), quota.OwnerUUID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.TermsAcceptedContract = (*TermsAcceptedRepository)(nil)
@@ -80,37 +81,35 @@ func (r *TermsAcceptedRepository) FindTermsAcceptedByOwner(ownerUUID string, web
 	return helpers.ScanTermsAccepted(row)
 }
 
-func (r *TermsAcceptedRepository) GetTermsAcceptedFromWebsite(websiteUUID string) ([]*domain.TermsAcceptedBy, error) {
+var termsAcceptedListSpec = helpers.ListSpec[*domain.TermsAcceptedBy]{
+	Query: `SELECT uuid, website_uuid, owner_uuid, owner_type, accepted_when
+	FROM terms_accepted`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.TermsAcceptedBy) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.TermsAcceptedBy]{
+		"accepted_when": {Column: "accepted_when", Kind: helpers.TimeColumn, Value: func(v *domain.TermsAcceptedBy) string { return helpers.TimeValue(v.AcceptedWhen) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"owner_uuid":      {Column: "owner_uuid", Kind: helpers.UUIDColumn},
+		"owner_type":      {Column: "owner_type", Kind: helpers.TextColumn},
+		"accepted_after":  {Column: "accepted_when", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"accepted_before": {Column: "accepted_when", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanTermsAcceptedSlice,
+}
+
+func (r *TermsAcceptedRepository) GetTermsAcceptedFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.TermsAcceptedBy], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, accepted_when
-	FROM terms_accepted
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanTermsAcceptedSlice(rows)
+	return helpers.QueryPage(ctx, r.db, termsAcceptedListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *TermsAcceptedRepository) GetTermsAccepted() ([]*domain.TermsAcceptedBy, error) {
+func (r *TermsAcceptedRepository) GetTermsAccepted(query *domain.ListQuery) (*domain.Page[*domain.TermsAcceptedBy], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, owner_uuid, owner_type, accepted_when
-	FROM terms_accepted`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanTermsAcceptedSlice(rows)
+	return helpers.QueryPage(ctx, r.db, termsAcceptedListSpec, query, nil)
 }
 
 func (r *TermsAcceptedRepository) UpdateTermsAcceptedByUUID(uuid string) error {
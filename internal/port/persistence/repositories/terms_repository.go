@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.TermsContract = (*TermsRepository)(nil)
@@ -79,20 +80,28 @@ func (r *TermsRepository) FindTermsByName(name string) (*domain.Terms, error) {
 	return helpers.ScanTerms(row)
 }
 
-func (r *TermsRepository) GetTerms() ([]*domain.Terms, error) {
+var termsListSpec = helpers.ListSpec[*domain.Terms]{
+	Query: `SELECT uuid, name, description, created_at, updated_at
+	FROM terms`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.Terms) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Terms]{
+		"name":       {Column: "name", Kind: helpers.TextColumn, Value: func(v *domain.Terms) string { return v.Name }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.Terms) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":           {Column: "name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanTermsSlice,
+}
+
+func (r *TermsRepository) GetTerms(query *domain.ListQuery) (*domain.Page[*domain.Terms], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, name, description, created_at, updated_at
-	FROM terms`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanTermsSlice(rows)
+	return helpers.QueryPage(ctx, r.db, termsListSpec, query, nil)
 }
 
 func (r *TermsRepository) UpdateTermsByUUID(uuid string) error {
@@ -0,0 +1,333 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.TwoFactorContract = (*TwoFactorRepository)(nil)
+
+const (
+	twoFactorColumns     = `user_uuid, secret, enabled_at, last_step, updated_at, created_at`
+	trustedDeviceColumns = `uuid, user_uuid, name, token_hash, expires_at, last_used_at, created_at`
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: db,
+	}
+}
+
+// FindTwoFactor fails with domain.ErrTwoFactorNotEnabled when the user
+// never started an enrollment.
+func (r *TwoFactorRepository) FindTwoFactor(userUUID string) (*domain.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + twoFactorColumns + ` FROM users_two_factor WHERE user_uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, userUUID)
+	return helpers.ScanTwoFactor(row)
+}
+
+// SaveTwoFactorSecret starts an enrollment, or restarts one not confirmed
+// yet with a new secret. It fails with domain.ErrTwoFactorEnabled once the
+// enrollment is confirmed.
+func (r *TwoFactorRepository) SaveTwoFactorSecret(userUUID string, secret string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO users_two_factor (user_uuid, secret)
+	VALUES ($1, $2)
+	ON CONFLICT (user_uuid) DO UPDATE SET secret = EXCLUDED.secret, last_step = 0, updated_at = NOW()
+	WHERE users_two_factor.enabled_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userUUID, secret)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+// EnableTwoFactor confirms an enrollment with the step of its first code
+// and stores the recovery codes handed out with it.
+func (r *TwoFactorRepository) EnableTwoFactor(userUUID string, step int64, recoveryHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE users_two_factor SET enabled_at = NOW(), last_step = $2, updated_at = NOW()
+		WHERE user_uuid = $1 AND enabled_at IS NULL AND last_step < $2`,
+		userUUID,
+		step,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTwoFactorCode
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userUUID, recoveryHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DisableTwoFactor forgets the enrollment, recovery codes and remembered
+// devices of the user.
+func (r *TwoFactorRepository) DisableTwoFactor(userUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`DELETE FROM users_two_factor WHERE user_uuid = $1`,
+		`DELETE FROM users_recovery_codes WHERE user_uuid = $1`,
+		`DELETE FROM users_trusted_devices WHERE user_uuid = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userUUID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseTwoFactorStep records the step of an accepted code, failing with
+// domain.ErrTwoFactorCode when a code of that step or a later one was
+// already used.
+func (r *TwoFactorRepository) UseTwoFactorStep(userUUID string, step int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE users_two_factor SET last_step = $2, updated_at = NOW()
+	WHERE user_uuid = $1 AND enabled_at IS NOT NULL AND last_step < $2`
+
+	result, err := r.db.ExecContext(ctx, query, userUUID, step)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTwoFactorCode
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userUUID string, recoveryHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userUUID, recoveryHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode spends a recovery code, failing with
+// domain.ErrTwoFactorCode when the user has no such unused code.
+func (r *TwoFactorRepository) UseRecoveryCode(userUUID string, codeHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE users_recovery_codes SET used_at = NOW()
+	WHERE user_uuid = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userUUID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTwoFactorCode
+	}
+
+	return nil
+}
+
+// CountRecoveryCodes counts the recovery codes the user has left.
+func (r *TwoFactorRepository) CountRecoveryCodes(userUUID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM users_recovery_codes WHERE user_uuid = $1 AND used_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userUUID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *TwoFactorRepository) CreateTrustedDevice(device *domain.TrustedDevice) (*domain.TrustedDevice, error) {
+	if device == nil {
+		return nil, errors.New("invalid trusted device")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO users_trusted_devices (user_uuid, name, token_hash, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + trustedDeviceColumns
+
+	created, err := helpers.ScanTrustedDevice(r.db.QueryRowContext(ctx, query, device.UserUUID, device.Name, device.TokenHash, device.ExpiresAt))
+	if err != nil {
+		return nil, errors.New("could not create trusted device")
+	}
+
+	return created, nil
+}
+
+// UseTrustedDevice tells whether the token belongs to an unexpired device
+// of the user, and notes that it was used.
+func (r *TwoFactorRepository) UseTrustedDevice(userUUID string, tokenHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE users_trusted_devices SET last_used_at = NOW()
+	WHERE user_uuid = $1 AND token_hash = $2 AND expires_at > NOW()`
+
+	result, err := r.db.ExecContext(ctx, query, userUUID, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *TwoFactorRepository) FindTrustedDevices(userUUID string) ([]*domain.TrustedDevice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + trustedDeviceColumns + `
+	FROM users_trusted_devices
+	WHERE user_uuid = $1 AND expires_at > NOW()
+	ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanTrustedDevices(rows)
+}
+
+func (r *TwoFactorRepository) DeleteTrustedDevice(userUUID string, deviceUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users_trusted_devices WHERE uuid = $1 AND user_uuid = $2`, deviceUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("trusted device not found")
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) PruneTrustedDevices(before time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users_trusted_devices WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userUUID string, recoveryHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users_recovery_codes WHERE user_uuid = $1`, userUUID); err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryHashes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO users_recovery_codes (user_uuid, code_hash) VALUES ($1, $2)`, userUUID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
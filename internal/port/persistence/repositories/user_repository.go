@@ -10,6 +10,7 @@ import (
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 type UserRepository struct {
@@ -61,7 +62,7 @@ func (r *UserRepository) FindUserByUUID(uuid string) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, created_at, updated_at
+	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, email_verified_at, sessions_revoked_at, failed_logins, last_failed_login_at, locked_until, created_at, updated_at
 	FROM users
 	WHERE uuid = $1`
 
@@ -73,7 +74,7 @@ func (r *UserRepository) FindUserByEmailAndWebsite(email string, websiteUUID str
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, created_at, updated_at
+	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, email_verified_at, sessions_revoked_at, failed_logins, last_failed_login_at, locked_until, created_at, updated_at
 	FROM users
 	WHERE email = $1 AND website_uuid = $2`
 
@@ -96,37 +97,38 @@ func (r *UserRepository) UserExists(email string, websiteUUID string) (bool, err
 	return exists, nil
 }
 
-func (r *UserRepository) GetUsersFromWebsite(websiteUUID string) ([]*domain.User, error) {
+var userListSpec = helpers.ListSpec[*domain.User]{
+	Query: `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, email_verified_at, sessions_revoked_at, failed_logins, last_failed_login_at, locked_until, created_at, updated_at
+	FROM users`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.User) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.User]{
+		"email":      {Column: "email", Kind: helpers.TextColumn, Value: func(v *domain.User) string { return v.Email }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.User) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"name":           {Column: "name", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"email":          {Column: "email", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"role":           {Column: "role", Kind: helpers.UUIDColumn},
+		"website_uuid":   {Column: "website_uuid", Kind: helpers.UUIDColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanUsers,
+}
+
+func (r *UserRepository) GetUsersFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.User], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, created_at, updated_at
-	FROM users
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanUsers(rows)
+	return helpers.QueryPage(ctx, r.db, userListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *UserRepository) GetUsers() ([]*domain.User, error) {
+func (r *UserRepository) GetUsers(query *domain.ListQuery) (*domain.Page[*domain.User], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, image_url, name, email, role, password, cpf, created_at, updated_at
-	FROM users`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanUsers(rows)
+	return helpers.QueryPage(ctx, r.db, userListSpec, query, nil)
 }
 
 func (r *UserRepository) UpdateUserByUUID(uuid string) error {
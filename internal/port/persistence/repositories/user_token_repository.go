@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities/enums"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+)
+
+var _ contracts.UserTokenContract = (*UserTokenRepository)(nil)
+
+const userTokenColumns = `uuid, user_uuid, purpose, token_hash, expires_at, used_at, created_at`
+
+type UserTokenRepository struct {
+	db *sql.DB
+}
+
+func NewUserTokenRepository(db *sql.DB) *UserTokenRepository {
+	return &UserTokenRepository{
+		db: db,
+	}
+}
+
+func (r *UserTokenRepository) CreateUserToken(token *domain.UserToken) (*domain.UserToken, error) {
+	if token == nil {
+		return nil, errors.New("invalid user token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO users_tokens (user_uuid, purpose, token_hash, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + userTokenColumns
+
+	created, err := helpers.ScanUserToken(r.db.QueryRowContext(ctx, query, token.UserUUID, token.Purpose, token.TokenHash, token.ExpiresAt))
+	if err != nil {
+		return nil, errors.New("could not create user token")
+	}
+
+	return created, nil
+}
+
+func (r *UserTokenRepository) FindUserTokenByHash(purpose enums.UserTokenPurposeType, tokenHash string) (*domain.UserToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + userTokenColumns + `
+	FROM users_tokens
+	WHERE token_hash = $1 AND purpose = $2`
+
+	row := r.db.QueryRowContext(ctx, query, tokenHash, purpose)
+	return helpers.ScanUserToken(row)
+}
+
+// CountUserTokensSince counts the tokens sent to a user since a moment,
+// to throttle how often they are mailed.
+func (r *UserTokenRepository) CountUserTokensSince(userUUID string, purpose enums.UserTokenPurposeType, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM users_tokens WHERE user_uuid = $1 AND purpose = $2 AND created_at > $3`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userUUID, purpose, since).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// VerifyUserEmail uses an email verification token and marks the email of
+// its user verified, in one transaction so a token only works once.
+func (r *UserTokenRepository) VerifyUserEmail(token *domain.UserToken) error {
+	if token == nil {
+		return errors.New("invalid user token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE users SET email_verified_at = NOW(), updated_at = NOW() WHERE uuid = $1 AND email_verified_at IS NULL`,
+		token.UserUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetUserPassword uses a password reset token and sets the new password
+// in one transaction. It also voids the user's other reset links, revokes
+// every session issued until now and every remembered device, lifts a
+// lockout and, as the link proved the user reads the email, marks it
+// verified.
+func (r *UserTokenRepository) ResetUserPassword(token *domain.UserToken, passwordHash string) error {
+	if token == nil {
+		return errors.New("invalid user token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE users_tokens SET used_at = NOW() WHERE user_uuid = $1 AND purpose = $2 AND used_at IS NULL`,
+		token.UserUUID,
+		token.Purpose,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM users_trusted_devices WHERE user_uuid = $1`, token.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE users
+		SET password = $2, sessions_revoked_at = NOW(), email_verified_at = COALESCE(email_verified_at, NOW()),
+			failed_logins = 0, last_failed_login_at = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE uuid = $1`,
+		token.UserUUID,
+		passwordHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return tx.Commit()
+}
+
+// UnlockUser uses an account unlock token and lifts the lockout of its
+// user, clearing the failed sign ins that caused it.
+func (r *UserTokenRepository) UnlockUser(token *domain.UserToken) error {
+	if token == nil {
+		return errors.New("invalid user token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE users SET failed_logins = 0, last_failed_login_at = NULL, locked_until = NULL WHERE uuid = $1`,
+		token.UserUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseUserToken marks a token used, failing with domain.ErrUserTokenInvalid
+// when it was used or expired meanwhile.
+func (r *UserTokenRepository) UseUserToken(token *domain.UserToken) error {
+	if token == nil {
+		return errors.New("invalid user token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func useUserToken(ctx context.Context, tx *sql.Tx, token *domain.UserToken) error {
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE users_tokens SET used_at = NOW() WHERE uuid = $1 AND used_at IS NULL AND expires_at > NOW()`,
+		token.UUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUserTokenInvalid
+	}
+
+	return nil
+}
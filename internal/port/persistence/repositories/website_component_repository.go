@@ -5,12 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.WebsiteComponentContract = (*WebsiteComponentRepository)(nil)
@@ -25,7 +27,11 @@ func NewWebsiteComponentRepository(db *sql.DB) *WebsiteComponentRepository {
 	}
 }
 
-func (r *WebsiteComponentRepository) CreateWebsiteComponent(component *domain.ComponentWebsites) (*domain.ComponentWebsites, error) {
+// CreateWebsiteComponent fails with domain.ErrQuotaExceeded when the
+// component adds a route and the website's owner already has as many as
+// quota allows. Another component on a path the website already serves
+// adds no route.
+func (r *WebsiteComponentRepository) CreateWebsiteComponent(component *domain.ComponentWebsites, quota domain.Quota) (*domain.ComponentWebsites, error) {
 	if component == nil {
 		return nil, errors.New("invalid website component")
 	}
@@ -33,11 +39,40 @@ func (r *WebsiteComponentRepository) CreateWebsiteComponent(component *domain.Co
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if quota.Limit > 0 {
+		if err := lockQuota(ctx, tx, quota.OwnerUUID); err != nil {
+			return nil, err
+		}
+
+		var known bool
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT EXISTS (SELECT 1 FROM websites_components WHERE website_uuid = $1 AND path IS NOT DISTINCT FROM $2)`,
+			component.WebsiteUUID,
+			component.Path,
+		).Scan(&known)
+		if err != nil {
+			return nil, err
+		}
+
+		if !known {
+			if err := checkQuota(ctx, tx, quota, routeUsageQuery); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	query := `INSERT INTO websites_components (website_uuid, logo_url, tittle, description, path, content, visits)
 	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING uuid, created_at, updated_at`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		component.WebsiteUUID,
@@ -57,6 +92,10 @@ func (r *WebsiteComponentRepository) CreateWebsiteComponent(component *domain.Co
 		return nil, errors.New("could not create website component")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return component, nil
 }
 
@@ -84,37 +123,36 @@ func (r *WebsiteComponentRepository) FindWebsiteComponentByPath(path string) (*d
 	return helpers.ScanWebsiteComponent(row)
 }
 
-func (r *WebsiteComponentRepository) GetWebsiteComponentsFromWebsite(websiteUUID string) ([]*domain.ComponentWebsites, error) {
+var websiteComponentListSpec = helpers.ListSpec[*domain.ComponentWebsites]{
+	Query: `SELECT uuid, website_uuid, logo_url, tittle, description, path, content, visits, updated_by, updated_at, created_at
+	FROM websites_components`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.ComponentWebsites) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.ComponentWebsites]{
+		"visits":     {Column: "visits", Kind: helpers.IntColumn, Value: func(v *domain.ComponentWebsites) string { return strconv.Itoa(v.Visists) }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.ComponentWebsites) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"path":           {Column: "path", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"tittle":         {Column: "tittle", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanWebsiteComponents,
+}
+
+func (r *WebsiteComponentRepository) GetWebsiteComponentsFromWebsite(websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.ComponentWebsites], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, logo_url, tittle, description, path, content, visits, updated_by, updated_at, created_at
-	FROM websites_components
-	WHERE website_uuid = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, websiteUUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanWebsiteComponents(rows)
+	return helpers.QueryPage(ctx, r.db, websiteComponentListSpec, query, []string{"website_uuid = $1"}, websiteUUID)
 }
 
-func (r *WebsiteComponentRepository) GetWebsiteComponents() ([]*domain.ComponentWebsites, error) {
+func (r *WebsiteComponentRepository) GetWebsiteComponents(query *domain.ListQuery) (*domain.Page[*domain.ComponentWebsites], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, website_uuid, logo_url, tittle, description, path, content, visits, updated_by, updated_at, created_at
-	FROM websites_components`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return helpers.ScanWebsiteComponents(rows)
+	return helpers.QueryPage(ctx, r.db, websiteComponentListSpec, query, nil)
 }
 
 func (r *WebsiteComponentRepository) UpdateWebsiteComponentByUUID(uuid string) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
 	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
 	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
 )
 
 var _ contracts.WebsiteContract = (*WebsiteRepository)(nil)
@@ -25,7 +26,9 @@ func NewWebsiteRepository(db *sql.DB) *WebsiteRepository {
 	}
 }
 
-func (r *WebsiteRepository) CreateWebsite(website *domain.Website) (*domain.Website, error) {
+// CreateWebsite fails with domain.ErrQuotaExceeded when the owner already
+// has as many websites as quota allows.
+func (r *WebsiteRepository) CreateWebsite(website *domain.Website, quota domain.Quota) (*domain.Website, error) {
 	if website == nil {
 		return nil, errors.New("invalid website")
 	}
@@ -33,11 +36,21 @@ func (r *WebsiteRepository) CreateWebsite(website *domain.Website) (*domain.Webs
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO websites (owner_uuid, owner_type, label, url, write_in, description)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := claimQuota(ctx, tx, quota, websiteUsageQuery); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO websites (owner_uuid, owner_type, label, url, write_in, description, base_coin)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING uuid, created_at, updated_at`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		website.OwnerUUID,
@@ -46,6 +59,7 @@ func (r *WebsiteRepository) CreateWebsite(website *domain.Website) (*domain.Webs
 		website.URL,
 		website.WriteIn,
 		website.Description,
+		website.BaseCoin,
 	).Scan(
 		&website.UUID,
 		&website.CreatedAt,
@@ -56,6 +70,10 @@ func (r *WebsiteRepository) CreateWebsite(website *domain.Website) (*domain.Webs
 		return nil, errors.New("could not create website")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return website, nil
 }
 
@@ -63,7 +81,7 @@ func (r *WebsiteRepository) FindWebsiteByUUID(uuid string) (*domain.Website, err
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, updated_at, created_at
+	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, base_coin, suspended_at, require_email_verification, require_two_factor, updated_at, created_at
 	FROM websites
 	WHERE uuid = $1`
 
@@ -75,7 +93,7 @@ func (r *WebsiteRepository) FindWebsiteByLabel(label string) (*domain.Website, e
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, updated_at, created_at
+	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, base_coin, suspended_at, require_email_verification, require_two_factor, updated_at, created_at
 	FROM websites
 	WHERE label = $1`
 
@@ -83,46 +101,102 @@ func (r *WebsiteRepository) FindWebsiteByLabel(label string) (*domain.Website, e
 	return helpers.ScanWebsite(row)
 }
 
-func (r *WebsiteRepository) FindWebsitesByOwner(ownerUUID string) ([]*domain.Website, error) {
+var websiteListSpec = helpers.ListSpec[*domain.Website]{
+	Query: `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, base_coin, suspended_at, require_email_verification, require_two_factor, updated_at, created_at
+	FROM websites`,
+	Key:   "uuid",
+	KeyOf: func(w *domain.Website) uuid.UUID { return w.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.Website]{
+		"label":      {Column: "label", Kind: helpers.TextColumn, Value: func(w *domain.Website) string { return w.Label }},
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(w *domain.Website) string { return helpers.TimeValue(w.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"label":          {Column: "label", Kind: helpers.TextColumn, Match: helpers.MatchContains},
+		"owner_type":     {Column: "owner_type", Kind: helpers.TextColumn},
+		"write_in":       {Column: "write_in", Kind: helpers.TextColumn},
+		"base_coin":      {Column: "base_coin", Kind: helpers.TextColumn},
+		"created_after":  {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMin},
+		"created_before": {Column: "created_at", Kind: helpers.TimeColumn, Match: helpers.MatchMax},
+	},
+	Scan: helpers.ScanWebsites,
+}
+
+func (r *WebsiteRepository) FindWebsitesByOwner(ownerUUID string, query *domain.ListQuery) (*domain.Page[*domain.Website], error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, updated_at, created_at
-	FROM websites
-	WHERE owner_uuid = $1`
+	return helpers.QueryPage(ctx, r.db, websiteListSpec, query, []string{"owner_uuid = $1"}, ownerUUID)
+}
+
+// FindWebsitesByUser lists the websites of userUUID and of the
+// organizations they are a member of.
+func (r *WebsiteRepository) FindWebsitesByUser(userUUID string, query *domain.ListQuery) (*domain.Page[*domain.Website], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, websiteListSpec, query, []string{"(owner_uuid = $1 OR owner_uuid IN (SELECT organization_uuid FROM organizations_members WHERE user_uuid = $1))"}, userUUID)
+}
+
+func (r *WebsiteRepository) GetWebsites(query *domain.ListQuery) (*domain.Page[*domain.Website], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, websiteListSpec, query, nil)
+}
+
+func (r *WebsiteRepository) UpdateWebsiteByUUID(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE websites SET updated_at = NOW() WHERE uuid = $1`
 
-	rows, err := r.db.QueryContext(ctx, query, ownerUUID)
+	result, err := r.db.ExecContext(ctx, query, uuid)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	return helpers.ScanWebsites(rows)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("website not found")
+	}
+
+	return nil
 }
 
-func (r *WebsiteRepository) GetWebsites() ([]*domain.Website, error) {
+func (r *WebsiteRepository) SetWebsiteEmailVerification(uuid string, required bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `SELECT uuid, owner_uuid, owner_type, label, url, write_in, description, updated_at, created_at
-	FROM websites`
+	query := `UPDATE websites SET require_email_verification = $2, updated_at = NOW() WHERE uuid = $1`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	result, err := r.db.ExecContext(ctx, query, uuid, required)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("website not found")
 	}
-	defer rows.Close()
 
-	return helpers.ScanWebsites(rows)
+	return nil
 }
 
-func (r *WebsiteRepository) UpdateWebsiteByUUID(uuid string) error {
+func (r *WebsiteRepository) SetWebsiteTwoFactor(uuid string, required bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	query := `UPDATE websites SET updated_at = NOW() WHERE uuid = $1`
+	query := `UPDATE websites SET require_two_factor = $2, updated_at = NOW() WHERE uuid = $1`
 
-	result, err := r.db.ExecContext(ctx, query, uuid)
+	result, err := r.db.ExecContext(ctx, query, uuid, required)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/entities"
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+	"github.com/ViitoJooj/verkoupe/internal/port/persistence/helpers"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var _ contracts.WishlistContract = (*WishlistRepository)(nil)
+
+const wishlistColumns = `w.uuid, w.user_uuid, w.website_uuid, w.name, w.share_token,
+	(SELECT COUNT(*) FROM wishlists_items i WHERE i.wishlist_uuid = w.uuid),
+	w.updated_at, w.created_at`
+
+// wishlistItemInStock and wishlistItemOnSale read the live state of an item
+// aliased i on a wishlist aliased w. Only base prices and the website's
+// price lists open to every shopper count as a sale.
+const (
+	wishlistItemInStock = `EXISTS (
+		SELECT 1 FROM products p
+		WHERE p.uuid = i.product_uuid AND p.active
+		AND (p.type <> 'physical' OR EXISTS (SELECT 1 FROM storage_products s WHERE s.product_uuid = p.uuid))
+	)`
+	wishlistItemOnSale = `EXISTS (
+		SELECT 1 FROM products_prices pp
+		LEFT JOIN prices_lists pl ON pl.uuid = pp.price_list_uuid
+		WHERE pp.product_uuid = i.product_uuid
+		AND (pp.variant_uuid IS NULL OR pp.variant_uuid = i.variant_uuid)
+		AND (pp.price_list_uuid IS NULL OR (pl.website_uuid = w.website_uuid AND pl.active AND pl.customer_group IS NULL))
+		AND pp.sale_amount IS NOT NULL
+		AND pp.sale_starts_at <= NOW()
+		AND (pp.sale_ends_at IS NULL OR pp.sale_ends_at > NOW())
+	)`
+)
+
+type WishlistRepository struct {
+	db *sql.DB
+}
+
+func NewWishlistRepository(db *sql.DB) *WishlistRepository {
+	return &WishlistRepository{
+		db: db,
+	}
+}
+
+func (r *WishlistRepository) CreateWishlist(wishlist *domain.Wishlist) (*domain.Wishlist, error) {
+	if wishlist == nil {
+		return nil, errors.New("invalid wishlist")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO wishlists (user_uuid, website_uuid, name)
+	VALUES ($1, $2, $3)
+	RETURNING uuid, created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		wishlist.UserUUID,
+		wishlist.WebsiteUUID,
+		wishlist.Name,
+	).Scan(
+		&wishlist.UUID,
+		&wishlist.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrWishlistExists
+		}
+		return nil, errors.New("could not create wishlist")
+	}
+
+	return wishlist, nil
+}
+
+func (r *WishlistRepository) FindWishlistByUUID(uuid string) (*domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + wishlistColumns + `
+	FROM wishlists w
+	WHERE w.uuid = $1`
+
+	row := r.db.QueryRowContext(ctx, query, uuid)
+	return helpers.ScanWishlist(row)
+}
+
+func (r *WishlistRepository) FindWishlistByShareToken(token string) (*domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + wishlistColumns + `
+	FROM wishlists w
+	WHERE w.share_token = $1`
+
+	row := r.db.QueryRowContext(ctx, query, token)
+	return helpers.ScanWishlist(row)
+}
+
+func (r *WishlistRepository) GetWishlistsByUser(userUUID string, websiteUUID string) ([]*domain.Wishlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + wishlistColumns + `
+	FROM wishlists w
+	WHERE w.user_uuid = $1 AND w.website_uuid = $2
+	ORDER BY w.created_at, w.uuid`
+
+	rows, err := r.db.QueryContext(ctx, query, userUUID, websiteUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanWishlists(rows)
+}
+
+func (r *WishlistRepository) CountWishlistsByUser(userUUID string, websiteUUID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM wishlists WHERE user_uuid = $1 AND website_uuid = $2`, userUUID, websiteUUID).Scan(&count)
+	return count, err
+}
+
+func (r *WishlistRepository) UpdateWishlist(wishlist *domain.Wishlist) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE wishlists
+	SET name = $2, share_token = NULLIF($3, ''), updated_at = NOW()
+	WHERE uuid = $1
+	RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, wishlist.UUID, wishlist.Name, wishlist.ShareToken).Scan(&wishlist.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("wishlist not found")
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_wishlists_user_name" {
+			return domain.ErrWishlistExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *WishlistRepository) DeleteWishlist(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM wishlists_items WHERE wishlist_uuid = $1`, uuid); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM wishlists WHERE uuid = $1`, uuid)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("wishlist not found")
+	}
+
+	return tx.Commit()
+}
+
+// AddWishlistItem stores the item with its current stock and sale state so
+// the alert job only reports what changes after it was wished for.
+func (r *WishlistRepository) AddWishlistItem(item *domain.WishlistItem) (*domain.WishlistItem, error) {
+	if item == nil {
+		return nil, errors.New("invalid wishlist item")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO wishlists_items (wishlist_uuid, product_uuid, variant_uuid, in_stock, on_sale)
+	SELECT i.wishlist_uuid, i.product_uuid, i.variant_uuid, ` + wishlistItemInStock + `, ` + wishlistItemOnSale + `
+	FROM (SELECT $1::UUID AS wishlist_uuid, $2::UUID AS product_uuid, $3::UUID AS variant_uuid) i
+	JOIN wishlists w ON w.uuid = i.wishlist_uuid
+	RETURNING uuid, in_stock, on_sale, created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		item.WishlistUUID,
+		item.ProductUUID,
+		item.VariantUUID,
+	).Scan(
+		&item.UUID,
+		&item.InStock,
+		&item.OnSale,
+		&item.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("wishlist not found")
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrWishlistItemExists
+		}
+		return nil, errors.New("could not add wishlist item")
+	}
+
+	return item, nil
+}
+
+var wishlistItemListSpec = helpers.ListSpec[*domain.WishlistItem]{
+	Query: `SELECT uuid, wishlist_uuid, product_uuid, variant_uuid, in_stock, on_sale, created_at
+	FROM wishlists_items`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.WishlistItem) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.WishlistItem]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.WishlistItem) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"product_uuid": {Column: "product_uuid", Kind: helpers.UUIDColumn},
+		"in_stock":     {Column: "in_stock", Kind: helpers.BoolColumn},
+		"on_sale":      {Column: "on_sale", Kind: helpers.BoolColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanWishlistItems,
+}
+
+func (r *WishlistRepository) GetWishlistItems(wishlistUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistItem], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, wishlistItemListSpec, query, []string{"wishlist_uuid = $1"}, wishlistUUID)
+}
+
+func (r *WishlistRepository) RemoveWishlistItem(wishlistUUID string, itemUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM wishlists_items WHERE uuid = $1 AND wishlist_uuid = $2`, itemUUID, wishlistUUID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("wishlist item not found")
+	}
+
+	return nil
+}
+
+// DetectWishlistAlerts compares every item with its live state, stores the
+// new state and records an alert for each item that came back in stock or
+// went on sale, all in one statement. Rows updated by a concurrent run no
+// longer differ once their lock is released, so each change is reported
+// once even with several instances running the job.
+func (r *WishlistRepository) DetectWishlistAlerts(ctx context.Context) ([]*domain.WishlistAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	query := `WITH live AS (
+		SELECT i.uuid, i.in_stock AS was_in_stock, i.on_sale AS was_on_sale,
+			` + wishlistItemInStock + ` AS in_stock,
+			` + wishlistItemOnSale + ` AS on_sale
+		FROM wishlists_items i
+		JOIN wishlists w ON w.uuid = i.wishlist_uuid
+	),
+	changed AS (
+		UPDATE wishlists_items i
+		SET in_stock = c.in_stock, on_sale = c.on_sale
+		FROM live c
+		WHERE i.uuid = c.uuid AND (i.in_stock <> c.in_stock OR i.on_sale <> c.on_sale)
+		RETURNING i.uuid, i.wishlist_uuid, i.product_uuid, i.variant_uuid, c.was_in_stock, c.was_on_sale, i.in_stock, i.on_sale
+	)
+	INSERT INTO wishlists_alerts (type, wishlist_uuid, item_uuid, user_uuid, website_uuid, product_uuid, variant_uuid)
+	SELECT a.type, c.wishlist_uuid, c.uuid, w.user_uuid, w.website_uuid, c.product_uuid, c.variant_uuid
+	FROM changed c
+	JOIN wishlists w ON w.uuid = c.wishlist_uuid
+	CROSS JOIN LATERAL (VALUES
+		(CASE WHEN c.in_stock AND NOT c.was_in_stock THEN 'back_in_stock' END),
+		(CASE WHEN c.on_sale AND NOT c.was_on_sale THEN 'on_sale' END)
+	) a(type)
+	WHERE a.type IS NOT NULL
+	RETURNING uuid, type, wishlist_uuid, item_uuid, user_uuid, website_uuid, product_uuid, variant_uuid, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return helpers.ScanWishlistAlerts(rows)
+}
+
+var wishlistAlertListSpec = helpers.ListSpec[*domain.WishlistAlert]{
+	Query: `SELECT uuid, type, wishlist_uuid, item_uuid, user_uuid, website_uuid, product_uuid, variant_uuid, created_at
+	FROM wishlists_alerts`,
+	Key:   "uuid",
+	KeyOf: func(v *domain.WishlistAlert) uuid.UUID { return v.UUID },
+	Sorts: map[string]helpers.SortColumn[*domain.WishlistAlert]{
+		"created_at": {Column: "created_at", Kind: helpers.TimeColumn, Value: func(v *domain.WishlistAlert) string { return helpers.TimeValue(v.CreatedAt) }},
+	},
+	Filters: map[string]helpers.FilterColumn{
+		"type":          {Column: "type", Kind: helpers.TextColumn},
+		"wishlist_uuid": {Column: "wishlist_uuid", Kind: helpers.UUIDColumn},
+		"product_uuid":  {Column: "product_uuid", Kind: helpers.UUIDColumn},
+	},
+	DefaultSort: "-created_at",
+	Scan:        helpers.ScanWishlistAlerts,
+}
+
+func (r *WishlistRepository) GetWishlistAlertsByUser(userUUID string, websiteUUID string, query *domain.ListQuery) (*domain.Page[*domain.WishlistAlert], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return helpers.QueryPage(ctx, r.db, wishlistAlertListSpec, query, []string{"user_uuid = $1 AND website_uuid = $2"}, userUUID, websiteUUID)
+}
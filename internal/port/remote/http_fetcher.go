@@ -0,0 +1,121 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/internal/domain/repositories/contracts"
+)
+
+var _ contracts.MediaFetcherContract = (*HTTPFetcher)(nil)
+
+var (
+	ErrUnsupportedURL   = errors.New("only http and https URLs can be fetched")
+	ErrPrivateAddress   = errors.New("URL points to a private address")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// carrierNAT is the shared address space (RFC 6598), which net.IP does not
+// count as private.
+var carrierNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// HTTPFetcher downloads files on behalf of imports. The URLs come from
+// uploaded spreadsheets, so the dialer refuses loopback, private and
+// link-local addresses after DNS resolution, redirects included.
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+func NewHTTPFetcher(timeout time.Duration) *HTTPFetcher {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &HTTPFetcher{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return ErrTooManyRedirects
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return ErrUnsupportedURL
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string, maxBytes int64) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxBytes)
+	}
+
+	return data, nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!carrierNAT.Contains(ip)
+}
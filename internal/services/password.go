@@ -1,6 +1,8 @@
 package services
 
 import (
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -18,3 +20,16 @@ func CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("verkoupe-dummy-password"), bcryptCost)
+	return hash
+})
+
+// CheckNoPassword takes as long as CheckPassword against a real hash and
+// always fails, so signing in with an unknown email is as slow as with a
+// wrong password.
+func CheckNoPassword(password string) bool {
+	bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+	return false
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS products_prices;
+DROP TABLE IF EXISTS prices_lists;
+DROP TABLE IF EXISTS products_variants;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS products_variants (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    product_uuid UUID NOT NULL,
+    sku VARCHAR(100),
+    label VARCHAR(250) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_variants_product ON products_variants (product_uuid);
+CREATE INDEX IF NOT EXISTS idx_products_variants_sku ON products_variants (sku);
+
+CREATE TABLE IF NOT EXISTS prices_lists (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    label VARCHAR(250) NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    customer_group VARCHAR(100),
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_prices_lists_website ON prices_lists (website_uuid);
+CREATE INDEX IF NOT EXISTS idx_prices_lists_coin ON prices_lists (coin);
+
+CREATE TABLE IF NOT EXISTS products_prices (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    product_uuid UUID NOT NULL,
+    variant_uuid UUID,
+    price_list_uuid UUID,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    amount INT NOT NULL CHECK (amount >= 0),
+    compare_at_amount INT CHECK (compare_at_amount >= 0),
+    sale_amount INT CHECK (sale_amount >= 0),
+    sale_starts_at TIMESTAMPTZ,
+    sale_ends_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_prices_product ON products_prices (product_uuid);
+CREATE INDEX IF NOT EXISTS idx_products_prices_variant ON products_prices (variant_uuid);
+CREATE INDEX IF NOT EXISTS idx_products_prices_list ON products_prices (price_list_uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_prices_unique ON products_prices (
+    product_uuid,
+    COALESCE(variant_uuid, '00000000-0000-0000-0000-000000000000'),
+    COALESCE(price_list_uuid, '00000000-0000-0000-0000-000000000000'),
+    coin
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS currencies_roundings;
+DROP TABLE IF EXISTS exchanges_rates;
+ALTER TABLE websites DROP COLUMN IF EXISTS base_coin;
@@ -0,0 +1,27 @@
+ALTER TABLE websites ADD COLUMN IF NOT EXISTS base_coin VARCHAR(3) NOT NULL DEFAULT 'BRL' CHECK (base_coin IN ('BRL', 'USD', 'EUR'));
+
+CREATE TABLE IF NOT EXISTS exchanges_rates (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    base_coin VARCHAR(3) NOT NULL CHECK (base_coin IN ('BRL', 'USD', 'EUR')),
+    quote_coin VARCHAR(3) NOT NULL CHECK (quote_coin IN ('BRL', 'USD', 'EUR')),
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    rate_date DATE NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchanges_rates_pair_date ON exchanges_rates (base_coin, quote_coin, rate_date);
+
+CREATE TABLE IF NOT EXISTS currencies_roundings (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    mode VARCHAR(10) NOT NULL CHECK (mode IN ('none', 'up', 'down', 'nearest')),
+    increment INT NOT NULL DEFAULT 1 CHECK (increment > 0),
+    ending INT NOT NULL DEFAULT 0 CHECK (ending >= 0),
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_currencies_roundings_website_coin ON currencies_roundings (website_uuid, coin);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS products_categories;
+DROP TABLE IF EXISTS categories;
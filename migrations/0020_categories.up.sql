@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS categories (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    parent_uuid UUID,
+    path VARCHAR(2000) NOT NULL DEFAULT '',
+    depth INT NOT NULL DEFAULT 0,
+    name VARCHAR(250) NOT NULL,
+    slug VARCHAR(250) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    seo_title VARCHAR(250),
+    seo_description VARCHAR(500),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_website ON categories (website_uuid);
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories (parent_uuid);
+CREATE INDEX IF NOT EXISTS idx_categories_path ON categories (path varchar_pattern_ops);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_website_slug ON categories (website_uuid, slug);
+
+CREATE TABLE IF NOT EXISTS products_categories (
+    product_uuid UUID NOT NULL,
+    category_uuid UUID NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_uuid, category_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_categories_category ON products_categories (category_uuid);
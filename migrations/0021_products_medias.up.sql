@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS products_medias (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    product_uuid UUID NOT NULL,
+    blob_key VARCHAR(500) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    alt VARCHAR(250),
+    position INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_medias_product ON products_medias (product_uuid, position);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_medias_blob_key ON products_medias (blob_key);
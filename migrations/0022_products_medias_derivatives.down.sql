@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS products_medias_derivatives;
+DROP INDEX IF EXISTS idx_products_medias_processing;
+ALTER TABLE products_medias DROP COLUMN IF EXISTS processed_at;
+ALTER TABLE products_medias DROP COLUMN IF EXISTS height;
+ALTER TABLE products_medias DROP COLUMN IF EXISTS width;
+ALTER TABLE products_medias DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE products_medias DROP COLUMN IF EXISTS processing_status;
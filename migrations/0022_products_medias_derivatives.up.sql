@@ -0,0 +1,20 @@
+ALTER TABLE products_medias ADD COLUMN IF NOT EXISTS processing_status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (processing_status IN ('pending', 'processing', 'ready', 'failed', 'skipped'));
+ALTER TABLE products_medias ADD COLUMN IF NOT EXISTS processing_error VARCHAR(500);
+ALTER TABLE products_medias ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE products_medias ADD COLUMN IF NOT EXISTS height INT;
+ALTER TABLE products_medias ADD COLUMN IF NOT EXISTS processed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_products_medias_processing ON products_medias (processing_status, created_at);
+
+CREATE TABLE IF NOT EXISTS products_medias_derivatives (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    media_uuid UUID NOT NULL,
+    blob_key VARCHAR(500) NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('webp', 'jpeg')),
+    width INT NOT NULL CHECK (width > 0),
+    height INT NOT NULL CHECK (height > 0),
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_medias_derivatives_unique ON products_medias_derivatives (media_uuid, format, width);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_products_tags_label_lower;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_vector;
+DROP TRIGGER IF EXISTS trg_products_tags_search ON products_tags;
+DROP TRIGGER IF EXISTS trg_products_search ON products;
+DROP FUNCTION IF EXISTS products_tags_search_trigger();
+DROP FUNCTION IF EXISTS products_search_trigger();
+DROP FUNCTION IF EXISTS products_search_refresh(UUID);
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
+DROP FUNCTION IF EXISTS html_escape(TEXT);
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
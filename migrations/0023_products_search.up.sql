@@ -0,0 +1,94 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is STABLE, index expressions need an IMMUTABLE wrapper
+CREATE OR REPLACE FUNCTION immutable_unaccent(value TEXT)
+RETURNS TEXT
+LANGUAGE SQL
+IMMUTABLE
+PARALLEL SAFE
+STRICT
+AS $$
+    SELECT public.unaccent('public.unaccent'::REGDICTIONARY, value);
+$$;
+
+-- Search snippets are returned with <mark> tags, so the source text is
+-- escaped first and only the highlight markup reaches the storefront as HTML
+CREATE OR REPLACE FUNCTION html_escape(value TEXT)
+RETURNS TEXT
+LANGUAGE SQL
+IMMUTABLE
+PARALLEL SAFE
+STRICT
+AS $$
+    SELECT REPLACE(REPLACE(REPLACE(REPLACE(value, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;');
+$$;
+
+-- Portuguese stemming on top of accent folding: "calçados" ~ "calcado"
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END;
+$$;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Weights: name A, tags B, short description C, description D
+CREATE OR REPLACE FUNCTION products_search_refresh(target UUID)
+RETURNS VOID
+LANGUAGE SQL
+AS $$
+    UPDATE products p SET search_vector =
+        setweight(to_tsvector('portuguese_unaccent', COALESCE(p.name, '')), 'A') ||
+        setweight(to_tsvector('portuguese_unaccent', COALESCE((
+            SELECT string_agg(t.label, ' ') FROM products_tags t WHERE t.product_uuid = p.uuid
+        ), '')), 'B') ||
+        setweight(to_tsvector('portuguese_unaccent', COALESCE(p.short_description, '')), 'C') ||
+        setweight(to_tsvector('portuguese_unaccent', COALESCE(p.description, '')), 'D')
+    WHERE p.uuid = target;
+$$;
+
+CREATE OR REPLACE FUNCTION products_search_trigger()
+RETURNS TRIGGER
+LANGUAGE PLPGSQL
+AS $$
+BEGIN
+    PERFORM products_search_refresh(NEW.uuid);
+    RETURN NULL;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION products_tags_search_trigger()
+RETURNS TRIGGER
+LANGUAGE PLPGSQL
+AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM products_search_refresh(OLD.product_uuid);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM products_search_refresh(NEW.product_uuid);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_products_search ON products;
+CREATE TRIGGER trg_products_search
+    AFTER INSERT OR UPDATE OF name, short_description, description ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_trigger();
+
+DROP TRIGGER IF EXISTS trg_products_tags_search ON products_tags;
+CREATE TRIGGER trg_products_tags_search
+    AFTER INSERT OR UPDATE OR DELETE ON products_tags
+    FOR EACH ROW EXECUTE FUNCTION products_tags_search_trigger();
+
+SELECT products_search_refresh(uuid) FROM products WHERE search_vector IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (immutable_unaccent(LOWER(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_tags_label_lower ON products_tags (LOWER(label));
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS search_queries (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    query VARCHAR(200) NOT NULL,
+    searches BIGINT NOT NULL DEFAULT 0,
+    last_results INT NOT NULL DEFAULT 0,
+    last_searched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The indexes are per website, see 0047_search_queries_website. Migrations
+-- run on every start, so the global ones are not created here anymore.
+
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (immutable_unaccent(LOWER(name)) gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS products_imports_errors;
+DROP TABLE IF EXISTS products_imports;
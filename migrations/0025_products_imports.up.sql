@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS products_imports (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    filename VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    mapping JSONB NOT NULL DEFAULT '{}',
+    blob_key VARCHAR(500) NOT NULL,
+    total_rows INT NOT NULL DEFAULT 0,
+    processed_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    error VARCHAR(500),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_imports_status ON products_imports (status, created_at);
+
+CREATE TABLE IF NOT EXISTS products_imports_errors (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    import_uuid UUID NOT NULL,
+    row_number INT NOT NULL,
+    field VARCHAR(50),
+    message VARCHAR(500) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_imports_errors_import ON products_imports_errors (import_uuid, row_number);
@@ -0,0 +1,5 @@
+ALTER TABLE products_imports_errors DROP COLUMN IF EXISTS level;
+
+ALTER TABLE products_imports DROP COLUMN IF EXISTS warning_count;
+ALTER TABLE products_imports DROP COLUMN IF EXISTS coin;
+ALTER TABLE products_imports DROP COLUMN IF EXISTS source;
@@ -0,0 +1,5 @@
+ALTER TABLE products_imports ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'native' CHECK (source IN ('native', 'shopify'));
+ALTER TABLE products_imports ADD COLUMN IF NOT EXISTS coin VARCHAR(3) NOT NULL DEFAULT 'BRL' CHECK (coin IN ('BRL', 'USD', 'EUR'));
+ALTER TABLE products_imports ADD COLUMN IF NOT EXISTS warning_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE products_imports_errors ADD COLUMN IF NOT EXISTS level VARCHAR(10) NOT NULL DEFAULT 'error' CHECK (level IN ('error', 'warning'));
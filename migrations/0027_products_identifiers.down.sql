@@ -0,0 +1,4 @@
+ALTER TABLE products_variants DROP COLUMN IF EXISTS gtin;
+
+ALTER TABLE products DROP COLUMN IF EXISTS gtin;
+ALTER TABLE products DROP COLUMN IF EXISTS brand;
@@ -0,0 +1,4 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS brand VARCHAR(120);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+
+ALTER TABLE products_variants ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS products_downloads;
+DROP TABLE IF EXISTS products_files;
+
+ALTER TABLE products DROP COLUMN IF EXISTS download_days;
+ALTER TABLE products DROP COLUMN IF EXISTS download_limit;
+ALTER TABLE products DROP COLUMN IF EXISTS type;
@@ -0,0 +1,31 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'physical';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS download_limit INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS download_days INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS products_files (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    product_uuid UUID NOT NULL,
+    blob_key VARCHAR(500) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_files_product ON products_files (product_uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_files_blob_key ON products_files (blob_key);
+
+CREATE TABLE IF NOT EXISTS products_downloads (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    order_uuid UUID NOT NULL,
+    product_uuid UUID NOT NULL,
+    file_uuid UUID NOT NULL,
+    download_limit INT NOT NULL CHECK (download_limit > 0),
+    download_count INT NOT NULL DEFAULT 0 CHECK (download_count >= 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_downloaded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_downloads_order_file ON products_downloads (order_uuid, file_uuid);
+CREATE INDEX IF NOT EXISTS idx_products_downloads_file ON products_downloads (file_uuid);
@@ -0,0 +1,5 @@
+ALTER TABLE products DROP COLUMN IF EXISTS rating_average;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+
+DROP TABLE IF EXISTS products_reviews_photos;
+DROP TABLE IF EXISTS products_reviews;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS products_reviews (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    product_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    shipped_uuid UUID,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(150),
+    body VARCHAR(5000),
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    rejection_reason VARCHAR(500),
+    moderated_by UUID,
+    moderated_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_reviews_product_user ON products_reviews (product_uuid, user_uuid);
+CREATE INDEX IF NOT EXISTS idx_products_reviews_product_status ON products_reviews (product_uuid, status);
+CREATE INDEX IF NOT EXISTS idx_products_reviews_status ON products_reviews (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_products_reviews_user_created ON products_reviews (user_uuid, created_at);
+
+CREATE TABLE IF NOT EXISTS products_reviews_photos (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    review_uuid UUID NOT NULL,
+    blob_key VARCHAR(500) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_reviews_photos_review ON products_reviews_photos (review_uuid, position);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_average NUMERIC(3, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS wishlists_alerts;
+DROP TABLE IF EXISTS wishlists_items;
+DROP TABLE IF EXISTS wishlists;
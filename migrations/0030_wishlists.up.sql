@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS wishlists (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    user_uuid UUID NOT NULL,
+    website_uuid UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    share_token VARCHAR(64),
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_user_name ON wishlists (user_uuid, website_uuid, LOWER(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_share_token ON wishlists (share_token) WHERE share_token IS NOT NULL;
+
+-- in_stock and on_sale hold the last state seen by the alert job, so only
+-- changes to it produce alerts.
+CREATE TABLE IF NOT EXISTS wishlists_items (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    wishlist_uuid UUID NOT NULL,
+    product_uuid UUID NOT NULL,
+    variant_uuid UUID,
+    in_stock BOOLEAN NOT NULL DEFAULT FALSE,
+    on_sale BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlists_items_unique ON wishlists_items (
+    wishlist_uuid,
+    product_uuid,
+    COALESCE(variant_uuid, '00000000-0000-0000-0000-000000000000')
+);
+CREATE INDEX IF NOT EXISTS idx_wishlists_items_product ON wishlists_items (product_uuid);
+
+CREATE TABLE IF NOT EXISTS wishlists_alerts (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    type VARCHAR(20) NOT NULL,
+    wishlist_uuid UUID NOT NULL,
+    item_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    website_uuid UUID NOT NULL,
+    product_uuid UUID NOT NULL,
+    variant_uuid UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlists_alerts_user ON wishlists_alerts (user_uuid, website_uuid, created_at);
@@ -0,0 +1,9 @@
+ALTER TABLE cupons DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE cupons DROP COLUMN IF EXISTS used_count;
+ALTER TABLE cupons DROP COLUMN IF EXISTS usage_limit;
+
+DROP TABLE IF EXISTS orders_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS carts_recovery_settings;
+DROP TABLE IF EXISTS carts_items;
+DROP TABLE IF EXISTS carts;
@@ -0,0 +1,97 @@
+-- A shopper has one open cart per website. activity_at moves with every
+-- change to the cart and is what the recovery job measures idleness from.
+CREATE TABLE IF NOT EXISTS carts (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    reminders_sent INT NOT NULL DEFAULT 0,
+    last_reminder_at TIMESTAMPTZ,
+    restored_at TIMESTAMPTZ,
+    recovery_cupom_uuid UUID,
+    order_uuid UUID,
+    activity_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_open ON carts (website_uuid, user_uuid) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_carts_idle ON carts (activity_at) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS carts_items (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    cart_uuid UUID NOT NULL,
+    product_uuid UUID NOT NULL,
+    variant_uuid UUID,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_items_unique ON carts_items (
+    cart_uuid,
+    product_uuid,
+    COALESCE(variant_uuid, '00000000-0000-0000-0000-000000000000')
+);
+
+CREATE TABLE IF NOT EXISTS carts_recovery_settings (
+    website_uuid UUID PRIMARY KEY NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    idle_minutes INT NOT NULL,
+    reminders INT NOT NULL,
+    interval_hours INT NOT NULL,
+    cupom_value VARCHAR(100),
+    cupom_value_type VARCHAR(10),
+    cupom_valid_days INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Amounts are in cents of coin. total is what the order costs after the
+-- coupon discount; amount_paid is what was charged to the payment method.
+CREATE TABLE IF NOT EXISTS orders (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    cart_uuid UUID,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    subtotal INT NOT NULL CHECK (subtotal >= 0),
+    discount INT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    amount_paid INT NOT NULL DEFAULT 0,
+    refunded_amount INT NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0),
+    cupom_uuid UUID,
+    payment_method VARCHAR(100),
+    payment_reference VARCHAR(255),
+    failure_reason VARCHAR(500),
+    recovered BOOLEAN NOT NULL DEFAULT FALSE,
+    paid_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_user ON orders (website_uuid, user_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_website ON orders (website_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_recovered ON orders (website_uuid, paid_at) WHERE recovered;
+
+CREATE TABLE IF NOT EXISTS orders_items (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    order_uuid UUID NOT NULL,
+    product_uuid UUID NOT NULL,
+    variant_uuid UUID,
+    product_type VARCHAR(20) NOT NULL,
+    name VARCHAR(250) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_amount INT NOT NULL CHECK (unit_amount >= 0),
+    amount INT NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_items_order ON orders_items (order_uuid);
+
+-- Recovery coupons are not tied to a tag and may only be used once.
+ALTER TABLE cupons ALTER COLUMN tag_uuid DROP NOT NULL;
+ALTER TABLE cupons ADD COLUMN IF NOT EXISTS usage_limit INT;
+ALTER TABLE cupons ADD COLUMN IF NOT EXISTS used_count INT NOT NULL DEFAULT 0;
+ALTER TABLE cupons ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
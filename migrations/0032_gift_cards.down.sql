@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS gift_cards_transactions;
+DROP TABLE IF EXISTS gift_cards;
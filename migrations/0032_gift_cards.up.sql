@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS gift_cards (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'gift_card',
+    source VARCHAR(20) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    code_last4 CHAR(4) NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    initial_amount INT NOT NULL CHECK (initial_amount > 0),
+    balance INT NOT NULL CHECK (balance >= 0),
+    user_uuid UUID,
+    product_uuid UUID,
+    order_uuid UUID,
+    recipient_email VARCHAR(255),
+    note VARCHAR(500),
+    expires_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_cards_code ON gift_cards (code_hash);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_website ON gift_cards (website_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_user ON gift_cards (website_uuid, user_uuid) WHERE user_uuid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_gift_cards_expiry ON gift_cards (expires_at) WHERE balance > 0;
+
+-- Every balance change is a row here, so a card's balance is always the sum
+-- of its transactions.
+CREATE TABLE IF NOT EXISTS gift_cards_transactions (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    gift_card_uuid UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount INT NOT NULL,
+    balance_after INT NOT NULL CHECK (balance_after >= 0),
+    order_uuid UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_cards_transactions_card ON gift_cards_transactions (gift_card_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_transactions_order ON gift_cards_transactions (order_uuid) WHERE order_uuid IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_cards_transactions_redeem ON gift_cards_transactions (gift_card_uuid, order_uuid) WHERE type = 'redeem';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_cards_transactions_release ON gift_cards_transactions (gift_card_uuid, order_uuid) WHERE type = 'release';
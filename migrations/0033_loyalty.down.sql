@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_rates;
+DROP TABLE IF EXISTS loyalty_programs;
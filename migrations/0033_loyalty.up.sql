@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS loyalty_programs (
+    website_uuid UUID PRIMARY KEY NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    first_order_bonus INT NOT NULL DEFAULT 0 CHECK (first_order_bonus >= 0),
+    review_bonus INT NOT NULL DEFAULT 0 CHECK (review_bonus >= 0),
+    expiry_days INT NOT NULL DEFAULT 0 CHECK (expiry_days >= 0),
+    min_redeem_points INT NOT NULL DEFAULT 0 CHECK (min_redeem_points >= 0),
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_rates (
+    website_uuid UUID NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    earn_points_per_unit INT NOT NULL CHECK (earn_points_per_unit >= 0),
+    point_value INT NOT NULL CHECK (point_value > 0),
+    PRIMARY KEY (website_uuid, coin)
+);
+
+-- Entries that add points are lots: remaining is what is left of them to
+-- spend, oldest expiry first. Entries that take points away keep it at 0.
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    website_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    points INT NOT NULL,
+    remaining INT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    coin VARCHAR(3),
+    amount INT,
+    order_uuid UUID,
+    review_uuid UUID,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_user ON loyalty_transactions (website_uuid, user_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_lots ON loyalty_transactions (website_uuid, user_uuid, expires_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_order ON loyalty_transactions (order_uuid) WHERE order_uuid IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_earn ON loyalty_transactions (order_uuid) WHERE type = 'earn';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_redeem ON loyalty_transactions (order_uuid) WHERE type = 'redeem';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_release ON loyalty_transactions (order_uuid) WHERE type = 'release';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_first_order ON loyalty_transactions (website_uuid, user_uuid) WHERE type = 'first_order_bonus';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_review ON loyalty_transactions (review_uuid) WHERE type = 'review_bonus';
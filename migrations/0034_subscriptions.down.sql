@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_website;
+ALTER TABLE products DROP COLUMN IF EXISTS website_uuid;
+DROP TABLE IF EXISTS subscriptions;
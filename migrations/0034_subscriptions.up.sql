@@ -0,0 +1,20 @@
+-- An owner (a user or an organization) is on one plan at a time; its
+-- limits cap the websites, routes and products the owner can create.
+CREATE TABLE IF NOT EXISTS subscriptions (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    owner_uuid UUID NOT NULL,
+    owner_type VARCHAR(12) NOT NULL CHECK (owner_type IN ('User', 'Organization')),
+    plan_uuid UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_owner ON subscriptions (owner_uuid);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_plan ON subscriptions (plan_uuid);
+
+-- Products count against the plan of the website they were created for.
+-- Imported products have no website and are not counted.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS website_uuid UUID;
+
+CREATE INDEX IF NOT EXISTS idx_products_website ON products (website_uuid);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS subscriptions_invoices;
+
+ALTER TABLE websites DROP COLUMN IF EXISTS suspended_at;
+
+DROP INDEX IF EXISTS idx_subscriptions_status;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS credit;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS past_due_since;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS cancel_at_period_end;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS current_period_end;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS current_period_start;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS trial_ends_at;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS payment_method;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS cycle;
+
+ALTER TABLE plans DROP COLUMN IF EXISTS trial_days;
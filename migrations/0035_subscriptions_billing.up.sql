@@ -0,0 +1,42 @@
+ALTER TABLE plans ADD COLUMN IF NOT EXISTS trial_days INT NOT NULL DEFAULT 0;
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cycle VARCHAR(10) NOT NULL DEFAULT 'monthly';
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS payment_method VARCHAR(250);
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS trial_ends_at TIMESTAMPTZ;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS current_period_start TIMESTAMPTZ;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS current_period_end TIMESTAMPTZ;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS past_due_since TIMESTAMPTZ;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS credit INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status ON subscriptions (status);
+
+ALTER TABLE websites ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+
+-- Every charge of a subscription. Open invoices are retried on
+-- next_attempt_at until paid or out of attempts.
+CREATE TABLE IF NOT EXISTS subscriptions_invoices (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    subscription_uuid UUID NOT NULL,
+    owner_uuid UUID NOT NULL,
+    plan_uuid UUID NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    cycle VARCHAR(10) NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    amount INT NOT NULL CHECK (amount >= 0),
+    credit_applied INT NOT NULL DEFAULT 0 CHECK (credit_applied >= 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    provider_reference VARCHAR(250),
+    failure_reason VARCHAR(500),
+    paid_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_invoices_owner ON subscriptions_invoices (owner_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_invoices_retry ON subscriptions_invoices (next_attempt_at) WHERE status = 'open';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_invoices_renewal ON subscriptions_invoices (subscription_uuid, period_start) WHERE reason = 'renewal';
@@ -0,0 +1,19 @@
+-- What Verkoupe takes from each sale. A paid order adds one 'fee' entry at
+-- the rate of the merchant's plan; refunds add 'refund' entries giving
+-- back the share of the fee the refunded amount paid.
+CREATE TABLE IF NOT EXISTS platform_fees (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    owner_uuid UUID NOT NULL,
+    website_uuid UUID NOT NULL,
+    order_uuid UUID NOT NULL,
+    plan_uuid UUID,
+    type VARCHAR(10) NOT NULL,
+    coin VARCHAR(3) NOT NULL CHECK (coin IN ('BRL', 'USD', 'EUR')),
+    amount INT NOT NULL CHECK (amount >= 0),
+    rate INT NOT NULL DEFAULT 0,
+    fee INT NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_platform_fees_order ON platform_fees (order_uuid) WHERE type = 'fee';
+CREATE INDEX IF NOT EXISTS idx_platform_fees_owner ON platform_fees (owner_uuid, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS organizations_invitations;
+DROP TABLE IF EXISTS organizations_members;
@@ -0,0 +1,37 @@
+-- Users of an organization. Exactly one member is the owner, the same user
+-- as organizations.owner_uuid; members may have a custom role from rbac.
+CREATE TABLE IF NOT EXISTS organizations_members (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    organization_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    rbac_uuid UUID,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_members_user ON organizations_members (organization_uuid, user_uuid);
+CREATE INDEX IF NOT EXISTS idx_organizations_members_by_user ON organizations_members (user_uuid);
+
+INSERT INTO organizations_members (organization_uuid, user_uuid, role)
+SELECT uuid, owner_uuid, 'owner' FROM organizations
+ON CONFLICT DO NOTHING;
+
+-- Invitations are sent by email with a token whose hash is kept here. An
+-- address has at most one pending invitation per organization.
+CREATE TABLE IF NOT EXISTS organizations_invitations (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    organization_uuid UUID NOT NULL,
+    email VARCHAR(250) NOT NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('admin', 'member')),
+    rbac_uuid UUID,
+    token_hash VARCHAR(64) NOT NULL,
+    invited_by UUID NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_invitations_token ON organizations_invitations (token_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_invitations_pending ON organizations_invitations (organization_uuid, LOWER(email)) WHERE status = 'pending';
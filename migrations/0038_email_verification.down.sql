@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS users_tokens;
+ALTER TABLE websites DROP COLUMN IF EXISTS require_email_verification;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
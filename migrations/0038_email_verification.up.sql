@@ -0,0 +1,22 @@
+-- Users registered before verification existed are taken as verified: the
+-- default fills existing rows when the column is added, then new users
+-- start unverified.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ DEFAULT NOW();
+ALTER TABLE users ALTER COLUMN email_verified_at DROP DEFAULT;
+
+ALTER TABLE websites ADD COLUMN IF NOT EXISTS require_email_verification BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Single-use tokens mailed to users, e.g. to verify their email. Only the
+-- hash of a token is stored.
+CREATE TABLE IF NOT EXISTS users_tokens (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    user_uuid UUID NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tokens_hash ON users_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_users_tokens_user ON users_tokens (user_uuid, purpose, created_at);
@@ -0,0 +1,2 @@
+DELETE FROM users_tokens WHERE purpose = 'password_reset';
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at;
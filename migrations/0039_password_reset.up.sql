@@ -0,0 +1,3 @@
+-- Access tokens issued before sessions_revoked_at are rejected, e.g. after
+-- a password reset. Reset tokens live in users_tokens.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS login_failures;
+DELETE FROM users_tokens WHERE purpose = 'account_unlock';
+ALTER TABLE users DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS last_failed_login_at;
+ALTER TABLE users DROP COLUMN IF EXISTS failed_logins;
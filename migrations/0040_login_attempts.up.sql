@@ -0,0 +1,16 @@
+-- Failed sign ins of an account since its last success; enough of them
+-- lock it until locked_until.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_logins INT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+-- Failed sign ins per client address, whatever email they tried. Rows only
+-- matter for a short window and are pruned.
+CREATE TABLE IF NOT EXISTS login_failures (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_ip ON login_failures (ip, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_failures_created ON login_failures (created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE websites DROP COLUMN IF EXISTS require_two_factor;
+DELETE FROM users_tokens WHERE purpose = 'two_factor_challenge';
+DROP TABLE IF EXISTS users_trusted_devices;
+DROP TABLE IF EXISTS users_recovery_codes;
+DROP TABLE IF EXISTS users_two_factor;
@@ -0,0 +1,40 @@
+-- TOTP enrollment per user. The secret is sealed with the server key;
+-- enabled_at stays NULL until the user confirms a first code. last_step is
+-- the time step of the last accepted code, so a code only works once.
+CREATE TABLE IF NOT EXISTS users_two_factor (
+    user_uuid UUID PRIMARY KEY NOT NULL,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Single-use codes for when the authenticator is lost. Only hashes are
+-- stored.
+CREATE TABLE IF NOT EXISTS users_recovery_codes (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    user_uuid UUID NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_recovery_codes_user ON users_recovery_codes (user_uuid, code_hash);
+
+-- Devices remembered at sign in, which skip the code until expires_at.
+CREATE TABLE IF NOT EXISTS users_trusted_devices (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    user_uuid UUID NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_trusted_devices_hash ON users_trusted_devices (token_hash);
+CREATE INDEX IF NOT EXISTS idx_users_trusted_devices_user ON users_trusted_devices (user_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_users_trusted_devices_expires ON users_trusted_devices (expires_at);
+
+ALTER TABLE websites ADD COLUMN IF NOT EXISTS require_two_factor BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cupons_website;
+ALTER TABLE cupons DROP COLUMN IF EXISTS coin;
+ALTER TABLE cupons DROP COLUMN IF EXISTS website_uuid;
@@ -0,0 +1,11 @@
+ALTER TABLE cupons ADD COLUMN IF NOT EXISTS website_uuid UUID;
+ALTER TABLE cupons ADD COLUMN IF NOT EXISTS coin VARCHAR(3) NOT NULL DEFAULT 'BRL' CHECK (coin IN ('BRL', 'USD', 'EUR'));
+
+-- Coupons made before this belong to the website of their tag's product.
+UPDATE cupons c
+SET website_uuid = p.website_uuid
+FROM products_tags t
+JOIN products p ON p.uuid = t.product_uuid
+WHERE c.website_uuid IS NULL AND t.uuid = c.tag_uuid;
+
+CREATE INDEX IF NOT EXISTS idx_cupons_website ON cupons (website_uuid);
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS provider_refunded;
+ALTER TABLE orders DROP COLUMN IF EXISTS gift_card_amount;
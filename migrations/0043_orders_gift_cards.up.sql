@@ -0,0 +1,11 @@
+-- gift_card_amount is the part of total covered by gift cards and store
+-- credit; provider_refunded the part of refunded_amount sent back to the
+-- payment method, the rest having gone to store credit.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS gift_card_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS provider_refunded INT CHECK (provider_refunded >= 0);
+
+-- Refunds made before this all went back to the payment method.
+UPDATE orders SET provider_refunded = refunded_amount WHERE provider_refunded IS NULL;
+
+ALTER TABLE orders ALTER COLUMN provider_refunded SET DEFAULT 0;
+ALTER TABLE orders ALTER COLUMN provider_refunded SET NOT NULL;
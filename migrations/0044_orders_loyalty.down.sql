@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS loyalty_discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS loyalty_points;
@@ -0,0 +1,3 @@
+-- loyalty_discount is what loyalty_points redeemed took off the order.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS loyalty_points INT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS loyalty_discount INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_imports_website;
+ALTER TABLE products_imports DROP COLUMN IF EXISTS website_uuid;
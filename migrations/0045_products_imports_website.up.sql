@@ -0,0 +1,3 @@
+ALTER TABLE products_imports ADD COLUMN IF NOT EXISTS website_uuid UUID;
+
+CREATE INDEX IF NOT EXISTS idx_products_imports_website ON products_imports (website_uuid, created_at);
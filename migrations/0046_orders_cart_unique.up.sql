@@ -0,0 +1,15 @@
+-- A cart has one order in progress or paid at a time, so two checkouts of
+-- the same cart cannot both charge. Failed orders do not count: the cart
+-- stays open and may be checked out again.
+UPDATE orders o
+SET status = 'failed', failure_reason = 'superseded', updated_at = NOW()
+WHERE o.status = 'pending'
+AND o.cart_uuid IS NOT NULL
+AND EXISTS (
+    SELECT 1 FROM orders n
+    WHERE n.cart_uuid = o.cart_uuid
+    AND n.status <> 'failed'
+    AND n.uuid > o.uuid
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_cart ON orders (cart_uuid) WHERE status <> 'failed';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_search_queries_website_searches;
+DROP INDEX IF EXISTS idx_search_queries_website_prefix;
+DROP INDEX IF EXISTS idx_search_queries_website_query;
+
+ALTER TABLE search_queries DROP COLUMN IF EXISTS website_uuid;
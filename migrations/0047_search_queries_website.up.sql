@@ -0,0 +1,15 @@
+-- Searches are counted per website. Counters recorded before carry no
+-- website and cannot be told apart, so they are dropped.
+ALTER TABLE search_queries ADD COLUMN IF NOT EXISTS website_uuid UUID;
+
+DELETE FROM search_queries WHERE website_uuid IS NULL;
+
+ALTER TABLE search_queries ALTER COLUMN website_uuid SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_search_queries_query;
+DROP INDEX IF EXISTS idx_search_queries_prefix;
+DROP INDEX IF EXISTS idx_search_queries_searches;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_queries_website_query ON search_queries (website_uuid, query);
+CREATE INDEX IF NOT EXISTS idx_search_queries_website_prefix ON search_queries (website_uuid, query varchar_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_search_queries_website_searches ON search_queries (website_uuid, searches DESC);
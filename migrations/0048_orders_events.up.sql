@@ -0,0 +1,20 @@
+-- The hooks owed to paid and refunded orders, written with the change they
+-- follow and run until they succeed. refunded_to tells the refunds of an
+-- order apart and is 0 for paid events.
+CREATE TABLE IF NOT EXISTS orders_events (
+    uuid UUID PRIMARY KEY NOT NULL DEFAULT uuid_v7(),
+    order_uuid UUID NOT NULL,
+    hook VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount INT NOT NULL DEFAULT 0,
+    refunded_to INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(500),
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_events_unique ON orders_events (order_uuid, type, refunded_to, hook);
+CREATE INDEX IF NOT EXISTS idx_orders_events_due ON orders_events (run_at) WHERE status IN ('pending', 'running');
@@ -0,0 +1,12 @@
+-- The customer group each customer of a website is in, which picks the
+-- price lists made for that group.
+CREATE TABLE IF NOT EXISTS customers_groups (
+    website_uuid UUID NOT NULL,
+    user_uuid UUID NOT NULL,
+    customer_group VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (website_uuid, user_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customers_groups_group ON customers_groups (website_uuid, customer_group);
@@ -0,0 +1 @@
+-- The WebP derivatives replaced when this ran are not recreated.
@@ -0,0 +1,7 @@
+-- WebP derivatives are no longer made. Medias that still have some are
+-- processed again, which replaces them with the configured formats and
+-- deletes their blobs.
+UPDATE products_medias
+SET processing_status = 'pending', processing_error = NULL
+WHERE processing_status <> 'processing'
+AND uuid IN (SELECT media_uuid FROM products_medias_derivatives WHERE format = 'webp');
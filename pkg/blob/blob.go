@@ -0,0 +1,18 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore abstracts where uploaded files live. Keys are slash separated
+// paths such as "products/<uuid>/<file>.jpg".
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -0,0 +1,29 @@
+package blob
+
+import (
+	"fmt"
+
+	"github.com/ViitoJooj/verkoupe/pkg/dotenv"
+)
+
+func Conn(cfg dotenv.Storage) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		dir := cfg.LocalDir
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return NewLocalStore(dir, cfg.PublicURL)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.PublicURL,
+		})
+	default:
+		return nil, fmt.Errorf("blob: unknown driver %q", cfg.Driver)
+	}
+}
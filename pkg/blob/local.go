@@ -0,0 +1,101 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ BlobStore = (*LocalStore)(nil)
+
+type LocalStore struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStore(root string, publicURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{
+		root:      root,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}, nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *LocalStore) Root() string {
+	return s.root
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid blob key")
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -29,6 +29,33 @@ func Conn() (*Config, error) {
 		},
 		Security: Security{
 			PasetoSecretKey: os.Getenv("PASETO_SECRET_KEY"),
+			PlatformAdmins:  os.Getenv("PLATFORM_ADMINS"),
+		},
+		Currency: Currency{
+			ExchangeRatesFile: os.Getenv("EXCHANGE_RATES_FILE"),
+		},
+		Storage: Storage{
+			Driver:         os.Getenv("BLOB_DRIVER"),
+			LocalDir:       os.Getenv("BLOB_LOCAL_DIR"),
+			PublicURL:      os.Getenv("BLOB_PUBLIC_URL"),
+			MaxUploadBytes: os.Getenv("MEDIA_MAX_UPLOAD_BYTES"),
+			ImageWidths:    os.Getenv("MEDIA_IMAGE_WIDTHS"),
+			ImageFormats:   os.Getenv("MEDIA_IMAGE_FORMATS"),
+			JPEGQuality:    os.Getenv("MEDIA_JPEG_QUALITY"),
+			MaxImportBytes: os.Getenv("IMPORT_MAX_UPLOAD_BYTES"),
+			MaxFileBytes:   os.Getenv("DOWNLOAD_MAX_UPLOAD_BYTES"),
+			S3Endpoint:     os.Getenv("S3_ENDPOINT"),
+			S3Region:       os.Getenv("S3_REGION"),
+			S3Bucket:       os.Getenv("S3_BUCKET"),
+			S3AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey:    os.Getenv("S3_SECRET_KEY"),
+		},
+		Billing: Billing{
+			PaymentProvider: os.Getenv("PAYMENT_PROVIDER"),
+		},
+		Mail: Mail{
+			Driver: os.Getenv("MAIL_DRIVER"),
+			From:   os.Getenv("MAIL_FROM"),
 		},
 	}, nil
 }
@@ -4,6 +4,10 @@ type Config struct {
 	Application Application
 	PostgreSQL  PostgreSQL
 	Security    Security
+	Currency    Currency
+	Storage     Storage
+	Billing     Billing
+	Mail        Mail
 }
 
 type Application struct {
@@ -24,4 +28,35 @@ type PostgreSQL struct {
 
 type Security struct {
 	PasetoSecretKey string
+	PlatformAdmins  string
+}
+
+type Currency struct {
+	ExchangeRatesFile string
+}
+
+type Storage struct {
+	Driver         string
+	LocalDir       string
+	PublicURL      string
+	MaxUploadBytes string
+	ImageWidths    string
+	ImageFormats   string
+	JPEGQuality    string
+	MaxImportBytes string
+	MaxFileBytes   string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+}
+
+type Billing struct {
+	PaymentProvider string
+}
+
+type Mail struct {
+	Driver string
+	From   string
 }
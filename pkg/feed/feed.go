@@ -0,0 +1,81 @@
+// Package feed writes product catalogs in the formats read by shopping
+// channels: the Google Merchant Center RSS feed and the Meta catalog CSV.
+package feed
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxAdditionalImages = 10
+
+type Availability string
+
+const (
+	InStock    Availability = "in_stock"
+	OutOfStock Availability = "out_of_stock"
+)
+
+// Money is an amount in the minor unit of an ISO 4217 currency.
+type Money struct {
+	Amount   int
+	Currency string
+}
+
+// String formats the amount as the channels expect it, e.g. "19.90 BRL".
+func (m Money) String() string {
+	return fmt.Sprintf("%d.%02d %s", m.Amount/100, m.Amount%100, strings.ToUpper(m.Currency))
+}
+
+type Channel struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+// Item is one sellable offer. Variants of the same product are separate
+// items sharing a GroupID.
+type Item struct {
+	ID                   string
+	GroupID              string
+	Title                string
+	Description          string
+	Link                 string
+	ImageLink            string
+	AdditionalImageLinks []string
+	Availability         Availability
+	Price                Money
+	SalePrice            *Money
+	Brand                string
+	GTIN                 string
+	MPN                  string
+	ProductType          string
+}
+
+// HasIdentifiers reports whether the item can be matched to a known product;
+// channels want identifier_exists set to no otherwise.
+func (i *Item) HasIdentifiers() bool {
+	return i.GTIN != "" || (i.Brand != "" && i.MPN != "")
+}
+
+func (i *Item) additionalImages() []string {
+	if len(i.AdditionalImageLinks) > maxAdditionalImages {
+		return i.AdditionalImageLinks[:maxAdditionalImages]
+	}
+	return i.AdditionalImageLinks
+}
+
+func (i *Item) description() string {
+	if i.Description == "" {
+		return i.Title
+	}
+	return i.Description
+}
+
+func shorten(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
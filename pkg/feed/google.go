@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const googleNamespace = "http://base.google.com/ns/1.0"
+
+const (
+	googleMaxTitle       = 150
+	googleMaxDescription = 5000
+)
+
+type googleRSS struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	G       string        `xml:"xmlns:g,attr"`
+	Channel googleChannel `xml:"channel"`
+}
+
+type googleChannel struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	Items       []googleItem `xml:"item"`
+}
+
+type googleItem struct {
+	ID                   string   `xml:"g:id"`
+	GroupID              string   `xml:"g:item_group_id,omitempty"`
+	Title                string   `xml:"g:title"`
+	Description          string   `xml:"g:description"`
+	Link                 string   `xml:"g:link"`
+	ImageLink            string   `xml:"g:image_link,omitempty"`
+	AdditionalImageLinks []string `xml:"g:additional_image_link,omitempty"`
+	Availability         string   `xml:"g:availability"`
+	Price                string   `xml:"g:price"`
+	SalePrice            string   `xml:"g:sale_price,omitempty"`
+	Brand                string   `xml:"g:brand,omitempty"`
+	GTIN                 string   `xml:"g:gtin,omitempty"`
+	MPN                  string   `xml:"g:mpn,omitempty"`
+	IdentifierExists     string   `xml:"g:identifier_exists,omitempty"`
+	Condition            string   `xml:"g:condition"`
+	ProductType          string   `xml:"g:product_type,omitempty"`
+}
+
+// WriteGoogleRSS writes the items as a Google Merchant Center RSS 2.0 feed.
+// Every item is sold as new.
+func WriteGoogleRSS(w io.Writer, channel Channel, items []*Item) error {
+	rss := googleRSS{
+		Version: "2.0",
+		G:       googleNamespace,
+		Channel: googleChannel{
+			Title:       channel.Title,
+			Link:        channel.Link,
+			Description: channel.Description,
+			Items:       make([]googleItem, 0, len(items)),
+		},
+	}
+
+	for _, item := range items {
+		g := googleItem{
+			ID:                   item.ID,
+			GroupID:              item.GroupID,
+			Title:                shorten(item.Title, googleMaxTitle),
+			Description:          shorten(item.description(), googleMaxDescription),
+			Link:                 item.Link,
+			ImageLink:            item.ImageLink,
+			AdditionalImageLinks: item.additionalImages(),
+			Availability:         string(item.Availability),
+			Price:                item.Price.String(),
+			Brand:                item.Brand,
+			GTIN:                 item.GTIN,
+			MPN:                  item.MPN,
+			Condition:            "new",
+			ProductType:          item.ProductType,
+		}
+		if item.SalePrice != nil {
+			g.SalePrice = item.SalePrice.String()
+		}
+		if !item.HasIdentifiers() {
+			g.IdentifierExists = "no"
+		}
+		rss.Channel.Items = append(rss.Channel.Items, g)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(rss); err != nil {
+		return err
+	}
+	return enc.Close()
+}
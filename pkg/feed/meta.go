@@ -0,0 +1,75 @@
+package feed
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+const (
+	metaMaxTitle       = 200
+	metaMaxDescription = 9999
+)
+
+var metaHeader = []string{
+	"id",
+	"title",
+	"description",
+	"availability",
+	"condition",
+	"price",
+	"link",
+	"image_link",
+	"additional_image_link",
+	"brand",
+	"gtin",
+	"mpn",
+	"item_group_id",
+	"sale_price",
+	"product_type",
+}
+
+var metaAvailability = map[Availability]string{
+	InStock:    "in stock",
+	OutOfStock: "out of stock",
+}
+
+// WriteMetaCSV writes the items as a Meta (Facebook and Instagram) catalog
+// data feed.
+func WriteMetaCSV(w io.Writer, items []*Item) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(metaHeader); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		salePrice := ""
+		if item.SalePrice != nil {
+			salePrice = item.SalePrice.String()
+		}
+
+		record := []string{
+			item.ID,
+			shorten(item.Title, metaMaxTitle),
+			shorten(item.description(), metaMaxDescription),
+			metaAvailability[item.Availability],
+			"new",
+			item.Price.String(),
+			item.Link,
+			item.ImageLink,
+			strings.Join(item.additionalImages(), ","),
+			item.Brand,
+			item.GTIN,
+			item.MPN,
+			item.GroupID,
+			salePrice,
+			item.ProductType,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
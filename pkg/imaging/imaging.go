@@ -0,0 +1,113 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds width×height of images Decode accepts, so a small file
+// declaring huge dimensions cannot exhaust memory.
+const MaxPixels = 50_000_000
+
+var ErrTooLarge = errors.New("image dimensions too large")
+
+// Decode reads a JPEG, PNG, GIF or WebP image and, for JPEGs, applies the
+// EXIF orientation so the returned pixels are upright.
+func Decode(data []byte) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	return img, format, nil
+}
+
+// Resize scales img to the given width keeping the aspect ratio. Images are
+// never upscaled.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width >= bounds.Dx() {
+		width = bounds.Dx()
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// EncodeJPEG flattens transparency over white, since JPEG has no alpha.
+func EncodeJPEG(w io.Writer, img image.Image, quality int) error {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+
+	return jpeg.Encode(w, flat, &jpeg.Options{Quality: quality})
+}
+
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
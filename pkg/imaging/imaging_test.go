@@ -0,0 +1,38 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestDecodeRejectsHugeDimensions(t *testing.T) {
+	small := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, small); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Decode(buf.Bytes()); err != nil {
+		t.Fatalf("Decode small: %v", err)
+	}
+
+	bomb := pngWithSize(buf.Bytes(), 100_000, 100_000)
+	if _, _, err := Decode(bomb); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Decode bomb err = %v, want ErrTooLarge", err)
+	}
+}
+
+// pngWithSize rewrites the IHDR of a PNG to declare other dimensions.
+func pngWithSize(data []byte, width, height uint32) []byte {
+	out := bytes.Clone(data)
+	ihdr := out[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:4], width)
+	binary.BigEndian.PutUint32(ihdr[4:8], height)
+	binary.BigEndian.PutUint32(out[8+8+13:], crc32.ChecksumIEEE(out[8+4:8+8+13]))
+	return out
+}
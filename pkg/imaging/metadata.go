@@ -0,0 +1,149 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// jpegOrientation returns the EXIF orientation tag (1-8) or 1 when absent.
+func jpegOrientation(data []byte) int {
+	for _, segment := range jpegSegments(data) {
+		if segment.marker != 0xe1 || !bytes.HasPrefix(segment.payload, []byte("Exif\x00\x00")) {
+			continue
+		}
+
+		tiff := segment.payload[6:]
+		if len(tiff) < 8 {
+			return 1
+		}
+
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 1
+		}
+
+		offset := int(order.Uint32(tiff[4:8]))
+		if offset+2 > len(tiff) {
+			return 1
+		}
+
+		entries := int(order.Uint16(tiff[offset:]))
+		for i := 0; i < entries; i++ {
+			entry := offset + 2 + i*12
+			if entry+12 > len(tiff) {
+				return 1
+			}
+			if order.Uint16(tiff[entry:]) == 0x0112 {
+				return int(order.Uint16(tiff[entry+8:]))
+			}
+		}
+		return 1
+	}
+
+	return 1
+}
+
+// StripMetadata drops EXIF, XMP, IPTC and comments from JPEG and PNG files
+// without touching the compressed image data. Other formats are returned
+// unchanged.
+func StripMetadata(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xd8}):
+		return stripJPEG(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return stripPNG(data)
+	default:
+		return data
+	}
+}
+
+type jpegSegment struct {
+	marker  byte
+	start   int
+	end     int
+	payload []byte
+}
+
+// jpegSegments lists the marker segments before the start of scan.
+func jpegSegments(data []byte) []jpegSegment {
+	var segments []jpegSegment
+	if !bytes.HasPrefix(data, []byte{0xff, 0xd8}) {
+		return nil
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xff {
+			return segments
+		}
+		marker := data[i+1]
+		if marker == 0xff {
+			i++
+			continue
+		}
+		if marker == 0xda || marker == 0xd9 {
+			return segments
+		}
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return segments
+		}
+
+		segments = append(segments, jpegSegment{marker: marker, start: i, end: end, payload: data[i+4 : end]})
+		i = end
+	}
+
+	return segments
+}
+
+func stripJPEG(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+
+	last := 2
+	for _, segment := range jpegSegments(data) {
+		out = append(out, data[last:segment.start]...)
+		last = segment.end
+		// APP1 (EXIF/XMP), APP13 (IPTC) and COM carry no rendering data.
+		if segment.marker == 0xe1 || segment.marker == 0xed || segment.marker == 0xfe {
+			continue
+		}
+		out = append(out, data[segment.start:segment.end]...)
+	}
+
+	return append(out, data[last:]...)
+}
+
+func stripPNG(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:8]...)
+
+	i := 8
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return data
+		}
+
+		switch string(data[i+4 : i+8]) {
+		case "eXIf", "tEXt", "iTXt", "zTXt", "tIME":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	return out
+}
@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The PDF support covers what statements and reports need: lines of
+// monospaced text on A4 pages, with bold headings. Text is written in
+// WinAnsiEncoding; characters outside it print as '?'.
+
+const (
+	Format      = "pdf"
+	ContentType = "application/pdf"
+)
+
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 50
+	fontSize     = 9
+	leading      = 12
+	linesPerPage = (pageHeight - 2*margin) / leading
+)
+
+type line struct {
+	text string
+	bold bool
+}
+
+// Document collects lines and lays them out top to bottom, starting a new
+// page when one is full.
+type Document struct {
+	lines []line
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+func (d *Document) Heading(text string) {
+	d.lines = append(d.lines, line{text: text, bold: true})
+}
+
+func (d *Document) Text(text string) {
+	d.lines = append(d.lines, line{text: text})
+}
+
+func (d *Document) Blank() {
+	d.lines = append(d.lines, line{})
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	pages := d.pages()
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	b.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		content := pageContent(page)
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+// pages splits the lines into pages; an empty document still has one.
+func (d *Document) pages() [][]line {
+	var pages [][]line
+	for start := 0; start < len(d.lines); start += linesPerPage {
+		pages = append(pages, d.lines[start:min(start+linesPerPage, len(d.lines))])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	return pages
+}
+
+func pageContent(lines []line) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n%d TL\n%d %d Td\n", leading, margin, pageHeight-margin)
+
+	current := ""
+	for _, l := range lines {
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		if font != current {
+			current = font
+			fmt.Fprintf(&b, "/%s %d Tf\n", font, fontSize)
+		}
+		b.WriteString("(")
+		b.WriteString(escape(l.text))
+		b.WriteString(") Tj T*\n")
+	}
+
+	b.WriteString("ET")
+	return b.String()
+}
+
+// escape encodes text as a WinAnsi string literal.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteByte(0x80)
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ViitoJooj/verkoupe/pkg/logger"
+)
+
+// Every runs job right away and then once per interval until the returned
+// stop function is called. A panicking job is logged and does not stop the
+// schedule.
+func Every(interval time.Duration, job func()) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		run(job)
+		for {
+			select {
+			case <-ticker.C:
+				run(job)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+func run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Warn(fmt.Errorf("scheduled job panicked: %v", r)).Print()
+		}
+	}()
+
+	job()
+}
@@ -0,0 +1,61 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type csvReader struct {
+	r *csv.Reader
+}
+
+// NewCSVReader skips a UTF-8 BOM and picks ';' as the delimiter when the
+// header has more semicolons than commas, as spreadsheet tools in
+// comma-decimal locales export.
+func NewCSVReader(r io.Reader) (Reader, error) {
+	br := bufio.NewReader(r)
+
+	if head, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(head, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+
+	header, _ := br.Peek(br.Size())
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+
+	reader := csv.NewReader(br)
+	if bytes.Count(header, []byte{';'}) > bytes.Count(header, []byte{','}) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = false
+
+	return &csvReader{r: reader}, nil
+}
+
+func (c *csvReader) Read() ([]string, error) {
+	return c.r.Read()
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(record []string) error {
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
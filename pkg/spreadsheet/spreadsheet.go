@@ -0,0 +1,66 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Reader yields one record per call and io.EOF after the last one. Records
+// are padded or trimmed by the caller; spreadsheets often have ragged rows.
+type Reader interface {
+	Read() ([]string, error)
+}
+
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+// FormatFromFilename maps a file extension to a supported format.
+func FormatFromFilename(name string) (string, error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(name), ".")) {
+	case FormatCSV, "txt":
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported spreadsheet format %q", filepath.Ext(name))
+	}
+}
+
+func NewReader(format string, data []byte) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVReader(bytes.NewReader(data))
+	case FormatXLSX:
+		return NewXLSXReader(data)
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
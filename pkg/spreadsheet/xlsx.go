@@ -0,0 +1,363 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// The XLSX support covers what catalog spreadsheets need: the first
+// worksheet, shared and inline strings, numbers and booleans. Styles, dates
+// and formulas are read as their stored text.
+
+// Limits on what an uploaded workbook may expand to.
+const (
+	MaxXLSXPartBytes = 64 << 20
+	MaxXLSXRows      = 100_000
+	MaxXLSXColumns   = 1024
+)
+
+var ErrXLSXTooLarge = errors.New("xlsx: workbook too large")
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, run := range t.R {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxCell struct {
+	Ref    string       `xml:"r,attr"`
+	Type   string       `xml:"t,attr"`
+	Value  string       `xml:"v"`
+	Inline xlsxRichText `xml:"is"`
+}
+
+type xlsxRow struct {
+	Index int        `xml:"r,attr"`
+	Cells []xlsxCell `xml:"c"`
+}
+
+type xlsxReader struct {
+	decoder *xml.Decoder
+	shared  []string
+	next    int
+}
+
+func NewXLSXReader(data []byte) (Reader, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := readSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New("xlsx: worksheet not found")
+	}
+	content, err := readZipPart(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxReader{
+		decoder: xml.NewDecoder(bytes.NewReader(content)),
+		shared:  shared,
+		next:    1,
+	}, nil
+}
+
+// Read fails on a skipped row instead of padding it, so record numbers
+// keep matching the row numbers users see.
+func (x *xlsxReader) Read() ([]string, error) {
+	row, err := x.nextRow()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case row.Index > MaxXLSXRows:
+		return nil, ErrXLSXTooLarge
+	case row.Index > x.next:
+		return nil, fmt.Errorf("xlsx: row %d is empty; remove blank rows", x.next)
+	case row.Index < x.next:
+		return nil, fmt.Errorf("xlsx: row %d is out of order", row.Index)
+	}
+	x.next++
+
+	return x.record(row)
+}
+
+func (x *xlsxReader) nextRow() (*xlsxRow, error) {
+	for {
+		token, err := x.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		row := &xlsxRow{}
+		if err := x.decoder.DecodeElement(row, &start); err != nil {
+			return nil, fmt.Errorf("xlsx: %w", err)
+		}
+		if row.Index == 0 {
+			row.Index = x.next
+		}
+		return row, nil
+	}
+}
+
+func (x *xlsxReader) record(row *xlsxRow) ([]string, error) {
+	var record []string
+	for i, cell := range row.Cells {
+		column := i
+		if cell.Ref != "" {
+			parsed, err := columnIndex(cell.Ref)
+			if err != nil {
+				return nil, err
+			}
+			column = parsed
+		}
+
+		if column >= MaxXLSXColumns {
+			return nil, ErrXLSXTooLarge
+		}
+		for len(record) <= column {
+			record = append(record, "")
+		}
+
+		switch cell.Type {
+		case "s":
+			idx, err := strconv.Atoi(cell.Value)
+			if err != nil || idx < 0 || idx >= len(x.shared) {
+				return nil, fmt.Errorf("xlsx: invalid shared string in %s", cell.Ref)
+			}
+			record[column] = x.shared[idx]
+		case "inlineStr":
+			record[column] = cell.Inline.String()
+		case "b":
+			if cell.Value == "1" {
+				record[column] = "true"
+			} else {
+				record[column] = "false"
+			}
+		default:
+			record[column] = cell.Value
+		}
+	}
+	return record, nil
+}
+
+// columnIndex turns the letters of a cell reference like "AB12" into a
+// zero-based column number.
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		if c >= 'A' && c <= 'Z' {
+			column = column*26 + int(c-'A'+1)
+			if column > MaxXLSXColumns {
+				return 0, ErrXLSXTooLarge
+			}
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return column - 1, nil
+	}
+	return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+}
+
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx: workbook has no sheets")
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", errors.New("xlsx: worksheet relationship not found")
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	var table struct {
+		Items []xlsxRichText `xml:"si"`
+	}
+	if err := decodeZipXML(f, &table); err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	if f == nil {
+		return errors.New("xlsx: missing workbook part")
+	}
+
+	content, err := readZipPart(f)
+	if err != nil {
+		return err
+	}
+
+	if err := xml.NewDecoder(bytes.NewReader(content)).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// readZipPart reads a whole zip entry, up to MaxXLSXPartBytes once
+// decompressed.
+func readZipPart(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, MaxXLSXPartBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	if len(content) > MaxXLSXPartBytes {
+		return nil, ErrXLSXTooLarge
+	}
+	return content, nil
+}
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+}
+
+// NewXLSXWriter streams a single-sheet workbook. Every cell is written as an
+// inline string, so nothing has to be buffered until Close.
+func NewXLSXWriter(w io.Writer) (Writer, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+	}
+
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) Write(record []string) error {
+	x.rows++
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<row r="%d">`, x.rows)
+	for _, value := range record {
+		b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(&b, []byte(value))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	_, err := x.sheet.Write(b.Bytes())
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
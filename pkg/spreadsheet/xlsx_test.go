@@ -0,0 +1,211 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXLSXRoundTrip(t *testing.T) {
+	records := [][]string{
+		{"sku", "name", "price"},
+		{"A-1", "Caneca <azul> & branca", "19.90"},
+		{},
+		{"B-2", "  espaços  ", ""},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readAllXLSX(t, buf.Bytes())
+	want := [][]string{
+		{"sku", "name", "price"},
+		{"A-1", "Caneca <azul> & branca", "19.90"},
+		nil,
+		{"B-2", "  espaços  ", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("records = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared []string
+		rows   string
+		want   [][]string
+		err    error
+	}{
+		{
+			name:   "shared strings and sparse cells",
+			shared: []string{"sku", "price"},
+			rows:   `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row><row r="2"><c r="A2"><v>7</v></c><c r="B2" t="b"><v>1</v></c></row>`,
+			want:   [][]string{{"sku", "", "price"}, {"7", "true"}},
+		},
+		{
+			name: "rows without numbers",
+			rows: `<row><c t="inlineStr"><is><t>a</t></is></c></row><row><c t="inlineStr"><is><t>b</t></is></c></row>`,
+			want: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "skipped row",
+			rows: `<row r="1"><c r="A1"><v>1</v></c></row><row r="3"><c r="A3"><v>3</v></c></row>`,
+			err:  errors.New("xlsx: row 2 is empty; remove blank rows"),
+		},
+		{
+			name: "row out of order",
+			rows: `<row r="1"><c r="A1"><v>1</v></c></row><row r="1"><c r="A1"><v>1</v></c></row>`,
+			err:  errors.New("xlsx: row 1 is out of order"),
+		},
+		{
+			name: "huge row index",
+			rows: `<row r="20000000"><c r="A20000000"><v>1</v></c></row>`,
+			err:  ErrXLSXTooLarge,
+		},
+		{
+			name: "huge column reference",
+			rows: `<row r="1"><c r="ZZZZZ1"><v>1</v></c></row>`,
+			err:  ErrXLSXTooLarge,
+		},
+		{
+			name: "overflowing column reference",
+			rows: `<row r="1"><c r="ZZZZZZZZZZZZZZZZZZZZ1"><v>1</v></c></row>`,
+			err:  ErrXLSXTooLarge,
+		},
+		{
+			name: "shared string out of range",
+			rows: `<row r="1"><c r="A1" t="s"><v>5</v></c></row>`,
+			err:  errors.New("xlsx: invalid shared string in A1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewXLSXReader(buildXLSX(t, tt.shared, strings.NewReader(sheetXML(tt.rows))))
+			if err != nil {
+				t.Fatalf("NewXLSXReader: %v", err)
+			}
+
+			var got [][]string
+			for {
+				record, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if tt.err == nil {
+						t.Fatalf("Read: %v", err)
+					}
+					if !errors.Is(err, tt.err) && err.Error() != tt.err.Error() {
+						t.Fatalf("Read err = %v, want %v", err, tt.err)
+					}
+					return
+				}
+				got = append(got, record)
+			}
+
+			if tt.err != nil {
+				t.Fatalf("records = %q, want error %v", got, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("records = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXLSXReaderRejectsOversizedParts(t *testing.T) {
+	// Whitespace compresses to almost nothing, the way a zip bomb does.
+	padding := io.LimitReader(zeroSpaces{}, MaxXLSXPartBytes)
+	sheet := io.MultiReader(strings.NewReader(sheetXML("")), padding)
+
+	if _, err := NewXLSXReader(buildXLSX(t, nil, sheet)); !errors.Is(err, ErrXLSXTooLarge) {
+		t.Fatalf("NewXLSXReader err = %v, want ErrXLSXTooLarge", err)
+	}
+}
+
+type zeroSpaces struct{}
+
+func (zeroSpaces) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	return len(p), nil
+}
+
+func sheetXML(rows string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+// buildXLSX packs a minimal workbook around a worksheet.
+func buildXLSX(t *testing.T, shared []string, sheet io.Reader) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]io.Reader{
+		"xl/workbook.xml": strings.NewReader(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`),
+		"xl/_rels/workbook.xml.rels": strings.NewReader(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`),
+		"xl/worksheets/sheet1.xml":   sheet,
+	}
+	if shared != nil {
+		var sst strings.Builder
+		sst.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+		for _, s := range shared {
+			sst.WriteString("<si><t>" + s + "</t></si>")
+		}
+		sst.WriteString("</sst>")
+		parts["xl/sharedStrings.xml"] = strings.NewReader(sst.String())
+	}
+
+	for name, body := range parts {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.Copy(f, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readAllXLSX(t *testing.T, data []byte) [][]string {
+	t.Helper()
+
+	reader, err := NewXLSXReader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+}
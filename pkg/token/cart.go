@@ -0,0 +1,53 @@
+package token
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/o1egl/paseto/v2"
+)
+
+var ErrInvalidCartRestore = errors.New("invalid cart restore token")
+
+type cartRestoreClaims struct {
+	Type      string    `json:"type"`
+	CartUUID  string    `json:"cart_uuid"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// cartRestoreKey derives a separate key for cart restore links, for the
+// same reason as downloadKey.
+func cartRestoreKey(secret []byte) []byte {
+	key := sha256.Sum256(append([]byte("verkoupe-cart-restore:"), secret...))
+	return key[:]
+}
+
+// GenerateCartRestore signs the link of a recovery reminder back to a cart.
+func GenerateCartRestore(secret []byte, cartUUID string, ttl time.Duration) (string, error) {
+	claims := cartRestoreClaims{
+		Type:      "cart_restore",
+		CartUUID:  cartUUID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	return paseto.NewV2().Encrypt(cartRestoreKey(secret), claims, nil)
+}
+
+func ParseCartRestore(tokenStr string, secret []byte) (string, error) {
+	var claims cartRestoreClaims
+
+	if err := paseto.NewV2().Decrypt(tokenStr, cartRestoreKey(secret), &claims, nil); err != nil {
+		return "", ErrInvalidCartRestore
+	}
+
+	if claims.Type != "cart_restore" || claims.CartUUID == "" {
+		return "", ErrInvalidCartRestore
+	}
+
+	if time.Now().After(claims.ExpiresAt) {
+		return "", ErrExpired
+	}
+
+	return claims.CartUUID, nil
+}
@@ -0,0 +1,60 @@
+package token
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/o1egl/paseto/v2"
+)
+
+var ErrInvalidDownload = errors.New("invalid download token")
+
+type downloadClaims struct {
+	Type         string    `json:"type"`
+	DownloadUUID string    `json:"download_uuid"`
+	ExpiresAt    time.Time `json:"exp"`
+}
+
+// downloadKey derives a separate key for download links, so a link can
+// never be decrypted as a session token and the other way around.
+func downloadKey(secret []byte) []byte {
+	key := sha256.Sum256(append([]byte("verkoupe-download:"), secret...))
+	return key[:]
+}
+
+// GenerateDownload signs a link to one download grant that stops working
+// after ttl. The grant itself still enforces its own limit and expiry.
+func GenerateDownload(secret []byte, downloadUUID string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := downloadClaims{
+		Type:         "download",
+		DownloadUUID: downloadUUID,
+		ExpiresAt:    expiresAt,
+	}
+
+	tokenStr, err := paseto.NewV2().Encrypt(downloadKey(secret), claims, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenStr, expiresAt, nil
+}
+
+func ParseDownload(tokenStr string, secret []byte) (string, error) {
+	var claims downloadClaims
+
+	if err := paseto.NewV2().Decrypt(tokenStr, downloadKey(secret), &claims, nil); err != nil {
+		return "", ErrInvalidDownload
+	}
+
+	if claims.Type != "download" || claims.DownloadUUID == "" {
+		return "", ErrInvalidDownload
+	}
+
+	if time.Now().After(claims.ExpiresAt) {
+		return "", ErrExpired
+	}
+
+	return claims.DownloadUUID, nil
+}
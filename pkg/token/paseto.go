@@ -7,9 +7,10 @@ import (
 )
 
 type Claims struct {
-	UserUUID    string `json:"user_uuid"`
-	WebSiteUUID string `json:"website_uuid"`
-	Role        string `json:"role"`
+	UserUUID    string    `json:"user_uuid"`
+	WebSiteUUID string    `json:"website_uuid"`
+	Role        string    `json:"role"`
+	IssuedAt    time.Time `json:"iat"`
 }
 
 type pasetoClaims struct {
@@ -64,6 +65,7 @@ func Parse(tokenStr string, secret []byte) (*Claims, error) {
 		UserUUID:    claims.UserUUID,
 		WebSiteUUID: claims.WebSiteUUID,
 		Role:        claims.Role,
+		IssuedAt:    claims.IssuedAt,
 	}, nil
 }
 
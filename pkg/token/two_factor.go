@@ -0,0 +1,47 @@
+package token
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/o1egl/paseto/v2"
+)
+
+var ErrInvalidTwoFactorSecret = errors.New("invalid two-factor secret")
+
+type twoFactorClaims struct {
+	Type   string `json:"type"`
+	Secret string `json:"secret"`
+}
+
+// twoFactorKey derives the key two-factor secrets are sealed with, apart
+// from the session and download keys.
+func twoFactorKey(secret []byte) []byte {
+	key := sha256.Sum256(append([]byte("verkoupe-two-factor:"), secret...))
+	return key[:]
+}
+
+// SealTwoFactorSecret encrypts a TOTP secret to store it, so a leaked
+// table alone cannot generate codes.
+func SealTwoFactorSecret(secret []byte, totpSecret string) (string, error) {
+	claims := twoFactorClaims{
+		Type:   "two_factor",
+		Secret: totpSecret,
+	}
+
+	return paseto.NewV2().Encrypt(twoFactorKey(secret), claims, nil)
+}
+
+func OpenTwoFactorSecret(sealed string, secret []byte) (string, error) {
+	var claims twoFactorClaims
+
+	if err := paseto.NewV2().Decrypt(sealed, twoFactorKey(secret), &claims, nil); err != nil {
+		return "", ErrInvalidTwoFactorSecret
+	}
+
+	if claims.Type != "two_factor" || claims.Secret == "" {
+		return "", ErrInvalidTwoFactorSecret
+	}
+
+	return claims.Secret, nil
+}
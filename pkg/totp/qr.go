@@ -0,0 +1,19 @@
+package totp
+
+import (
+	"rsc.io/qr"
+)
+
+const QRContentType = "image/png"
+
+// QR renders uri as a PNG QR code, with medium error correction so it
+// still scans from a dim or scaled screen.
+func QR(uri string) ([]byte, error) {
+	code, err := qr.Encode(uri, qr.M)
+	if err != nil {
+		return nil, err
+	}
+
+	code.Scale = 6
+	return code.PNG(), nil
+}
@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Codes follow RFC 6238 with the parameters every authenticator app
+// understands: HMAC-SHA1, six digits and 30 second steps.
+
+const (
+	Digits = 6
+	Period = 30 * time.Second
+
+	// Skew is how many steps before and after the current one a code is
+	// still accepted, for clocks that drift a little.
+	Skew = 1
+
+	secretSize = 20
+	modulus    = 1_000_000 // 10^Digits
+)
+
+var ErrInvalidSecret = errors.New("invalid totp secret")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random secret in base32, the way authenticator
+// apps take it.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// Step is the number of the time step t falls in.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code is the code of secret for a time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(key) == 0 {
+		return "", ErrInvalidSecret
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", Digits, value%modulus), nil
+}
+
+// Validate looks for code among the steps around now and returns the step
+// it belongs to. Callers keep the step to refuse the same code twice.
+func Validate(secret string, code string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(now)
+	for step := current - Skew; step <= current+Skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI is the otpauth:// link authenticator apps enroll from, usually
+// scanned as a QR code.
+func URI(issuer string, account string, secret string) string {
+	return fmt.Sprintf(
+		"otpauth://totp/%s:%s?secret=%s&issuer=%s&algorithm=SHA1&digits=%d&period=%d",
+		escape(issuer),
+		escape(account),
+		secret,
+		escape(issuer),
+		Digits,
+		int(Period.Seconds()),
+	)
+}
+
+// escape encodes spaces as %20, since some apps show a + as is.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,123 @@
+package totp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		err    error
+	}{
+		{name: "lower case", secret: strings.ToLower(rfcSecret)},
+		{name: "empty", secret: "", err: ErrInvalidSecret},
+		{name: "not base32", secret: "not-base32!", err: ErrInvalidSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Code(tt.secret, 1)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Code() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code := func(step int64) string {
+		c, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		ok       bool
+	}{
+		{name: "current step", secret: rfcSecret, code: code(current), wantStep: current, ok: true},
+		{name: "previous step", secret: rfcSecret, code: code(current - Skew), wantStep: current - Skew, ok: true},
+		{name: "next step", secret: rfcSecret, code: code(current + Skew), wantStep: current + Skew, ok: true},
+		{name: "spaced", secret: rfcSecret, code: code(current)[:3] + " " + code(current)[3:], wantStep: current, ok: true},
+		{name: "too old", secret: rfcSecret, code: code(current - Skew - 1)},
+		{name: "too new", secret: rfcSecret, code: code(current + Skew + 1)},
+		{name: "wrong code", secret: rfcSecret, code: "000000"},
+		{name: "short", secret: rfcSecret, code: code(current)[:5]},
+		{name: "invalid secret", secret: "", code: code(current)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now)
+			if ok != tt.ok || step != tt.wantStep {
+				t.Fatalf("Validate(%q) = %d, %v; want %d, %v", tt.code, step, ok, tt.wantStep, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a == b {
+		t.Fatal("GenerateSecret returned the same secret twice")
+	}
+	if _, err := Code(a, 1); err != nil {
+		t.Fatalf("Code with a generated secret: %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	got := URI("Loja Azul", "ana+teste@example.com", rfcSecret)
+	want := "otpauth://totp/Loja%20Azul:ana%2Bteste%40example.com?secret=" + rfcSecret + "&issuer=Loja%20Azul&algorithm=SHA1&digits=6&period=30"
+	if got != want {
+		t.Fatalf("URI() = %s, want %s", got, want)
+	}
+}